@@ -1,12 +1,15 @@
 import * as cdk from 'aws-cdk-lib';
 import {Construct} from 'constructs';
 import * as dynamodb from 'aws-cdk-lib/aws-dynamodb';
-import {AttributeType, BillingMode} from 'aws-cdk-lib/aws-dynamodb';
+import * as events from 'aws-cdk-lib/aws-events';
+import * as targets from 'aws-cdk-lib/aws-events-targets';
+import {AttributeType, BillingMode, ProjectionType} from 'aws-cdk-lib/aws-dynamodb';
 import * as iam from "aws-cdk-lib/aws-iam";
 import {AccountPrincipal} from "aws-cdk-lib/aws-iam";
 import * as lambdago from "@aws-cdk/aws-lambda-go-alpha";
 import * as lambda from "aws-cdk-lib/aws-lambda";
 import {FunctionUrlAuthType} from "aws-cdk-lib/aws-lambda";
+import * as cr from "aws-cdk-lib/custom-resources";
 import * as path from "path";
 
 export class InfraStack extends cdk.Stack {
@@ -25,6 +28,171 @@ export class InfraStack extends cdk.Stack {
           },
           billingMode: BillingMode.PAY_PER_REQUEST
       });
+      // Accounts of each product, i.e. account type, whose interest is accrued. Only the keys are needed, as each
+      // account is read consistently before it accrues.
+      accountsTable.addGlobalSecondaryIndex({
+          indexName: 'accounts-by-type-index',
+          partitionKey: {
+              name: 'AccountType',
+              type: AttributeType.STRING
+          },
+          sortKey: {
+              name: 'AccountId',
+              type: AttributeType.STRING
+          },
+          projectionType: ProjectionType.KEYS_ONLY
+      });
+
+      const transactionsTable = new dynamodb.Table(this, 'TransactionsTable', {
+          tableName: 'transactions-table',
+          partitionKey: {
+              name: 'AccountId',
+              type: AttributeType.STRING
+          },
+          sortKey: {
+              name: 'TransactionId',
+              type: AttributeType.STRING
+          },
+          billingMode: BillingMode.PAY_PER_REQUEST
+      });
+
+      const idempotencyTable = new dynamodb.Table(this, 'IdempotencyTable', {
+          tableName: 'idempotency-table',
+          partitionKey: {
+              name: 'AccountId',
+              type: AttributeType.STRING
+          },
+          sortKey: {
+              name: 'IdempotencyKey',
+              type: AttributeType.STRING
+          },
+          billingMode: BillingMode.PAY_PER_REQUEST,
+          timeToLiveAttribute: 'ExpiresAt'
+      });
+
+      // Quotes lock an exchange rate for a short time, and are deleted by the transfer which uses them
+      const quotesTable = new dynamodb.Table(this, 'QuotesTable', {
+          tableName: 'fx-quotes-table',
+          partitionKey: {
+              name: 'AccountId',
+              type: AttributeType.STRING
+          },
+          sortKey: {
+              name: 'QuoteId',
+              type: AttributeType.STRING
+          },
+          billingMode: BillingMode.PAY_PER_REQUEST,
+          timeToLiveAttribute: 'ExpiresAt'
+      });
+
+      // Exchange rates by currency pair, e.g. {Pair: 'USD/EUR', Rate: '0.9214'}
+      const ratesTable = new dynamodb.Table(this, 'RatesTable', {
+          tableName: 'fx-rates-table',
+          partitionKey: {
+              name: 'Pair',
+              type: AttributeType.STRING
+          },
+          billingMode: BillingMode.PAY_PER_REQUEST
+      });
+
+      // Product catalog, which defines the terms of each account type that accounts may be created with
+      const productsTable = new dynamodb.Table(this, 'ProductsTable', {
+          tableName: 'products-table',
+          partitionKey: {
+              name: 'AccountType',
+              type: AttributeType.STRING
+          },
+          billingMode: BillingMode.PAY_PER_REQUEST
+      });
+
+      // Scheduled transfers of each account. Only active schedules have a NextRunAt, so the index lists those which are
+      // due by time.
+      const schedulesTable = new dynamodb.Table(this, 'SchedulesTable', {
+          tableName: 'schedules-table',
+          partitionKey: {
+              name: 'AccountId',
+              type: AttributeType.STRING
+          },
+          sortKey: {
+              name: 'ScheduleId',
+              type: AttributeType.STRING
+          },
+          billingMode: BillingMode.PAY_PER_REQUEST
+      });
+      schedulesTable.addGlobalSecondaryIndex({
+          indexName: 'due-schedules-index',
+          partitionKey: {
+              name: 'ScheduleStatus',
+              type: AttributeType.STRING
+          },
+          sortKey: {
+              name: 'NextRunAt',
+              type: AttributeType.STRING
+          }
+      });
+
+      // Outcome of each occurrence of a schedule, by zero padded occurrence number
+      const scheduleExecutionsTable = new dynamodb.Table(this, 'ScheduleExecutionsTable', {
+          tableName: 'schedule-executions-table',
+          partitionKey: {
+              name: 'ScheduleId',
+              type: AttributeType.STRING
+          },
+          sortKey: {
+              name: 'Occurrence',
+              type: AttributeType.STRING
+          },
+          billingMode: BillingMode.PAY_PER_REQUEST
+      });
+
+      // Roles granted to callers beyond customer, e.g. {AccountId: '105343117262', Roles: ['admin']}
+      const rolesTable = new dynamodb.Table(this, 'RolesTable', {
+          tableName: 'roles-table',
+          partitionKey: {
+              name: 'AccountId',
+              type: AttributeType.STRING
+          },
+          billingMode: BillingMode.PAY_PER_REQUEST
+      });
+      // The admin account which the functions used to hardcode is seeded as an admin when the table is created, so that
+      // it keeps its role. Roles already granted to it are left as they are.
+      new cr.AwsCustomResource(this, 'SeedAdminRole', {
+          onCreate: {
+              service: 'DynamoDB',
+              action: 'putItem',
+              parameters: {
+                  TableName: rolesTable.tableName,
+                  Item: {
+                      AccountId: {S: '105343117262'},
+                      Roles: {SS: ['admin']}
+                  },
+                  ConditionExpression: 'attribute_not_exists(AccountId)'
+              },
+              physicalResourceId: cr.PhysicalResourceId.of('roles-table-admin-seed'),
+              ignoreErrorCodesMatching: 'ConditionalCheckFailedException'
+          },
+          policy: cr.AwsCustomResourcePolicy.fromSdkCalls({
+              resources: [rolesTable.tableArn]
+          })
+      });
+
+      // The functions only read roles, which are managed out of band
+      const rolesReadPolicy = new iam.PolicyStatement({
+          actions: [
+              'dynamodb:GetItem'
+          ],
+          effect: iam.Effect.ALLOW,
+          resources: [rolesTable.tableArn]
+      })
+
+      // The functions only read rates, which are managed out of band
+      const ratesReadPolicy = new iam.PolicyStatement({
+          actions: [
+              'dynamodb:GetItem'
+          ],
+          effect: iam.Effect.ALLOW,
+          resources: [ratesTable.tableArn]
+      })
 
       const dynamoDBAccessPolicy = new iam.PolicyStatement({
           actions: [
@@ -36,14 +204,26 @@ export class InfraStack extends cdk.Stack {
               'dynamodb:UpdateItem'
           ],
           effect: iam.Effect.ALLOW,
-          resources: [accountsTable.tableArn]
+          resources: [accountsTable.tableArn, `${accountsTable.tableArn}/index/*`, transactionsTable.tableArn, idempotencyTable.tableArn, quotesTable.tableArn, productsTable.tableArn]
+      })
+
+      const schedulesAccessPolicy = new iam.PolicyStatement({
+          actions: [
+              'dynamodb:GetItem',
+              'dynamodb:PutItem',
+              'dynamodb:Query',
+              'dynamodb:UpdateItem'
+          ],
+          effect: iam.Effect.ALLOW,
+          resources: [schedulesTable.tableArn, `${schedulesTable.tableArn}/index/*`, scheduleExecutionsTable.tableArn]
       })
 
       const createAccountLambda = new lambdago.GoFunction(this, 'create-account-function', {
           entry: path.join(__dirname, '../../lambda/functions/create-account'),
           functionName: 'create-account',
           initialPolicy: [
-              new iam.PolicyStatement(dynamoDBAccessPolicy)
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy)
           ]
       })
       createAccountLambda.addPermission('resource-policy', {
@@ -60,7 +240,8 @@ export class InfraStack extends cdk.Stack {
           entry: path.join(__dirname, '../../lambda/functions/delete-account'),
           functionName: 'delete-account',
           initialPolicy: [
-              new iam.PolicyStatement(dynamoDBAccessPolicy)
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy)
           ]
       })
       deleteAccountLambda.addPermission('resource-policy', {
@@ -77,7 +258,8 @@ export class InfraStack extends cdk.Stack {
           entry: path.join(__dirname, '../../lambda/functions/get-balance'),
           functionName: 'get-balance',
           initialPolicy: [
-              new iam.PolicyStatement(dynamoDBAccessPolicy)
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy)
           ]
       })
       getBalanceLambda.addPermission('resource-policy', {
@@ -94,7 +276,8 @@ export class InfraStack extends cdk.Stack {
           entry: path.join(__dirname, '../../lambda/functions/list-accounts'),
           functionName: 'list-accounts',
           initialPolicy: [
-              new iam.PolicyStatement(dynamoDBAccessPolicy)
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy)
           ]
       })
       listAccountsLambda.addPermission('resource-policy', {
@@ -107,11 +290,30 @@ export class InfraStack extends cdk.Stack {
           authType: lambda.FunctionUrlAuthType.AWS_IAM
       })
 
+      const listTransactionsLambda = new lambdago.GoFunction(this, 'list-transactions-function', {
+          entry: path.join(__dirname, '../../lambda/functions/list-transactions'),
+          functionName: 'list-transactions',
+          initialPolicy: [
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy)
+          ]
+      })
+      listTransactionsLambda.addPermission('resource-policy', {
+          action: 'lambda:InvokeFunctionUrl',
+          principal: new AccountPrincipal('*'),
+          functionUrlAuthType: FunctionUrlAuthType.AWS_IAM
+      })
+      new lambda.FunctionUrl(this, 'list-transactions-url', {
+          function: listTransactionsLambda,
+          authType: lambda.FunctionUrlAuthType.AWS_IAM
+      })
+
       const transferLambda = new lambdago.GoFunction(this, 'transfer-function', {
           entry: path.join(__dirname, '../../lambda/functions/transfer'),
           functionName: 'transfer',
           initialPolicy: [
-              new iam.PolicyStatement(dynamoDBAccessPolicy)
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy)
           ]
       })
       transferLambda.addPermission('resource-policy', {
@@ -124,6 +326,384 @@ export class InfraStack extends cdk.Stack {
           authType: lambda.FunctionUrlAuthType.AWS_IAM
       })
 
+      const depositLambda = new lambdago.GoFunction(this, 'deposit-function', {
+          entry: path.join(__dirname, '../../lambda/functions/deposit'),
+          functionName: 'deposit',
+          initialPolicy: [
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy)
+          ]
+      })
+      depositLambda.addPermission('resource-policy', {
+          action: 'lambda:InvokeFunctionUrl',
+          principal: new AccountPrincipal('*'),
+          functionUrlAuthType: FunctionUrlAuthType.AWS_IAM
+      })
+      new lambda.FunctionUrl(this, 'deposit-url', {
+          function: depositLambda,
+          authType: lambda.FunctionUrlAuthType.AWS_IAM
+      })
+
+      const withdrawLambda = new lambdago.GoFunction(this, 'withdraw-function', {
+          entry: path.join(__dirname, '../../lambda/functions/withdraw'),
+          functionName: 'withdraw',
+          initialPolicy: [
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy)
+          ]
+      })
+      withdrawLambda.addPermission('resource-policy', {
+          action: 'lambda:InvokeFunctionUrl',
+          principal: new AccountPrincipal('*'),
+          functionUrlAuthType: FunctionUrlAuthType.AWS_IAM
+      })
+      new lambda.FunctionUrl(this, 'withdraw-url', {
+          function: withdrawLambda,
+          authType: lambda.FunctionUrlAuthType.AWS_IAM
+      })
+
+      const reconcileLambda = new lambdago.GoFunction(this, 'reconcile-function', {
+          entry: path.join(__dirname, '../../lambda/functions/reconcile'),
+          functionName: 'reconcile',
+          initialPolicy: [
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy)
+          ]
+      })
+      reconcileLambda.addPermission('resource-policy', {
+          action: 'lambda:InvokeFunctionUrl',
+          principal: new AccountPrincipal('*'),
+          functionUrlAuthType: FunctionUrlAuthType.AWS_IAM
+      })
+      new lambda.FunctionUrl(this, 'reconcile-url', {
+          function: reconcileLambda,
+          authType: lambda.FunctionUrlAuthType.AWS_IAM
+      })
+
+      const quoteLambda = new lambdago.GoFunction(this, 'quote-function', {
+          entry: path.join(__dirname, '../../lambda/functions/quote'),
+          functionName: 'quote',
+          initialPolicy: [
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy),
+              new iam.PolicyStatement(ratesReadPolicy)
+          ]
+      })
+      quoteLambda.addPermission('resource-policy', {
+          action: 'lambda:InvokeFunctionUrl',
+          principal: new AccountPrincipal('*'),
+          functionUrlAuthType: FunctionUrlAuthType.AWS_IAM
+      })
+      new lambda.FunctionUrl(this, 'quote-url', {
+          function: quoteLambda,
+          authType: lambda.FunctionUrlAuthType.AWS_IAM
+      })
+
+      const setOverdraftLimitLambda = new lambdago.GoFunction(this, 'set-overdraft-limit-function', {
+          entry: path.join(__dirname, '../../lambda/functions/set-overdraft-limit'),
+          functionName: 'set-overdraft-limit',
+          initialPolicy: [
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy)
+          ]
+      })
+      setOverdraftLimitLambda.addPermission('resource-policy', {
+          action: 'lambda:InvokeFunctionUrl',
+          principal: new AccountPrincipal('*'),
+          functionUrlAuthType: FunctionUrlAuthType.AWS_IAM
+      })
+      new lambda.FunctionUrl(this, 'set-overdraft-limit-url', {
+          function: setOverdraftLimitLambda,
+          authType: lambda.FunctionUrlAuthType.AWS_IAM
+      })
+
+      const placeHoldLambda = new lambdago.GoFunction(this, 'place-hold-function', {
+          entry: path.join(__dirname, '../../lambda/functions/place-hold'),
+          functionName: 'place-hold',
+          initialPolicy: [
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy)
+          ]
+      })
+      placeHoldLambda.addPermission('resource-policy', {
+          action: 'lambda:InvokeFunctionUrl',
+          principal: new AccountPrincipal('*'),
+          functionUrlAuthType: FunctionUrlAuthType.AWS_IAM
+      })
+      new lambda.FunctionUrl(this, 'place-hold-url', {
+          function: placeHoldLambda,
+          authType: lambda.FunctionUrlAuthType.AWS_IAM
+      })
+
+      const captureHoldLambda = new lambdago.GoFunction(this, 'capture-hold-function', {
+          entry: path.join(__dirname, '../../lambda/functions/capture-hold'),
+          functionName: 'capture-hold',
+          initialPolicy: [
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy)
+          ]
+      })
+      captureHoldLambda.addPermission('resource-policy', {
+          action: 'lambda:InvokeFunctionUrl',
+          principal: new AccountPrincipal('*'),
+          functionUrlAuthType: FunctionUrlAuthType.AWS_IAM
+      })
+      new lambda.FunctionUrl(this, 'capture-hold-url', {
+          function: captureHoldLambda,
+          authType: lambda.FunctionUrlAuthType.AWS_IAM
+      })
+
+      const releaseHoldLambda = new lambdago.GoFunction(this, 'release-hold-function', {
+          entry: path.join(__dirname, '../../lambda/functions/release-hold'),
+          functionName: 'release-hold',
+          initialPolicy: [
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy)
+          ]
+      })
+      releaseHoldLambda.addPermission('resource-policy', {
+          action: 'lambda:InvokeFunctionUrl',
+          principal: new AccountPrincipal('*'),
+          functionUrlAuthType: FunctionUrlAuthType.AWS_IAM
+      })
+      new lambda.FunctionUrl(this, 'release-hold-url', {
+          function: releaseHoldLambda,
+          authType: lambda.FunctionUrlAuthType.AWS_IAM
+      })
+
+      const createScheduleLambda = new lambdago.GoFunction(this, 'create-schedule-function', {
+          entry: path.join(__dirname, '../../lambda/functions/create-schedule'),
+          functionName: 'create-schedule',
+          initialPolicy: [
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy),
+              new iam.PolicyStatement(schedulesAccessPolicy)
+          ]
+      })
+      createScheduleLambda.addPermission('resource-policy', {
+          action: 'lambda:InvokeFunctionUrl',
+          principal: new AccountPrincipal('*'),
+          functionUrlAuthType: FunctionUrlAuthType.AWS_IAM
+      })
+      new lambda.FunctionUrl(this, 'create-schedule-url', {
+          function: createScheduleLambda,
+          authType: lambda.FunctionUrlAuthType.AWS_IAM
+      })
+
+      const listSchedulesLambda = new lambdago.GoFunction(this, 'list-schedules-function', {
+          entry: path.join(__dirname, '../../lambda/functions/list-schedules'),
+          functionName: 'list-schedules',
+          initialPolicy: [
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy),
+              new iam.PolicyStatement(schedulesAccessPolicy)
+          ]
+      })
+      listSchedulesLambda.addPermission('resource-policy', {
+          action: 'lambda:InvokeFunctionUrl',
+          principal: new AccountPrincipal('*'),
+          functionUrlAuthType: FunctionUrlAuthType.AWS_IAM
+      })
+      new lambda.FunctionUrl(this, 'list-schedules-url', {
+          function: listSchedulesLambda,
+          authType: lambda.FunctionUrlAuthType.AWS_IAM
+      })
+
+      const cancelScheduleLambda = new lambdago.GoFunction(this, 'cancel-schedule-function', {
+          entry: path.join(__dirname, '../../lambda/functions/cancel-schedule'),
+          functionName: 'cancel-schedule',
+          initialPolicy: [
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy),
+              new iam.PolicyStatement(schedulesAccessPolicy)
+          ]
+      })
+      cancelScheduleLambda.addPermission('resource-policy', {
+          action: 'lambda:InvokeFunctionUrl',
+          principal: new AccountPrincipal('*'),
+          functionUrlAuthType: FunctionUrlAuthType.AWS_IAM
+      })
+      new lambda.FunctionUrl(this, 'cancel-schedule-url', {
+          function: cancelScheduleLambda,
+          authType: lambda.FunctionUrlAuthType.AWS_IAM
+      })
+
+      const listScheduleExecutionsLambda = new lambdago.GoFunction(this, 'list-schedule-executions-function', {
+          entry: path.join(__dirname, '../../lambda/functions/list-schedule-executions'),
+          functionName: 'list-schedule-executions',
+          initialPolicy: [
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy),
+              new iam.PolicyStatement(schedulesAccessPolicy)
+          ]
+      })
+      listScheduleExecutionsLambda.addPermission('resource-policy', {
+          action: 'lambda:InvokeFunctionUrl',
+          principal: new AccountPrincipal('*'),
+          functionUrlAuthType: FunctionUrlAuthType.AWS_IAM
+      })
+      new lambda.FunctionUrl(this, 'list-schedule-executions-url', {
+          function: listScheduleExecutionsLambda,
+          authType: lambda.FunctionUrlAuthType.AWS_IAM
+      })
+
+      const runSchedulesLambda = new lambdago.GoFunction(this, 'run-schedules-function', {
+          entry: path.join(__dirname, '../../lambda/functions/run-schedules'),
+          functionName: 'run-schedules',
+          initialPolicy: [
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy),
+              new iam.PolicyStatement(schedulesAccessPolicy)
+          ]
+      })
+      runSchedulesLambda.addPermission('resource-policy', {
+          action: 'lambda:InvokeFunctionUrl',
+          principal: new AccountPrincipal('*'),
+          functionUrlAuthType: FunctionUrlAuthType.AWS_IAM
+      })
+      new lambda.FunctionUrl(this, 'run-schedules-url', {
+          function: runSchedulesLambda,
+          authType: lambda.FunctionUrlAuthType.AWS_IAM
+      })
+
+      // Runs the schedules which are due every few minutes, so transfers are made within a few minutes of their time
+      const scheduleRunnerLambda = new lambdago.GoFunction(this, 'schedule-runner-function', {
+          entry: path.join(__dirname, '../../lambda/functions/schedule-runner'),
+          functionName: 'schedule-runner',
+          timeout: cdk.Duration.minutes(1),
+          initialPolicy: [
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(schedulesAccessPolicy)
+          ]
+      })
+      new events.Rule(this, 'schedule-runner-rule', {
+          schedule: events.Schedule.rate(cdk.Duration.minutes(5)),
+          targets: [new targets.LambdaFunction(scheduleRunnerLambda)]
+      })
+
+      const accrueInterestLambda = new lambdago.GoFunction(this, 'accrue-interest-function', {
+          entry: path.join(__dirname, '../../lambda/functions/accrue-interest'),
+          functionName: 'accrue-interest',
+          timeout: cdk.Duration.minutes(5),
+          initialPolicy: [
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy)
+          ]
+      })
+      accrueInterestLambda.addPermission('resource-policy', {
+          action: 'lambda:InvokeFunctionUrl',
+          principal: new AccountPrincipal('*'),
+          functionUrlAuthType: FunctionUrlAuthType.AWS_IAM
+      })
+      new lambda.FunctionUrl(this, 'accrue-interest-url', {
+          function: accrueInterestLambda,
+          authType: lambda.FunctionUrlAuthType.AWS_IAM
+      })
+
+      // Accrues the interest of the previous day shortly after midnight UTC, posting it at the end of each period
+      const interestRunnerLambda = new lambdago.GoFunction(this, 'interest-runner-function', {
+          entry: path.join(__dirname, '../../lambda/functions/interest-runner'),
+          functionName: 'interest-runner',
+          timeout: cdk.Duration.minutes(15),
+          initialPolicy: [
+              new iam.PolicyStatement(dynamoDBAccessPolicy)
+          ]
+      })
+      new events.Rule(this, 'interest-runner-rule', {
+          schedule: events.Schedule.cron({minute: '30', hour: '0'}),
+          targets: [new targets.LambdaFunction(interestRunnerLambda)]
+      })
+
+      const putProductLambda = new lambdago.GoFunction(this, 'put-product-function', {
+          entry: path.join(__dirname, '../../lambda/functions/put-product'),
+          functionName: 'put-product',
+          initialPolicy: [
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy)
+          ]
+      })
+      putProductLambda.addPermission('resource-policy', {
+          action: 'lambda:InvokeFunctionUrl',
+          principal: new AccountPrincipal('*'),
+          functionUrlAuthType: FunctionUrlAuthType.AWS_IAM
+      })
+      new lambda.FunctionUrl(this, 'put-product-url', {
+          function: putProductLambda,
+          authType: lambda.FunctionUrlAuthType.AWS_IAM
+      })
+
+      const listProductsLambda = new lambdago.GoFunction(this, 'list-products-function', {
+          entry: path.join(__dirname, '../../lambda/functions/list-products'),
+          functionName: 'list-products',
+          initialPolicy: [
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy)
+          ]
+      })
+      listProductsLambda.addPermission('resource-policy', {
+          action: 'lambda:InvokeFunctionUrl',
+          principal: new AccountPrincipal('*'),
+          functionUrlAuthType: FunctionUrlAuthType.AWS_IAM
+      })
+      new lambda.FunctionUrl(this, 'list-products-url', {
+          function: listProductsLambda,
+          authType: lambda.FunctionUrlAuthType.AWS_IAM
+      })
+
+      const quoteTransferLambda = new lambdago.GoFunction(this, 'quote-transfer-function', {
+          entry: path.join(__dirname, '../../lambda/functions/quote-transfer'),
+          functionName: 'quote-transfer',
+          initialPolicy: [
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy)
+          ]
+      })
+      quoteTransferLambda.addPermission('resource-policy', {
+          action: 'lambda:InvokeFunctionUrl',
+          principal: new AccountPrincipal('*'),
+          functionUrlAuthType: FunctionUrlAuthType.AWS_IAM
+      })
+      new lambda.FunctionUrl(this, 'quote-transfer-url', {
+          function: quoteTransferLambda,
+          authType: lambda.FunctionUrlAuthType.AWS_IAM
+      })
+
+      const setAccountStatusLambda = new lambdago.GoFunction(this, 'set-account-status-function', {
+          entry: path.join(__dirname, '../../lambda/functions/set-account-status'),
+          functionName: 'set-account-status',
+          initialPolicy: [
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy)
+          ]
+      })
+      setAccountStatusLambda.addPermission('resource-policy', {
+          action: 'lambda:InvokeFunctionUrl',
+          principal: new AccountPrincipal('*'),
+          functionUrlAuthType: FunctionUrlAuthType.AWS_IAM
+      })
+      new lambda.FunctionUrl(this, 'set-account-status-url', {
+          function: setAccountStatusLambda,
+          authType: lambda.FunctionUrlAuthType.AWS_IAM
+      })
+
+      const closeAccountLambda = new lambdago.GoFunction(this, 'close-account-function', {
+          entry: path.join(__dirname, '../../lambda/functions/close-account'),
+          functionName: 'close-account',
+          initialPolicy: [
+              new iam.PolicyStatement(dynamoDBAccessPolicy),
+              new iam.PolicyStatement(rolesReadPolicy)
+          ]
+      })
+      closeAccountLambda.addPermission('resource-policy', {
+          action: 'lambda:InvokeFunctionUrl',
+          principal: new AccountPrincipal('*'),
+          functionUrlAuthType: FunctionUrlAuthType.AWS_IAM
+      })
+      new lambda.FunctionUrl(this, 'close-account-url', {
+          function: closeAccountLambda,
+          authType: lambda.FunctionUrlAuthType.AWS_IAM
+      })
+
       // TODO: Add CloudTrail to log failed API calls, or use API Gateway which features CloudWatch logging
 
   }
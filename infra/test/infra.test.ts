@@ -29,6 +29,24 @@ test('DynamoDB Table Created', () => {
                AttributeType: "S"
            }
        ],
+       GlobalSecondaryIndexes: [
+           {
+               IndexName: "accounts-by-type-index",
+               KeySchema: [
+                   {
+                       AttributeName: "AccountType",
+                       KeyType: "HASH"
+                   },
+                   {
+                       AttributeName: "AccountId",
+                       KeyType: "RANGE"
+                   }
+               ],
+               Projection: {
+                   ProjectionType: "KEYS_ONLY"
+               }
+           }
+       ],
        BillingMode: "PAY_PER_REQUEST"
    });
 
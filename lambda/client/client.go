@@ -0,0 +1,399 @@
+// Package client is a Go client for the banking API. Requests are signed with AWS Signature Version 4, as the Lambda
+// Function URLs require, and error responses are mapped back into the same error types the service returns by their
+// stable error codes.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Function URLs are signed for the lambda service
+const signingService = "lambda"
+
+type (
+	Problem                      = functions.Problem
+	AccountKey                   = internal.AccountKey
+	AccountStatus                = internal.AccountStatus
+	AccrueInterestInput          = internal.AccrueInterestInput
+	AccrueInterestOutput         = internal.AccrueInterestOutput
+	CaptureHoldInput             = internal.CaptureHoldInput
+	CaptureHoldOutput            = internal.CaptureHoldOutput
+	CancelScheduleInput          = internal.CancelScheduleInput
+	CreateAccountInput           = internal.CreateAccountInput
+	CreateScheduleInput          = internal.CreateScheduleInput
+	CreateScheduleOutput         = internal.CreateScheduleOutput
+	CloseAccountInput            = internal.CloseAccountInput
+	CloseAccountOutput           = internal.CloseAccountOutput
+	DeleteAccountInput           = internal.DeleteAccountInput
+	DepositInput                 = internal.DepositInput
+	DepositOutput                = internal.DepositOutput
+	GetBalanceInput              = internal.GetBalanceInput
+	GetBalanceOutput             = internal.GetBalanceOutput
+	Hold                         = internal.Hold
+	ListAccountsInput            = internal.ListAccountsInput
+	ListAccountsOutput           = internal.ListAccountsOutput
+	ListScheduleExecutionsInput  = internal.ListScheduleExecutionsInput
+	ListScheduleExecutionsOutput = internal.ListScheduleExecutionsOutput
+	ListSchedulesInput           = internal.ListSchedulesInput
+	ListSchedulesOutput          = internal.ListSchedulesOutput
+	ListTransactionsInput        = internal.ListTransactionsInput
+	ListProductsInput            = internal.ListProductsInput
+	ListProductsOutput           = internal.ListProductsOutput
+	ListTransactionsOutput       = internal.ListTransactionsOutput
+	Conversion                   = internal.Conversion
+	Fee                          = internal.Fee
+	FeeCharge                    = internal.FeeCharge
+	FeeRules                     = internal.FeeRules
+	FeeType                      = internal.FeeType
+	InterestTerms                = internal.InterestTerms
+	PlaceHoldInput               = internal.PlaceHoldInput
+	PlaceHoldOutput              = internal.PlaceHoldOutput
+	ProductAccrual               = internal.ProductAccrual
+	Posting                      = internal.Posting
+	Product                      = internal.Product
+	Quote                        = internal.Quote
+	QuoteInput                   = internal.QuoteInput
+	QuoteOutput                  = internal.QuoteOutput
+	QuoteTransferOutput          = internal.QuoteTransferOutput
+	ReconcileInput               = internal.ReconcileInput
+	ReconcileOutput              = internal.ReconcileOutput
+	ReleaseHoldInput             = internal.ReleaseHoldInput
+	RunSchedulesInput            = internal.RunSchedulesInput
+	RunSchedulesOutput           = internal.RunSchedulesOutput
+	Schedule                     = internal.Schedule
+	ScheduleExecution            = internal.ScheduleExecution
+	ScheduleKey                  = internal.ScheduleKey
+	SetAccountStatusInput        = internal.SetAccountStatusInput
+	SetOverdraftLimitInput       = internal.SetOverdraftLimitInput
+	Transaction                  = internal.Transaction
+	TransactionKey               = internal.TransactionKey
+	TransactionParty             = internal.TransactionParty
+	TransferRules                = internal.TransferRules
+	TransferInput                = internal.TransferInput
+	TransferOutput               = internal.TransferOutput
+	WithdrawInput                = internal.WithdrawInput
+	WithdrawOutput               = internal.WithdrawOutput
+
+	AccountAlreadyExistsError       = internal.AccountAlreadyExistsError
+	AccountDoesNotExistError        = internal.AccountDoesNotExistError
+	AccountNotActiveError           = internal.AccountNotActiveError
+	CaptureExceedsHoldError         = internal.CaptureExceedsHoldError
+	ConversionRequiredError         = internal.ConversionRequiredError
+	ConversionTooSmallError         = internal.ConversionTooSmallError
+	CurrencyMismatchError           = internal.CurrencyMismatchError
+	HoldNotFoundError               = internal.HoldNotFoundError
+	HoldsOutstandingError           = internal.HoldsOutstandingError
+	IdempotencyKeyConflictError     = internal.IdempotencyKeyConflictError
+	InsufficientFundsError          = internal.InsufficientFundsError
+	InvalidStatusTransitionError    = internal.InvalidStatusTransitionError
+	MinimumOpeningBalanceError      = internal.MinimumOpeningBalanceError
+	NonZeroBalanceError             = internal.NonZeroBalanceError
+	OverdraftAllowanceExceededError = internal.OverdraftAllowanceExceededError
+	ProductNotFoundError            = internal.ProductNotFoundError
+	ProductRetiredError             = internal.ProductRetiredError
+	QuoteMismatchError              = internal.QuoteMismatchError
+	QuoteNotFoundError              = internal.QuoteNotFoundError
+	RateNotAvailableError           = internal.RateNotAvailableError
+	ScheduleNotFoundError           = internal.ScheduleNotFoundError
+	SelfTransferError               = internal.SelfTransferError
+	SourceAccountDoesNotExistError  = internal.SourceAccountDoesNotExistError
+	TransactionConflictError        = internal.TransactionConflictError
+	TransferNotAllowedError         = internal.TransferNotAllowedError
+	UnexpectedConversionError       = internal.UnexpectedConversionError
+)
+
+// FormatAmount formats an amount of minor units in the major units of its currency, e.g. 1234 USD as "12.34 USD"
+var FormatAmount = internal.FormatAmount
+
+// Endpoints are the URLs of each operation. When deployed, every operation has its own Function URL.
+type Endpoints struct {
+	CreateAccount          string `json:"createAccount"`
+	DeleteAccount          string `json:"deleteAccount"`
+	CloseAccount           string `json:"closeAccount"`
+	GetBalance             string `json:"getBalance"`
+	ListAccounts           string `json:"listAccounts"`
+	ListTransactions       string `json:"listTransactions"`
+	Transfer               string `json:"transfer"`
+	QuoteTransfer          string `json:"quoteTransfer"`
+	Deposit                string `json:"deposit"`
+	Withdraw               string `json:"withdraw"`
+	Reconcile              string `json:"reconcile"`
+	Quote                  string `json:"quote"`
+	SetOverdraftLimit      string `json:"setOverdraftLimit"`
+	SetAccountStatus       string `json:"setAccountStatus"`
+	PlaceHold              string `json:"placeHold"`
+	CaptureHold            string `json:"captureHold"`
+	ReleaseHold            string `json:"releaseHold"`
+	CreateSchedule         string `json:"createSchedule"`
+	ListSchedules          string `json:"listSchedules"`
+	CancelSchedule         string `json:"cancelSchedule"`
+	ListScheduleExecutions string `json:"listScheduleExecutions"`
+	RunSchedules           string `json:"runSchedules"`
+	AccrueInterest         string `json:"accrueInterest"`
+	PutProduct             string `json:"putProduct"`
+	ListProducts           string `json:"listProducts"`
+}
+
+// NewEndpointsFromBaseURL returns the endpoints of a server hosting every operation under one URL, such as the local
+// HTTP server
+func NewEndpointsFromBaseURL(baseURL string) Endpoints {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	return Endpoints{
+		CreateAccount:          baseURL + "/create-account",
+		DeleteAccount:          baseURL + "/delete-account",
+		CloseAccount:           baseURL + "/close-account",
+		GetBalance:             baseURL + "/get-balance",
+		ListAccounts:           baseURL + "/list-accounts",
+		ListTransactions:       baseURL + "/list-transactions",
+		Transfer:               baseURL + "/transfer",
+		QuoteTransfer:          baseURL + "/quote-transfer",
+		Deposit:                baseURL + "/deposit",
+		Withdraw:               baseURL + "/withdraw",
+		Reconcile:              baseURL + "/reconcile",
+		Quote:                  baseURL + "/quote",
+		SetOverdraftLimit:      baseURL + "/set-overdraft-limit",
+		SetAccountStatus:       baseURL + "/set-account-status",
+		PlaceHold:              baseURL + "/place-hold",
+		CaptureHold:            baseURL + "/capture-hold",
+		ReleaseHold:            baseURL + "/release-hold",
+		CreateSchedule:         baseURL + "/create-schedule",
+		ListSchedules:          baseURL + "/list-schedules",
+		CancelSchedule:         baseURL + "/cancel-schedule",
+		ListScheduleExecutions: baseURL + "/list-schedule-executions",
+		RunSchedules:           baseURL + "/run-schedules",
+		AccrueInterest:         baseURL + "/accrue-interest",
+		PutProduct:             baseURL + "/put-product",
+		ListProducts:           baseURL + "/list-products",
+	}
+}
+
+type Options struct {
+	Endpoints   Endpoints
+	Region      string
+	Credentials aws.CredentialsProvider
+	// Defaults to http.DefaultClient
+	HTTPClient *http.Client
+}
+
+type Client struct {
+	options Options
+	signer  *v4.Signer
+}
+
+func New(options Options) *Client {
+	if options.HTTPClient == nil {
+		options.HTTPClient = http.DefaultClient
+	}
+	return &Client{
+		options: options,
+		signer:  v4.NewSigner(),
+	}
+}
+
+func (client *Client) CreateAccount(ctx context.Context, input CreateAccountInput) error {
+	return client.invoke(ctx, client.options.Endpoints.CreateAccount, input, nil)
+}
+
+func (client *Client) DeleteAccount(ctx context.Context, input DeleteAccountInput) error {
+	return client.invoke(ctx, client.options.Endpoints.DeleteAccount, input, nil)
+}
+
+func (client *Client) CloseAccount(ctx context.Context, input CloseAccountInput) (CloseAccountOutput, error) {
+	var output CloseAccountOutput
+	err := client.invoke(ctx, client.options.Endpoints.CloseAccount, input, &output)
+	return output, err
+}
+
+func (client *Client) GetBalance(ctx context.Context, input GetBalanceInput) (GetBalanceOutput, error) {
+	var output GetBalanceOutput
+	err := client.invoke(ctx, client.options.Endpoints.GetBalance, input, &output)
+	return output, err
+}
+
+func (client *Client) ListAccounts(ctx context.Context, input ListAccountsInput) (ListAccountsOutput, error) {
+	var output ListAccountsOutput
+	err := client.invoke(ctx, client.options.Endpoints.ListAccounts, input, &output)
+	return output, err
+}
+
+func (client *Client) ListTransactions(ctx context.Context, input ListTransactionsInput) (ListTransactionsOutput, error) {
+	var output ListTransactionsOutput
+	err := client.invoke(ctx, client.options.Endpoints.ListTransactions, input, &output)
+	return output, err
+}
+
+func (client *Client) Transfer(ctx context.Context, input TransferInput) (TransferOutput, error) {
+	var output TransferOutput
+	err := client.invoke(ctx, client.options.Endpoints.Transfer, input, &output)
+	return output, err
+}
+
+// QuoteTransfer returns the fees that a transfer would be charged and the amounts it would move, without transferring
+func (client *Client) QuoteTransfer(ctx context.Context, input TransferInput) (QuoteTransferOutput, error) {
+	var output QuoteTransferOutput
+	err := client.invoke(ctx, client.options.Endpoints.QuoteTransfer, input, &output)
+	return output, err
+}
+
+// Quote locks the rate of a conversion, which a transfer then refers to by the quote's ID
+func (client *Client) Quote(ctx context.Context, input QuoteInput) (QuoteOutput, error) {
+	var output QuoteOutput
+	err := client.invoke(ctx, client.options.Endpoints.Quote, input, &output)
+	return output, err
+}
+
+// Deposit requires the caller to have the operator or admin role
+func (client *Client) Deposit(ctx context.Context, input DepositInput) (DepositOutput, error) {
+	var output DepositOutput
+	err := client.invoke(ctx, client.options.Endpoints.Deposit, input, &output)
+	return output, err
+}
+
+// Withdraw requires the caller to have the operator or admin role
+func (client *Client) Withdraw(ctx context.Context, input WithdrawInput) (WithdrawOutput, error) {
+	var output WithdrawOutput
+	err := client.invoke(ctx, client.options.Endpoints.Withdraw, input, &output)
+	return output, err
+}
+
+// Reconcile requires the caller to have the auditor or admin role
+func (client *Client) Reconcile(ctx context.Context, input ReconcileInput) (ReconcileOutput, error) {
+	var output ReconcileOutput
+	err := client.invoke(ctx, client.options.Endpoints.Reconcile, input, &output)
+	return output, err
+}
+
+// SetOverdraftLimit requires the caller to have the admin role
+func (client *Client) SetOverdraftLimit(ctx context.Context, input SetOverdraftLimitInput) error {
+	return client.invoke(ctx, client.options.Endpoints.SetOverdraftLimit, input, nil)
+}
+
+// SetAccountStatus requires the caller to have the operator or admin role
+func (client *Client) SetAccountStatus(ctx context.Context, input SetAccountStatusInput) error {
+	return client.invoke(ctx, client.options.Endpoints.SetAccountStatus, input, nil)
+}
+
+// PlaceHold requires the caller to have the operator or admin role
+func (client *Client) PlaceHold(ctx context.Context, input PlaceHoldInput) (PlaceHoldOutput, error) {
+	var output PlaceHoldOutput
+	err := client.invoke(ctx, client.options.Endpoints.PlaceHold, input, &output)
+	return output, err
+}
+
+// CaptureHold requires the caller to have the operator or admin role
+func (client *Client) CaptureHold(ctx context.Context, input CaptureHoldInput) (CaptureHoldOutput, error) {
+	var output CaptureHoldOutput
+	err := client.invoke(ctx, client.options.Endpoints.CaptureHold, input, &output)
+	return output, err
+}
+
+// ReleaseHold requires the caller to have the operator or admin role
+func (client *Client) ReleaseHold(ctx context.Context, input ReleaseHoldInput) error {
+	return client.invoke(ctx, client.options.Endpoints.ReleaseHold, input, nil)
+}
+
+func (client *Client) CreateSchedule(ctx context.Context, input CreateScheduleInput) (CreateScheduleOutput, error) {
+	var output CreateScheduleOutput
+	err := client.invoke(ctx, client.options.Endpoints.CreateSchedule, input, &output)
+	return output, err
+}
+
+func (client *Client) ListSchedules(ctx context.Context, input ListSchedulesInput) (ListSchedulesOutput, error) {
+	var output ListSchedulesOutput
+	err := client.invoke(ctx, client.options.Endpoints.ListSchedules, input, &output)
+	return output, err
+}
+
+func (client *Client) CancelSchedule(ctx context.Context, input CancelScheduleInput) error {
+	return client.invoke(ctx, client.options.Endpoints.CancelSchedule, input, nil)
+}
+
+func (client *Client) ListScheduleExecutions(ctx context.Context, input ListScheduleExecutionsInput) (ListScheduleExecutionsOutput, error) {
+	var output ListScheduleExecutionsOutput
+	err := client.invoke(ctx, client.options.Endpoints.ListScheduleExecutions, input, &output)
+	return output, err
+}
+
+// RunSchedules requires the caller to have the operator or admin role. Schedules are otherwise run every few minutes.
+func (client *Client) RunSchedules(ctx context.Context, input RunSchedulesInput) (RunSchedulesOutput, error) {
+	var output RunSchedulesOutput
+	err := client.invoke(ctx, client.options.Endpoints.RunSchedules, input, &output)
+	return output, err
+}
+
+// AccrueInterest requires the caller to have the operator or admin role. Interest is otherwise accrued daily for the
+// previous day.
+func (client *Client) AccrueInterest(ctx context.Context, input AccrueInterestInput) (AccrueInterestOutput, error) {
+	var output AccrueInterestOutput
+	err := client.invoke(ctx, client.options.Endpoints.AccrueInterest, input, &output)
+	return output, err
+}
+
+// PutProduct requires the caller to have the admin role
+func (client *Client) PutProduct(ctx context.Context, product Product) error {
+	return client.invoke(ctx, client.options.Endpoints.PutProduct, product, nil)
+}
+
+func (client *Client) ListProducts(ctx context.Context, input ListProductsInput) (ListProductsOutput, error) {
+	var output ListProductsOutput
+	err := client.invoke(ctx, client.options.Endpoints.ListProducts, input, &output)
+	return output, err
+}
+
+// invoke signs and sends input as the JSON body of a request to endpoint, unmarshalling a successful response into
+// output when it is non-nil
+func (client *Client) invoke(ctx context.Context, endpoint string, input interface{}, output interface{}) error {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	credentials, err := client.options.Credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("retrieving credentials: %w", err)
+	}
+	payloadHash := sha256.Sum256(body)
+	err = client.signer.SignHTTP(ctx, credentials, request, hex.EncodeToString(payloadHash[:]), signingService, client.options.Region, time.Now())
+	if err != nil {
+		return fmt.Errorf("signing request: %w", err)
+	}
+
+	response, err := client.options.HTTPClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return newResponseError(response.StatusCode, string(responseBody))
+	}
+
+	if output == nil || len(responseBody) == 0 {
+		return nil
+	}
+	return json.Unmarshal(responseBody, output)
+}
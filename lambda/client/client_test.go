@@ -0,0 +1,361 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/handlers"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	testAccountID = "123456789012"
+	testRegion    = "us-west-2"
+)
+
+type ClientSuite struct {
+	suite.Suite
+	server *httptest.Server
+	client *Client
+	// Authorization headers of the requests received by the server
+	authorizations []string
+}
+
+// clientTestAccountTypes are the products in the catalog of the server that the client calls
+var clientTestAccountTypes = []string{"savings", "checking", "euro", "a", "b", "c", "d", "e"}
+
+func TestClientSuite(t *testing.T) {
+	suite.Run(t, new(ClientSuite))
+}
+
+func (s *ClientSuite) SetupTest() {
+	accountManager := internal.NewInMemoryAccountManager(internal.NewStaticRateProvider(map[string]*big.Rat{
+		"USD/EUR": big.NewRat(92, 100),
+	}))
+	for _, accountType := range clientTestAccountTypes {
+		s.Require().NoError(accountManager.PutProduct(context.Background(), internal.Product{AccountType: accountType}))
+	}
+	handlers.SetAccountManager(accountManager)
+	s.authorizations = nil
+
+	routes := map[string]func(context.Context, events.LambdaFunctionURLRequest) (events.LambdaFunctionURLResponse, error){
+		"/create-account": handlers.CreateAccount,
+		"/delete-account": handlers.DeleteAccount,
+		"/get-balance":    handlers.GetBalance,
+		"/list-accounts":  handlers.ListAccounts,
+		"/transfer":       handlers.Transfer,
+		"/deposit":        handlers.Deposit,
+		"/withdraw":       handlers.Withdraw,
+		"/quote":          handlers.Quote,
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.authorizations = append(s.authorizations, r.Header.Get("Authorization"))
+		body, _ := io.ReadAll(r.Body)
+		response, err := routes[r.URL.Path](r.Context(), events.LambdaFunctionURLRequest{
+			RequestContext: events.LambdaFunctionURLRequestContext{
+				Authorizer: &events.LambdaFunctionURLRequestContextAuthorizerDescription{
+					IAM: &events.LambdaFunctionURLRequestContextAuthorizerIAMDescription{AccountID: testAccountID},
+				},
+			},
+			Body: string(body),
+		})
+		s.Require().NoError(err)
+		w.WriteHeader(response.StatusCode)
+		_, _ = w.Write([]byte(response.Body))
+	}))
+
+	s.client = New(Options{
+		Endpoints:   NewEndpointsFromBaseURL(s.server.URL),
+		Region:      testRegion,
+		Credentials: credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "SECRET", ""),
+	})
+}
+
+func (s *ClientSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *ClientSuite) createAccount(accountType string, balance int) {
+	err := s.client.CreateAccount(context.Background(), CreateAccountInput{AccountType: accountType, InitialBalance: &balance})
+	s.Require().NoError(err)
+}
+
+func (s *ClientSuite) TestCreateAccount_SignsRequest() {
+	// === When ===
+	s.createAccount("savings", 10)
+
+	// === Then ===
+	s.Require().Len(s.authorizations, 1)
+	s.True(strings.HasPrefix(s.authorizations[0], "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"))
+	s.Contains(s.authorizations[0], "/"+testRegion+"/lambda/aws4_request")
+}
+
+func (s *ClientSuite) TestCreateAccount_AlreadyExists() {
+	// === Given ===
+	s.createAccount("savings", 10)
+
+	// === When ===
+	balance := 10
+	err := s.client.CreateAccount(context.Background(), CreateAccountInput{AccountType: "savings", InitialBalance: &balance})
+
+	// === Then ===
+	s.Equal(AccountAlreadyExistsError{AccountID: testAccountID, AccountType: "savings"}, err)
+}
+
+func (s *ClientSuite) TestCreateAccount_ProductNotFound() {
+	// === Given ===
+	balance := 10
+
+	// === When ===
+	err := s.client.CreateAccount(context.Background(), CreateAccountInput{AccountType: "svngs", InitialBalance: &balance})
+
+	// === Then ===
+	s.Equal(ProductNotFoundError{AccountType: "svngs"}, err)
+}
+
+func (s *ClientSuite) TestDeleteAccount_NonZeroBalance() {
+	// === Given ===
+	s.createAccount("savings", 10)
+
+	// === When ===
+	err := s.client.DeleteAccount(context.Background(), DeleteAccountInput{AccountType: "savings"})
+
+	// === Then ===
+	s.Equal(NonZeroBalanceError{AccountID: testAccountID, AccountType: "savings"}, err)
+}
+
+func (s *ClientSuite) TestGetBalance() {
+	// === Given ===
+	s.createAccount("savings", 10)
+
+	// === When ===
+	output, err := s.client.GetBalance(context.Background(), GetBalanceInput{AccountType: "savings"})
+
+	// === Then ===
+	s.NoError(err)
+	s.Equal(GetBalanceOutput{Balance: 10, Currency: "USD", Available: 10, Status: "ACTIVE"}, output)
+}
+
+func (s *ClientSuite) TestGetBalance_AccountDoesNotExist() {
+	// === When ===
+	_, err := s.client.GetBalance(context.Background(), GetBalanceInput{AccountType: "savings"})
+
+	// === Then ===
+	s.Equal(AccountDoesNotExistError{AccountID: testAccountID, AccountType: "savings"}, err)
+}
+
+func (s *ClientSuite) TestTransfer() {
+	// === Given ===
+	s.createAccount("savings", 10)
+	s.createAccount("checking", 0)
+	amount := 4
+
+	// === When ===
+	output, err := s.client.Transfer(context.Background(), TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   testAccountID,
+		DestAccountType: "checking",
+		Amount:          &amount,
+	})
+
+	// === Then ===
+	s.NoError(err)
+	s.Equal(4, output.Transaction.Amount)
+	s.Equal(6, output.Transaction.Src.Balance)
+	s.Equal(4, output.Transaction.Dest.Balance)
+}
+
+func (s *ClientSuite) TestTransfer_InsufficientFunds() {
+	// === Given ===
+	s.createAccount("savings", 10)
+	s.createAccount("checking", 0)
+	amount := 11
+
+	// === When ===
+	_, err := s.client.Transfer(context.Background(), TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   testAccountID,
+		DestAccountType: "checking",
+		Amount:          &amount,
+	})
+
+	// === Then ===
+	var insufficientFundsErr InsufficientFundsError
+	s.True(errors.As(err, &insufficientFundsErr))
+	s.Equal("savings", insufficientFundsErr.AccountType)
+}
+
+func (s *ClientSuite) TestTransfer_SrcAccountDoesNotExist() {
+	// === Given ===
+	s.createAccount("checking", 0)
+	amount := 1
+
+	// === When ===
+	_, err := s.client.Transfer(context.Background(), TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   testAccountID,
+		DestAccountType: "checking",
+		Amount:          &amount,
+	})
+
+	// === Then ===
+	s.Equal(SourceAccountDoesNotExistError{AccountID: testAccountID, AccountType: "savings"}, err)
+}
+
+func (s *ClientSuite) TestTransfer_ConversionRequired() {
+	// === Given ===
+	s.createAccount("savings", 10)
+	amount := 1
+	err := s.client.CreateAccount(context.Background(), CreateAccountInput{AccountType: "euro", InitialBalance: &amount, Currency: "EUR"})
+	s.Require().NoError(err)
+
+	// === When ===
+	_, err = s.client.Transfer(context.Background(), TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   testAccountID,
+		DestAccountType: "euro",
+		Amount:          &amount,
+	})
+
+	// === Then ===
+	s.Equal(ConversionRequiredError{SrcCurrency: "USD", DestCurrency: "EUR"}, err)
+}
+
+func (s *ClientSuite) TestTransfer_AtQuotedRate() {
+	// === Given ===
+	s.createAccount("savings", 1000)
+	zero := 0
+	err := s.client.CreateAccount(context.Background(), CreateAccountInput{AccountType: "euro", InitialBalance: &zero, Currency: "EUR"})
+	s.Require().NoError(err)
+	amount := 500
+	quote, err := s.client.Quote(context.Background(), QuoteInput{SrcCurrency: "USD", DestCurrency: "EUR", Amount: &amount})
+	s.Require().NoError(err)
+
+	// === When ===
+	output, err := s.client.Transfer(context.Background(), TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   testAccountID,
+		DestAccountType: "euro",
+		Amount:          &amount,
+		QuoteID:         quote.Quote.QuoteID,
+	})
+	_, reuseErr := s.client.Transfer(context.Background(), TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   testAccountID,
+		DestAccountType: "euro",
+		Amount:          &amount,
+		QuoteID:         quote.Quote.QuoteID,
+	})
+
+	// === Then ===
+	s.Require().NoError(err)
+	s.Equal(460, output.Transaction.DestAmount)
+	s.Equal(&Conversion{QuoteID: quote.Quote.QuoteID, Rate: "0.92", Rounding: "0"}, output.Transaction.Conversion)
+	s.Equal(QuoteNotFoundError{QuoteID: quote.Quote.QuoteID}, reuseErr)
+}
+
+func (s *ClientSuite) TestTransfer_ValidationError() {
+	// === When ===
+	_, err := s.client.Transfer(context.Background(), TransferInput{SrcAccountType: "savings"})
+
+	// === Then ===
+	var apiErr *APIError
+	s.Require().True(errors.As(err, &apiErr))
+	s.Equal(400, apiErr.StatusCode)
+	s.Equal("VALIDATION_FAILED", apiErr.Problem.Code)
+	s.Equal([]functions.InvalidParam{
+		{Name: "destAccountID", Reason: "destAccountID is a required field"},
+		{Name: "destAccountType", Reason: "destAccountType is a required field"},
+		{Name: "amount", Reason: "amount must be greater than 0"},
+	}, apiErr.Problem.InvalidParams)
+}
+
+func (s *ClientSuite) TestDeposit() {
+	// === Given ===
+	handlers.SetRoleStore(internal.NewStaticRoleStore(map[string][]internal.Role{
+		testAccountID: {internal.RoleOperator},
+	}))
+	s.T().Cleanup(func() {
+		handlers.SetRoleStore(internal.NewStaticRoleStore(nil))
+	})
+	s.createAccount("checking", 1)
+	amount := 4
+
+	// === When ===
+	output, err := s.client.Deposit(context.Background(), DepositInput{
+		AccountID:         testAccountID,
+		AccountType:       "checking",
+		Amount:            &amount,
+		ExternalReference: "wire-0001",
+	})
+
+	// === Then ===
+	s.NoError(err)
+	s.Equal(5, output.Transaction.Dest.Balance)
+	s.Equal("wire-0001", output.Transaction.ExternalReference)
+}
+
+func (s *ClientSuite) TestWithdraw_Forbidden() {
+	// === Given ===
+	s.createAccount("checking", 10)
+	amount := 4
+
+	// === When ===
+	_, err := s.client.Withdraw(context.Background(), WithdrawInput{
+		AccountID:         testAccountID,
+		AccountType:       "checking",
+		Amount:            &amount,
+		ExternalReference: "ach-0001",
+	})
+
+	// === Then ===
+	var apiErr *APIError
+	s.Require().True(errors.As(err, &apiErr))
+	s.Equal(403, apiErr.StatusCode)
+	s.Equal("FORBIDDEN", apiErr.Problem.Code)
+}
+
+func (s *ClientSuite) TestAccounts_Paginates() {
+	// === Given ===
+	accountTypes := []string{"a", "b", "c", "d", "e"}
+	for _, accountType := range accountTypes {
+		s.createAccount(accountType, 0)
+	}
+	s.authorizations = nil
+	limit := int32(2)
+
+	// === When ===
+	var listed []string
+	accounts := s.client.Accounts(ListAccountsInput{Limit: &limit})
+	for accounts.Next(context.Background()) {
+		listed = append(listed, accounts.Account().AccountType)
+	}
+
+	// === Then ===
+	s.NoError(accounts.Err())
+	s.Equal(accountTypes, listed)
+	// Pages of a and b, c and d, then e
+	s.Len(s.authorizations, 3)
+}
+
+func (s *ClientSuite) TestAccounts_Error() {
+	// === Given ===
+	limit := int32(0)
+
+	// === When ===
+	accounts := s.client.Accounts(ListAccountsInput{Limit: &limit})
+	hasNext := accounts.Next(context.Background())
+
+	// === Then ===
+	s.False(hasNext)
+	s.Error(accounts.Err())
+}
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+)
+
+// APIError is returned for error responses which don't correspond to one of the service's error types, such as
+// validation failures and internal errors
+type APIError struct {
+	StatusCode int
+	// Problem is the decoded body of the response, which is zero if the body was not a problem document, e.g. for
+	// requests rejected by the Function URL itself
+	Problem Problem
+	Body    string
+}
+
+func (err *APIError) Error() string {
+	if err.Problem.Code != "" {
+		return fmt.Sprintf("banking API responded with %d %s (request ID %s): %s",
+			err.StatusCode, err.Problem.Code, err.Problem.RequestID, err.Problem.Detail)
+	}
+	return fmt.Sprintf("banking API responded with %d: %s", err.StatusCode, err.Body)
+}
+
+// problemErrors decode the details of a problem into the error type of its code
+var problemErrors = map[string]func(details json.RawMessage) (error, error){
+	internal.CodeAccountAlreadyExists:       decodeDetails[AccountAlreadyExistsError],
+	internal.CodeNonZeroBalance:             decodeDetails[NonZeroBalanceError],
+	internal.CodeInsufficientFunds:          decodeDetails[InsufficientFundsError],
+	internal.CodeAccountNotFound:            decodeDetails[AccountDoesNotExistError],
+	internal.CodeSourceAccountNotFound:      decodeDetails[SourceAccountDoesNotExistError],
+	internal.CodeSelfTransfer:               decodeDetails[SelfTransferError],
+	internal.CodeTransactionConflict:        decodeDetails[TransactionConflictError],
+	internal.CodeIdempotencyKeyConflict:     decodeDetails[IdempotencyKeyConflictError],
+	internal.CodeCurrencyMismatch:           decodeDetails[CurrencyMismatchError],
+	internal.CodeConversionRequired:         decodeDetails[ConversionRequiredError],
+	internal.CodeUnexpectedConversion:       decodeDetails[UnexpectedConversionError],
+	internal.CodeRateNotAvailable:           decodeDetails[RateNotAvailableError],
+	internal.CodeConversionTooSmall:         decodeDetails[ConversionTooSmallError],
+	internal.CodeQuoteNotFound:              decodeDetails[QuoteNotFoundError],
+	internal.CodeQuoteMismatch:              decodeDetails[QuoteMismatchError],
+	internal.CodeHoldNotFound:               decodeDetails[HoldNotFoundError],
+	internal.CodeCaptureExceedsHold:         decodeDetails[CaptureExceedsHoldError],
+	internal.CodeScheduleNotFound:           decodeDetails[ScheduleNotFoundError],
+	internal.CodeProductNotFound:            decodeDetails[ProductNotFoundError],
+	internal.CodeProductRetired:             decodeDetails[ProductRetiredError],
+	internal.CodeMinimumOpeningBalance:      decodeDetails[MinimumOpeningBalanceError],
+	internal.CodeOverdraftAllowanceExceeded: decodeDetails[OverdraftAllowanceExceededError],
+	internal.CodeTransferNotAllowed:         decodeDetails[TransferNotAllowedError],
+	internal.CodeAccountNotActive:           decodeDetails[AccountNotActiveError],
+	internal.CodeInvalidStatusTransition:    decodeDetails[InvalidStatusTransitionError],
+	internal.CodeHoldsOutstanding:           decodeDetails[HoldsOutstandingError],
+}
+
+func decodeDetails[E error](details json.RawMessage) (error, error) {
+	var target E
+	err := json.Unmarshal(details, &target)
+	if err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// newResponseError maps an error response back into the error type that the service returned it for
+func newResponseError(statusCode int, body string) error {
+	var problem Problem
+	if json.Unmarshal([]byte(body), &problem) != nil {
+		return &APIError{StatusCode: statusCode, Body: body}
+	}
+
+	if decode, ok := problemErrors[problem.Code]; ok && len(problem.Details) > 0 {
+		err, decodeErr := decode(problem.Details)
+		if decodeErr == nil {
+			return err
+		}
+	}
+	return &APIError{StatusCode: statusCode, Problem: problem, Body: body}
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewResponseError_TransactionConflict(t *testing.T) {
+	// === When ===
+	err := newResponseError(409, `{
+		"type": "about:blank",
+		"title": "Conflict",
+		"status": 409,
+		"code": "TRANSACTION_CONFLICT",
+		"details": {
+			"src": {"accountID": "123456789012", "accountType": "savings"},
+			"dest": {"accountID": "210987654321", "accountType": "checking"}
+		}
+	}`)
+
+	// === Then ===
+	assert.Equal(t, TransactionConflictError{
+		Src:  AccountKey{AccountID: "123456789012", AccountType: "savings"},
+		Dest: AccountKey{AccountID: "210987654321", AccountType: "checking"},
+	}, err)
+}
+
+func TestNewResponseError_InternalError(t *testing.T) {
+	// === Given ===
+	body := `{"type":"about:blank","title":"Internal Server Error","status":500,"code":"INTERNAL_ERROR","requestId":"abc"}`
+
+	// === When ===
+	err := newResponseError(500, body)
+
+	// === Then ===
+	assert.Equal(t, &APIError{
+		StatusCode: 500,
+		Problem: Problem{
+			Type:      "about:blank",
+			Title:     "Internal Server Error",
+			Status:    500,
+			Code:      "INTERNAL_ERROR",
+			RequestID: "abc",
+		},
+		Body: body,
+	}, err)
+}
+
+func TestNewResponseError_NotAProblem(t *testing.T) {
+	// === When ===
+	err := newResponseError(403, `{"Message":"Forbidden"}`)
+
+	// === Then ===
+	assert.Equal(t, &APIError{StatusCode: 403, Body: `{"Message":"Forbidden"}`}, err)
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+)
+
+// AccountIterator iterates over the caller's accounts, requesting further pages as they are needed:
+//
+//	accounts := c.Accounts(ListAccountsInput{})
+//	for accounts.Next(ctx) {
+//		fmt.Println(accounts.Account())
+//	}
+//	if err := accounts.Err(); err != nil {
+//		...
+//	}
+type AccountIterator struct {
+	list    func(ctx context.Context, input ListAccountsInput) (ListAccountsOutput, error)
+	input   ListAccountsInput
+	page    []AccountKey
+	current AccountKey
+	done    bool
+	err     error
+}
+
+// Accounts returns an iterator over the caller's accounts starting from input.ExclusiveStartKey, with input.Limit
+// as the page size
+func (client *Client) Accounts(input ListAccountsInput) *AccountIterator {
+	return &AccountIterator{list: client.ListAccounts, input: input}
+}
+
+// Next advances to the next account, returning false once there are no more accounts or an error occurs
+func (iterator *AccountIterator) Next(ctx context.Context) bool {
+	for len(iterator.page) == 0 {
+		if iterator.done || iterator.err != nil {
+			return false
+		}
+
+		output, err := iterator.list(ctx, iterator.input)
+		if err != nil {
+			iterator.err = err
+			return false
+		}
+
+		iterator.page = output.Accounts
+		if output.LastEvaluatedKey == (AccountKey{}) {
+			iterator.done = true
+		} else {
+			lastEvaluatedKey := output.LastEvaluatedKey
+			iterator.input.ExclusiveStartKey = &lastEvaluatedKey
+		}
+	}
+
+	iterator.current = iterator.page[0]
+	iterator.page = iterator.page[1:]
+	return true
+}
+
+// Account returns the account that the last call to Next advanced to
+func (iterator *AccountIterator) Account() AccountKey {
+	return iterator.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (iterator *AccountIterator) Err() error {
+	return iterator.err
+}
@@ -0,0 +1,499 @@
+// Command bankctl is a command line client for the banking API. Requests are signed with the credentials of the default
+// AWS configuration.
+//
+// Usage:
+//
+//	bankctl [-endpoint URL | -endpoints FILE] [-region REGION] <command> [flags]
+//
+// Commands:
+//
+//	create-account    -type TYPE -balance N [-overdraft-limit N]
+//	delete-account    -type TYPE
+//	close-account     -type TYPE -dest-id ID -dest-type TYPE
+//	get-balance       -type TYPE
+//	list-accounts     [-limit N]
+//	list-transactions [-limit N]
+//	quote             -src-currency CODE -dest-currency CODE -amount N
+//	transfer          -src-type TYPE -dest-id ID -dest-type TYPE -amount N [-quote-id ID] [-idempotency-key KEY]
+//	quote-transfer    -src-type TYPE -dest-id ID -dest-type TYPE -amount N [-quote-id ID]
+//	deposit           -id ID -type TYPE -amount N -reference REF
+//	withdraw          -id ID -type TYPE -amount N -reference REF
+//	reconcile         -id ID -type TYPE
+//	set-overdraft-limit -id ID -type TYPE -limit N
+//	set-account-status -id ID -type TYPE -status ACTIVE|FROZEN|DORMANT|CLOSED
+//	place-hold        -id ID -type TYPE -dest-id ID -dest-type TYPE -amount N -reference REF [-validity SECONDS]
+//	capture-hold      -id ID -type TYPE -hold-id ID [-amount N]
+//	release-hold      -id ID -type TYPE -hold-id ID
+//	create-schedule   -src-type TYPE -dest-id ID -dest-type TYPE -amount N [-start TIME] [-recurrence RRULE]
+//	list-schedules    [-limit N]
+//	cancel-schedule   -schedule-id ID
+//	list-schedule-executions -schedule-id ID [-limit N]
+//	run-schedules     [-limit N]
+//	accrue-interest   [-date YYYY-MM-DD]
+//	put-product       -type TYPE [-description TEXT] [-min-opening-balance N] [-overdraft-allowance N] [-max-transfer N] [-own-accounts-only] [-dest-types TYPE,...] [-fees JSON] [-interest JSON] [-retired]
+//	list-products     [-include-retired]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/jakepatzer/banking-service/lambda/client"
+	"os"
+	"strings"
+	"time"
+)
+
+func main() {
+	endpoint := flag.String("endpoint", "", "base URL of a server hosting every operation, e.g. http://localhost:8080")
+	endpointsPath := flag.String("endpoints", "", "JSON file of the Function URL of each operation, e.g. {\"createAccount\": \"https://...\"}")
+	region := flag.String("region", "us-west-2", "region that requests are signed for")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	err := run(context.Background(), *endpoint, *endpointsPath, *region, flag.Arg(0), flag.Args()[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(flag.CommandLine.Output(), "Usage: bankctl [flags] <create-account|delete-account|close-account|get-balance|list-accounts|list-transactions|quote|transfer|quote-transfer|deposit|withdraw|reconcile|set-overdraft-limit|set-account-status|place-hold|capture-hold|release-hold|create-schedule|list-schedules|cancel-schedule|list-schedule-executions|run-schedules|accrue-interest|put-product|list-products> [command flags]")
+	flag.PrintDefaults()
+}
+
+func run(ctx context.Context, endpoint, endpointsPath, region, command string, args []string) error {
+	endpoints, err := loadEndpoints(endpoint, endpointsPath)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	c := client.New(client.Options{
+		Endpoints:   endpoints,
+		Region:      region,
+		Credentials: cfg.Credentials,
+	})
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	switch command {
+	case "create-account":
+		accountType := flags.String("type", "", "account type")
+		balance := flags.Int("balance", 0, "initial balance in minor units of the currency, e.g. cents")
+		currency := flags.String("currency", "", "ISO 4217 code of the currency the account holds. When empty, USD is used")
+		overdraftLimit := flags.Int("overdraft-limit", 0, "how far below zero the balance may be debited, which requires the admin role")
+		_ = flags.Parse(args)
+		return c.CreateAccount(ctx, client.CreateAccountInput{
+			AccountType:    *accountType,
+			InitialBalance: balance,
+			Currency:       *currency,
+			OverdraftLimit: *overdraftLimit,
+		})
+	case "delete-account":
+		accountType := flags.String("type", "", "account type")
+		_ = flags.Parse(args)
+		return c.DeleteAccount(ctx, client.DeleteAccountInput{AccountType: *accountType})
+	case "close-account":
+		accountType := flags.String("type", "", "account type")
+		destAccountID := flags.String("dest-id", "", "ID of the account that the balance is swept to")
+		destAccountType := flags.String("dest-type", "", "type of the account that the balance is swept to")
+		_ = flags.Parse(args)
+		output, err := c.CloseAccount(ctx, client.CloseAccountInput{
+			AccountType:     *accountType,
+			DestAccountID:   *destAccountID,
+			DestAccountType: *destAccountType,
+		})
+		if err != nil {
+			return err
+		}
+		return printJSON(output)
+	case "get-balance":
+		accountType := flags.String("type", "", "account type")
+		_ = flags.Parse(args)
+		output, err := c.GetBalance(ctx, client.GetBalanceInput{AccountType: *accountType})
+		if err != nil {
+			return err
+		}
+		return printJSON(output)
+	case "list-accounts":
+		limit := flags.Int("limit", 0, "page size of each request. When 0, the service default is used")
+		_ = flags.Parse(args)
+		var input client.ListAccountsInput
+		if *limit > 0 {
+			pageSize := int32(*limit)
+			input.Limit = &pageSize
+		}
+		accounts := c.Accounts(input)
+		for accounts.Next(ctx) {
+			err := printJSON(accounts.Account())
+			if err != nil {
+				return err
+			}
+		}
+		return accounts.Err()
+	case "list-transactions":
+		limit := flags.Int("limit", 0, "maximum number of transactions. When 0, the service default is used")
+		_ = flags.Parse(args)
+		var input client.ListTransactionsInput
+		if *limit > 0 {
+			pageSize := int32(*limit)
+			input.Limit = &pageSize
+		}
+		output, err := c.ListTransactions(ctx, input)
+		if err != nil {
+			return err
+		}
+		return printJSON(output)
+	case "quote":
+		srcCurrency := flags.String("src-currency", "", "currency to convert from")
+		destCurrency := flags.String("dest-currency", "", "currency to convert to")
+		amount := flags.Int("amount", 0, "amount to convert in minor units of the source currency")
+		_ = flags.Parse(args)
+		output, err := c.Quote(ctx, client.QuoteInput{
+			SrcCurrency:  *srcCurrency,
+			DestCurrency: *destCurrency,
+			Amount:       amount,
+		})
+		if err != nil {
+			return err
+		}
+		return printJSON(output)
+	case "transfer":
+		srcAccountType := flags.String("src-type", "", "type of the source account")
+		destAccountID := flags.String("dest-id", "", "ID of the destination account")
+		destAccountType := flags.String("dest-type", "", "type of the destination account")
+		amount := flags.Int("amount", 0, "amount to transfer in minor units of the source account's currency")
+		currency := flags.String("currency", "", "currency of the amount, which must be that of the source account")
+		destAmount := flags.Int("dest-amount", 0, "amount to credit in the destination account's currency, when converting")
+		quoteID := flags.String("quote-id", "", "ID of a quote to convert between currencies at the rate of")
+		idempotencyKey := flags.String("idempotency-key", "", "retrying with the same key returns the original transfer")
+		_ = flags.Parse(args)
+		input := client.TransferInput{
+			SrcAccountType:  *srcAccountType,
+			DestAccountID:   *destAccountID,
+			DestAccountType: *destAccountType,
+			Amount:          amount,
+			Currency:        *currency,
+			QuoteID:         *quoteID,
+			IdempotencyKey:  *idempotencyKey,
+		}
+		if *destAmount > 0 {
+			input.DestAmount = destAmount
+		}
+		output, err := c.Transfer(ctx, input)
+		if err != nil {
+			return err
+		}
+		return printJSON(output)
+	case "quote-transfer":
+		srcAccountType := flags.String("src-type", "", "type of the source account")
+		destAccountID := flags.String("dest-id", "", "ID of the destination account")
+		destAccountType := flags.String("dest-type", "", "type of the destination account")
+		amount := flags.Int("amount", 0, "amount to transfer in minor units of the source account's currency")
+		quoteID := flags.String("quote-id", "", "ID of a quote to convert between currencies at the rate of, which is not used up")
+		_ = flags.Parse(args)
+		output, err := c.QuoteTransfer(ctx, client.TransferInput{
+			SrcAccountType:  *srcAccountType,
+			DestAccountID:   *destAccountID,
+			DestAccountType: *destAccountType,
+			Amount:          amount,
+			QuoteID:         *quoteID,
+		})
+		if err != nil {
+			return err
+		}
+		return printJSON(output)
+	case "deposit":
+		accountID := flags.String("id", "", "ID of the account")
+		accountType := flags.String("type", "", "type of the account")
+		amount := flags.Int("amount", 0, "amount to deposit in minor units of the account's currency")
+		currency := flags.String("currency", "", "currency of the amount, which must be that of the account")
+		reference := flags.String("reference", "", "external reference, e.g. the trace ID of the wire")
+		_ = flags.Parse(args)
+		output, err := c.Deposit(ctx, client.DepositInput{
+			AccountID:         *accountID,
+			AccountType:       *accountType,
+			Amount:            amount,
+			Currency:          *currency,
+			ExternalReference: *reference,
+		})
+		if err != nil {
+			return err
+		}
+		return printJSON(output)
+	case "withdraw":
+		accountID := flags.String("id", "", "ID of the account")
+		accountType := flags.String("type", "", "type of the account")
+		amount := flags.Int("amount", 0, "amount to withdraw in minor units of the account's currency")
+		currency := flags.String("currency", "", "currency of the amount, which must be that of the account")
+		reference := flags.String("reference", "", "external reference, e.g. the trace ID of the ACH transfer")
+		_ = flags.Parse(args)
+		output, err := c.Withdraw(ctx, client.WithdrawInput{
+			AccountID:         *accountID,
+			AccountType:       *accountType,
+			Amount:            amount,
+			Currency:          *currency,
+			ExternalReference: *reference,
+		})
+		if err != nil {
+			return err
+		}
+		return printJSON(output)
+	case "reconcile":
+		accountID := flags.String("id", "", "ID of the account, which is \"system\" for system accounts")
+		accountType := flags.String("type", "", "type of the account")
+		currency := flags.String("currency", "", "currency to reconcile a system account in. When empty, USD is used")
+		date := flags.String("date", "", "day to reconcile the postings of a system account on, as YYYY-MM-DD. When empty, today in UTC is used")
+		_ = flags.Parse(args)
+		output, err := c.Reconcile(ctx, client.ReconcileInput{
+			AccountID:   *accountID,
+			AccountType: *accountType,
+			Currency:    *currency,
+			Date:        *date,
+		})
+		if err != nil {
+			return err
+		}
+		return printJSON(output)
+	case "set-overdraft-limit":
+		accountID := flags.String("id", "", "ID of the account")
+		accountType := flags.String("type", "", "type of the account")
+		limit := flags.Int("limit", 0, "how far below zero the balance may be debited, in minor units of the account's currency")
+		_ = flags.Parse(args)
+		return c.SetOverdraftLimit(ctx, client.SetOverdraftLimitInput{
+			AccountID:      *accountID,
+			AccountType:    *accountType,
+			OverdraftLimit: limit,
+		})
+	case "set-account-status":
+		accountID := flags.String("id", "", "ID of the account")
+		accountType := flags.String("type", "", "type of the account")
+		status := flags.String("status", "", "status to move the account to: ACTIVE, FROZEN, DORMANT or CLOSED")
+		_ = flags.Parse(args)
+		return c.SetAccountStatus(ctx, client.SetAccountStatusInput{
+			AccountID:   *accountID,
+			AccountType: *accountType,
+			Status:      client.AccountStatus(*status),
+		})
+	case "place-hold":
+		accountID := flags.String("id", "", "ID of the account")
+		accountType := flags.String("type", "", "type of the account")
+		destAccountID := flags.String("dest-id", "", "ID of the account that captures are paid to")
+		destAccountType := flags.String("dest-type", "", "type of the account that captures are paid to")
+		amount := flags.Int("amount", 0, "amount to hold in minor units of the account's currency")
+		currency := flags.String("currency", "", "currency of the amount, which must be that of the account")
+		reference := flags.String("reference", "", "external reference, e.g. the ID of the card authorization")
+		validity := flags.Int("validity", 0, "seconds until the hold expires. When 0, the hold lasts 7 days")
+		_ = flags.Parse(args)
+		output, err := c.PlaceHold(ctx, client.PlaceHoldInput{
+			AccountID:         *accountID,
+			AccountType:       *accountType,
+			DestAccountID:     *destAccountID,
+			DestAccountType:   *destAccountType,
+			Amount:            amount,
+			Currency:          *currency,
+			ExternalReference: *reference,
+			ValiditySeconds:   *validity,
+		})
+		if err != nil {
+			return err
+		}
+		return printJSON(output)
+	case "capture-hold":
+		accountID := flags.String("id", "", "ID of the account")
+		accountType := flags.String("type", "", "type of the account")
+		holdID := flags.String("hold-id", "", "ID of the hold")
+		amount := flags.Int("amount", 0, "amount to capture. When 0, everything still held is captured")
+		_ = flags.Parse(args)
+		input := client.CaptureHoldInput{
+			AccountID:   *accountID,
+			AccountType: *accountType,
+			HoldID:      *holdID,
+		}
+		if *amount > 0 {
+			input.Amount = amount
+		}
+		output, err := c.CaptureHold(ctx, input)
+		if err != nil {
+			return err
+		}
+		return printJSON(output)
+	case "release-hold":
+		accountID := flags.String("id", "", "ID of the account")
+		accountType := flags.String("type", "", "type of the account")
+		holdID := flags.String("hold-id", "", "ID of the hold")
+		_ = flags.Parse(args)
+		return c.ReleaseHold(ctx, client.ReleaseHoldInput{
+			AccountID:   *accountID,
+			AccountType: *accountType,
+			HoldID:      *holdID,
+		})
+	case "create-schedule":
+		srcAccountType := flags.String("src-type", "", "type of the account to transfer from")
+		destAccountID := flags.String("dest-id", "", "ID of the account to transfer to")
+		destAccountType := flags.String("dest-type", "", "type of the account to transfer to")
+		amount := flags.Int("amount", 0, "amount of each transfer in minor units of the currency, e.g. cents")
+		currency := flags.String("currency", "", "currency of the amount, which must be that of the source account")
+		start := flags.String("start", "", "RFC 3339 time of the first transfer. When empty, it is made by the next run")
+		recurrence := flags.String("recurrence", "", "RRULE of the transfers after the first, e.g. FREQ=MONTHLY;BYMONTHDAY=1. When empty, only one transfer is made")
+		_ = flags.Parse(args)
+		input := client.CreateScheduleInput{
+			SrcAccountType:  *srcAccountType,
+			DestAccountID:   *destAccountID,
+			DestAccountType: *destAccountType,
+			Amount:          amount,
+			Currency:        *currency,
+			Recurrence:      *recurrence,
+		}
+		if *start != "" {
+			startAt, err := time.Parse(time.RFC3339, *start)
+			if err != nil {
+				return fmt.Errorf("parsing -start: %w", err)
+			}
+			input.StartAt = &startAt
+		}
+		output, err := c.CreateSchedule(ctx, input)
+		if err != nil {
+			return err
+		}
+		return printJSON(output)
+	case "list-schedules":
+		limit := flags.Int("limit", 0, "maximum number of schedules. When 0, the service default is used")
+		_ = flags.Parse(args)
+		var input client.ListSchedulesInput
+		if *limit > 0 {
+			pageSize := int32(*limit)
+			input.Limit = &pageSize
+		}
+		output, err := c.ListSchedules(ctx, input)
+		if err != nil {
+			return err
+		}
+		return printJSON(output)
+	case "cancel-schedule":
+		scheduleID := flags.String("schedule-id", "", "ID of the schedule")
+		_ = flags.Parse(args)
+		return c.CancelSchedule(ctx, client.CancelScheduleInput{ScheduleID: *scheduleID})
+	case "list-schedule-executions":
+		scheduleID := flags.String("schedule-id", "", "ID of the schedule")
+		limit := flags.Int("limit", 0, "maximum number of executions. When 0, the service default is used")
+		_ = flags.Parse(args)
+		input := client.ListScheduleExecutionsInput{ScheduleID: *scheduleID}
+		if *limit > 0 {
+			pageSize := int32(*limit)
+			input.Limit = &pageSize
+		}
+		output, err := c.ListScheduleExecutions(ctx, input)
+		if err != nil {
+			return err
+		}
+		return printJSON(output)
+	case "run-schedules":
+		limit := flags.Int("limit", 0, "maximum number of due schedules to run. When 0, up to 100 are run")
+		_ = flags.Parse(args)
+		var input client.RunSchedulesInput
+		if *limit > 0 {
+			runLimit := int32(*limit)
+			input.Limit = &runLimit
+		}
+		output, err := c.RunSchedules(ctx, input)
+		if err != nil {
+			return err
+		}
+		return printJSON(output)
+	case "accrue-interest":
+		date := flags.String("date", "", "day to accrue interest for. When empty, the previous day in UTC")
+		_ = flags.Parse(args)
+		output, err := c.AccrueInterest(ctx, client.AccrueInterestInput{Date: *date})
+		if err != nil {
+			return err
+		}
+		return printJSON(output)
+	case "put-product":
+		accountType := flags.String("type", "", "account type that the product defines")
+		description := flags.String("description", "", "description of the product")
+		minimumOpeningBalance := flags.Int("min-opening-balance", 0, "smallest initial balance of new accounts")
+		overdraftAllowance := flags.Int("overdraft-allowance", 0, "largest overdraft limit that accounts may be given")
+		maxTransfer := flags.Int("max-transfer", 0, "largest amount of a single transfer. When 0, transfers are unlimited")
+		ownAccountsOnly := flags.Bool("own-accounts-only", false, "only allow transfers to accounts of the same owner")
+		destTypes := flags.String("dest-types", "", "comma separated account types that transfers may be made to. When empty, any account type")
+		fees := flags.String("fees", "", `JSON of the fees charged on transfers, e.g. {"crossOwner": {"flat": 25}, "fx": {"basisPoints": 100}}`)
+		interest := flags.String("interest", "", `JSON of the interest paid on accounts, e.g. {"apy": "0.0425", "dayCount": "ACT/365", "compounding": "MONTHLY"}`)
+		retired := flags.Bool("retired", false, "stop new accounts of the product from being created")
+		_ = flags.Parse(args)
+		product := client.Product{
+			AccountType:           *accountType,
+			Description:           *description,
+			MinimumOpeningBalance: *minimumOpeningBalance,
+			OverdraftAllowance:    *overdraftAllowance,
+			TransferRules: client.TransferRules{
+				MaxAmount:       *maxTransfer,
+				OwnAccountsOnly: *ownAccountsOnly,
+			},
+			Retired: *retired,
+		}
+		if *destTypes != "" {
+			product.TransferRules.DestAccountTypes = strings.Split(*destTypes, ",")
+		}
+		if *fees != "" {
+			if err := json.Unmarshal([]byte(*fees), &product.Fees); err != nil {
+				return fmt.Errorf("parsing -fees: %w", err)
+			}
+		}
+		if *interest != "" {
+			if err := json.Unmarshal([]byte(*interest), &product.Interest); err != nil {
+				return fmt.Errorf("parsing -interest: %w", err)
+			}
+		}
+		return c.PutProduct(ctx, product)
+	case "list-products":
+		includeRetired := flags.Bool("include-retired", false, "include products which no longer open new accounts")
+		_ = flags.Parse(args)
+		output, err := c.ListProducts(ctx, client.ListProductsInput{IncludeRetired: *includeRetired})
+		if err != nil {
+			return err
+		}
+		return printJSON(output)
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
+
+func loadEndpoints(endpoint, endpointsPath string) (client.Endpoints, error) {
+	if endpoint != "" {
+		return client.NewEndpointsFromBaseURL(endpoint), nil
+	}
+	if endpointsPath == "" {
+		return client.Endpoints{}, errors.New("one of -endpoint or -endpoints is required")
+	}
+
+	contents, err := os.ReadFile(endpointsPath)
+	if err != nil {
+		return client.Endpoints{}, err
+	}
+	var endpoints client.Endpoints
+	err = json.Unmarshal(contents, &endpoints)
+	if err != nil {
+		return client.Endpoints{}, fmt.Errorf("parsing endpoints %s: %w", endpointsPath, err)
+	}
+	return endpoints, nil
+}
+
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type callerIdentityKey struct{}
+
+// withCallerIdentity records the authenticated identity of the caller, in the form the IAM authorizer of a Function URL
+// would provide it
+func withCallerIdentity(ctx context.Context, identity events.LambdaFunctionURLRequestContextAuthorizerIAMDescription) context.Context {
+	return context.WithValue(ctx, callerIdentityKey{}, identity)
+}
+
+// lambdaAdapter serves a Lambda Function URL handler over net/http
+type lambdaAdapter struct {
+	handler functions.LambdaHandler
+	// Account ID of the caller when the request was not authenticated by middleware, standing in for the IAM
+	// authorizer of a Function URL
+	callerAccountID string
+	// Maximum duration of an invocation, standing in for the Lambda function timeout
+	timeout time.Duration
+}
+
+func (adapter lambdaAdapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Error reading the request body", http.StatusBadRequest)
+		return
+	}
+
+	request := adapter.toLambdaRequest(r, body)
+
+	ctx, cancel := context.WithTimeout(r.Context(), adapter.timeout)
+	defer cancel()
+
+	response, err := adapter.handler(ctx, request)
+	if err != nil {
+		// Function URLs respond with a 502 when the function returns an error
+		log.Printf("Handler for %s returned an error: %v", r.URL.Path, err)
+		http.Error(w, "Internal Server Error", http.StatusBadGateway)
+		return
+	}
+
+	writeLambdaResponse(w, request.RequestContext.RequestID, response)
+}
+
+func (adapter lambdaAdapter) toLambdaRequest(r *http.Request, body []byte) events.LambdaFunctionURLRequest {
+	headers := make(map[string]string)
+	for name, values := range r.Header {
+		headers[strings.ToLower(name)] = strings.Join(values, ",")
+	}
+	headers["host"] = r.Host
+
+	var queryStringParameters map[string]string
+	if query := r.URL.Query(); len(query) > 0 {
+		queryStringParameters = make(map[string]string)
+		for name, values := range query {
+			queryStringParameters[name] = strings.Join(values, ",")
+		}
+	}
+
+	identity, ok := r.Context().Value(callerIdentityKey{}).(events.LambdaFunctionURLRequestContextAuthorizerIAMDescription)
+	if !ok {
+		identity = events.LambdaFunctionURLRequestContextAuthorizerIAMDescription{
+			AccountID: adapter.callerAccountID,
+		}
+	}
+
+	now := time.Now()
+	return events.LambdaFunctionURLRequest{
+		Version:               "2.0",
+		RawPath:               r.URL.Path,
+		RawQueryString:        r.URL.RawQuery,
+		Headers:               headers,
+		QueryStringParameters: queryStringParameters,
+		RequestContext: events.LambdaFunctionURLRequestContext{
+			AccountID: identity.AccountID,
+			RequestID: newRequestID(),
+			Authorizer: &events.LambdaFunctionURLRequestContextAuthorizerDescription{
+				IAM: &identity,
+			},
+			DomainName: r.Host,
+			Time:       now.UTC().Format("02/Jan/2006:15:04:05 -0700"),
+			TimeEpoch:  now.UnixMilli(),
+			HTTP: events.LambdaFunctionURLRequestContextHTTPDescription{
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				Protocol:  r.Proto,
+				SourceIP:  r.RemoteAddr,
+				UserAgent: r.UserAgent(),
+			},
+		},
+		Body: string(body),
+	}
+}
+
+func writeLambdaResponse(w http.ResponseWriter, requestID string, response events.LambdaFunctionURLResponse) {
+	body := []byte(response.Body)
+	if response.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(response.Body)
+		if err != nil {
+			log.Printf("Error decoding base64 response body: %v", err)
+			http.Error(w, "Internal Server Error", http.StatusBadGateway)
+			return
+		}
+		body = decoded
+	}
+
+	for name, value := range response.Headers {
+		w.Header().Set(name, value)
+	}
+	for _, cookie := range response.Cookies {
+		w.Header().Add("Set-Cookie", cookie)
+	}
+	if w.Header().Get("Content-Type") == "" {
+		// Function URLs default to JSON when the function doesn't specify a content type
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.Header().Set("X-Amzn-RequestId", requestID)
+
+	statusCode := response.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(body)
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jakepatzer/banking-service/lambda/handlers"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testAccountID = "123456789012"
+)
+
+func TestLambdaAdapter_TranslatesRequest(t *testing.T) {
+	// === Given ===
+	var received events.LambdaFunctionURLRequest
+	var hasDeadline bool
+	adapter := lambdaAdapter{
+		handler: func(ctx context.Context, request events.LambdaFunctionURLRequest) (events.LambdaFunctionURLResponse, error) {
+			received = request
+			_, hasDeadline = ctx.Deadline()
+			return events.LambdaFunctionURLResponse{StatusCode: 201, Body: "created"}, nil
+		},
+		callerAccountID: testAccountID,
+		timeout:         time.Second,
+	}
+	request := httptest.NewRequest(http.MethodPost, "/create-account?dryRun=true", strings.NewReader(`{"accountType":"savings"}`))
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	// === When ===
+	adapter.ServeHTTP(recorder, request)
+
+	// === Then ===
+	assert.Equal(t, `{"accountType":"savings"}`, received.Body)
+	assert.Equal(t, testAccountID, received.RequestContext.Authorizer.IAM.AccountID)
+	assert.Equal(t, http.MethodPost, received.RequestContext.HTTP.Method)
+	assert.Equal(t, "/create-account", received.RawPath)
+	assert.Equal(t, "dryRun=true", received.RawQueryString)
+	assert.Equal(t, "true", received.QueryStringParameters["dryRun"])
+	assert.Equal(t, "application/json", received.Headers["content-type"])
+	assert.True(t, hasDeadline)
+
+	assert.Equal(t, 201, recorder.Code)
+	assert.Equal(t, "created", recorder.Body.String())
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, received.RequestContext.RequestID, recorder.Header().Get("X-Amzn-RequestId"))
+}
+
+func TestLambdaAdapter_DecodesBase64Response(t *testing.T) {
+	// === Given ===
+	adapter := lambdaAdapter{
+		handler: func(context.Context, events.LambdaFunctionURLRequest) (events.LambdaFunctionURLResponse, error) {
+			return events.LambdaFunctionURLResponse{
+				StatusCode:      200,
+				Headers:         map[string]string{"Content-Type": "text/plain"},
+				Body:            base64.StdEncoding.EncodeToString([]byte("hello")),
+				IsBase64Encoded: true,
+			}, nil
+		},
+		timeout: time.Second,
+	}
+	recorder := httptest.NewRecorder()
+
+	// === When ===
+	adapter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// === Then ===
+	assert.Equal(t, "hello", recorder.Body.String())
+	assert.Equal(t, "text/plain", recorder.Header().Get("Content-Type"))
+}
+
+func TestLambdaAdapter_HandlerError(t *testing.T) {
+	// === Given ===
+	adapter := lambdaAdapter{
+		handler: func(context.Context, events.LambdaFunctionURLRequest) (events.LambdaFunctionURLResponse, error) {
+			return events.LambdaFunctionURLResponse{}, errors.New("ERROR")
+		},
+		timeout: time.Second,
+	}
+	recorder := httptest.NewRecorder()
+
+	// === When ===
+	adapter.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	// === Then ===
+	assert.Equal(t, http.StatusBadGateway, recorder.Code)
+}
+
+func TestRoutes_InMemoryStore(t *testing.T) {
+	// === Given ===
+	accountManager := internal.NewInMemoryAccountManager(internal.NewStaticRateProvider(nil))
+	productsPath := filepath.Join(t.TempDir(), "products.json")
+	assert.NoError(t, os.WriteFile(productsPath, []byte(`[{"accountType": "savings"}]`), 0o600))
+	assert.NoError(t, putProducts(context.Background(), accountManager, productsPath))
+	handlers.SetAccountManager(accountManager)
+	post := func(path, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		adapter := lambdaAdapter{handler: routes[path], callerAccountID: testAccountID, timeout: time.Second}
+		adapter.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return recorder
+	}
+
+	// === When ===
+	createResponse := post("/create-account", `{"accountType":"savings","initialBalance":5}`)
+	balanceResponse := post("/get-balance", `{"accountType":"savings"}`)
+
+	// === Then ===
+	assert.Equal(t, 200, createResponse.Code)
+	assert.Equal(t, 200, balanceResponse.Code)
+	assert.JSONEq(t, `{"balance":5,"currency":"USD","overdraftLimit":0,"held":0,"available":5,"status":"ACTIVE"}`, balanceResponse.Body.String())
+}
+
+func TestPutProducts_ErrorWhenAccountTypeIsInvalid(t *testing.T) {
+	// === Given ===
+	accountManager := internal.NewInMemoryAccountManager(internal.NewStaticRateProvider(nil))
+	productsPath := filepath.Join(t.TempDir(), "products.json")
+	assert.NoError(t, os.WriteFile(productsPath, []byte(`[{"accountType": "Savings"}]`), 0o600))
+
+	// === When ===
+	err := putProducts(context.Background(), accountManager, productsPath)
+
+	// === Then ===
+	assert.Error(t, err)
+	output, err := accountManager.ListProducts(context.Background(), internal.ListProductsInput{})
+	assert.NoError(t, err)
+	assert.Empty(t, output.Products)
+}
@@ -0,0 +1,217 @@
+// Command server hosts all of the Lambda handlers on a single local HTTP server, so that the service can be run without
+// deploying to AWS. Each handler is mounted at the path matching its function name, e.g. POST /transfer.
+//
+// Usage:
+//
+//	server -store memory
+//	server -store dynamodb -dynamodb-endpoint http://localhost:8000
+//	server -credentials credentials.json
+//	server -roles roles.json
+//	server -rates rates.json
+//	server -schedule-interval 1m
+//	server -products catalog.json
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/handlers"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+var routes = map[string]functions.LambdaHandler{
+	"/create-account":           handlers.CreateAccount,
+	"/delete-account":           handlers.DeleteAccount,
+	"/close-account":            handlers.CloseAccount,
+	"/get-balance":              handlers.GetBalance,
+	"/list-accounts":            handlers.ListAccounts,
+	"/list-transactions":        handlers.ListTransactions,
+	"/transfer":                 handlers.Transfer,
+	"/quote-transfer":           handlers.QuoteTransfer,
+	"/deposit":                  handlers.Deposit,
+	"/withdraw":                 handlers.Withdraw,
+	"/reconcile":                handlers.Reconcile,
+	"/quote":                    handlers.Quote,
+	"/set-overdraft-limit":      handlers.SetOverdraftLimit,
+	"/set-account-status":       handlers.SetAccountStatus,
+	"/place-hold":               handlers.PlaceHold,
+	"/capture-hold":             handlers.CaptureHold,
+	"/release-hold":             handlers.ReleaseHold,
+	"/create-schedule":          handlers.CreateSchedule,
+	"/list-schedules":           handlers.ListSchedules,
+	"/cancel-schedule":          handlers.CancelSchedule,
+	"/list-schedule-executions": handlers.ListScheduleExecutions,
+	"/run-schedules":            handlers.RunSchedules,
+	"/accrue-interest":          handlers.AccrueInterest,
+	"/put-product":              handlers.PutProduct,
+	"/list-products":            handlers.ListProducts,
+}
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "address to listen on")
+	store := flag.String("store", "memory", "account store to use: memory or dynamodb")
+	dynamoDBEndpoint := flag.String("dynamodb-endpoint", "", "endpoint of DynamoDB Local, e.g. http://localhost:8000. When empty, the default AWS configuration is used")
+	callerAccountID := flag.String("account-id", "123456789012", "account ID of the caller, standing in for the IAM authorizer. Ignored when -credentials is set")
+	credentialsPath := flag.String("credentials", "", "JSON file mapping access key IDs to secret access keys and account IDs. When set, every request must carry a valid SigV4 signature")
+	region := flag.String("region", "us-west-2", "region that requests must be signed for when -credentials is set")
+	rolesPath := flag.String("roles", "", `JSON file mapping account IDs to their roles, e.g. {"123456789012": ["admin"]}. When empty, every caller is only a customer`)
+	ratesPath := flag.String("rates", "", `JSON file of exchange rates, e.g. {"USD/EUR": "0.9214"}. When empty, the memory store has no rates and the dynamodb store reads the rates table`)
+	productsPath := flag.String("products", "", `JSON file of products to add to the catalog on startup, e.g. [{"accountType": "savings", "minimumOpeningBalance": 100, "interest": {"apy": "0.0425", "dayCount": "ACT/365", "compounding": "MONTHLY"}}]. Accounts may only be created with the account types of products`)
+	timeout := flag.Duration("timeout", 3*time.Second, "maximum duration of each request, standing in for the Lambda function timeout")
+	scheduleInterval := flag.Duration("schedule-interval", 0, "how often to run the scheduled transfers which are due, standing in for the EventBridge rule. When zero, they only run through POST /run-schedules")
+	flag.Parse()
+
+	var rates internal.RateProvider
+	if *ratesPath != "" {
+		config, err := os.ReadFile(*ratesPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		rates, err = internal.ParseStaticRateProvider(config)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	accountManager, err := newAccountManager(context.Background(), *store, *dynamoDBEndpoint, rates)
+	if err != nil {
+		log.Fatal(err)
+	}
+	handlers.SetAccountManager(accountManager)
+
+	if *productsPath != "" {
+		err := putProducts(context.Background(), accountManager, *productsPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *rolesPath != "" {
+		config, err := os.ReadFile(*rolesPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		roleStore, err := internal.ParseStaticRoleStore(config)
+		if err != nil {
+			log.Fatal(err)
+		}
+		handlers.SetRoleStore(roleStore)
+	}
+
+	if *scheduleInterval > 0 {
+		go runSchedules(accountManager, *scheduleInterval)
+	}
+
+	mux := http.NewServeMux()
+	for path, handler := range routes {
+		mux.Handle(path, lambdaAdapter{
+			handler:         handler,
+			callerAccountID: *callerAccountID,
+			timeout:         *timeout,
+		})
+	}
+
+	var server http.Handler = mux
+	if *credentialsPath != "" {
+		credentials, err := loadCredentialStore(*credentialsPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		// Requests are signed for the lambda service, as they would be for a Function URL
+		server = newSigV4Verifier(credentials, *region, "lambda", mux)
+		log.Printf("Verifying SigV4 signatures for %d access keys", len(credentials))
+	}
+
+	log.Printf("Listening on %s with the %s store", *addr, *store)
+	log.Fatal(http.ListenAndServe(*addr, server))
+}
+
+// newAccountManager returns the account manager of a store, which quotes at the given rates if they are not nil
+func newAccountManager(ctx context.Context, store, dynamoDBEndpoint string, rates internal.RateProvider) (internal.AccountManager, error) {
+	switch store {
+	case "memory":
+		if rates == nil {
+			rates = internal.NewStaticRateProvider(nil)
+		}
+		return internal.NewInMemoryAccountManager(rates), nil
+	case "dynamodb":
+		var ddb *dynamodb.Client
+		if dynamoDBEndpoint == "" {
+			cfg, err := config.LoadDefaultConfig(ctx)
+			if err != nil {
+				return nil, err
+			}
+			ddb = dynamodb.NewFromConfig(cfg)
+		} else {
+			var err error
+			ddb, err = internal.NewLocalDynamoDBClient(ctx, dynamoDBEndpoint)
+			if err != nil {
+				return nil, err
+			}
+			err = internal.CreateTables(ctx, ddb)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if rates == nil {
+			rates = internal.NewDynamoDBRateProvider(ddb)
+		}
+		return internal.NewAccountManager(ddb, rates), nil
+	default:
+		return nil, fmt.Errorf("unknown store %q", store)
+	}
+}
+
+// putProducts adds the products in a JSON file to the catalog of accountManager
+func putProducts(ctx context.Context, accountManager internal.AccountManager, path string) error {
+	config, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var products []internal.Product
+	err = json.Unmarshal(config, &products)
+	if err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		if !internal.IsValidAccountType(product.AccountType) {
+			return fmt.Errorf("invalid account type %q in %s", product.AccountType, path)
+		}
+		if product.Interest != nil {
+			if err := product.Interest.Validate(); err != nil {
+				return fmt.Errorf("invalid interest terms of %s in %s: %w", product.AccountType, path, err)
+			}
+		}
+		err = accountManager.PutProduct(ctx, product)
+		if err != nil {
+			return err
+		}
+	}
+	log.Printf("Added %d products to the catalog", len(products))
+	return nil
+}
+
+// runSchedules runs the scheduled transfers which are due every interval, as the schedule-runner function does in AWS
+func runSchedules(accountManager internal.AccountManager, interval time.Duration) {
+	for range time.Tick(interval) {
+		output, err := accountManager.RunSchedules(context.Background(), internal.RunSchedulesInput{})
+		if err != nil {
+			log.Printf("Failed to run schedules: %v", err)
+			continue
+		}
+		if len(output.Executions) > 0 {
+			log.Printf("Ran %d scheduled transfers", len(output.Executions))
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"golang.org/x/exp/slices"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	amzDateFormat   = "20060102T150405Z"
+	sigV4Terminator = "aws4_request"
+
+	// Requests signed further than this from the server's clock are rejected, matching AWS
+	maxClockSkew = 5 * time.Minute
+)
+
+// credential is a secret access key and the account that it belongs to
+type credential struct {
+	SecretAccessKey string `json:"secretAccessKey"`
+	AccountID       string `json:"accountID"`
+}
+
+// credentialStore maps access key IDs to their credentials
+type credentialStore map[string]credential
+
+// loadCredentialStore reads a credential store from a JSON file of the form
+//
+//	{"AKIDEXAMPLE": {"secretAccessKey": "...", "accountID": "123456789012"}}
+func loadCredentialStore(path string) (credentialStore, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var store credentialStore
+	err = json.Unmarshal(contents, &store)
+	if err != nil {
+		return nil, fmt.Errorf("parsing credential store %s: %w", path, err)
+	}
+	return store, nil
+}
+
+// sigV4Verifier is middleware which authenticates requests by their AWS Signature Version 4 signature, taking the
+// place of the IAM authorizer of a Lambda Function URL. Verified requests carry the caller's identity in their
+// context; all other requests are rejected with a 403.
+type sigV4Verifier struct {
+	credentials credentialStore
+	region      string
+	service     string
+	next        http.Handler
+	now         func() time.Time
+}
+
+func newSigV4Verifier(credentials credentialStore, region, service string, next http.Handler) sigV4Verifier {
+	return sigV4Verifier{
+		credentials: credentials,
+		region:      region,
+		service:     service,
+		next:        next,
+		now:         time.Now,
+	}
+}
+
+func (verifier sigV4Verifier) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Error reading the request body", http.StatusBadRequest)
+		return
+	}
+	// The handler reads the body again once the signature is verified
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	identity, err := verifier.verify(r, body)
+	if err != nil {
+		log.Printf("Rejected request to %s from %s: %v", r.URL.Path, r.RemoteAddr, err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"Message":"Forbidden"}`))
+		return
+	}
+
+	verifier.next.ServeHTTP(w, r.WithContext(withCallerIdentity(r.Context(), identity)))
+}
+
+// sigV4Authorization is the parsed Authorization header of a signed request
+type sigV4Authorization struct {
+	accessKeyID   string
+	date          string
+	region        string
+	service       string
+	signedHeaders []string
+	signature     string
+}
+
+func parseSigV4Authorization(header string) (sigV4Authorization, error) {
+	if !strings.HasPrefix(header, sigV4Algorithm+" ") {
+		return sigV4Authorization{}, fmt.Errorf("unsupported authorization %q", header)
+	}
+
+	var authorization sigV4Authorization
+	for _, part := range strings.Split(strings.TrimPrefix(header, sigV4Algorithm+" "), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return sigV4Authorization{}, fmt.Errorf("malformed authorization component %q", part)
+		}
+
+		switch name {
+		case "Credential":
+			scope := strings.Split(value, "/")
+			if len(scope) != 5 || scope[4] != sigV4Terminator {
+				return sigV4Authorization{}, fmt.Errorf("malformed credential %q", value)
+			}
+			authorization.accessKeyID = scope[0]
+			authorization.date = scope[1]
+			authorization.region = scope[2]
+			authorization.service = scope[3]
+		case "SignedHeaders":
+			authorization.signedHeaders = strings.Split(value, ";")
+		case "Signature":
+			authorization.signature = value
+		}
+	}
+
+	if authorization.accessKeyID == "" || len(authorization.signedHeaders) == 0 || authorization.signature == "" {
+		return sigV4Authorization{}, fmt.Errorf("incomplete authorization %q", header)
+	}
+	return authorization, nil
+}
+
+func (verifier sigV4Verifier) verify(r *http.Request, body []byte) (events.LambdaFunctionURLRequestContextAuthorizerIAMDescription, error) {
+	authorization, err := parseSigV4Authorization(r.Header.Get("Authorization"))
+	if err != nil {
+		return events.LambdaFunctionURLRequestContextAuthorizerIAMDescription{}, err
+	}
+
+	credential, ok := verifier.credentials[authorization.accessKeyID]
+	if !ok {
+		return events.LambdaFunctionURLRequestContextAuthorizerIAMDescription{}, fmt.Errorf("unknown access key %s", authorization.accessKeyID)
+	}
+
+	if authorization.region != verifier.region || authorization.service != verifier.service {
+		return events.LambdaFunctionURLRequestContextAuthorizerIAMDescription{}, fmt.Errorf("credential scoped to %s/%s", authorization.region, authorization.service)
+	}
+
+	amzDate := r.Header.Get("X-Amz-Date")
+	signingTime, err := time.Parse(amzDateFormat, amzDate)
+	if err != nil {
+		return events.LambdaFunctionURLRequestContextAuthorizerIAMDescription{}, fmt.Errorf("invalid X-Amz-Date %q", amzDate)
+	}
+	if skew := verifier.now().Sub(signingTime); skew > maxClockSkew || skew < -maxClockSkew {
+		return events.LambdaFunctionURLRequestContextAuthorizerIAMDescription{}, fmt.Errorf("request signed at %s is outside of the allowed clock skew", amzDate)
+	}
+	if authorization.date != signingTime.Format("20060102") {
+		return events.LambdaFunctionURLRequestContextAuthorizerIAMDescription{}, fmt.Errorf("credential date %s does not match X-Amz-Date", authorization.date)
+	}
+	if !slices.Contains(authorization.signedHeaders, "host") {
+		return events.LambdaFunctionURLRequestContextAuthorizerIAMDescription{}, errors.New("host header is not signed")
+	}
+
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash == "" || payloadHash == "UNSIGNED-PAYLOAD" {
+		payloadHash = hashHex(body)
+	}
+	if payloadHash != hashHex(body) {
+		return events.LambdaFunctionURLRequestContextAuthorizerIAMDescription{}, errors.New("payload hash does not match the body")
+	}
+
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		canonicalURI(r),
+		canonicalQueryString(r),
+		canonicalHeaders(r, authorization.signedHeaders),
+		strings.Join(authorization.signedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{authorization.date, authorization.region, authorization.service, sigV4Terminator}, "/")
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		amzDate,
+		scope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+credential.SecretAccessKey), authorization.date)
+	signingKey = hmacSHA256(signingKey, authorization.region)
+	signingKey = hmacSHA256(signingKey, authorization.service)
+	signingKey = hmacSHA256(signingKey, sigV4Terminator)
+	expected := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	if !hmac.Equal([]byte(expected), []byte(authorization.signature)) {
+		return events.LambdaFunctionURLRequestContextAuthorizerIAMDescription{}, errors.New("signature does not match")
+	}
+
+	return events.LambdaFunctionURLRequestContextAuthorizerIAMDescription{
+		AccessKey: authorization.accessKeyID,
+		AccountID: credential.AccountID,
+		CallerID:  authorization.accessKeyID,
+	}, nil
+}
+
+// canonicalURI encodes the already escaped path a second time, as AWS does for every service other than S3
+func canonicalURI(r *http.Request) string {
+	path := r.URL.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func canonicalQueryString(r *http.Request) string {
+	return strings.ReplaceAll(r.URL.Query().Encode(), "+", "%20")
+}
+
+func canonicalHeaders(r *http.Request, signedHeaders []string) string {
+	var builder strings.Builder
+	for _, name := range signedHeaders {
+		var values []string
+		switch name {
+		// net/http moves these out of the header map
+		case "host":
+			values = []string{r.Host}
+		case "content-length":
+			values = []string{strconv.FormatInt(r.ContentLength, 10)}
+		default:
+			values = r.Header.Values(name)
+		}
+
+		trimmed := make([]string, len(values))
+		for i, value := range values {
+			trimmed[i] = strings.Join(strings.Fields(value), " ")
+		}
+		builder.WriteString(name)
+		builder.WriteString(":")
+		builder.WriteString(strings.Join(trimmed, ","))
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// uriEncode percent-encodes every byte other than the unreserved characters defined by RFC 3986
+func uriEncode(s string) string {
+	var builder strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' {
+			builder.WriteByte(c)
+		} else {
+			fmt.Fprintf(&builder, "%%%02X", c)
+		}
+	}
+	return builder.String()
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testAccessKeyID     = "AKIDEXAMPLE"
+	testSecretAccessKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	testRegion          = "us-west-2"
+)
+
+var testSigningTime = time.Date(2022, 9, 1, 12, 0, 0, 0, time.UTC)
+
+func TestSigV4Verifier_AcceptsValidSignature(t *testing.T) {
+	// === Given ===
+	var identity events.LambdaFunctionURLRequestContextAuthorizerIAMDescription
+	var body string
+	verifier := newTestVerifier(lambdaAdapter{
+		handler: func(ctx context.Context, request events.LambdaFunctionURLRequest) (events.LambdaFunctionURLResponse, error) {
+			identity = *request.RequestContext.Authorizer.IAM
+			body = request.Body
+			return events.LambdaFunctionURLResponse{StatusCode: 200}, nil
+		},
+		callerAccountID: "ignored",
+		timeout:         time.Second,
+	})
+	request := signedRequest(t, "/transfer?dry run=true", `{"amount":5}`, testSecretAccessKey, testSigningTime)
+	recorder := httptest.NewRecorder()
+
+	// === When ===
+	verifier.ServeHTTP(recorder, request)
+
+	// === Then ===
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, testAccountID, identity.AccountID)
+	assert.Equal(t, testAccessKeyID, identity.AccessKey)
+	assert.Equal(t, `{"amount":5}`, body)
+}
+
+func TestSigV4Verifier_RejectsTamperedBody(t *testing.T) {
+	// === Given ===
+	verifier := newTestVerifier(unreachableHandler(t))
+	request := signedRequest(t, "/transfer", `{"amount":5}`, testSecretAccessKey, testSigningTime)
+	tampered := httptest.NewRequest(http.MethodPost, "/transfer", strings.NewReader(`{"amount":500}`))
+	tampered.Header = request.Header
+	recorder := httptest.NewRecorder()
+
+	// === When ===
+	verifier.ServeHTTP(recorder, tampered)
+
+	// === Then ===
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func TestSigV4Verifier_RejectsWrongSecret(t *testing.T) {
+	// === Given ===
+	verifier := newTestVerifier(unreachableHandler(t))
+	request := signedRequest(t, "/transfer", `{"amount":5}`, "not-the-secret", testSigningTime)
+	recorder := httptest.NewRecorder()
+
+	// === When ===
+	verifier.ServeHTTP(recorder, request)
+
+	// === Then ===
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func TestSigV4Verifier_RejectsStaleSignature(t *testing.T) {
+	// === Given ===
+	verifier := newTestVerifier(unreachableHandler(t))
+	request := signedRequest(t, "/transfer", `{"amount":5}`, testSecretAccessKey, testSigningTime.Add(-10*time.Minute))
+	recorder := httptest.NewRecorder()
+
+	// === When ===
+	verifier.ServeHTTP(recorder, request)
+
+	// === Then ===
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func TestSigV4Verifier_RejectsUnsignedRequest(t *testing.T) {
+	// === Given ===
+	verifier := newTestVerifier(unreachableHandler(t))
+	recorder := httptest.NewRecorder()
+
+	// === When ===
+	verifier.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/transfer", strings.NewReader("{}")))
+
+	// === Then ===
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func newTestVerifier(next http.Handler) sigV4Verifier {
+	verifier := newSigV4Verifier(credentialStore{
+		testAccessKeyID: {
+			SecretAccessKey: testSecretAccessKey,
+			AccountID:       testAccountID,
+		},
+	}, testRegion, "lambda", next)
+	verifier.now = func() time.Time {
+		return testSigningTime
+	}
+	return verifier
+}
+
+func unreachableHandler(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("handler should not be reached")
+	})
+}
+
+// signedRequest signs a request with the AWS SDK's signer, as a client of the server would
+func signedRequest(t *testing.T, target, body, secretAccessKey string, signingTime time.Time) *http.Request {
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080"+strings.Replace(target, " ", "%20", 1), strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	payloadHash := sha256.Sum256([]byte(body))
+	credentials := aws.Credentials{AccessKeyID: testAccessKeyID, SecretAccessKey: secretAccessKey}
+	err := v4.NewSigner().SignHTTP(context.Background(), credentials, request, hex.EncodeToString(payloadHash[:]), "lambda", testRegion, signingTime)
+	require.NoError(t, err)
+	return request
+}
@@ -0,0 +1,126 @@
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"golang.org/x/exp/slices"
+	"io"
+	"log"
+	"os"
+	"time"
+)
+
+// Outcomes of audited calls
+const (
+	auditDenied    = "denied"
+	auditSucceeded = "succeeded"
+	auditFailed    = "failed"
+)
+
+// auditOutput is where audit records are written. Lambda sends stdout to CloudWatch Logs.
+var auditOutput io.Writer = os.Stdout
+
+// Permission is the right to call an operation, or to use some privileged behaviour of one
+type Permission string
+
+// Policy is the set of permissions granted to each role
+type Policy map[internal.Role][]Permission
+
+func (policy Policy) allows(roles []internal.Role, permission Permission) bool {
+	if slices.Contains(policy[internal.RoleCustomer], permission) {
+		return true
+	}
+	for _, role := range roles {
+		if slices.Contains(policy[role], permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// Caller is the identity of the caller of an operation
+type Caller struct {
+	AccountID string
+	// Roles granted to the caller in addition to internal.RoleCustomer, set by the Authorize middleware
+	Roles  []internal.Role
+	policy Policy
+}
+
+// Can returns whether the caller has permission, for operations which behave differently for privileged callers
+func (caller Caller) Can(permission Permission) bool {
+	return caller.policy.allows(caller.Roles, permission)
+}
+
+// privileged returns whether the caller has been granted any roles, in which case all their calls are audited
+func (caller Caller) privileged() bool {
+	return len(caller.Roles) > 0
+}
+
+type auditRecord struct {
+	Type       string          `json:"type"`
+	Timestamp  time.Time       `json:"timestamp"`
+	RequestID  string          `json:"requestId"`
+	AccountID  string          `json:"accountId"`
+	Roles      []internal.Role `json:"roles"`
+	Permission Permission      `json:"permission"`
+	Outcome    string          `json:"outcome"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// Authorize looks up the roles of the caller in store, and rejects the request with ErrForbidden unless policy grants
+// them permission. Every call by a caller with any roles, and every denied call, is audited. It must run after
+// Authenticate.
+func Authorize(store internal.RoleStore, policy Policy, permission Permission) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, request *Request) (events.LambdaFunctionURLResponse, error) {
+			roles, err := store.GetRoles(ctx, request.Caller.AccountID)
+			if err != nil {
+				return events.LambdaFunctionURLResponse{}, err
+			}
+			request.Caller.Roles = roles
+			request.Caller.policy = policy
+
+			if !request.Caller.Can(permission) {
+				audit(request, permission, auditDenied, ErrForbidden)
+				return events.LambdaFunctionURLResponse{}, ErrForbidden
+			}
+
+			response, err := next(ctx, request)
+			if request.Caller.privileged() {
+				if err != nil {
+					audit(request, permission, auditFailed, err)
+				} else {
+					audit(request, permission, auditSucceeded, nil)
+				}
+			}
+			return response, err
+		}
+	}
+}
+
+func audit(request *Request, permission Permission, outcome string, err error) {
+	record := auditRecord{
+		Type:       "audit",
+		Timestamp:  time.Now().UTC(),
+		RequestID:  request.Event.RequestContext.RequestID,
+		AccountID:  request.Caller.AccountID,
+		Roles:      request.Caller.Roles,
+		Permission: permission,
+		Outcome:    outcome,
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		log.Printf("Failed to marshal audit record: %v", err)
+		return
+	}
+	_, err = auditOutput.Write(append(line, '\n'))
+	if err != nil {
+		log.Printf("Failed to write audit record: %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package functions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const (
+	testAdminAccountID = "105343117262"
+
+	testPermissionGreet    Permission = "greetings:create"
+	testPermissionGreetAll Permission = "greetings:create-all"
+)
+
+var testPolicy = Policy{
+	internal.RoleCustomer: {testPermissionGreet},
+	internal.RoleAdmin:    {testPermissionGreetAll},
+}
+
+var testRoleStore = internal.NewStaticRoleStore(map[string][]internal.Role{
+	testAdminAccountID: {internal.RoleAdmin},
+})
+
+type failingRoleStore struct{}
+
+func (failingRoleStore) GetRoles(context.Context, string) ([]internal.Role, error) {
+	return nil, errors.New("ERROR")
+}
+
+// captureAudit redirects audit records for the duration of the test, returning each record written
+func captureAudit(t *testing.T) func() []auditRecord {
+	var buffer bytes.Buffer
+	previous := auditOutput
+	auditOutput = &buffer
+	t.Cleanup(func() {
+		auditOutput = previous
+	})
+
+	return func() []auditRecord {
+		var records []auditRecord
+		for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var record auditRecord
+			assert.NoError(t, json.Unmarshal([]byte(line), &record))
+			records = append(records, record)
+		}
+		return records
+	}
+}
+
+func authorizedMiddleware(store internal.RoleStore, permission Permission) []Middleware {
+	return []Middleware{MapErrors(NewErrorRegistry()), Authenticate, Authorize(store, testPolicy, permission), Decode(false)}
+}
+
+func greetAll(_ context.Context, caller Caller, input testInput) (testOutput, error) {
+	if caller.Can(testPermissionGreetAll) {
+		return testOutput{Greeting: "Hello everyone"}, nil
+	}
+	return testOutput{Greeting: "Hello " + input.Name}, nil
+}
+
+func TestAuthorize_CustomerIsNotAudited(t *testing.T) {
+	// === Given ===
+	audit := captureAudit(t)
+	handler := NewHandler(greetAll, authorizedMiddleware(testRoleStore, testPermissionGreet)...)
+
+	// === When ===
+	response, err := handler(context.Background(), getRequest(testAccountID, `{"name":"Jake"}`))
+
+	// === Then ===
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.JSONEq(t, `{"greeting":"Hello Jake"}`, response.Body)
+	assert.Empty(t, audit())
+}
+
+func TestAuthorize_PrivilegedCallIsAudited(t *testing.T) {
+	// === Given ===
+	audit := captureAudit(t)
+	handler := NewHandler(greetAll, authorizedMiddleware(testRoleStore, testPermissionGreet)...)
+	request := getRequest(testAdminAccountID, `{"name":"Jake"}`)
+	request.RequestContext.RequestID = "request-id"
+
+	// === When ===
+	response, err := handler(context.Background(), request)
+
+	// === Then ===
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.JSONEq(t, `{"greeting":"Hello everyone"}`, response.Body)
+
+	records := audit()
+	assert.Len(t, records, 1)
+	assert.Equal(t, "request-id", records[0].RequestID)
+	assert.Equal(t, testAdminAccountID, records[0].AccountID)
+	assert.Equal(t, []internal.Role{internal.RoleAdmin}, records[0].Roles)
+	assert.Equal(t, testPermissionGreet, records[0].Permission)
+	assert.Equal(t, auditSucceeded, records[0].Outcome)
+}
+
+func TestAuthorize_FailedPrivilegedCallIsAudited(t *testing.T) {
+	// === Given ===
+	audit := captureAudit(t)
+	handler := NewHandler(greet, authorizedMiddleware(testRoleStore, testPermissionGreet)...)
+
+	// === When ===
+	response, err := handler(context.Background(), getRequest(testAdminAccountID, `{"name":"error"}`))
+
+	// === Then ===
+	assert.NoError(t, err)
+	assertProblem(t, response, 500, CodeInternalError)
+
+	records := audit()
+	assert.Len(t, records, 1)
+	assert.Equal(t, auditFailed, records[0].Outcome)
+	assert.Equal(t, "test error", records[0].Error)
+}
+
+func TestAuthorize_Forbidden(t *testing.T) {
+	// === Given ===
+	audit := captureAudit(t)
+	handler := NewHandler(greet, authorizedMiddleware(testRoleStore, testPermissionGreetAll)...)
+
+	// === When ===
+	response, err := handler(context.Background(), getRequest(testAccountID, `{"name":"Jake"}`))
+
+	// === Then ===
+	assert.NoError(t, err)
+	assertProblem(t, response, 403, CodeForbidden)
+
+	records := audit()
+	assert.Len(t, records, 1)
+	assert.Equal(t, testAccountID, records[0].AccountID)
+	assert.Equal(t, testPermissionGreetAll, records[0].Permission)
+	assert.Equal(t, auditDenied, records[0].Outcome)
+}
+
+func TestAuthorize_RoleStoreError(t *testing.T) {
+	// === Given ===
+	captureAudit(t)
+	handler := NewHandler(greet, authorizedMiddleware(failingRoleStore{}, testPermissionGreet)...)
+
+	// === When ===
+	response, err := handler(context.Background(), getRequest(testAccountID, `{"name":"Jake"}`))
+
+	// === Then ===
+	assert.NoError(t, err)
+	assertProblem(t, response, 500, CodeInternalError)
+}
@@ -0,0 +1,9 @@
+package main
+
+import (
+	"github.com/jakepatzer/banking-service/lambda/handlers"
+)
+
+func main() {
+	handlers.StartLambda(handlers.CreateSchedule)
+}
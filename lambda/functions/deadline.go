@@ -0,0 +1,59 @@
+package functions
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+)
+
+const (
+	// Time reserved before the Lambda deadline for a handler to log and respond once the account manager gives up
+	deadlineHeadroom = 500 * time.Millisecond
+	// Requests are rejected up front when less than this would be left for the account manager
+	minimumBudget = 100 * time.Millisecond
+	// Suggested delay before retrying a request which ran out of time
+	retryAfter = 1 * time.Second
+)
+
+var errInsufficientBudget = errors.New("not enough time remains before the function deadline to process the request")
+
+// withDeadlineBudget returns a context which expires deadlineHeadroom before the deadline of ctx, so that calls to
+// DynamoDB are abandoned while there is still time to respond. It returns errInsufficientBudget if too little time
+// remains to start the request at all. Contexts without a deadline are returned as is.
+func withDeadlineBudget(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return ctx, func() {}, nil
+	}
+
+	budgetDeadline := deadline.Add(-deadlineHeadroom)
+	if time.Until(budgetDeadline) < minimumBudget {
+		return ctx, func() {}, errInsufficientBudget
+	}
+
+	budgetCtx, cancel := context.WithDeadline(ctx, budgetDeadline)
+	return budgetCtx, cancel, nil
+}
+
+// processDeadlineError returns the problem for an error caused by the deadline budget, if err is one
+func processDeadlineError(err error) (Problem, bool) {
+	var problem Problem
+	if errors.Is(err, errInsufficientBudget) {
+		problem = NewProblem(503, CodeServiceUnavailable,
+			"The request could not be started before the function timeout. Please retry the request.")
+	} else if errors.Is(err, context.DeadlineExceeded) {
+		problem = NewProblem(504, CodeTimeout,
+			"The request did not complete before the function timeout, and may or may not have been applied. Please retry the request.")
+	} else {
+		return Problem{}, false
+	}
+	problem.headers = retryAfterHeaders()
+	return problem, true
+}
+
+func retryAfterHeaders() map[string]string {
+	return map[string]string{
+		"Retry-After": strconv.Itoa(int(retryAfter.Seconds())),
+	}
+}
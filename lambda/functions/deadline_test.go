@@ -0,0 +1,70 @@
+package functions
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestWithDeadlineBudget_NoDeadline(t *testing.T) {
+	// === Given ===
+	ctx := context.Background()
+
+	// === When ===
+	budgetCtx, cancel, err := withDeadlineBudget(ctx)
+	defer cancel()
+
+	// === Then ===
+	assert.NoError(t, err)
+	assert.Equal(t, ctx, budgetCtx)
+}
+
+func TestWithDeadlineBudget_LeavesHeadroom(t *testing.T) {
+	// === Given ===
+	deadline := time.Now().Add(3 * time.Second)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	// === When ===
+	budgetCtx, budgetCancel, err := withDeadlineBudget(ctx)
+	defer budgetCancel()
+
+	// === Then ===
+	assert.NoError(t, err)
+	budgetDeadline, ok := budgetCtx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, deadline.Add(-deadlineHeadroom), budgetDeadline)
+}
+
+func TestWithDeadlineBudget_ErrorWhenDeadlineIsTooClose(t *testing.T) {
+	// === Given ===
+	ctx, cancel := context.WithTimeout(context.Background(), deadlineHeadroom)
+	defer cancel()
+
+	// === When ===
+	_, budgetCancel, err := withDeadlineBudget(ctx)
+	defer budgetCancel()
+
+	// === Then ===
+	assert.ErrorIs(t, err, errInsufficientBudget)
+}
+
+func TestProcessDeadlineError(t *testing.T) {
+	// === When ===
+	unavailable, unavailableOk := processDeadlineError(errInsufficientBudget)
+	timeout, timeoutOk := processDeadlineError(fmt.Errorf("operation error DynamoDB: TransactWriteItems: %w", context.DeadlineExceeded))
+	_, otherOk := processDeadlineError(fmt.Errorf("ERROR"))
+
+	// === Then ===
+	assert.True(t, unavailableOk)
+	assert.Equal(t, 503, unavailable.Status)
+	assert.Equal(t, CodeServiceUnavailable, unavailable.Code)
+	assert.Equal(t, "1", unavailable.headers["Retry-After"])
+	assert.True(t, timeoutOk)
+	assert.Equal(t, 504, timeout.Status)
+	assert.Equal(t, CodeTimeout, timeout.Code)
+	assert.Equal(t, "1", timeout.headers["Retry-After"])
+	assert.False(t, otherOk)
+}
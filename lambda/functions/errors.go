@@ -2,6 +2,7 @@ package functions
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 )
 
@@ -24,3 +25,99 @@ func MarshalOutput(output interface{}) string {
 		return string(json)
 	}
 }
+
+// ParseError is returned when the request body is not valid JSON for the operation's input
+type ParseError struct {
+	Err error
+}
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("Error parsing the request body: %v", e.Err)
+}
+
+func (e ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ErrUnauthenticated is returned when the request does not carry the caller's IAM identity
+var ErrUnauthenticated = errors.New("the request does not identify the caller")
+
+// ErrForbidden is returned when none of the caller's roles grant permission to call the operation
+var ErrForbidden = errors.New("the caller does not have permission to call the operation")
+
+// ErrorMapper returns the problem for err, and whether it applies to err at all
+type ErrorMapper func(err error) (Problem, bool)
+
+// ErrorRegistry maps errors to problems. Mappers are tried in the order they were registered, and mappers of a
+// registry take precedence over those of the registry it extends.
+type ErrorRegistry struct {
+	parent  *ErrorRegistry
+	mappers []ErrorMapper
+}
+
+// NewErrorRegistry returns a registry which maps the errors of the framework itself, such as ParseError and validation
+// errors
+func NewErrorRegistry() *ErrorRegistry {
+	registry := &ErrorRegistry{}
+	registry.Register(func(err error) (Problem, bool) {
+		var parseErr ParseError
+		if errors.As(err, &parseErr) {
+			return NewProblem(400, CodeInvalidJSON, "Error parsing the provided request"), true
+		}
+		return Problem{}, false
+	})
+	registry.Register(func(err error) (Problem, bool) {
+		if errors.Is(err, ErrUnauthenticated) {
+			return NewProblem(401, CodeUnauthenticated, "The request does not identify the caller"), true
+		}
+		return Problem{}, false
+	})
+	registry.Register(func(err error) (Problem, bool) {
+		if errors.Is(err, ErrForbidden) {
+			return NewProblem(403, CodeForbidden, "You do not have permission to perform this operation"), true
+		}
+		return Problem{}, false
+	})
+	registry.Register(processValidationError)
+	registry.Register(processDeadlineError)
+	return registry
+}
+
+// Extend returns a registry whose mappings take precedence over those of registry, for endpoints which respond to some
+// errors differently
+func (registry *ErrorRegistry) Extend() *ErrorRegistry {
+	return &ErrorRegistry{parent: registry}
+}
+
+func (registry *ErrorRegistry) Register(mapper ErrorMapper) {
+	registry.mappers = append(registry.mappers, mapper)
+}
+
+// RegisterError maps errors of type E to problems with statusCode and code. The error's message is the detail of the
+// problem, and its fields are the details.
+func RegisterError[E error](registry *ErrorRegistry, statusCode int, code string) {
+	registry.Register(func(err error) (Problem, bool) {
+		var target E
+		if errors.As(err, &target) {
+			problem := NewProblem(statusCode, code, target.Error())
+			details, err := json.Marshal(target)
+			if err == nil {
+				problem.Details = details
+			}
+			return problem, true
+		}
+		return Problem{}, false
+	})
+}
+
+// Problem returns the problem for err, which is an internal error if no mapper applies
+func (registry *ErrorRegistry) Problem(err error) Problem {
+	for r := registry; r != nil; r = r.parent {
+		for _, mapper := range r.mappers {
+			if problem, ok := mapper(err); ok {
+				return problem
+			}
+		}
+	}
+	return NewProblem(500, CodeInternalError, "Internal error")
+}
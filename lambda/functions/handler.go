@@ -0,0 +1,60 @@
+package functions
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// LambdaHandler is the signature shared by all Lambda Function URL handlers
+type LambdaHandler func(ctx context.Context, event events.LambdaFunctionURLRequest) (events.LambdaFunctionURLResponse, error)
+
+// Request is a Function URL request as it passes through middleware
+type Request struct {
+	Event events.LambdaFunctionURLRequest
+	// Identity of the caller, set by the Authenticate and Authorize middleware
+	Caller Caller
+	// Pointer to the operation's input, populated by the Decode middleware
+	Input interface{}
+}
+
+// Handler processes a request. Errors are turned into responses by the MapErrors middleware.
+type Handler func(ctx context.Context, request *Request) (events.LambdaFunctionURLResponse, error)
+
+// Middleware wraps a Handler to run code before or after it
+type Middleware func(next Handler) Handler
+
+// Operation is the business logic of an endpoint, called with the caller's identity and the decoded input
+type Operation[I any, O any] func(ctx context.Context, caller Caller, input I) (O, error)
+
+// NoOutput is the output of operations which respond with an empty body
+type NoOutput struct{}
+
+// NewHandler returns a Lambda handler which runs the middleware, outermost first, around operation. The output of the
+// operation is marshalled as the body of a 200 response.
+func NewHandler[I any, O any](operation Operation[I, O], middleware ...Middleware) LambdaHandler {
+	var handler Handler = func(ctx context.Context, request *Request) (events.LambdaFunctionURLResponse, error) {
+		output, err := operation(ctx, request.Caller, *request.Input.(*I))
+		if err != nil {
+			return events.LambdaFunctionURLResponse{}, err
+		}
+
+		if _, ok := interface{}(output).(NoOutput); ok {
+			return events.LambdaFunctionURLResponse{StatusCode: 200}, nil
+		}
+		return events.LambdaFunctionURLResponse{
+			StatusCode: 200,
+			Body:       MarshalOutput(output),
+		}, nil
+	}
+
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	return func(ctx context.Context, event events.LambdaFunctionURLRequest) (events.LambdaFunctionURLResponse, error) {
+		return handler(ctx, &Request{
+			Event: event,
+			Input: new(I),
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const (
+	testAccountID = "123456789"
+)
+
+type testInput struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type testOutput struct {
+	Greeting string `json:"greeting"`
+}
+
+type testError struct{}
+
+func (testError) Error() string {
+	return "test error"
+}
+
+func getRequest(accountID, requestBody string) events.LambdaFunctionURLRequest {
+	return events.LambdaFunctionURLRequest{
+		RequestContext: events.LambdaFunctionURLRequestContext{
+			Authorizer: &events.LambdaFunctionURLRequestContextAuthorizerDescription{
+				IAM: &events.LambdaFunctionURLRequestContextAuthorizerIAMDescription{
+					AccountID: accountID,
+				},
+			},
+		},
+		Body: requestBody,
+	}
+}
+
+func testMiddleware(registry *ErrorRegistry, bodyOptional bool) []Middleware {
+	return []Middleware{MapErrors(registry), Logging, Authenticate, DeadlineBudget, Decode(bodyOptional), Validate}
+}
+
+// assertProblem asserts that response is a problem with the given status and code, returning the problem
+func assertProblem(t *testing.T, response events.LambdaFunctionURLResponse, status int, code string) Problem {
+	assert.Equal(t, status, response.StatusCode)
+	assert.Equal(t, ProblemContentType, response.Headers["Content-Type"])
+
+	var problem Problem
+	assert.NoError(t, json.Unmarshal([]byte(response.Body), &problem))
+	assert.Equal(t, status, problem.Status)
+	assert.Equal(t, code, problem.Code)
+	return problem
+}
+
+func greet(_ context.Context, caller Caller, input testInput) (testOutput, error) {
+	if input.Name == "error" {
+		return testOutput{}, testError{}
+	}
+	return testOutput{Greeting: "Hello " + input.Name + " from " + caller.AccountID}, nil
+}
+
+func TestNewHandler_Success(t *testing.T) {
+	// === Given ===
+	handler := NewHandler(greet, testMiddleware(NewErrorRegistry(), false)...)
+
+	// === When ===
+	response, err := handler(context.Background(), getRequest(testAccountID, `{"name":"Jake"}`))
+
+	// === Then ===
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.JSONEq(t, `{"greeting":"Hello Jake from 123456789"}`, response.Body)
+}
+
+func TestNewHandler_NoOutput(t *testing.T) {
+	// === Given ===
+	handler := NewHandler(func(context.Context, Caller, testInput) (NoOutput, error) {
+		return NoOutput{}, nil
+	}, testMiddleware(NewErrorRegistry(), false)...)
+
+	// === When ===
+	response, err := handler(context.Background(), getRequest(testAccountID, `{"name":"Jake"}`))
+
+	// === Then ===
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Empty(t, response.Body)
+}
+
+func TestNewHandler_ErrorWhenBodyIsInvalidJSON(t *testing.T) {
+	// === Given ===
+	handler := NewHandler(greet, testMiddleware(NewErrorRegistry(), false)...)
+
+	// === When ===
+	response, err := handler(context.Background(), getRequest(testAccountID, `{"name":`))
+
+	// === Then ===
+	assert.NoError(t, err)
+	assertProblem(t, response, 400, CodeInvalidJSON)
+}
+
+func TestNewHandler_ErrorWhenBodyIsMissing(t *testing.T) {
+	// === Given ===
+	handler := NewHandler(greet, testMiddleware(NewErrorRegistry(), false)...)
+
+	// === When ===
+	response, err := handler(context.Background(), getRequest(testAccountID, ""))
+
+	// === Then ===
+	assert.NoError(t, err)
+	assert.Equal(t, 400, response.StatusCode)
+}
+
+func TestNewHandler_OptionalBody(t *testing.T) {
+	// === Given ===
+	var received *testInput
+	handler := NewHandler(func(_ context.Context, _ Caller, input testInput) (NoOutput, error) {
+		received = &input
+		return NoOutput{}, nil
+	}, MapErrors(NewErrorRegistry()), Authenticate, Decode(true))
+
+	// === When ===
+	response, err := handler(context.Background(), getRequest(testAccountID, ""))
+
+	// === Then ===
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, &testInput{}, received)
+}
+
+func TestNewHandler_ErrorWhenValidationFails(t *testing.T) {
+	// === Given ===
+	handler := NewHandler(greet, testMiddleware(NewErrorRegistry(), false)...)
+
+	// === When ===
+	response, err := handler(context.Background(), getRequest(testAccountID, `{}`))
+
+	// === Then ===
+	assert.NoError(t, err)
+	problem := assertProblem(t, response, 400, CodeValidationFailed)
+	assert.Equal(t, []InvalidParam{{Name: "name", Reason: "name is a required field"}}, problem.InvalidParams)
+}
+
+func TestNewHandler_ErrorWhenUnauthenticated(t *testing.T) {
+	// === Given ===
+	handler := NewHandler(greet, testMiddleware(NewErrorRegistry(), false)...)
+
+	// === When ===
+	response, err := handler(context.Background(), events.LambdaFunctionURLRequest{Body: `{"name":"Jake"}`})
+
+	// === Then ===
+	assert.NoError(t, err)
+	assertProblem(t, response, 401, CodeUnauthenticated)
+}
+
+func TestNewHandler_ErrorMappedByRegistry(t *testing.T) {
+	// === Given ===
+	registry := NewErrorRegistry()
+	RegisterError[testError](registry, 418, "TEAPOT")
+	handler := NewHandler(greet, testMiddleware(registry, false)...)
+
+	// === When ===
+	response, err := handler(context.Background(), getRequest(testAccountID, `{"name":"error"}`))
+
+	// === Then ===
+	assert.NoError(t, err)
+	problem := assertProblem(t, response, 418, "TEAPOT")
+	assert.Equal(t, "test error", problem.Detail)
+	assert.JSONEq(t, `{}`, string(problem.Details))
+}
+
+func TestNewHandler_UnmappedError(t *testing.T) {
+	// === Given ===
+	handler := NewHandler(greet, testMiddleware(NewErrorRegistry(), false)...)
+
+	// === When ===
+	response, err := handler(context.Background(), getRequest(testAccountID, `{"name":"error"}`))
+
+	// === Then ===
+	assert.NoError(t, err)
+	assertProblem(t, response, 500, CodeInternalError)
+}
+
+func TestNewHandler_MiddlewareOrder(t *testing.T) {
+	// === Given ===
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, request *Request) (events.LambdaFunctionURLResponse, error) {
+				calls = append(calls, name)
+				return next(ctx, request)
+			}
+		}
+	}
+	handler := NewHandler(greet, record("outer"), record("inner"), Decode(false))
+
+	// === When ===
+	_, err := handler(context.Background(), getRequest(testAccountID, `{"name":"Jake"}`))
+
+	// === Then ===
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"outer", "inner"}, calls)
+}
+
+func TestErrorRegistry_ExtendTakesPrecedence(t *testing.T) {
+	// === Given ===
+	registry := NewErrorRegistry()
+	RegisterError[testError](registry, 400, "TEST")
+	extended := registry.Extend()
+	RegisterError[testError](extended, 422, "TEST")
+
+	// === When ===
+	problem := extended.Problem(fmt.Errorf("wrapped: %w", testError{}))
+	parentProblem := registry.Problem(testError{})
+
+	// === Then ===
+	assert.Equal(t, 422, problem.Status)
+	assert.Equal(t, 400, parentProblem.Status)
+}
+
+func TestNewHandler_ProblemIncludesRequestID(t *testing.T) {
+	// === Given ===
+	handler := NewHandler(greet, testMiddleware(NewErrorRegistry(), false)...)
+	request := getRequest(testAccountID, `{"name":"error"}`)
+	request.RequestContext.RequestID = "c6af9ac6-7b61-11e6-9a41-93e8deadbeef"
+
+	// === When ===
+	response, err := handler(context.Background(), request)
+
+	// === Then ===
+	assert.NoError(t, err)
+	problem := assertProblem(t, response, 500, CodeInternalError)
+	assert.Equal(t, "c6af9ac6-7b61-11e6-9a41-93e8deadbeef", problem.RequestID)
+	assert.Equal(t, "about:blank", problem.Type)
+	assert.Equal(t, "Internal Server Error", problem.Title)
+}
@@ -0,0 +1,100 @@
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-lambda-go/events"
+	"log"
+)
+
+// MapErrors turns errors returned by the rest of the chain into problem responses, so that the Lambda runtime never sees
+// an error. It should be the outermost middleware.
+func MapErrors(registry *ErrorRegistry) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, request *Request) (events.LambdaFunctionURLResponse, error) {
+			response, err := next(ctx, request)
+			if err != nil {
+				problem := registry.Problem(err)
+				problem.RequestID = request.Event.RequestContext.RequestID
+				return problem.toResponse(), nil
+			}
+			return response, nil
+		}
+	}
+}
+
+// Logging logs every request and the error of every failed request
+func Logging(next Handler) Handler {
+	return func(ctx context.Context, request *Request) (events.LambdaFunctionURLResponse, error) {
+		accountID := callerAccountID(request.Event)
+		log.Printf("Received request from account ID %s: %s", accountID, request.Event.Body)
+
+		response, err := next(ctx, request)
+		if err != nil {
+			log.Print(RequestError{
+				AccountID:   accountID,
+				RequestBody: request.Event.Body,
+				Err:         err.Error(),
+			})
+		}
+		return response, err
+	}
+}
+
+// Authenticate sets the account ID of the request from the identity provided by the IAM authorizer of the Function URL
+func Authenticate(next Handler) Handler {
+	return func(ctx context.Context, request *Request) (events.LambdaFunctionURLResponse, error) {
+		accountID := callerAccountID(request.Event)
+		if accountID == "" {
+			return events.LambdaFunctionURLResponse{}, ErrUnauthenticated
+		}
+		request.Caller.AccountID = accountID
+		return next(ctx, request)
+	}
+}
+
+func callerAccountID(event events.LambdaFunctionURLRequest) string {
+	if event.RequestContext.Authorizer == nil || event.RequestContext.Authorizer.IAM == nil {
+		return ""
+	}
+	return event.RequestContext.Authorizer.IAM.AccountID
+}
+
+// DeadlineBudget gives the rest of the chain a context which expires before the Lambda deadline, leaving time to respond
+func DeadlineBudget(next Handler) Handler {
+	return func(ctx context.Context, request *Request) (events.LambdaFunctionURLResponse, error) {
+		budgetCtx, cancel, err := withDeadlineBudget(ctx)
+		if err != nil {
+			return events.LambdaFunctionURLResponse{}, err
+		}
+		defer cancel()
+		return next(budgetCtx, request)
+	}
+}
+
+// Decode unmarshals the request body into the operation's input. When optional is true, a request without a body is
+// decoded as the zero input.
+func Decode(optional bool) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, request *Request) (events.LambdaFunctionURLResponse, error) {
+			if len(request.Event.Body) > 0 || !optional {
+				err := json.Unmarshal([]byte(request.Event.Body), request.Input)
+				if err != nil {
+					return events.LambdaFunctionURLResponse{}, ParseError{Err: err}
+				}
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+// Validate validates the decoded input against its validate struct tags
+func Validate(next Handler) Handler {
+	return func(ctx context.Context, request *Request) (events.LambdaFunctionURLResponse, error) {
+		err := inputValidator.Struct(request.Input)
+		if err != nil {
+			return events.LambdaFunctionURLResponse{}, err
+		}
+		return next(ctx, request)
+	}
+}
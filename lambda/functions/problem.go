@@ -0,0 +1,71 @@
+package functions
+
+import (
+	"encoding/json"
+	"github.com/aws/aws-lambda-go/events"
+	"net/http"
+)
+
+// ProblemContentType is the media type of error responses, as defined by RFC 7807
+const ProblemContentType = "application/problem+json"
+
+// Stable codes of the errors of the framework itself
+const (
+	CodeInvalidJSON        = "INVALID_JSON"
+	CodeValidationFailed   = "VALIDATION_FAILED"
+	CodeUnauthenticated    = "UNAUTHENTICATED"
+	CodeForbidden          = "FORBIDDEN"
+	CodeServiceUnavailable = "SERVICE_UNAVAILABLE"
+	CodeTimeout            = "TIMEOUT"
+	CodeInternalError      = "INTERNAL_ERROR"
+)
+
+// Problem is the body of every error response, in the problem details format of RFC 7807. Clients should switch on
+// Code rather than on Status or Detail.
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Machine-readable code of the error, which is stable across releases
+	Code string `json:"code"`
+	// ID of the request which failed, for correlation with the service's logs
+	RequestID string `json:"requestId,omitempty"`
+	// Fields of the input which failed validation, for VALIDATION_FAILED
+	InvalidParams []InvalidParam `json:"invalidParams,omitempty"`
+	// Fields of the error, such as the account which has insufficient funds
+	Details json.RawMessage `json:"details,omitempty"`
+
+	headers map[string]string
+}
+
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// NewProblem returns a problem whose title is the description of its status, as RFC 7807 recommends for problems
+// without a more specific type
+func NewProblem(status int, code, detail string) Problem {
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+func (problem Problem) toResponse() events.LambdaFunctionURLResponse {
+	headers := map[string]string{
+		"Content-Type": ProblemContentType,
+	}
+	for name, value := range problem.headers {
+		headers[name] = value
+	}
+	return events.LambdaFunctionURLResponse{
+		StatusCode: problem.Status,
+		Headers:    headers,
+		Body:       MarshalOutput(problem),
+	}
+}
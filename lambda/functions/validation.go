@@ -0,0 +1,126 @@
+package functions
+
+import (
+	"errors"
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"reflect"
+	"strings"
+)
+
+var inputValidator *validator.Validate
+var translator ut.Translator
+
+func init() {
+	inputValidator = validator.New()
+
+	english := en.New()
+	uni := ut.New(english, english)
+	var ok bool
+	translator, ok = uni.GetTranslator("en")
+	if !ok {
+		panic("Failed to initialize translator!")
+	}
+	err := enTranslations.RegisterDefaultTranslations(inputValidator, translator)
+	if err != nil {
+		panic(err)
+	}
+
+	// Accept only the ISO 4217 currencies that accounts may hold
+	err = inputValidator.RegisterValidation("currency", func(field validator.FieldLevel) bool {
+		return internal.IsSupportedCurrency(field.Field().String())
+	})
+	if err != nil {
+		panic(err)
+	}
+	err = inputValidator.RegisterTranslation("currency", translator, func(translator ut.Translator) error {
+		return translator.Add("currency", "{0} must be a supported ISO 4217 currency code", false)
+	}, func(translator ut.Translator, fieldErr validator.FieldError) string {
+		message, _ := translator.T("currency", fieldErr.Field())
+		return message
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// Accept only the recurrence rules that schedules support
+	err = inputValidator.RegisterValidation("recurrence", func(field validator.FieldLevel) bool {
+		return internal.IsValidRecurrence(field.Field().String())
+	})
+	if err != nil {
+		panic(err)
+	}
+	err = inputValidator.RegisterTranslation("recurrence", translator, func(translator ut.Translator) error {
+		return translator.Add("recurrence", "{0} must be a supported RRULE, e.g. FREQ=MONTHLY;BYMONTHDAY=1", false)
+	}, func(translator ut.Translator, fieldErr validator.FieldError) string {
+		message, _ := translator.T("recurrence", fieldErr.Field())
+		return message
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// Accept only account types that may name a product
+	err = inputValidator.RegisterValidation("accounttype", func(field validator.FieldLevel) bool {
+		return internal.IsValidAccountType(field.Field().String())
+	})
+	if err != nil {
+		panic(err)
+	}
+	err = inputValidator.RegisterTranslation("accounttype", translator, func(translator ut.Translator) error {
+		return translator.Add("accounttype", "{0} must be lowercase letters, digits and hyphens, e.g. savings or high-yield-savings", false)
+	}, func(translator ut.Translator, fieldErr validator.FieldError) string {
+		message, _ := translator.T("accounttype", fieldErr.Field())
+		return message
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// Accept only the annual percentage yields that interest terms may pay
+	err = inputValidator.RegisterValidation("apy", func(field validator.FieldLevel) bool {
+		return internal.IsValidAPY(field.Field().String())
+	})
+	if err != nil {
+		panic(err)
+	}
+	err = inputValidator.RegisterTranslation("apy", translator, func(translator ut.Translator) error {
+		return translator.Add("apy", "{0} must be a non-negative decimal fraction, e.g. 0.0425 for 4.25%", false)
+	}, func(translator ut.Translator, fieldErr validator.FieldError) string {
+		message, _ := translator.T("apy", fieldErr.Field())
+		return message
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// Report invalid fields by the names that clients send them as
+	inputValidator.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+}
+
+func processValidationError(err error) (Problem, bool) {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return Problem{}, false
+	}
+
+	problem := NewProblem(400, CodeValidationFailed, "Invalid request")
+	for _, fieldErr := range validationErrs {
+		// The namespace is qualified by the name of the input type, e.g. TransferInput.amount
+		_, name, _ := strings.Cut(fieldErr.Namespace(), ".")
+		problem.InvalidParams = append(problem.InvalidParams, InvalidParam{
+			Name:   name,
+			Reason: fieldErr.Translate(translator),
+		})
+	}
+	return problem, true
+}
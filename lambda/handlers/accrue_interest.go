@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"log"
+)
+
+var AccrueInterest = functions.NewHandler(accrueInterest, middleware(errorRegistry, true, PermissionAccrueInterest)...)
+
+func accrueInterest(ctx context.Context, caller functions.Caller, input internal.AccrueInterestInput) (internal.AccrueInterestOutput, error) {
+	output, err := internal.AccrueInterest(ctx, accountManager, input)
+	if err != nil {
+		return internal.AccrueInterestOutput{}, err
+	}
+
+	log.Printf("Successfully accrued interest for %s on %d products on behalf of %s", output.Date, len(output.Products), caller.AccountID)
+	return output, nil
+}
+
+// ScheduledAccrueInterest accrues the interest of the previous day when invoked by an EventBridge schedule rather than
+// a caller
+func ScheduledAccrueInterest(ctx context.Context, _ events.CloudWatchEvent) error {
+	output, err := internal.AccrueInterest(ctx, accountManager, internal.AccrueInterestInput{})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Successfully accrued interest for %s on %d products", output.Date, len(output.Products))
+	return nil
+}
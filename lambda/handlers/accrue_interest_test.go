@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/golang/mock/gomock"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"github.com/jakepatzer/banking-service/lambda/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type accrueInterestTestSuite struct {
+	suite.Suite
+	ctrl               *gomock.Controller
+	mockAccountManager *mocks.MockAccountManager
+}
+
+func TestAccrueInterestSuite(t *testing.T) {
+	suite.Run(t, new(accrueInterestTestSuite))
+}
+
+func (suite *accrueInterestTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockAccountManager = mocks.NewMockAccountManager(suite.ctrl)
+}
+
+func (suite *accrueInterestTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *accrueInterestTestSuite) TestHandler_Success() {
+	// === Given ===
+	ctx := context.Background()
+	request := getRequest(testOperatorAccountID, `{"date": "2022-09-30"}`)
+
+	expectedAccrual := internal.ProductAccrual{AccountType: "savings", Accrued: 2, Posted: 2}
+	expectedOutput := internal.AccrueInterestOutput{
+		Date:     "2022-09-30",
+		Products: []internal.ProductAccrual{expectedAccrual},
+	}
+	responseBody, err := json.Marshal(expectedOutput)
+	assert.NoError(suite.T(), err)
+
+	// Only the products with interest terms are accrued
+	suite.mockAccountManager.EXPECT().ListProducts(ctx, internal.ListProductsInput{IncludeRetired: true}).Return(getInterestProducts(), nil)
+	suite.mockAccountManager.EXPECT().AccrueProductInterest(ctx, internal.AccrueProductInterestInput{
+		AccountType: "savings",
+		Date:        "2022-09-30",
+	}).Return(expectedAccrual, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := AccrueInterest(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+	assert.Equal(suite.T(), string(responseBody), response.Body)
+}
+
+func (suite *accrueInterestTestSuite) TestHandler_ErrorWhenDateIsInvalid() {
+	// === Given ===
+	ctx := context.Background()
+	request := getRequest(testOperatorAccountID, `{"date": "30/09/2022"}`)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := AccrueInterest(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+}
+
+func (suite *accrueInterestTestSuite) TestHandler_ErrorWhenCallerIsCustomer() {
+	// === Given ===
+	ctx := context.Background()
+	request := getRequest(testAccountID, "")
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := AccrueInterest(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 403, response.StatusCode)
+}
+
+func (suite *accrueInterestTestSuite) TestScheduledHandler_Success() {
+	// === Given ===
+	ctx := context.Background()
+	suite.mockAccountManager.EXPECT().ListProducts(ctx, internal.ListProductsInput{IncludeRetired: true}).Return(getInterestProducts(), nil)
+	suite.mockAccountManager.EXPECT().AccrueProductInterest(ctx, gomock.Any()).Return(internal.ProductAccrual{AccountType: "savings"}, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	err := ScheduledAccrueInterest(ctx, events.CloudWatchEvent{})
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *accrueInterestTestSuite) TestScheduledHandler_InternalError() {
+	// === Given ===
+	ctx := context.Background()
+	suite.mockAccountManager.EXPECT().ListProducts(ctx, internal.ListProductsInput{IncludeRetired: true}).Return(getInterestProducts(), nil)
+	suite.mockAccountManager.EXPECT().AccrueProductInterest(ctx, gomock.Any()).Return(internal.ProductAccrual{}, errors.New("ERROR"))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	err := ScheduledAccrueInterest(ctx, events.CloudWatchEvent{})
+
+	// === Then ===
+	assert.Error(suite.T(), err)
+}
+
+func (suite *accrueInterestTestSuite) TestScheduledHandler_ErrorWhenProductsCannotBeListed() {
+	// === Given ===
+	ctx := context.Background()
+	suite.mockAccountManager.EXPECT().ListProducts(ctx, internal.ListProductsInput{IncludeRetired: true}).Return(internal.ListProductsOutput{}, errors.New("ERROR"))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	err := ScheduledAccrueInterest(ctx, events.CloudWatchEvent{})
+
+	// === Then ===
+	assert.Error(suite.T(), err)
+}
+
+func getInterestProducts() internal.ListProductsOutput {
+	interest := getInterestTerms()
+	return internal.ListProductsOutput{Products: []internal.Product{
+		{AccountType: "checking"},
+		{AccountType: "savings", Interest: &interest},
+	}}
+}
+
+func getInterestTerms() internal.InterestTerms {
+	return internal.InterestTerms{
+		APY:         "0.0425",
+		DayCount:    internal.DayCountActual365,
+		Compounding: internal.CompoundingMonthly,
+	}
+}
@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"context"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"log"
+)
+
+var CancelSchedule = functions.NewHandler(cancelSchedule, middleware(errorRegistry, false, PermissionCancelSchedule)...)
+
+func cancelSchedule(ctx context.Context, caller functions.Caller, input internal.CancelScheduleInput) (functions.NoOutput, error) {
+	err := accountManager.CancelSchedule(ctx, caller.AccountID, input)
+	if err != nil {
+		return functions.NoOutput{}, err
+	}
+
+	log.Printf("Successfully cancelled schedule %s of %s", input.ScheduleID, caller.AccountID)
+	return functions.NoOutput{}, nil
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/golang/mock/gomock"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"github.com/jakepatzer/banking-service/lambda/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type cancelScheduleTestSuite struct {
+	suite.Suite
+	ctrl               *gomock.Controller
+	mockAccountManager *mocks.MockAccountManager
+}
+
+func TestCancelScheduleSuite(t *testing.T) {
+	suite.Run(t, new(cancelScheduleTestSuite))
+}
+
+func (suite *cancelScheduleTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockAccountManager = mocks.NewMockAccountManager(suite.ctrl)
+}
+
+func (suite *cancelScheduleTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *cancelScheduleTestSuite) TestHandler_Success() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.CancelScheduleInput{
+		ScheduleID: "0123456789abcdef",
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().CancelSchedule(ctx, testAccountID, expectedInput).Return(nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := CancelSchedule(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+}
+
+func (suite *cancelScheduleTestSuite) TestHandler_ValidationErrorWhenScheduleIDIsMissing() {
+	// === Given ===
+	ctx := context.Background()
+	request := getRequest(testAccountID, "{}")
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := CancelSchedule(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+}
+
+func (suite *cancelScheduleTestSuite) TestHandler_ScheduleNotFoundError() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.CancelScheduleInput{
+		ScheduleID: "0123456789abcdef",
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().CancelSchedule(ctx, testAccountID, expectedInput).Return(internal.ScheduleNotFoundError{
+		ScheduleID: "0123456789abcdef",
+	})
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := CancelSchedule(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 404, response.StatusCode)
+	assert.Contains(suite.T(), response.Body, internal.CodeScheduleNotFound)
+}
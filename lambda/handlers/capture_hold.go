@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"context"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"log"
+)
+
+var CaptureHold = functions.NewHandler(captureHold, middleware(errorRegistry, false, PermissionCaptureHold)...)
+
+func captureHold(ctx context.Context, caller functions.Caller, input internal.CaptureHoldInput) (internal.CaptureHoldOutput, error) {
+	output, err := accountManager.CaptureHold(ctx, input)
+	if err != nil {
+		return internal.CaptureHoldOutput{}, err
+	}
+
+	log.Printf("Successfully captured %s of hold %s on %s:%s on behalf of %s",
+		internal.FormatAmount(output.Transaction.Amount, output.Transaction.Currency),
+		input.HoldID,
+		input.AccountID,
+		input.AccountType,
+		caller.AccountID)
+	return output, nil
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/golang/mock/gomock"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"github.com/jakepatzer/banking-service/lambda/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type captureHoldTestSuite struct {
+	suite.Suite
+	ctrl               *gomock.Controller
+	mockAccountManager *mocks.MockAccountManager
+}
+
+func TestCaptureHoldSuite(t *testing.T) {
+	suite.Run(t, new(captureHoldTestSuite))
+}
+
+func (suite *captureHoldTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockAccountManager = mocks.NewMockAccountManager(suite.ctrl)
+}
+
+func (suite *captureHoldTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *captureHoldTestSuite) TestHandler_Success() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.CaptureHoldInput{
+		AccountID:   testAccountID,
+		AccountType: "checking",
+		HoldID:      "0123456789abcdef",
+		Amount:      aws.Int(3),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testOperatorAccountID, string(requestBody))
+
+	expectedOutput := internal.CaptureHoldOutput{
+		Transaction: internal.Transaction{
+			TransactionID: testTransactionID,
+			Type:          internal.TransactionTypeCapture,
+			Amount:        3,
+			Src: &internal.TransactionParty{
+				AccountID:   testAccountID,
+				AccountType: "checking",
+				Balance:     2,
+			},
+			Dest: &internal.TransactionParty{
+				AccountID:   "987654321",
+				AccountType: "merchant",
+				Balance:     3,
+			},
+			ExternalReference: "auth-0001",
+			HoldID:            "0123456789abcdef",
+		},
+		Hold: internal.Hold{
+			HoldID:            "0123456789abcdef",
+			Amount:            2,
+			DestAccountID:     "987654321",
+			DestAccountType:   "merchant",
+			ExternalReference: "auth-0001",
+		},
+	}
+	responseBody, err := json.Marshal(expectedOutput)
+	assert.NoError(suite.T(), err)
+
+	suite.mockAccountManager.EXPECT().CaptureHold(ctx, expectedInput).Return(expectedOutput, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := CaptureHold(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+	assert.Equal(suite.T(), string(responseBody), response.Body)
+}
+
+func (suite *captureHoldTestSuite) TestHandler_ErrorWhenCallerIsCustomer() {
+	// === Given ===
+	ctx := context.Background()
+	requestBody, err := json.Marshal(internal.CaptureHoldInput{
+		AccountID:   testAccountID,
+		AccountType: "checking",
+		HoldID:      "0123456789abcdef",
+	})
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := CaptureHold(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 403, response.StatusCode)
+}
+
+func (suite *captureHoldTestSuite) TestHandler_CaptureExceedsHoldError() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.CaptureHoldInput{
+		AccountID:   testAccountID,
+		AccountType: "checking",
+		HoldID:      "0123456789abcdef",
+		Amount:      aws.Int(7),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testOperatorAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().CaptureHold(ctx, expectedInput).Return(internal.CaptureHoldOutput{}, internal.CaptureExceedsHoldError{
+		HoldID:     "0123456789abcdef",
+		HeldAmount: 5,
+	})
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := CaptureHold(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+	assert.Contains(suite.T(), response.Body, internal.CodeCaptureExceedsHold)
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"context"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"log"
+)
+
+// CloseAccount shares the error responses of Transfer, as the only account which may be missing is the destination
+var CloseAccount = functions.NewHandler(closeAccount, middleware(transferErrorRegistry, false, PermissionCloseAccount)...)
+
+func closeAccount(ctx context.Context, caller functions.Caller, input internal.CloseAccountInput) (internal.CloseAccountOutput, error) {
+	output, err := accountManager.CloseAccount(ctx, caller.AccountID, input)
+	if err != nil {
+		return internal.CloseAccountOutput{}, err
+	}
+
+	if output.Transaction == nil {
+		log.Printf("Account %s:%s is already closed or does not exist", caller.AccountID, input.AccountType)
+		return output, nil
+	}
+	log.Printf("Successfully closed account %s:%s, sweeping %s to %s:%s",
+		caller.AccountID,
+		input.AccountType,
+		internal.FormatAmount(output.Transaction.Amount, output.Transaction.Src.Currency),
+		input.DestAccountID,
+		input.DestAccountType)
+	return output, nil
+}
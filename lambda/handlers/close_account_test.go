@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/golang/mock/gomock"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"github.com/jakepatzer/banking-service/lambda/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type closeAccountTestSuite struct {
+	suite.Suite
+	ctrl               *gomock.Controller
+	mockAccountManager *mocks.MockAccountManager
+}
+
+func TestCloseAccountSuite(t *testing.T) {
+	suite.Run(t, new(closeAccountTestSuite))
+}
+
+func (suite *closeAccountTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockAccountManager = mocks.NewMockAccountManager(suite.ctrl)
+}
+
+func (suite *closeAccountTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *closeAccountTestSuite) TestHandler_Success() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.CloseAccountInput{
+		AccountType:     "savings",
+		DestAccountID:   "080785581916",
+		DestAccountType: "checking",
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	expectedOutput := internal.CloseAccountOutput{
+		Transaction: &internal.Transaction{
+			TransactionID: "tx-1",
+			Type:          internal.TransactionTypeClose,
+			Amount:        10,
+			Src:           &internal.TransactionParty{AccountID: testAccountID, AccountType: "savings", Currency: "USD", Balance: 0},
+			Dest:          &internal.TransactionParty{AccountID: "080785581916", AccountType: "checking", Currency: "USD", Balance: 10},
+		},
+	}
+	suite.mockAccountManager.EXPECT().CloseAccount(ctx, testAccountID, expectedInput).Return(expectedOutput, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := CloseAccount(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+	var output internal.CloseAccountOutput
+	assert.NoError(suite.T(), json.Unmarshal([]byte(response.Body), &output))
+	assert.Equal(suite.T(), expectedOutput.Transaction.TransactionID, output.Transaction.TransactionID)
+}
+
+func (suite *closeAccountTestSuite) TestHandler_SuccessWhenAccountDoesNotExist() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.CloseAccountInput{
+		AccountType:     "savings",
+		DestAccountID:   "080785581916",
+		DestAccountType: "checking",
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().CloseAccount(ctx, testAccountID, expectedInput).Return(internal.CloseAccountOutput{}, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := CloseAccount(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+	assert.JSONEq(suite.T(), `{}`, response.Body)
+}
+
+func (suite *closeAccountTestSuite) TestHandler_ErrorWhenDestinationIsMissing() {
+	// === Given ===
+	ctx := context.Background()
+	requestBody, err := json.Marshal(internal.CloseAccountInput{AccountType: "savings"})
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := CloseAccount(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+}
+
+func (suite *closeAccountTestSuite) TestHandler_HoldsOutstandingError() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.CloseAccountInput{
+		AccountType:     "savings",
+		DestAccountID:   "080785581916",
+		DestAccountType: "checking",
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().CloseAccount(ctx, testAccountID, expectedInput).Return(internal.CloseAccountOutput{}, internal.HoldsOutstandingError{
+		AccountID:   testAccountID,
+		AccountType: "savings",
+	})
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := CloseAccount(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+	assert.Contains(suite.T(), response.Body, `"code":"HOLDS_OUTSTANDING"`)
+}
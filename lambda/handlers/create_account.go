@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"context"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"log"
+)
+
+var CreateAccount = functions.NewHandler(createAccount, middleware(errorRegistry, false, PermissionCreateAccount)...)
+
+func createAccount(ctx context.Context, caller functions.Caller, input internal.CreateAccountInput) (functions.NoOutput, error) {
+	// Customers open accounts without an overdraft, which only admins may extend
+	if input.OverdraftLimit != 0 && !caller.Can(PermissionSetOverdraftLimit) {
+		return functions.NoOutput{}, functions.ErrForbidden
+	}
+
+	err := accountManager.CreateAccount(ctx, caller.AccountID, input)
+	if err != nil {
+		return functions.NoOutput{}, err
+	}
+
+	log.Printf("Successfully created account %s:%s with balance %d", caller.AccountID, input.AccountType, *input.InitialBalance)
+	return functions.NoOutput{}, nil
+}
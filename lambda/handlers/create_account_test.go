@@ -1,10 +1,9 @@
-package main
+package handlers
 
 import (
 	"context"
 	"encoding/json"
 	"errors"
-	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/golang/mock/gomock"
 	"github.com/jakepatzer/banking-service/lambda/internal"
@@ -14,17 +13,13 @@ import (
 	"testing"
 )
 
-const (
-	testAccountID = "123456789"
-)
-
 type createAccountTestSuite struct {
 	suite.Suite
 	ctrl               *gomock.Controller
 	mockAccountManager *mocks.MockAccountManager
 }
 
-func TestSuite(t *testing.T) {
+func TestCreateAccountSuite(t *testing.T) {
 	suite.Run(t, new(createAccountTestSuite))
 }
 
@@ -52,7 +47,46 @@ func (suite *createAccountTestSuite) TestHandler_Success() {
 	accountManager = suite.mockAccountManager
 
 	// === When ===
-	response, err := handler(ctx, request)
+	response, err := CreateAccount(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+}
+
+func (suite *createAccountTestSuite) TestHandler_ErrorWhenCustomerSetsOverdraftLimit() {
+	// === Given ===
+	ctx := context.Background()
+	request := getRequest(testAccountID, `{"accountType":"checking","initialBalance":0,"overdraftLimit":500}`)
+
+	// The account manager must not be called
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := CreateAccount(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 403, response.StatusCode)
+}
+
+func (suite *createAccountTestSuite) TestHandler_AdminSetsOverdraftLimit() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.CreateAccountInput{
+		AccountType:    "checking",
+		InitialBalance: aws.Int(0),
+		OverdraftLimit: 500,
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAdminAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().CreateAccount(ctx, testAdminAccountID, expectedInput).Return(nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := CreateAccount(ctx, request)
 
 	// === Then ===
 	assert.NoError(suite.T(), err)
@@ -65,7 +99,7 @@ func (suite *createAccountTestSuite) TestHandler_UnmarshalRequestError() {
 	request := getRequest(testAccountID, "}invalidJSON{")
 
 	// === When ===
-	response, err := handler(ctx, request)
+	response, err := CreateAccount(ctx, request)
 
 	// === Then ===
 	assert.NoError(suite.T(), err)
@@ -84,7 +118,7 @@ func (suite *createAccountTestSuite) TestHandler_ErrorWhenInitialBalanceIsInvali
 	request := getRequest(testAccountID, string(requestBody))
 
 	// === When ===
-	response, err := handler(ctx, request)
+	response, err := CreateAccount(ctx, request)
 
 	// === Then ===
 	assert.NoError(suite.T(), err)
@@ -102,7 +136,7 @@ func (suite *createAccountTestSuite) TestHandler_ErrorWhenInitialBalanceIsUndefi
 	request := getRequest(testAccountID, string(requestBody))
 
 	// === When ===
-	response, err := handler(ctx, request)
+	response, err := CreateAccount(ctx, request)
 
 	// === Then ===
 	assert.NoError(suite.T(), err)
@@ -120,11 +154,54 @@ func (suite *createAccountTestSuite) TestHandler_ErrorWhenAccountTypeIsUndefined
 	request := getRequest(testAccountID, string(requestBody))
 
 	// === When ===
-	response, err := handler(ctx, request)
+	response, err := CreateAccount(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+}
+
+func (suite *createAccountTestSuite) TestHandler_ErrorWhenAccountTypeIsInvalid() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.CreateAccountInput{
+		AccountType:    "Savings",
+		InitialBalance: aws.Int(5),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	// === When ===
+	response, err := CreateAccount(ctx, request)
 
 	// === Then ===
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), 400, response.StatusCode)
+	assert.Contains(suite.T(), response.Body, "accountType must be lowercase letters, digits and hyphens")
+}
+
+func (suite *createAccountTestSuite) TestHandler_ErrorWhenProductNotFound() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.CreateAccountInput{
+		AccountType:    "svngs",
+		InitialBalance: aws.Int(5),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().CreateAccount(ctx, testAccountID, expectedInput).Return(internal.ProductNotFoundError{AccountType: "svngs"})
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := CreateAccount(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+	assert.Contains(suite.T(), response.Body, internal.CodeProductNotFound)
 }
 
 func (suite *createAccountTestSuite) TestHandler_ErrorWhenAccountAlreadyExists() {
@@ -142,7 +219,7 @@ func (suite *createAccountTestSuite) TestHandler_ErrorWhenAccountAlreadyExists()
 	accountManager = suite.mockAccountManager
 
 	// === When ===
-	response, err := handler(ctx, request)
+	response, err := CreateAccount(ctx, request)
 
 	// === Then ===
 	assert.NoError(suite.T(), err)
@@ -164,22 +241,9 @@ func (suite *createAccountTestSuite) TestHandler_InternalError() {
 	accountManager = suite.mockAccountManager
 
 	// === When ===
-	response, err := handler(ctx, request)
+	response, err := CreateAccount(ctx, request)
 
 	// === Then ===
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), 500, response.StatusCode)
 }
-
-func getRequest(accountID, requestBody string) events.LambdaFunctionURLRequest {
-	return events.LambdaFunctionURLRequest{
-		RequestContext: events.LambdaFunctionURLRequestContext{
-			Authorizer: &events.LambdaFunctionURLRequestContextAuthorizerDescription{
-				IAM: &events.LambdaFunctionURLRequestContextAuthorizerIAMDescription{
-					AccountID: accountID,
-				},
-			},
-		},
-		Body: requestBody,
-	}
-}
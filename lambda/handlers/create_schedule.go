@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"context"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"log"
+)
+
+var CreateSchedule = functions.NewHandler(createSchedule, middleware(transferErrorRegistry, false, PermissionCreateSchedule)...)
+
+func createSchedule(ctx context.Context, caller functions.Caller, input internal.CreateScheduleInput) (internal.CreateScheduleOutput, error) {
+	output, err := accountManager.CreateSchedule(ctx, caller.AccountID, input)
+	if err != nil {
+		return internal.CreateScheduleOutput{}, err
+	}
+
+	log.Printf("Successfully created schedule %s of %s from %s:%s to %s:%s starting at %s with recurrence %q",
+		output.Schedule.ScheduleID,
+		internal.FormatAmount(output.Schedule.Amount, output.Schedule.Currency),
+		caller.AccountID,
+		input.SrcAccountType,
+		input.DestAccountID,
+		input.DestAccountType,
+		output.Schedule.StartAt,
+		input.Recurrence)
+	return output, nil
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/golang/mock/gomock"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"github.com/jakepatzer/banking-service/lambda/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type createScheduleTestSuite struct {
+	suite.Suite
+	ctrl               *gomock.Controller
+	mockAccountManager *mocks.MockAccountManager
+}
+
+func TestCreateScheduleSuite(t *testing.T) {
+	suite.Run(t, new(createScheduleTestSuite))
+}
+
+func (suite *createScheduleTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockAccountManager = mocks.NewMockAccountManager(suite.ctrl)
+}
+
+func (suite *createScheduleTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *createScheduleTestSuite) TestHandler_Success() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := getCreateScheduleInput()
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	expectedOutput := internal.CreateScheduleOutput{
+		Schedule: getSchedule(),
+	}
+	responseBody, err := json.Marshal(expectedOutput)
+	assert.NoError(suite.T(), err)
+
+	suite.mockAccountManager.EXPECT().CreateSchedule(ctx, testAccountID, expectedInput).Return(expectedOutput, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := CreateSchedule(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+	assert.Equal(suite.T(), string(responseBody), response.Body)
+}
+
+func (suite *createScheduleTestSuite) TestHandler_ValidationErrorWhenRecurrenceIsUnsupported() {
+	// === Given ===
+	ctx := context.Background()
+	input := getCreateScheduleInput()
+	input.Recurrence = "FREQ=HOURLY"
+	requestBody, err := json.Marshal(input)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := CreateSchedule(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+	assert.Contains(suite.T(), response.Body, "recurrence must be a supported RRULE")
+}
+
+func (suite *createScheduleTestSuite) TestHandler_ValidationErrorWhenAmountIsMissing() {
+	// === Given ===
+	ctx := context.Background()
+	input := getCreateScheduleInput()
+	input.Amount = nil
+	requestBody, err := json.Marshal(input)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := CreateSchedule(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+}
+
+func (suite *createScheduleTestSuite) TestHandler_InternalError() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := getCreateScheduleInput()
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().CreateSchedule(ctx, testAccountID, expectedInput).Return(internal.CreateScheduleOutput{}, errors.New("ERROR"))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := CreateSchedule(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 500, response.StatusCode)
+}
+
+func getCreateScheduleInput() internal.CreateScheduleInput {
+	startAt := time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC)
+	return internal.CreateScheduleInput{
+		SrcAccountType:  "checking",
+		DestAccountID:   "987654321",
+		DestAccountType: "savings",
+		Amount:          aws.Int(5),
+		StartAt:         &startAt,
+		Recurrence:      "FREQ=MONTHLY;BYMONTHDAY=1",
+	}
+}
+
+func getSchedule() internal.Schedule {
+	nextRunAt := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	return internal.Schedule{
+		ScheduleID:      "0123456789abcdef",
+		AccountID:       testAccountID,
+		SrcAccountType:  "checking",
+		DestAccountID:   "987654321",
+		DestAccountType: "savings",
+		Amount:          5,
+		Recurrence:      "FREQ=MONTHLY;BYMONTHDAY=1",
+		StartAt:         time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC),
+		Status:          internal.ScheduleStatusActive,
+		Occurrences:     1,
+		NextRunAt:       &nextRunAt,
+	}
+}
@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"context"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"log"
+)
+
+var DeleteAccount = functions.NewHandler(deleteAccount, middleware(errorRegistry, false, PermissionDeleteAccount)...)
+
+func deleteAccount(ctx context.Context, caller functions.Caller, input internal.DeleteAccountInput) (functions.NoOutput, error) {
+	err := accountManager.DeleteAccount(ctx, caller.AccountID, input)
+	if err != nil {
+		return functions.NoOutput{}, err
+	}
+
+	log.Printf("Successfully deleted account %s:%s", caller.AccountID, input.AccountType)
+	return functions.NoOutput{}, nil
+}
@@ -1,10 +1,9 @@
-package main
+package handlers
 
 import (
 	"context"
 	"encoding/json"
 	"errors"
-	"github.com/aws/aws-lambda-go/events"
 	"github.com/golang/mock/gomock"
 	"github.com/jakepatzer/banking-service/lambda/internal"
 	"github.com/jakepatzer/banking-service/lambda/mocks"
@@ -13,17 +12,13 @@ import (
 	"testing"
 )
 
-const (
-	testAccountID = "123456789"
-)
-
 type deleteAccountTestSuite struct {
 	suite.Suite
 	ctrl               *gomock.Controller
 	mockAccountManager *mocks.MockAccountManager
 }
 
-func TestSuite(t *testing.T) {
+func TestDeleteAccountSuite(t *testing.T) {
 	suite.Run(t, new(deleteAccountTestSuite))
 }
 
@@ -50,7 +45,7 @@ func (suite *deleteAccountTestSuite) TestHandler_Success() {
 	accountManager = suite.mockAccountManager
 
 	// === When ===
-	response, err := handler(ctx, request)
+	response, err := DeleteAccount(ctx, request)
 
 	// === Then ===
 	assert.NoError(suite.T(), err)
@@ -63,7 +58,7 @@ func (suite *deleteAccountTestSuite) TestHandler_UnmarshalRequestError() {
 	request := getRequest(testAccountID, "}invalidJSON{")
 
 	// === When ===
-	response, err := handler(ctx, request)
+	response, err := DeleteAccount(ctx, request)
 
 	// === Then ===
 	assert.NoError(suite.T(), err)
@@ -79,7 +74,7 @@ func (suite *deleteAccountTestSuite) TestHandler_ErrorWhenAccountTypeIsUndefined
 	request := getRequest(testAccountID, string(requestBody))
 
 	// === When ===
-	response, err := handler(ctx, request)
+	response, err := DeleteAccount(ctx, request)
 
 	// === Then ===
 	assert.NoError(suite.T(), err)
@@ -100,7 +95,7 @@ func (suite *deleteAccountTestSuite) TestHandler_ErrorWhenAccountHasNonZeroBalan
 	accountManager = suite.mockAccountManager
 
 	// === When ===
-	response, err := handler(ctx, request)
+	response, err := DeleteAccount(ctx, request)
 
 	// === Then ===
 	assert.NoError(suite.T(), err)
@@ -121,22 +116,9 @@ func (suite *deleteAccountTestSuite) TestHandler_InternalError() {
 	accountManager = suite.mockAccountManager
 
 	// === When ===
-	response, err := handler(ctx, request)
+	response, err := DeleteAccount(ctx, request)
 
 	// === Then ===
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), 500, response.StatusCode)
 }
-
-func getRequest(accountID, requestBody string) events.LambdaFunctionURLRequest {
-	return events.LambdaFunctionURLRequest{
-		RequestContext: events.LambdaFunctionURLRequestContext{
-			Authorizer: &events.LambdaFunctionURLRequestContextAuthorizerDescription{
-				IAM: &events.LambdaFunctionURLRequestContextAuthorizerIAMDescription{
-					AccountID: accountID,
-				},
-			},
-		},
-		Body: requestBody,
-	}
-}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"context"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"log"
+)
+
+var Deposit = functions.NewHandler(deposit, middleware(errorRegistry, false, PermissionDeposit)...)
+
+func deposit(ctx context.Context, caller functions.Caller, input internal.DepositInput) (internal.DepositOutput, error) {
+	output, err := accountManager.Deposit(ctx, input)
+	if err != nil {
+		return internal.DepositOutput{}, err
+	}
+
+	log.Printf("Successfully deposited %s to %s:%s with reference %s on behalf of %s",
+		internal.FormatAmount(output.Transaction.Amount, output.Transaction.Currency),
+		input.AccountID,
+		input.AccountType,
+		input.ExternalReference,
+		caller.AccountID)
+	return output, nil
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/golang/mock/gomock"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"github.com/jakepatzer/banking-service/lambda/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type depositTestSuite struct {
+	suite.Suite
+	ctrl               *gomock.Controller
+	mockAccountManager *mocks.MockAccountManager
+}
+
+func TestDepositSuite(t *testing.T) {
+	suite.Run(t, new(depositTestSuite))
+}
+
+func (suite *depositTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockAccountManager = mocks.NewMockAccountManager(suite.ctrl)
+}
+
+func (suite *depositTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *depositTestSuite) TestHandler_Success() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.DepositInput{
+		AccountID:         testAccountID,
+		AccountType:       "checking",
+		Amount:            aws.Int(5),
+		ExternalReference: "wire-0001",
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testOperatorAccountID, string(requestBody))
+
+	expectedOutput := internal.DepositOutput{
+		Transaction: internal.Transaction{
+			TransactionID: testTransactionID,
+			Type:          internal.TransactionTypeDeposit,
+			Amount:        5,
+			Dest: &internal.TransactionParty{
+				AccountID:   testAccountID,
+				AccountType: "checking",
+				Balance:     5,
+			},
+			ExternalReference: "wire-0001",
+		},
+	}
+	responseBody, err := json.Marshal(expectedOutput)
+	assert.NoError(suite.T(), err)
+
+	suite.mockAccountManager.EXPECT().Deposit(ctx, expectedInput).Return(expectedOutput, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Deposit(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+	assert.Equal(suite.T(), string(responseBody), response.Body)
+}
+
+func (suite *depositTestSuite) TestHandler_ErrorWhenCallerIsCustomer() {
+	// === Given ===
+	ctx := context.Background()
+	requestBody, err := json.Marshal(internal.DepositInput{
+		AccountID:         testAccountID,
+		AccountType:       "checking",
+		Amount:            aws.Int(5),
+		ExternalReference: "wire-0001",
+	})
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Deposit(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 403, response.StatusCode)
+}
+
+func (suite *depositTestSuite) TestHandler_ErrorWhenExternalReferenceIsUndefined() {
+	// === Given ===
+	ctx := context.Background()
+	requestBody, err := json.Marshal(internal.DepositInput{
+		AccountID:   testAccountID,
+		AccountType: "checking",
+		Amount:      aws.Int(5),
+	})
+	assert.NoError(suite.T(), err)
+	request := getRequest(testOperatorAccountID, string(requestBody))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Deposit(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+}
+
+func (suite *depositTestSuite) TestHandler_AccountDoesNotExistError() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.DepositInput{
+		AccountID:         testAccountID,
+		AccountType:       "checking",
+		Amount:            aws.Int(5),
+		ExternalReference: "wire-0001",
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testOperatorAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().Deposit(ctx, expectedInput).Return(internal.DepositOutput{}, internal.AccountDoesNotExistError{
+		AccountID:   testAccountID,
+		AccountType: "checking",
+	})
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Deposit(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+}
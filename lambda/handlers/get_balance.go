@@ -0,0 +1,13 @@
+package handlers
+
+import (
+	"context"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+)
+
+var GetBalance = functions.NewHandler(getBalance, middleware(errorRegistry, false, PermissionGetBalance)...)
+
+func getBalance(ctx context.Context, caller functions.Caller, input internal.GetBalanceInput) (internal.GetBalanceOutput, error) {
+	return accountManager.GetBalance(ctx, caller.AccountID, input)
+}
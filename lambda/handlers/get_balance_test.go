@@ -1,10 +1,9 @@
-package main
+package handlers
 
 import (
 	"context"
 	"encoding/json"
 	"errors"
-	"github.com/aws/aws-lambda-go/events"
 	"github.com/golang/mock/gomock"
 	"github.com/jakepatzer/banking-service/lambda/internal"
 	"github.com/jakepatzer/banking-service/lambda/mocks"
@@ -13,17 +12,13 @@ import (
 	"testing"
 )
 
-const (
-	testAccountID = "123456789"
-)
-
 type getBalanceTestSuite struct {
 	suite.Suite
 	ctrl               *gomock.Controller
 	mockAccountManager *mocks.MockAccountManager
 }
 
-func TestSuite(t *testing.T) {
+func TestGetBalanceSuite(t *testing.T) {
 	suite.Run(t, new(getBalanceTestSuite))
 }
 
@@ -56,7 +51,7 @@ func (suite *getBalanceTestSuite) TestHandler_Success() {
 	accountManager = suite.mockAccountManager
 
 	// === When ===
-	response, err := handler(ctx, request)
+	response, err := GetBalance(ctx, request)
 
 	// === Then ===
 	assert.NoError(suite.T(), err)
@@ -70,7 +65,7 @@ func (suite *getBalanceTestSuite) TestHandler_UnmarshalRequestError() {
 	request := getRequest(testAccountID, "}invalidJSON{")
 
 	// === When ===
-	response, err := handler(ctx, request)
+	response, err := GetBalance(ctx, request)
 
 	// === Then ===
 	assert.NoError(suite.T(), err)
@@ -86,7 +81,7 @@ func (suite *getBalanceTestSuite) TestHandler_ErrorWhenAccountTypeIsUndefined()
 	request := getRequest(testAccountID, string(requestBody))
 
 	// === When ===
-	response, err := handler(ctx, request)
+	response, err := GetBalance(ctx, request)
 
 	// === Then ===
 	assert.NoError(suite.T(), err)
@@ -107,7 +102,7 @@ func (suite *getBalanceTestSuite) TestHandler_ErrorWhenAccountDoesNotExist() {
 	accountManager = suite.mockAccountManager
 
 	// === When ===
-	response, err := handler(ctx, request)
+	response, err := GetBalance(ctx, request)
 
 	// === Then ===
 	assert.NoError(suite.T(), err)
@@ -128,22 +123,9 @@ func (suite *getBalanceTestSuite) TestHandler_InternalError() {
 	accountManager = suite.mockAccountManager
 
 	// === When ===
-	response, err := handler(ctx, request)
+	response, err := GetBalance(ctx, request)
 
 	// === Then ===
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), 500, response.StatusCode)
 }
-
-func getRequest(accountID, requestBody string) events.LambdaFunctionURLRequest {
-	return events.LambdaFunctionURLRequest{
-		RequestContext: events.LambdaFunctionURLRequestContext{
-			Authorizer: &events.LambdaFunctionURLRequestContextAuthorizerDescription{
-				IAM: &events.LambdaFunctionURLRequestContextAuthorizerIAMDescription{
-					AccountID: accountID,
-				},
-			},
-		},
-		Body: requestBody,
-	}
-}
@@ -0,0 +1,128 @@
+// Package handlers contains the Lambda Function URL handlers for each of the banking operations. The handlers are
+// shared by the Lambda functions and the local HTTP server.
+package handlers
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"log"
+	"os"
+	"time"
+)
+
+var accountManager internal.AccountManager
+
+// How long the roles of a caller are cached by each Lambda instance
+const roleCacheTTL = 1 * time.Minute
+
+// roleStore looks up the roles of callers. By default no caller has any roles beyond customer.
+var roleStore = internal.NewStaticRoleStore(nil)
+
+// currentRoleStore delegates to roleStore, so that SetRoleStore applies to handlers which were created before it was
+// called
+type currentRoleStore struct{}
+
+func (currentRoleStore) GetRoles(ctx context.Context, accountID string) ([]internal.Role, error) {
+	return roleStore.GetRoles(ctx, accountID)
+}
+
+// errorRegistry maps the errors of the account manager to responses, consistently across every handler
+var errorRegistry = functions.NewErrorRegistry()
+
+func init() {
+	functions.RegisterError[internal.AccountAlreadyExistsError](errorRegistry, 400, internal.CodeAccountAlreadyExists)
+	functions.RegisterError[internal.NonZeroBalanceError](errorRegistry, 400, internal.CodeNonZeroBalance)
+	functions.RegisterError[internal.InsufficientFundsError](errorRegistry, 400, internal.CodeInsufficientFunds)
+	functions.RegisterError[internal.AccountDoesNotExistError](errorRegistry, 400, internal.CodeAccountNotFound)
+	functions.RegisterError[internal.SourceAccountDoesNotExistError](errorRegistry, 404, internal.CodeSourceAccountNotFound)
+	functions.RegisterError[internal.TransactionConflictError](errorRegistry, 409, internal.CodeTransactionConflict)
+	functions.RegisterError[internal.IdempotencyKeyConflictError](errorRegistry, 409, internal.CodeIdempotencyKeyConflict)
+	functions.RegisterError[internal.CurrencyMismatchError](errorRegistry, 400, internal.CodeCurrencyMismatch)
+	functions.RegisterError[internal.ConversionRequiredError](errorRegistry, 400, internal.CodeConversionRequired)
+	functions.RegisterError[internal.UnexpectedConversionError](errorRegistry, 400, internal.CodeUnexpectedConversion)
+	functions.RegisterError[internal.RateNotAvailableError](errorRegistry, 400, internal.CodeRateNotAvailable)
+	functions.RegisterError[internal.ConversionTooSmallError](errorRegistry, 400, internal.CodeConversionTooSmall)
+	functions.RegisterError[internal.QuoteNotFoundError](errorRegistry, 400, internal.CodeQuoteNotFound)
+	functions.RegisterError[internal.QuoteMismatchError](errorRegistry, 400, internal.CodeQuoteMismatch)
+	functions.RegisterError[internal.HoldNotFoundError](errorRegistry, 400, internal.CodeHoldNotFound)
+	functions.RegisterError[internal.CaptureExceedsHoldError](errorRegistry, 400, internal.CodeCaptureExceedsHold)
+	functions.RegisterError[internal.ScheduleNotFoundError](errorRegistry, 404, internal.CodeScheduleNotFound)
+	functions.RegisterError[internal.ProductNotFoundError](errorRegistry, 400, internal.CodeProductNotFound)
+	functions.RegisterError[internal.ProductRetiredError](errorRegistry, 400, internal.CodeProductRetired)
+	functions.RegisterError[internal.MinimumOpeningBalanceError](errorRegistry, 400, internal.CodeMinimumOpeningBalance)
+	functions.RegisterError[internal.OverdraftAllowanceExceededError](errorRegistry, 400, internal.CodeOverdraftAllowanceExceeded)
+	functions.RegisterError[internal.TransferNotAllowedError](errorRegistry, 400, internal.CodeTransferNotAllowed)
+	functions.RegisterError[internal.AccountNotActiveError](errorRegistry, 400, internal.CodeAccountNotActive)
+	functions.RegisterError[internal.InvalidStatusTransitionError](errorRegistry, 400, internal.CodeInvalidStatusTransition)
+	functions.RegisterError[internal.HoldsOutstandingError](errorRegistry, 400, internal.CodeHoldsOutstanding)
+}
+
+// SetAccountManager sets the AccountManager used by all handlers. It must be called before any handler is invoked.
+func SetAccountManager(manager internal.AccountManager) {
+	accountManager = manager
+}
+
+// SetRoleStore sets the RoleStore used to authorize callers of all handlers
+func SetRoleStore(store internal.RoleStore) {
+	roleStore = store
+}
+
+// StartLambda runs handler as a Lambda function backed by DynamoDB. Roles and exchange rates are read from their tables,
+// unless the ROLES and FX_RATES environment variables provide them as JSON.
+func StartLambda(handler functions.LambdaHandler) {
+	start(handler)
+}
+
+// StartScheduledLambda runs handler as a Lambda function backed by DynamoDB, which is invoked by EventBridge rather than
+// through a Function URL
+func StartScheduledLambda(handler func(ctx context.Context, event events.CloudWatchEvent) error) {
+	start(handler)
+}
+
+func start(handler interface{}) {
+	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithRegion(os.Getenv("AWS_REGION")))
+	if err != nil {
+		log.Fatal(err)
+	}
+	ddb := dynamodb.NewFromConfig(cfg)
+
+	rates := internal.NewDynamoDBRateProvider(ddb)
+	if rateConfig := os.Getenv("FX_RATES"); rateConfig != "" {
+		rates, err = internal.ParseStaticRateProvider([]byte(rateConfig))
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	SetAccountManager(internal.NewAccountManager(ddb, rates))
+
+	if roles := os.Getenv("ROLES"); roles != "" {
+		store, err := internal.ParseStaticRoleStore([]byte(roles))
+		if err != nil {
+			log.Fatal(err)
+		}
+		SetRoleStore(store)
+	} else {
+		SetRoleStore(internal.NewDynamoDBRoleStore(ddb, roleCacheTTL))
+	}
+
+	lambda.Start(handler)
+}
+
+// middleware returns the middleware shared by every handler, which only lets callers with permission through.
+// bodyOptional allows requests without a body, which are decoded as the zero input.
+func middleware(registry *functions.ErrorRegistry, bodyOptional bool, permission functions.Permission) []functions.Middleware {
+	return []functions.Middleware{
+		functions.MapErrors(registry),
+		functions.Logging,
+		functions.Authenticate,
+		functions.DeadlineBudget,
+		functions.Authorize(currentRoleStore{}, policy, permission),
+		functions.Decode(bodyOptional),
+		functions.Validate,
+	}
+}
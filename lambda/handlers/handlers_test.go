@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+)
+
+const (
+	testAccountID         = "123456789"
+	testAdminAccountID    = "105343117262"
+	testAuditorAccountID  = "210987654321"
+	testOperatorAccountID = "321098765432"
+	testTransactionID     = "00001662000000000000-0123456789abcdef"
+)
+
+func init() {
+	SetRoleStore(internal.NewStaticRoleStore(map[string][]internal.Role{
+		testAdminAccountID:    {internal.RoleAdmin},
+		testAuditorAccountID:  {internal.RoleAuditor},
+		testOperatorAccountID: {internal.RoleOperator},
+	}))
+}
+
+func getRequest(accountID, requestBody string) events.LambdaFunctionURLRequest {
+	return events.LambdaFunctionURLRequest{
+		RequestContext: events.LambdaFunctionURLRequestContext{
+			Authorizer: &events.LambdaFunctionURLRequestContextAuthorizerDescription{
+				IAM: &events.LambdaFunctionURLRequestContextAuthorizerIAMDescription{
+					AccountID: accountID,
+				},
+			},
+		},
+		Body: requestBody,
+	}
+}
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"context"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+)
+
+var ListAccounts = functions.NewHandler(listAccounts, middleware(errorRegistry, true, PermissionListAccounts)...)
+
+func listAccounts(ctx context.Context, caller functions.Caller, input internal.ListAccountsInput) (internal.ListAccountsOutput, error) {
+	if caller.Can(PermissionListAllAccounts) {
+		return accountManager.ListAccountsAdmin(ctx, input)
+	}
+	return accountManager.ListAccounts(ctx, caller.AccountID, input)
+}
@@ -1,10 +1,9 @@
-package main
+package handlers
 
 import (
 	"context"
 	"encoding/json"
 	"errors"
-	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/golang/mock/gomock"
 	"github.com/jakepatzer/banking-service/lambda/internal"
@@ -14,18 +13,13 @@ import (
 	"testing"
 )
 
-const (
-	testAccountID      = "123456789"
-	testAdminAccountID = "105343117262"
-)
-
 type listAccountsTestSuite struct {
 	suite.Suite
 	ctrl               *gomock.Controller
 	mockAccountManager *mocks.MockAccountManager
 }
 
-func TestSuite(t *testing.T) {
+func TestListAccountsSuite(t *testing.T) {
 	suite.Run(t, new(listAccountsTestSuite))
 }
 
@@ -71,7 +65,7 @@ func (suite *listAccountsTestSuite) TestHandler_Success() {
 	accountManager = suite.mockAccountManager
 
 	// === When ===
-	response, err := handler(ctx, request)
+	response, err := ListAccounts(ctx, request)
 
 	// === Then ===
 	assert.NoError(suite.T(), err)
@@ -112,7 +106,40 @@ func (suite *listAccountsTestSuite) TestHandler_SuccessWhenAdmin() {
 	accountManager = suite.mockAccountManager
 
 	// === When ===
-	response, err := handler(ctx, request)
+	response, err := ListAccounts(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+	assert.Equal(suite.T(), string(responseBody), response.Body)
+}
+
+func (suite *listAccountsTestSuite) TestHandler_SuccessWhenAuditor() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.ListAccountsInput{
+		Limit: aws.Int32(10),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAuditorAccountID, string(requestBody))
+
+	expectedOutput := internal.ListAccountsOutput{
+		Accounts: []internal.AccountKey{
+			{
+				AccountID:   testAccountID,
+				AccountType: "savings",
+			},
+		},
+	}
+	responseBody, err := json.Marshal(expectedOutput)
+	assert.NoError(suite.T(), err)
+
+	suite.mockAccountManager.EXPECT().ListAccountsAdmin(ctx, expectedInput).Return(expectedOutput, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := ListAccounts(ctx, request)
 
 	// === Then ===
 	assert.NoError(suite.T(), err)
@@ -149,7 +176,7 @@ func (suite *listAccountsTestSuite) TestHandler_SuccessWhenExclusiveStartKeyIsUn
 	accountManager = suite.mockAccountManager
 
 	// === When ===
-	response, err := handler(ctx, request)
+	response, err := ListAccounts(ctx, request)
 
 	// === Then ===
 	assert.NoError(suite.T(), err)
@@ -189,7 +216,7 @@ func (suite *listAccountsTestSuite) TestHandler_SuccessWhenLimitIsUndefined() {
 	accountManager = suite.mockAccountManager
 
 	// === When ===
-	response, err := handler(ctx, request)
+	response, err := ListAccounts(ctx, request)
 
 	// === Then ===
 	assert.NoError(suite.T(), err)
@@ -203,7 +230,7 @@ func (suite *listAccountsTestSuite) TestHandler_UnmarshalRequestError() {
 	request := getRequest(testAccountID, "}invalidJSON{")
 
 	// === When ===
-	response, err := handler(ctx, request)
+	response, err := ListAccounts(ctx, request)
 
 	// === Then ===
 	assert.NoError(suite.T(), err)
@@ -228,22 +255,9 @@ func (suite *listAccountsTestSuite) TestHandler_InternalError() {
 	accountManager = suite.mockAccountManager
 
 	// === When ===
-	response, err := handler(ctx, request)
+	response, err := ListAccounts(ctx, request)
 
 	// === Then ===
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), 500, response.StatusCode)
 }
-
-func getRequest(accountID, requestBody string) events.LambdaFunctionURLRequest {
-	return events.LambdaFunctionURLRequest{
-		RequestContext: events.LambdaFunctionURLRequestContext{
-			Authorizer: &events.LambdaFunctionURLRequestContextAuthorizerDescription{
-				IAM: &events.LambdaFunctionURLRequestContextAuthorizerIAMDescription{
-					AccountID: accountID,
-				},
-			},
-		},
-		Body: requestBody,
-	}
-}
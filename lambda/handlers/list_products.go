@@ -0,0 +1,13 @@
+package handlers
+
+import (
+	"context"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+)
+
+var ListProducts = functions.NewHandler(listProducts, middleware(errorRegistry, true, PermissionListProducts)...)
+
+func listProducts(ctx context.Context, _ functions.Caller, input internal.ListProductsInput) (internal.ListProductsOutput, error) {
+	return accountManager.ListProducts(ctx, input)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/golang/mock/gomock"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"github.com/jakepatzer/banking-service/lambda/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type listProductsTestSuite struct {
+	suite.Suite
+	ctrl               *gomock.Controller
+	mockAccountManager *mocks.MockAccountManager
+}
+
+func TestListProductsSuite(t *testing.T) {
+	suite.Run(t, new(listProductsTestSuite))
+}
+
+func (suite *listProductsTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockAccountManager = mocks.NewMockAccountManager(suite.ctrl)
+}
+
+func (suite *listProductsTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *listProductsTestSuite) TestHandler_Success() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.ListProductsInput{IncludeRetired: true}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	expectedOutput := internal.ListProductsOutput{
+		Products: []internal.Product{getProduct()},
+	}
+	responseBody, err := json.Marshal(expectedOutput)
+	assert.NoError(suite.T(), err)
+
+	suite.mockAccountManager.EXPECT().ListProducts(ctx, expectedInput).Return(expectedOutput, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := ListProducts(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+	assert.Equal(suite.T(), string(responseBody), response.Body)
+}
+
+func (suite *listProductsTestSuite) TestHandler_SuccessWhenBodyIsEmpty() {
+	// === Given ===
+	ctx := context.Background()
+	request := getRequest(testAccountID, "")
+
+	suite.mockAccountManager.EXPECT().ListProducts(ctx, internal.ListProductsInput{}).Return(internal.ListProductsOutput{}, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := ListProducts(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+}
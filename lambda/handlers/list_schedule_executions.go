@@ -0,0 +1,13 @@
+package handlers
+
+import (
+	"context"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+)
+
+var ListScheduleExecutions = functions.NewHandler(listScheduleExecutions, middleware(errorRegistry, false, PermissionListScheduleExecutions)...)
+
+func listScheduleExecutions(ctx context.Context, caller functions.Caller, input internal.ListScheduleExecutionsInput) (internal.ListScheduleExecutionsOutput, error) {
+	return accountManager.ListScheduleExecutions(ctx, caller.AccountID, input)
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/golang/mock/gomock"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"github.com/jakepatzer/banking-service/lambda/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type listScheduleExecutionsTestSuite struct {
+	suite.Suite
+	ctrl               *gomock.Controller
+	mockAccountManager *mocks.MockAccountManager
+}
+
+func TestListScheduleExecutionsSuite(t *testing.T) {
+	suite.Run(t, new(listScheduleExecutionsTestSuite))
+}
+
+func (suite *listScheduleExecutionsTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockAccountManager = mocks.NewMockAccountManager(suite.ctrl)
+}
+
+func (suite *listScheduleExecutionsTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *listScheduleExecutionsTestSuite) TestHandler_Success() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.ListScheduleExecutionsInput{
+		ScheduleID:               "0123456789abcdef",
+		ExclusiveStartOccurrence: aws.Int(3),
+		Limit:                    aws.Int32(2),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	expectedOutput := internal.ListScheduleExecutionsOutput{
+		Executions: []internal.ScheduleExecution{getScheduleExecution()},
+	}
+	responseBody, err := json.Marshal(expectedOutput)
+	assert.NoError(suite.T(), err)
+
+	suite.mockAccountManager.EXPECT().ListScheduleExecutions(ctx, testAccountID, expectedInput).Return(expectedOutput, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := ListScheduleExecutions(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+	assert.Equal(suite.T(), string(responseBody), response.Body)
+}
+
+func (suite *listScheduleExecutionsTestSuite) TestHandler_ScheduleNotFoundError() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.ListScheduleExecutionsInput{
+		ScheduleID: "0123456789abcdef",
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().ListScheduleExecutions(ctx, testAccountID, expectedInput).Return(internal.ListScheduleExecutionsOutput{}, internal.ScheduleNotFoundError{
+		ScheduleID: "0123456789abcdef",
+	})
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := ListScheduleExecutions(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 404, response.StatusCode)
+	assert.Contains(suite.T(), response.Body, internal.CodeScheduleNotFound)
+}
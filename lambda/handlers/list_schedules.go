@@ -0,0 +1,13 @@
+package handlers
+
+import (
+	"context"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+)
+
+var ListSchedules = functions.NewHandler(listSchedules, middleware(errorRegistry, true, PermissionListSchedules)...)
+
+func listSchedules(ctx context.Context, caller functions.Caller, input internal.ListSchedulesInput) (internal.ListSchedulesOutput, error) {
+	return accountManager.ListSchedules(ctx, caller.AccountID, input)
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/golang/mock/gomock"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"github.com/jakepatzer/banking-service/lambda/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type listSchedulesTestSuite struct {
+	suite.Suite
+	ctrl               *gomock.Controller
+	mockAccountManager *mocks.MockAccountManager
+}
+
+func TestListSchedulesSuite(t *testing.T) {
+	suite.Run(t, new(listSchedulesTestSuite))
+}
+
+func (suite *listSchedulesTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockAccountManager = mocks.NewMockAccountManager(suite.ctrl)
+}
+
+func (suite *listSchedulesTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *listSchedulesTestSuite) TestHandler_Success() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.ListSchedulesInput{
+		ExclusiveStartKey: &internal.ScheduleKey{
+			AccountID:  testAccountID,
+			ScheduleID: "0123456789abcdef",
+		},
+		Limit: aws.Int32(10),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	expectedOutput := internal.ListSchedulesOutput{
+		Schedules: []internal.Schedule{getSchedule()},
+	}
+	responseBody, err := json.Marshal(expectedOutput)
+	assert.NoError(suite.T(), err)
+
+	suite.mockAccountManager.EXPECT().ListSchedules(ctx, testAccountID, expectedInput).Return(expectedOutput, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := ListSchedules(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+	assert.Equal(suite.T(), string(responseBody), response.Body)
+}
+
+func (suite *listSchedulesTestSuite) TestHandler_SuccessWhenBodyIsEmpty() {
+	// === Given ===
+	ctx := context.Background()
+	request := getRequest(testAccountID, "")
+
+	suite.mockAccountManager.EXPECT().ListSchedules(ctx, testAccountID, internal.ListSchedulesInput{}).Return(internal.ListSchedulesOutput{}, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := ListSchedules(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+}
+
+func (suite *listSchedulesTestSuite) TestHandler_InternalError() {
+	// === Given ===
+	ctx := context.Background()
+	request := getRequest(testAccountID, "")
+
+	suite.mockAccountManager.EXPECT().ListSchedules(ctx, testAccountID, internal.ListSchedulesInput{}).Return(internal.ListSchedulesOutput{}, errors.New("ERROR"))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := ListSchedules(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 500, response.StatusCode)
+}
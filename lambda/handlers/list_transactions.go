@@ -0,0 +1,13 @@
+package handlers
+
+import (
+	"context"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+)
+
+var ListTransactions = functions.NewHandler(listTransactions, middleware(errorRegistry, true, PermissionListTransactions)...)
+
+func listTransactions(ctx context.Context, caller functions.Caller, input internal.ListTransactionsInput) (internal.ListTransactionsOutput, error) {
+	return accountManager.ListTransactions(ctx, caller.AccountID, input)
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/golang/mock/gomock"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"github.com/jakepatzer/banking-service/lambda/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type listTransactionsTestSuite struct {
+	suite.Suite
+	ctrl               *gomock.Controller
+	mockAccountManager *mocks.MockAccountManager
+}
+
+func TestListTransactionsSuite(t *testing.T) {
+	suite.Run(t, new(listTransactionsTestSuite))
+}
+
+func (suite *listTransactionsTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockAccountManager = mocks.NewMockAccountManager(suite.ctrl)
+}
+
+func (suite *listTransactionsTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *listTransactionsTestSuite) TestHandler_Success() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.ListTransactionsInput{
+		ExclusiveStartKey: &internal.TransactionKey{
+			AccountID:     testAccountID,
+			TransactionID: testTransactionID,
+		},
+		Limit: aws.Int32(10),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	expectedOutput := getListTransactionsOutput()
+	responseBody, err := json.Marshal(expectedOutput)
+	assert.NoError(suite.T(), err)
+
+	suite.mockAccountManager.EXPECT().ListTransactions(ctx, testAccountID, expectedInput).Return(expectedOutput, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := ListTransactions(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+	assert.Equal(suite.T(), string(responseBody), response.Body)
+}
+
+func (suite *listTransactionsTestSuite) TestHandler_SuccessWhenBodyIsEmpty() {
+	// === Given ===
+	ctx := context.Background()
+	request := getRequest(testAccountID, "")
+
+	expectedOutput := getListTransactionsOutput()
+	responseBody, err := json.Marshal(expectedOutput)
+	assert.NoError(suite.T(), err)
+
+	suite.mockAccountManager.EXPECT().ListTransactions(ctx, testAccountID, internal.ListTransactionsInput{}).Return(expectedOutput, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := ListTransactions(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+	assert.Equal(suite.T(), string(responseBody), response.Body)
+}
+
+func (suite *listTransactionsTestSuite) TestHandler_UnmarshalRequestError() {
+	// === Given ===
+	ctx := context.Background()
+	request := getRequest(testAccountID, "}invalidJSON{")
+
+	// === When ===
+	response, err := ListTransactions(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+}
+
+func (suite *listTransactionsTestSuite) TestHandler_InternalError() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.ListTransactionsInput{
+		Limit: aws.Int32(10),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().ListTransactions(ctx, testAccountID, expectedInput).Return(internal.ListTransactionsOutput{}, errors.New("ERROR"))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := ListTransactions(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 500, response.StatusCode)
+}
+
+func getListTransactionsOutput() internal.ListTransactionsOutput {
+	return internal.ListTransactionsOutput{
+		Transactions: []internal.Transaction{
+			{
+				TransactionID: testTransactionID,
+				Type:          internal.TransactionTypeTransfer,
+				Timestamp:     time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC),
+				Amount:        5,
+				Src: &internal.TransactionParty{
+					AccountID:   testAccountID,
+					AccountType: "savings",
+					Balance:     10,
+				},
+				Dest: &internal.TransactionParty{
+					AccountID:   testAccountID,
+					AccountType: "checking",
+					Balance:     5,
+				},
+			},
+		},
+		LastEvaluatedKey: internal.TransactionKey{
+			AccountID:     testAccountID,
+			TransactionID: testTransactionID,
+		},
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+)
+
+// Permissions of the operations. A caller's own accounts are all they may act on unless stated otherwise.
+const (
+	PermissionCreateAccount    functions.Permission = "accounts:create"
+	PermissionDeleteAccount    functions.Permission = "accounts:delete"
+	PermissionCloseAccount     functions.Permission = "accounts:close"
+	PermissionGetBalance       functions.Permission = "accounts:get-balance"
+	PermissionListAccounts     functions.Permission = "accounts:list"
+	PermissionListAllAccounts  functions.Permission = "accounts:list-all"
+	PermissionListTransactions functions.Permission = "transactions:list"
+	PermissionTransfer         functions.Permission = "transfers:create"
+	// Quoting a transfer prices its fees without transferring
+	PermissionQuoteTransfer functions.Permission = "transfers:quote"
+	// Schedules make transfers from the caller's accounts later or repeatedly
+	PermissionCreateSchedule         functions.Permission = "schedules:create"
+	PermissionListSchedules          functions.Permission = "schedules:list"
+	PermissionCancelSchedule         functions.Permission = "schedules:cancel"
+	PermissionListScheduleExecutions functions.Permission = "schedules:list-executions"
+	// Running schedules makes the transfers of every schedule which is due, which is normally left to the scheduled runner
+	PermissionRunSchedules functions.Permission = "schedules:run"
+	// Accruing interest accrues and posts a day of interest on every account which pays it, which is normally left to the
+	// scheduled runner
+	PermissionAccrueInterest functions.Permission = "interest:accrue"
+	// Quoting locks the rate of a conversion, which any customer may transfer at
+	PermissionQuote functions.Permission = "fx:quote"
+	// Converting a transfer between currencies without a quote sets the amount credited to the destination, i.e. the exchange rate
+	PermissionConvertCurrency functions.Permission = "transfers:convert"
+	// Deposits and withdrawals move money in and out of the ledger, for any account
+	PermissionDeposit  functions.Permission = "deposits:create"
+	PermissionWithdraw functions.Permission = "withdrawals:create"
+	// Holds reserve the funds of any account for a payment which is captured or released later
+	PermissionPlaceHold   functions.Permission = "holds:place"
+	PermissionCaptureHold functions.Permission = "holds:capture"
+	PermissionReleaseHold functions.Permission = "holds:release"
+	// An overdraft lets an account be debited below zero, whether set on creation or later for any account
+	PermissionSetOverdraftLimit functions.Permission = "accounts:set-overdraft-limit"
+	// Statuses freeze, make dormant, reactivate or close any account
+	PermissionSetAccountStatus functions.Permission = "accounts:set-status"
+	// Every caller may see the products in the catalog, but only admins may change what an account type means
+	PermissionListProducts functions.Permission = "products:list"
+	PermissionPutProduct   functions.Permission = "products:put"
+	// Reconciling checks the balance of any account, including system accounts, against the journal
+	PermissionReconcile functions.Permission = "ledger:reconcile"
+)
+
+// policy grants permissions to each role. Every caller is a customer, so the other roles only list the permissions
+// they add.
+var policy = functions.Policy{
+	internal.RoleCustomer: {
+		PermissionCreateAccount,
+		PermissionDeleteAccount,
+		PermissionCloseAccount,
+		PermissionGetBalance,
+		PermissionListAccounts,
+		PermissionListTransactions,
+		PermissionTransfer,
+		PermissionQuoteTransfer,
+		PermissionQuote,
+		PermissionCreateSchedule,
+		PermissionListSchedules,
+		PermissionCancelSchedule,
+		PermissionListScheduleExecutions,
+		PermissionListProducts,
+	},
+	internal.RoleAuditor: {
+		PermissionListAllAccounts,
+		PermissionReconcile,
+	},
+	internal.RoleOperator: {
+		PermissionListAllAccounts,
+		PermissionDeposit,
+		PermissionWithdraw,
+		PermissionPlaceHold,
+		PermissionCaptureHold,
+		PermissionReleaseHold,
+		PermissionConvertCurrency,
+		PermissionRunSchedules,
+		PermissionAccrueInterest,
+		PermissionSetAccountStatus,
+	},
+	internal.RoleAdmin: {
+		PermissionListAllAccounts,
+		PermissionDeposit,
+		PermissionWithdraw,
+		PermissionPlaceHold,
+		PermissionCaptureHold,
+		PermissionReleaseHold,
+		PermissionReconcile,
+		PermissionConvertCurrency,
+		PermissionSetOverdraftLimit,
+		PermissionSetAccountStatus,
+		PermissionRunSchedules,
+		PermissionAccrueInterest,
+		PermissionPutProduct,
+	},
+}
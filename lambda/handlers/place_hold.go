@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"context"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"log"
+)
+
+var PlaceHold = functions.NewHandler(placeHold, middleware(errorRegistry, false, PermissionPlaceHold)...)
+
+func placeHold(ctx context.Context, caller functions.Caller, input internal.PlaceHoldInput) (internal.PlaceHoldOutput, error) {
+	output, err := accountManager.PlaceHold(ctx, input)
+	if err != nil {
+		return internal.PlaceHoldOutput{}, err
+	}
+
+	log.Printf("Successfully placed hold %s of %s on %s:%s for %s:%s with reference %s on behalf of %s",
+		output.Hold.HoldID,
+		internal.FormatAmount(output.Hold.Amount, output.Hold.Currency),
+		input.AccountID,
+		input.AccountType,
+		input.DestAccountID,
+		input.DestAccountType,
+		input.ExternalReference,
+		caller.AccountID)
+	return output, nil
+}
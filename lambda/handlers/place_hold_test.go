@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/golang/mock/gomock"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"github.com/jakepatzer/banking-service/lambda/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type placeHoldTestSuite struct {
+	suite.Suite
+	ctrl               *gomock.Controller
+	mockAccountManager *mocks.MockAccountManager
+}
+
+func TestPlaceHoldSuite(t *testing.T) {
+	suite.Run(t, new(placeHoldTestSuite))
+}
+
+func (suite *placeHoldTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockAccountManager = mocks.NewMockAccountManager(suite.ctrl)
+}
+
+func (suite *placeHoldTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *placeHoldTestSuite) TestHandler_Success() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.PlaceHoldInput{
+		AccountID:         testAccountID,
+		AccountType:       "checking",
+		DestAccountID:     "987654321",
+		DestAccountType:   "merchant",
+		Amount:            aws.Int(5),
+		ExternalReference: "auth-0001",
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testOperatorAccountID, string(requestBody))
+
+	expectedOutput := internal.PlaceHoldOutput{
+		Hold: internal.Hold{
+			HoldID:            "0123456789abcdef",
+			Amount:            5,
+			Currency:          "USD",
+			DestAccountID:     "987654321",
+			DestAccountType:   "merchant",
+			ExternalReference: "auth-0001",
+		},
+	}
+	responseBody, err := json.Marshal(expectedOutput)
+	assert.NoError(suite.T(), err)
+
+	suite.mockAccountManager.EXPECT().PlaceHold(ctx, expectedInput).Return(expectedOutput, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := PlaceHold(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+	assert.Equal(suite.T(), string(responseBody), response.Body)
+}
+
+func (suite *placeHoldTestSuite) TestHandler_ErrorWhenCallerIsCustomer() {
+	// === Given ===
+	ctx := context.Background()
+	requestBody, err := json.Marshal(internal.PlaceHoldInput{
+		AccountID:         testAccountID,
+		AccountType:       "checking",
+		DestAccountID:     "987654321",
+		DestAccountType:   "merchant",
+		Amount:            aws.Int(5),
+		ExternalReference: "auth-0001",
+	})
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := PlaceHold(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 403, response.StatusCode)
+}
+
+func (suite *placeHoldTestSuite) TestHandler_InsufficientFundsError() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.PlaceHoldInput{
+		AccountID:         testAccountID,
+		AccountType:       "checking",
+		DestAccountID:     "987654321",
+		DestAccountType:   "merchant",
+		Amount:            aws.Int(5),
+		ExternalReference: "auth-0001",
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testOperatorAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().PlaceHold(ctx, expectedInput).Return(internal.PlaceHoldOutput{}, internal.InsufficientFundsError{
+		AccountID:   testAccountID,
+		AccountType: "checking",
+	})
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := PlaceHold(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+}
+
+func (suite *placeHoldTestSuite) TestHandler_ErrorWhenValidityIsTooLong() {
+	// === Given ===
+	ctx := context.Background()
+	requestBody, err := json.Marshal(internal.PlaceHoldInput{
+		AccountID:         testAccountID,
+		AccountType:       "checking",
+		DestAccountID:     "987654321",
+		DestAccountType:   "merchant",
+		Amount:            aws.Int(5),
+		ExternalReference: "auth-0001",
+		ValiditySeconds:   31 * 24 * 60 * 60,
+	})
+	assert.NoError(suite.T(), err)
+	request := getRequest(testOperatorAccountID, string(requestBody))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := PlaceHold(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+}
@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"context"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"log"
+)
+
+var PutProduct = functions.NewHandler(putProduct, middleware(errorRegistry, false, PermissionPutProduct)...)
+
+func putProduct(ctx context.Context, caller functions.Caller, input internal.Product) (functions.NoOutput, error) {
+	err := accountManager.PutProduct(ctx, input)
+	if err != nil {
+		return functions.NoOutput{}, err
+	}
+
+	log.Printf("Successfully put product %s on behalf of %s", input.AccountType, caller.AccountID)
+	return functions.NoOutput{}, nil
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"github.com/jakepatzer/banking-service/lambda/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type putProductTestSuite struct {
+	suite.Suite
+	ctrl               *gomock.Controller
+	mockAccountManager *mocks.MockAccountManager
+}
+
+func TestPutProductSuite(t *testing.T) {
+	suite.Run(t, new(putProductTestSuite))
+}
+
+func (suite *putProductTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockAccountManager = mocks.NewMockAccountManager(suite.ctrl)
+}
+
+func (suite *putProductTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *putProductTestSuite) TestHandler_Success() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := getProduct()
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAdminAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().PutProduct(ctx, expectedInput).Return(nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := PutProduct(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+}
+
+func (suite *putProductTestSuite) TestHandler_ErrorWhenCallerIsOperator() {
+	// === Given ===
+	ctx := context.Background()
+	requestBody, err := json.Marshal(getProduct())
+	assert.NoError(suite.T(), err)
+	request := getRequest(testOperatorAccountID, string(requestBody))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := PutProduct(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 403, response.StatusCode)
+}
+
+func (suite *putProductTestSuite) TestHandler_ErrorWhenProductIsInvalid() {
+	tests := []struct {
+		name   string
+		modify func(product *internal.Product)
+	}{
+		{name: "account type is undefined", modify: func(product *internal.Product) { product.AccountType = "" }},
+		{name: "account type is not lowercase", modify: func(product *internal.Product) { product.AccountType = "Savings" }},
+		{name: "minimum opening balance is negative", modify: func(product *internal.Product) { product.MinimumOpeningBalance = -1 }},
+		{name: "overdraft allowance is negative", modify: func(product *internal.Product) { product.OverdraftAllowance = -1 }},
+		{name: "destination account type is invalid", modify: func(product *internal.Product) {
+			product.TransferRules.DestAccountTypes = []string{"Checking"}
+		}},
+		{name: "destination account types repeat", modify: func(product *internal.Product) {
+			product.TransferRules.DestAccountTypes = []string{"checking", "checking"}
+		}},
+		{name: "flat fee is negative", modify: func(product *internal.Product) { product.Fees.CrossOwner = &internal.Fee{Flat: -1} }},
+		{name: "fee is more than the amount", modify: func(product *internal.Product) { product.Fees.FX = &internal.Fee{BasisPoints: 10001} }},
+		{name: "APY is negative", modify: func(product *internal.Product) {
+			product.Interest = &internal.InterestTerms{APY: "-0.01", DayCount: internal.DayCountActual365, Compounding: internal.CompoundingMonthly}
+		}},
+		{name: "day count is unsupported", modify: func(product *internal.Product) {
+			product.Interest = &internal.InterestTerms{APY: "0.0425", DayCount: "ACT/364", Compounding: internal.CompoundingMonthly}
+		}},
+		{name: "compounding is unsupported", modify: func(product *internal.Product) {
+			product.Interest = &internal.InterestTerms{APY: "0.0425", DayCount: internal.DayCountActual365, Compounding: "WEEKLY"}
+		}},
+	}
+
+	for _, test := range tests {
+		suite.Run(test.name, func() {
+			// === Given ===
+			ctx := context.Background()
+			product := getProduct()
+			test.modify(&product)
+			requestBody, err := json.Marshal(product)
+			assert.NoError(suite.T(), err)
+			request := getRequest(testAdminAccountID, string(requestBody))
+			accountManager = suite.mockAccountManager
+
+			// === When ===
+			response, err := PutProduct(ctx, request)
+
+			// === Then ===
+			assert.NoError(suite.T(), err)
+			assert.Equal(suite.T(), 400, response.StatusCode)
+		})
+	}
+}
+
+func (suite *putProductTestSuite) TestHandler_InternalError() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := getProduct()
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAdminAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().PutProduct(ctx, expectedInput).Return(errors.New("ERROR"))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := PutProduct(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 500, response.StatusCode)
+}
+
+func getProduct() internal.Product {
+	return internal.Product{
+		AccountType:           "savings",
+		Description:           "Instant access savings",
+		MinimumOpeningBalance: 100,
+		OverdraftAllowance:    0,
+		TransferRules: internal.TransferRules{
+			MaxAmount:        100000,
+			OwnAccountsOnly:  true,
+			DestAccountTypes: []string{"checking"},
+		},
+		Fees: internal.FeeRules{
+			BelowMinimumBalance: &internal.Fee{Flat: 500},
+			MinimumBalance:      10000,
+		},
+	}
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"context"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"log"
+)
+
+var Quote = functions.NewHandler(quote, middleware(errorRegistry, false, PermissionQuote)...)
+
+func quote(ctx context.Context, caller functions.Caller, input internal.QuoteInput) (internal.QuoteOutput, error) {
+	output, err := accountManager.Quote(ctx, caller.AccountID, input)
+	if err != nil {
+		return internal.QuoteOutput{}, err
+	}
+
+	log.Printf("Quoted %s as %s at %s for %s until %s",
+		internal.FormatAmount(output.Quote.Amount, output.Quote.SrcCurrency),
+		internal.FormatAmount(output.Quote.DestAmount, output.Quote.DestCurrency),
+		output.Quote.Rate,
+		caller.AccountID,
+		output.Quote.ExpiresAt)
+	return output, nil
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/golang/mock/gomock"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"github.com/jakepatzer/banking-service/lambda/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type quoteTestSuite struct {
+	suite.Suite
+	ctrl               *gomock.Controller
+	mockAccountManager *mocks.MockAccountManager
+}
+
+func TestQuoteSuite(t *testing.T) {
+	suite.Run(t, new(quoteTestSuite))
+}
+
+func (suite *quoteTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockAccountManager = mocks.NewMockAccountManager(suite.ctrl)
+}
+
+func (suite *quoteTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *quoteTestSuite) TestHandler_Success() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.QuoteInput{
+		SrcCurrency:  "USD",
+		DestCurrency: "EUR",
+		Amount:       aws.Int(500),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	expectedOutput := internal.QuoteOutput{
+		Quote: internal.Quote{
+			QuoteID:      "0123456789abcdef0123456789abcdef",
+			SrcCurrency:  "USD",
+			DestCurrency: "EUR",
+			Amount:       500,
+			DestAmount:   461,
+			Rate:         "0.9214",
+			Rounding:     "0.3",
+			ExpiresAt:    time.Date(2023, 1, 1, 0, 0, 30, 0, time.UTC),
+		},
+	}
+	responseBody, err := json.Marshal(expectedOutput)
+	assert.NoError(suite.T(), err)
+
+	suite.mockAccountManager.EXPECT().Quote(ctx, testAccountID, expectedInput).Return(expectedOutput, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Quote(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+	assert.Equal(suite.T(), string(responseBody), response.Body)
+}
+
+func (suite *quoteTestSuite) TestHandler_ErrorWhenCurrencyIsUnsupported() {
+	// === Given ===
+	ctx := context.Background()
+	requestBody, err := json.Marshal(internal.QuoteInput{
+		SrcCurrency:  "USD",
+		DestCurrency: "XXX",
+		Amount:       aws.Int(500),
+	})
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Quote(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+}
+
+func (suite *quoteTestSuite) TestHandler_RateNotAvailableError() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.QuoteInput{
+		SrcCurrency:  "GBP",
+		DestCurrency: "CHF",
+		Amount:       aws.Int(500),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().Quote(ctx, testAccountID, expectedInput).Return(internal.QuoteOutput{}, internal.RateNotAvailableError{
+		From: "GBP",
+		To:   "CHF",
+	})
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Quote(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+	assert.Contains(suite.T(), response.Body, `"code":"RATE_NOT_AVAILABLE"`)
+}
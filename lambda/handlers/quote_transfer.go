@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"context"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"log"
+)
+
+var QuoteTransfer = functions.NewHandler(quoteTransfer, middleware(transferErrorRegistry, false, PermissionQuoteTransfer)...)
+
+func quoteTransfer(ctx context.Context, caller functions.Caller, input internal.TransferInput) (internal.QuoteTransferOutput, error) {
+	// A dry run of a conversion is priced like the transfer itself
+	if input.DestAmount != nil && !caller.Can(PermissionConvertCurrency) {
+		return internal.QuoteTransferOutput{}, functions.ErrForbidden
+	}
+
+	output, err := accountManager.QuoteTransfer(ctx, caller.AccountID, input)
+	if err != nil {
+		return internal.QuoteTransferOutput{}, err
+	}
+
+	log.Printf("Quoted a transfer of %s from %s:%s to %s:%s with fees of %s",
+		internal.FormatAmount(output.Amount, output.Currency),
+		caller.AccountID,
+		input.SrcAccountType,
+		input.DestAccountID,
+		input.DestAccountType,
+		internal.FormatAmount(output.TotalFees, output.Currency))
+	return output, nil
+}
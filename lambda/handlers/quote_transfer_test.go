@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/golang/mock/gomock"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"github.com/jakepatzer/banking-service/lambda/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type quoteTransferTestSuite struct {
+	suite.Suite
+	ctrl               *gomock.Controller
+	mockAccountManager *mocks.MockAccountManager
+}
+
+func TestQuoteTransferSuite(t *testing.T) {
+	suite.Run(t, new(quoteTransferTestSuite))
+}
+
+func (suite *quoteTransferTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockAccountManager = mocks.NewMockAccountManager(suite.ctrl)
+}
+
+func (suite *quoteTransferTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *quoteTransferTestSuite) TestHandler_Success() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   "123456789",
+		DestAccountType: "checking",
+		Amount:          aws.Int(500),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	expectedOutput := internal.QuoteTransferOutput{
+		Amount:       500,
+		Currency:     "USD",
+		DestAmount:   500,
+		DestCurrency: "USD",
+		Fees:         []internal.FeeCharge{{Type: internal.FeeTypeCrossOwner, Amount: 25}},
+		TotalFees:    25,
+		TotalDebit:   525,
+	}
+	responseBody, err := json.Marshal(expectedOutput)
+	assert.NoError(suite.T(), err)
+
+	suite.mockAccountManager.EXPECT().QuoteTransfer(ctx, testAccountID, expectedInput).Return(expectedOutput, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := QuoteTransfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+	assert.Equal(suite.T(), string(responseBody), response.Body)
+}
+
+func (suite *quoteTransferTestSuite) TestHandler_ErrorWhenAmountIsUndefined() {
+	// === Given ===
+	ctx := context.Background()
+	request := getRequest(testAccountID, `{"srcAccountType":"savings","destAccountID":"123456789","destAccountType":"checking"}`)
+
+	// The account manager must not be called
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := QuoteTransfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+}
+
+func (suite *quoteTransferTestSuite) TestHandler_ErrorWhenAccountHasInsufficientBalance() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   "123456789",
+		DestAccountType: "checking",
+		Amount:          aws.Int(500),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().QuoteTransfer(ctx, testAccountID, expectedInput).Return(internal.QuoteTransferOutput{}, internal.InsufficientFundsError{})
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := QuoteTransfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+}
+
+func (suite *quoteTransferTestSuite) TestHandler_ErrorWhenDestAccountDoesNotExist() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   "123456789",
+		DestAccountType: "checking",
+		Amount:          aws.Int(500),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().QuoteTransfer(ctx, testAccountID, expectedInput).Return(internal.QuoteTransferOutput{}, internal.AccountDoesNotExistError{})
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := QuoteTransfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 422, response.StatusCode)
+}
+
+func (suite *quoteTransferTestSuite) TestHandler_ErrorWhenCustomerConverts() {
+	// === Given ===
+	ctx := context.Background()
+	request := getRequest(testAccountID, `{"srcAccountType":"savings","destAccountID":"123456789","destAccountType":"checking","amount":5,"destAmount":4}`)
+
+	// The account manager must not be called
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := QuoteTransfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 403, response.StatusCode)
+}
+
+func (suite *quoteTransferTestSuite) TestHandler_InternalError() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   "123456789",
+		DestAccountType: "checking",
+		Amount:          aws.Int(500),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().QuoteTransfer(ctx, testAccountID, expectedInput).Return(internal.QuoteTransferOutput{}, errors.New("ERROR"))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := QuoteTransfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 500, response.StatusCode)
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"context"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"log"
+)
+
+var Reconcile = functions.NewHandler(reconcile, middleware(errorRegistry, false, PermissionReconcile)...)
+
+func reconcile(ctx context.Context, caller functions.Caller, input internal.ReconcileInput) (internal.ReconcileOutput, error) {
+	output, err := accountManager.Reconcile(ctx, input)
+	if err != nil {
+		return internal.ReconcileOutput{}, err
+	}
+
+	if !output.Reconciled {
+		log.Printf("Account %s:%s did not reconcile for %s: %s",
+			input.AccountID,
+			input.AccountType,
+			caller.AccountID,
+			functions.MarshalOutput(output))
+	}
+	return output, nil
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/golang/mock/gomock"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"github.com/jakepatzer/banking-service/lambda/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type reconcileTestSuite struct {
+	suite.Suite
+	ctrl               *gomock.Controller
+	mockAccountManager *mocks.MockAccountManager
+}
+
+func TestReconcileSuite(t *testing.T) {
+	suite.Run(t, new(reconcileTestSuite))
+}
+
+func (suite *reconcileTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockAccountManager = mocks.NewMockAccountManager(suite.ctrl)
+}
+
+func (suite *reconcileTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *reconcileTestSuite) TestHandler_Success() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.ReconcileInput{
+		AccountID:   testAccountID,
+		AccountType: "savings",
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAuditorAccountID, string(requestBody))
+
+	expectedOutput := internal.ReconcileOutput{
+		Balance:       aws.Int(5),
+		PostedBalance: 5,
+		Reconciled:    true,
+	}
+	responseBody, err := json.Marshal(expectedOutput)
+	assert.NoError(suite.T(), err)
+
+	suite.mockAccountManager.EXPECT().Reconcile(ctx, expectedInput).Return(expectedOutput, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Reconcile(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+	assert.Equal(suite.T(), string(responseBody), response.Body)
+}
+
+func (suite *reconcileTestSuite) TestHandler_ErrorWhenCallerIsOperator() {
+	// === Given ===
+	ctx := context.Background()
+	requestBody, err := json.Marshal(internal.ReconcileInput{
+		AccountID:   testAccountID,
+		AccountType: "savings",
+	})
+	assert.NoError(suite.T(), err)
+	request := getRequest(testOperatorAccountID, string(requestBody))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Reconcile(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 403, response.StatusCode)
+}
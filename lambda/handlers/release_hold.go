@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"context"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"log"
+)
+
+var ReleaseHold = functions.NewHandler(releaseHold, middleware(errorRegistry, false, PermissionReleaseHold)...)
+
+func releaseHold(ctx context.Context, caller functions.Caller, input internal.ReleaseHoldInput) (functions.NoOutput, error) {
+	err := accountManager.ReleaseHold(ctx, input)
+	if err != nil {
+		return functions.NoOutput{}, err
+	}
+
+	log.Printf("Successfully released hold %s on %s:%s on behalf of %s",
+		input.HoldID,
+		input.AccountID,
+		input.AccountType,
+		caller.AccountID)
+	return functions.NoOutput{}, nil
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/golang/mock/gomock"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"github.com/jakepatzer/banking-service/lambda/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type releaseHoldTestSuite struct {
+	suite.Suite
+	ctrl               *gomock.Controller
+	mockAccountManager *mocks.MockAccountManager
+}
+
+func TestReleaseHoldSuite(t *testing.T) {
+	suite.Run(t, new(releaseHoldTestSuite))
+}
+
+func (suite *releaseHoldTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockAccountManager = mocks.NewMockAccountManager(suite.ctrl)
+}
+
+func (suite *releaseHoldTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *releaseHoldTestSuite) TestHandler_Success() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.ReleaseHoldInput{
+		AccountID:   testAccountID,
+		AccountType: "checking",
+		HoldID:      "0123456789abcdef",
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testOperatorAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().ReleaseHold(ctx, expectedInput).Return(nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := ReleaseHold(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+}
+
+func (suite *releaseHoldTestSuite) TestHandler_ErrorWhenCallerIsCustomer() {
+	// === Given ===
+	ctx := context.Background()
+	requestBody, err := json.Marshal(internal.ReleaseHoldInput{
+		AccountID:   testAccountID,
+		AccountType: "checking",
+		HoldID:      "0123456789abcdef",
+	})
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := ReleaseHold(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 403, response.StatusCode)
+}
+
+func (suite *releaseHoldTestSuite) TestHandler_HoldNotFoundError() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.ReleaseHoldInput{
+		AccountID:   testAccountID,
+		AccountType: "checking",
+		HoldID:      "0123456789abcdef",
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testOperatorAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().ReleaseHold(ctx, expectedInput).Return(internal.HoldNotFoundError{
+		AccountID:   testAccountID,
+		AccountType: "checking",
+		HoldID:      "0123456789abcdef",
+	})
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := ReleaseHold(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+	assert.Contains(suite.T(), response.Body, internal.CodeHoldNotFound)
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"log"
+)
+
+var RunSchedules = functions.NewHandler(runSchedules, middleware(errorRegistry, true, PermissionRunSchedules)...)
+
+func runSchedules(ctx context.Context, caller functions.Caller, input internal.RunSchedulesInput) (internal.RunSchedulesOutput, error) {
+	output, err := accountManager.RunSchedules(ctx, input)
+	if err != nil {
+		return internal.RunSchedulesOutput{}, err
+	}
+
+	log.Printf("Successfully ran %d scheduled transfers on behalf of %s", len(output.Executions), caller.AccountID)
+	return output, nil
+}
+
+// ScheduledRunSchedules runs the schedules which are due when invoked by an EventBridge schedule rather than a caller
+func ScheduledRunSchedules(ctx context.Context, _ events.CloudWatchEvent) error {
+	output, err := accountManager.RunSchedules(ctx, internal.RunSchedulesInput{})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Successfully ran %d scheduled transfers", len(output.Executions))
+	return nil
+}
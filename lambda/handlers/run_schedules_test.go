@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/golang/mock/gomock"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"github.com/jakepatzer/banking-service/lambda/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type runSchedulesTestSuite struct {
+	suite.Suite
+	ctrl               *gomock.Controller
+	mockAccountManager *mocks.MockAccountManager
+}
+
+func TestRunSchedulesSuite(t *testing.T) {
+	suite.Run(t, new(runSchedulesTestSuite))
+}
+
+func (suite *runSchedulesTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockAccountManager = mocks.NewMockAccountManager(suite.ctrl)
+}
+
+func (suite *runSchedulesTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *runSchedulesTestSuite) TestHandler_Success() {
+	// === Given ===
+	ctx := context.Background()
+	request := getRequest(testOperatorAccountID, "")
+
+	expectedOutput := internal.RunSchedulesOutput{
+		Executions: []internal.ScheduleExecution{getScheduleExecution()},
+	}
+	responseBody, err := json.Marshal(expectedOutput)
+	assert.NoError(suite.T(), err)
+
+	suite.mockAccountManager.EXPECT().RunSchedules(ctx, internal.RunSchedulesInput{}).Return(expectedOutput, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := RunSchedules(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+	assert.Equal(suite.T(), string(responseBody), response.Body)
+}
+
+func (suite *runSchedulesTestSuite) TestHandler_ErrorWhenCallerIsCustomer() {
+	// === Given ===
+	ctx := context.Background()
+	request := getRequest(testAccountID, "")
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := RunSchedules(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 403, response.StatusCode)
+}
+
+func (suite *runSchedulesTestSuite) TestScheduledHandler_Success() {
+	// === Given ===
+	ctx := context.Background()
+	suite.mockAccountManager.EXPECT().RunSchedules(ctx, internal.RunSchedulesInput{}).Return(internal.RunSchedulesOutput{}, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	err := ScheduledRunSchedules(ctx, events.CloudWatchEvent{})
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *runSchedulesTestSuite) TestScheduledHandler_InternalError() {
+	// === Given ===
+	ctx := context.Background()
+	suite.mockAccountManager.EXPECT().RunSchedules(ctx, internal.RunSchedulesInput{}).Return(internal.RunSchedulesOutput{}, errors.New("ERROR"))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	err := ScheduledRunSchedules(ctx, events.CloudWatchEvent{})
+
+	// === Then ===
+	assert.Error(suite.T(), err)
+}
+
+func getScheduleExecution() internal.ScheduleExecution {
+	return internal.ScheduleExecution{
+		ScheduleID:    "0123456789abcdef",
+		Occurrence:    0,
+		ScheduledAt:   time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC),
+		ExecutedAt:    time.Date(2022, 9, 1, 0, 1, 0, 0, time.UTC),
+		Status:        internal.ExecutionStatusSucceeded,
+		TransactionID: testTransactionID,
+	}
+}
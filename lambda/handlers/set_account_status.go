@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"context"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"log"
+)
+
+var SetAccountStatus = functions.NewHandler(setAccountStatus, middleware(errorRegistry, false, PermissionSetAccountStatus)...)
+
+func setAccountStatus(ctx context.Context, caller functions.Caller, input internal.SetAccountStatusInput) (functions.NoOutput, error) {
+	err := accountManager.SetAccountStatus(ctx, input)
+	if err != nil {
+		return functions.NoOutput{}, err
+	}
+
+	log.Printf("Successfully set the status of %s:%s to %s on behalf of %s",
+		input.AccountID,
+		input.AccountType,
+		input.Status,
+		caller.AccountID)
+	return functions.NoOutput{}, nil
+}
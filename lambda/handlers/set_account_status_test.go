@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/golang/mock/gomock"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"github.com/jakepatzer/banking-service/lambda/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type setAccountStatusTestSuite struct {
+	suite.Suite
+	ctrl               *gomock.Controller
+	mockAccountManager *mocks.MockAccountManager
+}
+
+func TestSetAccountStatusSuite(t *testing.T) {
+	suite.Run(t, new(setAccountStatusTestSuite))
+}
+
+func (suite *setAccountStatusTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockAccountManager = mocks.NewMockAccountManager(suite.ctrl)
+}
+
+func (suite *setAccountStatusTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *setAccountStatusTestSuite) TestHandler_Success() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.SetAccountStatusInput{
+		AccountID:   testAccountID,
+		AccountType: "checking",
+		Status:      internal.AccountStatusFrozen,
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testOperatorAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().SetAccountStatus(ctx, expectedInput).Return(nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := SetAccountStatus(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+}
+
+func (suite *setAccountStatusTestSuite) TestHandler_ErrorWhenCallerIsCustomer() {
+	// === Given ===
+	ctx := context.Background()
+	requestBody, err := json.Marshal(internal.SetAccountStatusInput{
+		AccountID:   testAccountID,
+		AccountType: "checking",
+		Status:      internal.AccountStatusActive,
+	})
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := SetAccountStatus(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 403, response.StatusCode)
+}
+
+func (suite *setAccountStatusTestSuite) TestHandler_ErrorWhenStatusIsUnknown() {
+	// === Given ===
+	ctx := context.Background()
+	requestBody, err := json.Marshal(internal.SetAccountStatusInput{
+		AccountID:   testAccountID,
+		AccountType: "checking",
+		Status:      "SUSPENDED",
+	})
+	assert.NoError(suite.T(), err)
+	request := getRequest(testOperatorAccountID, string(requestBody))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := SetAccountStatus(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+}
+
+func (suite *setAccountStatusTestSuite) TestHandler_InvalidStatusTransitionError() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.SetAccountStatusInput{
+		AccountID:   testAccountID,
+		AccountType: "checking",
+		Status:      internal.AccountStatusActive,
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testOperatorAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().SetAccountStatus(ctx, expectedInput).Return(internal.InvalidStatusTransitionError{
+		AccountID:   testAccountID,
+		AccountType: "checking",
+		From:        internal.AccountStatusClosed,
+		To:          internal.AccountStatusActive,
+	})
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := SetAccountStatus(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+	assert.Contains(suite.T(), response.Body, `"code":"INVALID_STATUS_TRANSITION"`)
+}
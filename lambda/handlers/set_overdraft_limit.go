@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"context"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"log"
+)
+
+var SetOverdraftLimit = functions.NewHandler(setOverdraftLimit, middleware(errorRegistry, false, PermissionSetOverdraftLimit)...)
+
+func setOverdraftLimit(ctx context.Context, caller functions.Caller, input internal.SetOverdraftLimitInput) (functions.NoOutput, error) {
+	err := accountManager.SetOverdraftLimit(ctx, input)
+	if err != nil {
+		return functions.NoOutput{}, err
+	}
+
+	log.Printf("Successfully set the overdraft limit of %s:%s to %d on behalf of %s",
+		input.AccountID,
+		input.AccountType,
+		*input.OverdraftLimit,
+		caller.AccountID)
+	return functions.NoOutput{}, nil
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/golang/mock/gomock"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"github.com/jakepatzer/banking-service/lambda/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type setOverdraftLimitTestSuite struct {
+	suite.Suite
+	ctrl               *gomock.Controller
+	mockAccountManager *mocks.MockAccountManager
+}
+
+func TestSetOverdraftLimitSuite(t *testing.T) {
+	suite.Run(t, new(setOverdraftLimitTestSuite))
+}
+
+func (suite *setOverdraftLimitTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockAccountManager = mocks.NewMockAccountManager(suite.ctrl)
+}
+
+func (suite *setOverdraftLimitTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *setOverdraftLimitTestSuite) TestHandler_Success() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.SetOverdraftLimitInput{
+		AccountID:      testAccountID,
+		AccountType:    "checking",
+		OverdraftLimit: aws.Int(500),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAdminAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().SetOverdraftLimit(ctx, expectedInput).Return(nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := SetOverdraftLimit(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+}
+
+func (suite *setOverdraftLimitTestSuite) TestHandler_ErrorWhenCallerIsOperator() {
+	// === Given ===
+	ctx := context.Background()
+	requestBody, err := json.Marshal(internal.SetOverdraftLimitInput{
+		AccountID:      testAccountID,
+		AccountType:    "checking",
+		OverdraftLimit: aws.Int(500),
+	})
+	assert.NoError(suite.T(), err)
+	request := getRequest(testOperatorAccountID, string(requestBody))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := SetOverdraftLimit(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 403, response.StatusCode)
+}
+
+func (suite *setOverdraftLimitTestSuite) TestHandler_ErrorWhenOverdraftLimitIsNegative() {
+	// === Given ===
+	ctx := context.Background()
+	requestBody, err := json.Marshal(internal.SetOverdraftLimitInput{
+		AccountID:      testAccountID,
+		AccountType:    "checking",
+		OverdraftLimit: aws.Int(-1),
+	})
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAdminAccountID, string(requestBody))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := SetOverdraftLimit(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+}
+
+func (suite *setOverdraftLimitTestSuite) TestHandler_AccountDoesNotExistError() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.SetOverdraftLimitInput{
+		AccountID:      testAccountID,
+		AccountType:    "checking",
+		OverdraftLimit: aws.Int(500),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAdminAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().SetOverdraftLimit(ctx, expectedInput).Return(internal.AccountDoesNotExistError{
+		AccountID:   testAccountID,
+		AccountType: "checking",
+	})
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := SetOverdraftLimit(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+	assert.Contains(suite.T(), response.Body, `"code":"ACCOUNT_NOT_FOUND"`)
+}
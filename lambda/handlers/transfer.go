@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"context"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"log"
+)
+
+// transferErrorRegistry distinguishes a missing destination account, which the request refers to, from a missing
+// source account
+var transferErrorRegistry = errorRegistry.Extend()
+
+func init() {
+	functions.RegisterError[internal.AccountDoesNotExistError](transferErrorRegistry, 422, internal.CodeAccountNotFound)
+	functions.RegisterError[internal.SelfTransferError](transferErrorRegistry, 400, internal.CodeSelfTransfer)
+}
+
+var Transfer = functions.NewHandler(transfer, middleware(transferErrorRegistry, false, PermissionTransfer)...)
+
+func transfer(ctx context.Context, caller functions.Caller, input internal.TransferInput) (internal.TransferOutput, error) {
+	// Customers transfer between accounts of the same currency, while conversions are priced by operators
+	if input.DestAmount != nil && !caller.Can(PermissionConvertCurrency) {
+		return internal.TransferOutput{}, functions.ErrForbidden
+	}
+
+	output, err := accountManager.Transfer(ctx, caller.AccountID, input)
+	if err != nil {
+		return internal.TransferOutput{}, err
+	}
+
+	log.Printf("Successfully transferred %s from %s:%s to %s:%s",
+		internal.FormatAmount(output.Transaction.Amount, output.Transaction.Currency),
+		caller.AccountID,
+		input.SrcAccountType,
+		input.DestAccountID,
+		input.DestAccountType)
+	return output, nil
+}
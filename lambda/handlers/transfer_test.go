@@ -0,0 +1,548 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/golang/mock/gomock"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"github.com/jakepatzer/banking-service/lambda/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type transferTestSuite struct {
+	suite.Suite
+	ctrl               *gomock.Controller
+	mockAccountManager *mocks.MockAccountManager
+}
+
+func TestTransferSuite(t *testing.T) {
+	suite.Run(t, new(transferTestSuite))
+}
+
+func (suite *transferTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockAccountManager = mocks.NewMockAccountManager(suite.ctrl)
+}
+
+func (suite *transferTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *transferTestSuite) TestHandler_Success() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   testAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(5),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().Transfer(ctx, testAccountID, expectedInput).Return(internal.TransferOutput{}, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Transfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+}
+
+func (suite *transferTestSuite) TestHandler_UnmarshalRequestError() {
+	// === Given ===
+	ctx := context.Background()
+	request := getRequest(testAccountID, "}invalidJSON{")
+
+	// === When ===
+	response, err := Transfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+}
+
+func (suite *transferTestSuite) TestHandler_ErrorWhenSrcAccountTypeIsUndefined() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.TransferInput{
+		DestAccountID:   testAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(5),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	// === When ===
+	response, err := Transfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+}
+
+func (suite *transferTestSuite) TestHandler_ErrorWhenDestAccountIDIsUndefined() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountType: "checking",
+		Amount:          aws.Int(5),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	// === When ===
+	response, err := Transfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+}
+
+func (suite *transferTestSuite) TestHandler_ErrorWhenDestAccountTypeIsUndefined() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.TransferInput{
+		SrcAccountType: "savings",
+		DestAccountID:  testAccountID,
+		Amount:         aws.Int(5),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	// === When ===
+	response, err := Transfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+}
+
+func (suite *transferTestSuite) TestHandler_ErrorWhenAmountIsUndefined() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   testAccountID,
+		DestAccountType: "checking",
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	// === When ===
+	response, err := Transfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+}
+
+func (suite *transferTestSuite) TestHandler_ErrorWhenAmountIsInvalid() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   testAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(0),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	// === When ===
+	response, err := Transfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+}
+
+func (suite *transferTestSuite) TestHandler_ErrorWhenAccountHasInsufficientBalance() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   testAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(5),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().Transfer(ctx, testAccountID, expectedInput).Return(internal.TransferOutput{}, internal.InsufficientFundsError{})
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Transfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+}
+
+func (suite *transferTestSuite) TestHandler_ErrorWhenDestAccountDoesNotExist() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   testAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(5),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().Transfer(ctx, testAccountID, expectedInput).Return(internal.TransferOutput{}, internal.AccountDoesNotExistError{})
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Transfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 422, response.StatusCode)
+}
+
+func (suite *transferTestSuite) TestHandler_ErrorWhenSrcAccountDoesNotExist() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   testAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(5),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().Transfer(ctx, testAccountID, expectedInput).Return(internal.TransferOutput{}, internal.SourceAccountDoesNotExistError{AccountID: testAccountID, AccountType: "savings"})
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Transfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 404, response.StatusCode)
+	assert.JSONEq(suite.T(), `{
+		"type": "about:blank",
+		"title": "Not Found",
+		"status": 404,
+		"detail": "The source account 123456789:savings does not exist.",
+		"code": "SOURCE_ACCOUNT_NOT_FOUND",
+		"details": {"accountID": "123456789", "accountType": "savings"}
+	}`, response.Body)
+}
+
+func (suite *transferTestSuite) TestHandler_ErrorWhenSrcAccountIsDestAccount() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   testAccountID,
+		DestAccountType: "savings",
+		Amount:          aws.Int(5),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().Transfer(ctx, testAccountID, expectedInput).Return(internal.TransferOutput{}, internal.SelfTransferError{AccountID: testAccountID, AccountType: "savings"})
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Transfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+	assert.JSONEq(suite.T(), `{
+		"type": "about:blank",
+		"title": "Bad Request",
+		"status": 400,
+		"detail": "The account 123456789:savings cannot transfer to itself.",
+		"code": "SELF_TRANSFER",
+		"details": {"accountID": "123456789", "accountType": "savings"}
+	}`, response.Body)
+}
+
+func (suite *transferTestSuite) TestHandler_ErrorWhenTransactionConflicts() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   testAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(5),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().Transfer(ctx, testAccountID, expectedInput).Return(internal.TransferOutput{}, internal.TransactionConflictError{
+		Src:  internal.AccountKey{AccountID: testAccountID, AccountType: "savings"},
+		Dest: internal.AccountKey{AccountID: testAccountID, AccountType: "checking"},
+	})
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Transfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 409, response.StatusCode)
+	assert.JSONEq(suite.T(), `{
+		"type": "about:blank",
+		"title": "Conflict",
+		"status": 409,
+		"detail": "The transfer from 123456789:savings to 123456789:checking conflicted with a concurrent transaction.",
+		"code": "TRANSACTION_CONFLICT",
+		"details": {
+			"src": {"accountID": "123456789", "accountType": "savings"},
+			"dest": {"accountID": "123456789", "accountType": "checking"}
+		}
+	}`, response.Body)
+}
+
+func (suite *transferTestSuite) TestHandler_SuccessWithIdempotencyKey() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   testAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(5),
+		IdempotencyKey:  "payout-2022-09-01",
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	expectedOutput := internal.TransferOutput{
+		Transaction: internal.Transaction{
+			TransactionID: "00001662000000000000-0123456789abcdef",
+			Type:          internal.TransactionTypeTransfer,
+			Amount:        5,
+		},
+	}
+	responseBody, err := json.Marshal(expectedOutput)
+	assert.NoError(suite.T(), err)
+
+	suite.mockAccountManager.EXPECT().Transfer(ctx, testAccountID, expectedInput).Return(expectedOutput, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Transfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+	assert.Equal(suite.T(), string(responseBody), response.Body)
+}
+
+func (suite *transferTestSuite) TestHandler_ErrorWhenIdempotencyKeyConflicts() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   testAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(5),
+		IdempotencyKey:  "payout-2022-09-01",
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().Transfer(ctx, testAccountID, expectedInput).Return(internal.TransferOutput{}, internal.IdempotencyKeyConflictError{})
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Transfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 409, response.StatusCode)
+}
+
+func (suite *transferTestSuite) TestHandler_ErrorWhenCurrencyIsUnsupported() {
+	// === Given ===
+	ctx := context.Background()
+	request := getRequest(testAccountID, `{"srcAccountType":"savings","destAccountID":"123456789","destAccountType":"checking","amount":5,"currency":"ABC"}`)
+
+	// The account manager must not be called
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Transfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+	assert.Contains(suite.T(), response.Body, "currency must be a supported ISO 4217 currency code")
+}
+
+func (suite *transferTestSuite) TestHandler_ErrorWhenConversionIsRequired() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   testAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(5),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().Transfer(ctx, testAccountID, expectedInput).Return(internal.TransferOutput{}, internal.ConversionRequiredError{SrcCurrency: "USD", DestCurrency: "EUR"})
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Transfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+	assert.JSONEq(suite.T(), `{
+		"type": "about:blank",
+		"title": "Bad Request",
+		"status": 400,
+		"detail": "The transfer from USD to EUR requires a currency conversion.",
+		"code": "CONVERSION_REQUIRED",
+		"details": {"srcCurrency": "USD", "destCurrency": "EUR"}
+	}`, response.Body)
+}
+
+func (suite *transferTestSuite) TestHandler_ErrorWhenCustomerConverts() {
+	// === Given ===
+	ctx := context.Background()
+	request := getRequest(testAccountID, `{"srcAccountType":"savings","destAccountID":"123456789","destAccountType":"checking","amount":5,"destAmount":4}`)
+
+	// The account manager must not be called
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Transfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 403, response.StatusCode)
+}
+
+func (suite *transferTestSuite) TestHandler_OperatorConverts() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   testAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(5),
+		DestAmount:      aws.Int(4),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testOperatorAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().Transfer(ctx, testOperatorAccountID, expectedInput).Return(internal.TransferOutput{}, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Transfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+}
+
+func (suite *transferTestSuite) TestHandler_InternalError() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   testAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(5),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().Transfer(ctx, testAccountID, expectedInput).Return(internal.TransferOutput{}, errors.New("ERROR"))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Transfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 500, response.StatusCode)
+}
+
+func (suite *transferTestSuite) TestHandler_ErrorWhenDeadlineIsTooClose() {
+	// === Given ===
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	request := getRequest(testAccountID, `{"srcAccountType":"savings","destAccountID":"123456789","destAccountType":"checking","amount":5}`)
+
+	// The account manager must not be called
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Transfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 503, response.StatusCode)
+	assert.Equal(suite.T(), "1", response.Headers["Retry-After"])
+}
+
+func (suite *transferTestSuite) TestHandler_ErrorWhenDeadlineIsExceeded() {
+	// === Given ===
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	expectedInput := internal.TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   testAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(5),
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().Transfer(gomock.Any(), testAccountID, expectedInput).DoAndReturn(
+		func(ctx context.Context, _ string, _ internal.TransferInput) (internal.TransferOutput, error) {
+			// The account manager is given less time than the function
+			deadline, ok := ctx.Deadline()
+			assert.True(suite.T(), ok)
+			assert.True(suite.T(), time.Until(deadline) < 3*time.Second-100*time.Millisecond)
+			return internal.TransferOutput{}, context.DeadlineExceeded
+		})
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Transfer(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 504, response.StatusCode)
+	assert.Equal(suite.T(), "1", response.Headers["Retry-After"])
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"context"
+	"github.com/jakepatzer/banking-service/lambda/functions"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"log"
+)
+
+var Withdraw = functions.NewHandler(withdraw, middleware(errorRegistry, false, PermissionWithdraw)...)
+
+func withdraw(ctx context.Context, caller functions.Caller, input internal.WithdrawInput) (internal.WithdrawOutput, error) {
+	output, err := accountManager.Withdraw(ctx, input)
+	if err != nil {
+		return internal.WithdrawOutput{}, err
+	}
+
+	log.Printf("Successfully withdrew %s from %s:%s with reference %s on behalf of %s",
+		internal.FormatAmount(output.Transaction.Amount, output.Transaction.Currency),
+		input.AccountID,
+		input.AccountType,
+		input.ExternalReference,
+		caller.AccountID)
+	return output, nil
+}
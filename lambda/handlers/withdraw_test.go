@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/golang/mock/gomock"
+	"github.com/jakepatzer/banking-service/lambda/internal"
+	"github.com/jakepatzer/banking-service/lambda/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type withdrawTestSuite struct {
+	suite.Suite
+	ctrl               *gomock.Controller
+	mockAccountManager *mocks.MockAccountManager
+}
+
+func TestWithdrawSuite(t *testing.T) {
+	suite.Run(t, new(withdrawTestSuite))
+}
+
+func (suite *withdrawTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockAccountManager = mocks.NewMockAccountManager(suite.ctrl)
+}
+
+func (suite *withdrawTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *withdrawTestSuite) TestHandler_Success() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.WithdrawInput{
+		AccountID:         testAccountID,
+		AccountType:       "checking",
+		Amount:            aws.Int(5),
+		ExternalReference: "ach-0001",
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testOperatorAccountID, string(requestBody))
+
+	expectedOutput := internal.WithdrawOutput{
+		Transaction: internal.Transaction{
+			TransactionID: testTransactionID,
+			Type:          internal.TransactionTypeWithdrawal,
+			Amount:        5,
+			Src: &internal.TransactionParty{
+				AccountID:   testAccountID,
+				AccountType: "checking",
+				Balance:     0,
+			},
+			ExternalReference: "ach-0001",
+		},
+	}
+	responseBody, err := json.Marshal(expectedOutput)
+	assert.NoError(suite.T(), err)
+
+	suite.mockAccountManager.EXPECT().Withdraw(ctx, expectedInput).Return(expectedOutput, nil)
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Withdraw(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, response.StatusCode)
+	assert.Equal(suite.T(), string(responseBody), response.Body)
+}
+
+func (suite *withdrawTestSuite) TestHandler_ErrorWhenCallerIsCustomer() {
+	// === Given ===
+	ctx := context.Background()
+	requestBody, err := json.Marshal(internal.WithdrawInput{
+		AccountID:         testAccountID,
+		AccountType:       "checking",
+		Amount:            aws.Int(5),
+		ExternalReference: "ach-0001",
+	})
+	assert.NoError(suite.T(), err)
+	request := getRequest(testAccountID, string(requestBody))
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Withdraw(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 403, response.StatusCode)
+}
+
+func (suite *withdrawTestSuite) TestHandler_InsufficientFundsError() {
+	// === Given ===
+	ctx := context.Background()
+	expectedInput := internal.WithdrawInput{
+		AccountID:         testAccountID,
+		AccountType:       "checking",
+		Amount:            aws.Int(5),
+		ExternalReference: "ach-0001",
+	}
+	requestBody, err := json.Marshal(expectedInput)
+	assert.NoError(suite.T(), err)
+	request := getRequest(testOperatorAccountID, string(requestBody))
+
+	suite.mockAccountManager.EXPECT().Withdraw(ctx, expectedInput).Return(internal.WithdrawOutput{}, internal.InsufficientFundsError{
+		AccountID:   testAccountID,
+		AccountType: "checking",
+	})
+	accountManager = suite.mockAccountManager
+
+	// === When ===
+	response, err := Withdraw(ctx, request)
+
+	// === Then ===
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 400, response.StatusCode)
+}
@@ -14,10 +14,19 @@ import (
 
 const (
 	tableName = "accounts-table"
+	// Index of accounts by type, so that the accounts of a product are found without scanning every account
+	accountsByTypeIndexName = "accounts-by-type-index"
 
 	accountIDAttr   = "AccountId"
 	accountTypeAttr = "AccountType"
 	balanceAttr     = "Balance"
+
+	// Code of a transaction cancellation reason for a write whose condition expression failed
+	conditionalCheckFailedReason = "ConditionalCheckFailed"
+	// Code of a transaction cancellation reason for a write to an item with another transaction in progress
+	transactionConflictReason = "TransactionConflict"
+	// Code of a transaction cancellation reason for a write which exceeded the provisioned throughput of its table
+	throttlingErrorReason = "ThrottlingError"
 )
 
 func NewAccountKeyFromItem(item map[string]types.AttributeValue) (AccountKey, error) {
@@ -50,8 +59,8 @@ func (key *AccountKey) toAccountItem() map[string]types.AttributeValue {
 }
 
 type AccountAlreadyExistsError struct {
-	AccountID   string
-	AccountType string
+	AccountID   string `json:"accountID"`
+	AccountType string `json:"accountType"`
 }
 
 func (err AccountAlreadyExistsError) Error() string {
@@ -59,8 +68,8 @@ func (err AccountAlreadyExistsError) Error() string {
 }
 
 type NonZeroBalanceError struct {
-	AccountID   string
-	AccountType string
+	AccountID   string `json:"accountID"`
+	AccountType string `json:"accountType"`
 }
 
 func (err NonZeroBalanceError) Error() string {
@@ -68,8 +77,8 @@ func (err NonZeroBalanceError) Error() string {
 }
 
 type InsufficientFundsError struct {
-	AccountID   string
-	AccountType string
+	AccountID   string `json:"accountID"`
+	AccountType string `json:"accountType"`
 }
 
 func (err InsufficientFundsError) Error() string {
@@ -77,53 +86,174 @@ func (err InsufficientFundsError) Error() string {
 }
 
 type AccountDoesNotExistError struct {
-	AccountID   string
-	AccountType string
+	AccountID   string `json:"accountID"`
+	AccountType string `json:"accountType"`
 }
 
 func (err AccountDoesNotExistError) Error() string {
 	return fmt.Sprintf("The account %s:%s does not exist.", err.AccountID, err.AccountType)
 }
 
+type SourceAccountDoesNotExistError struct {
+	AccountID   string `json:"accountID"`
+	AccountType string `json:"accountType"`
+}
+
+func (err SourceAccountDoesNotExistError) Error() string {
+	return fmt.Sprintf("The source account %s:%s does not exist.", err.AccountID, err.AccountType)
+}
+
+// SelfTransferError is returned when the source and destination of a transfer are the same account
+type SelfTransferError struct {
+	AccountID   string `json:"accountID"`
+	AccountType string `json:"accountType"`
+}
+
+func (err SelfTransferError) Error() string {
+	return fmt.Sprintf("The account %s:%s cannot transfer to itself.", err.AccountID, err.AccountType)
+}
+
+// TransactionConflictError is returned when the balance of an account in a transaction was modified by a concurrent
+// transaction. Retrying the transaction is safe. Withdrawals only have a Src, and deposits only have a Dest.
+type TransactionConflictError struct {
+	Src  AccountKey `json:"src"`
+	Dest AccountKey `json:"dest"`
+	// ID of the hold being placed, captured or released, for conflicts of holds
+	HoldID string `json:"holdID,omitempty"`
+	// Status being set, for conflicts of status changes
+	Status AccountStatus `json:"status,omitempty"`
+	// Whether Src was being deleted, for conflicts of deletions
+	Delete bool  `json:"delete,omitempty"`
+	Err    error `json:"-"`
+}
+
+func (err TransactionConflictError) Error() string {
+	switch {
+	case err.HoldID != "":
+		return fmt.Sprintf("The hold %s on %s:%s conflicted with a concurrent transaction.",
+			err.HoldID, err.Src.AccountID, err.Src.AccountType)
+	case err.Status != "":
+		return fmt.Sprintf("Setting the status of %s:%s to %s conflicted with a concurrent transaction.",
+			err.Src.AccountID, err.Src.AccountType, err.Status)
+	case err.Delete:
+		return fmt.Sprintf("The deletion of %s:%s conflicted with a concurrent transaction.",
+			err.Src.AccountID, err.Src.AccountType)
+	case err.Dest == AccountKey{}:
+		return fmt.Sprintf("The withdrawal from %s:%s conflicted with a concurrent transaction.",
+			err.Src.AccountID, err.Src.AccountType)
+	case err.Src == AccountKey{}:
+		return fmt.Sprintf("The deposit to %s:%s conflicted with a concurrent transaction.",
+			err.Dest.AccountID, err.Dest.AccountType)
+	}
+	return fmt.Sprintf("The transfer from %s:%s to %s:%s conflicted with a concurrent transaction.",
+		err.Src.AccountID, err.Src.AccountType, err.Dest.AccountID, err.Dest.AccountType)
+}
+
+func (err TransactionConflictError) Unwrap() error {
+	return err.Err
+}
+
 type AccountManager interface {
 	CreateAccount(ctx context.Context, accountID string, createAccountInput CreateAccountInput) error
 	DeleteAccount(ctx context.Context, accountID string, deleteAccountInput DeleteAccountInput) error
-	Transfer(ctx context.Context, srcAccountID string, transferInput TransferInput) error
+	CloseAccount(ctx context.Context, accountID string, closeAccountInput CloseAccountInput) (CloseAccountOutput, error)
+	Transfer(ctx context.Context, srcAccountID string, transferInput TransferInput) (TransferOutput, error)
+	QuoteTransfer(ctx context.Context, srcAccountID string, transferInput TransferInput) (QuoteTransferOutput, error)
+	Deposit(ctx context.Context, depositInput DepositInput) (DepositOutput, error)
+	Withdraw(ctx context.Context, withdrawInput WithdrawInput) (WithdrawOutput, error)
 	GetBalance(ctx context.Context, accountID string, getBalanceInput GetBalanceInput) (GetBalanceOutput, error)
 	ListAccounts(ctx context.Context, accountID string, listAccountsInput ListAccountsInput) (ListAccountsOutput, error)
 	ListAccountsAdmin(ctx context.Context, listAccountsInput ListAccountsInput) (ListAccountsOutput, error)
+	ListTransactions(ctx context.Context, accountID string, listTransactionsInput ListTransactionsInput) (ListTransactionsOutput, error)
+	Reconcile(ctx context.Context, reconcileInput ReconcileInput) (ReconcileOutput, error)
+	Quote(ctx context.Context, accountID string, quoteInput QuoteInput) (QuoteOutput, error)
+	SetOverdraftLimit(ctx context.Context, setOverdraftLimitInput SetOverdraftLimitInput) error
+	SetAccountStatus(ctx context.Context, setAccountStatusInput SetAccountStatusInput) error
+	PlaceHold(ctx context.Context, placeHoldInput PlaceHoldInput) (PlaceHoldOutput, error)
+	CaptureHold(ctx context.Context, captureHoldInput CaptureHoldInput) (CaptureHoldOutput, error)
+	ReleaseHold(ctx context.Context, releaseHoldInput ReleaseHoldInput) error
+	CreateSchedule(ctx context.Context, accountID string, createScheduleInput CreateScheduleInput) (CreateScheduleOutput, error)
+	ListSchedules(ctx context.Context, accountID string, listSchedulesInput ListSchedulesInput) (ListSchedulesOutput, error)
+	CancelSchedule(ctx context.Context, accountID string, cancelScheduleInput CancelScheduleInput) error
+	ListScheduleExecutions(ctx context.Context, accountID string, listScheduleExecutionsInput ListScheduleExecutionsInput) (ListScheduleExecutionsOutput, error)
+	RunSchedules(ctx context.Context, runSchedulesInput RunSchedulesInput) (RunSchedulesOutput, error)
+	AccrueProductInterest(ctx context.Context, accrueProductInterestInput AccrueProductInterestInput) (ProductAccrual, error)
+	PutProduct(ctx context.Context, product Product) error
+	ListProducts(ctx context.Context, listProductsInput ListProductsInput) (ListProductsOutput, error)
 }
 
-func NewAccountManager(ddb *dynamodb.Client) AccountManager {
-	return accountManagerImpl{ddb: ddb}
+// NewAccountManager returns an AccountManager backed by DynamoDB, which quotes conversions between currencies at the
+// rates of rates
+func NewAccountManager(ddb *dynamodb.Client, rates RateProvider) AccountManager {
+	return accountManagerImpl{ddb: ddb, rates: rates}
 }
 
 type accountManagerImpl struct {
-	ddb *dynamodb.Client
+	ddb   *dynamodb.Client
+	rates RateProvider
 }
 
 type CreateAccountInput struct {
-	// TODO: Ensure that AccountType is within the maximum length allowed by DynamoDB
-	AccountType string `json:"accountType" validate:"required"`
+	// Account type of a product in the catalog, whose terms the account is created on
+	AccountType string `json:"accountType" validate:"required,accounttype"`
 	// Use pointer for InitialBalance to ensure that it's explicitly defined
 	InitialBalance *int `json:"initialBalance" validate:"required,gte=0"`
+	// ISO 4217 code of the currency the account holds, which defaults to DefaultCurrency. Amounts are always in minor
+	// units of the currency, e.g. cents for USD.
+	Currency string `json:"currency,omitempty" validate:"omitempty,currency"`
+	// How far below zero the balance may be debited, in minor units of Currency
+	OverdraftLimit int `json:"overdraftLimit,omitempty" validate:"gte=0"`
 }
 
 func (manager accountManagerImpl) CreateAccount(ctx context.Context, accountID string, createAccountInput CreateAccountInput) error {
+	product, found, err := manager.getProduct(ctx, createAccountInput.AccountType)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ProductNotFoundError{AccountType: createAccountInput.AccountType}
+	}
+	if err := product.checkAccount(createAccountInput); err != nil {
+		return err
+	}
+
 	item := make(map[string]types.AttributeValue)
 	item[accountIDAttr] = &types.AttributeValueMemberS{Value: accountID}
 	item[accountTypeAttr] = &types.AttributeValueMemberS{Value: createAccountInput.AccountType}
 	item[balanceAttr] = &types.AttributeValueMemberN{Value: strconv.Itoa(*createAccountInput.InitialBalance)}
+	item[currencyAttr] = &types.AttributeValueMemberS{Value: currencyOrDefault(createAccountInput.Currency)}
+	if createAccountInput.OverdraftLimit != 0 {
+		item[overdraftLimitAttr] = &types.AttributeValueMemberN{Value: strconv.Itoa(createAccountInput.OverdraftLimit)}
+	}
+
+	exprAttrValues := make(map[string]types.AttributeValue)
+	exprAttrValues[":closed"] = &types.AttributeValueMemberS{Value: string(AccountStatusClosed)}
+
+	// A closed account is replaced by the new account, while its transactions are kept
+	accountItemTransaction := types.TransactWriteItem{
+		Put: &types.Put{
+			Item:                      item,
+			TableName:                 aws.String(tableName),
+			ConditionExpression:       aws.String(fmt.Sprintf("attribute_not_exists(%s) OR %s = :closed", accountIDAttr, accountStatusAttr)),
+			ExpressionAttributeValues: exprAttrValues,
+		},
+	}
+
+	tx := newTransaction(TransactionTypeCreate, *createAccountInput.InitialBalance, nil, &TransactionParty{
+		AccountID:   accountID,
+		AccountType: createAccountInput.AccountType,
+		Currency:    currencyOrDefault(createAccountInput.Currency),
+		Balance:     *createAccountInput.InitialBalance,
+	})
 
-	input := &dynamodb.PutItemInput{
-		Item:                item,
-		TableName:           aws.String(tableName),
-		ConditionExpression: aws.String(fmt.Sprintf("attribute_not_exists(%s)", accountIDAttr)),
+	input := &dynamodb.TransactWriteItemsInput{
+		TransactItems: append([]types.TransactWriteItem{accountItemTransaction}, tx.toTransactWriteItems()...),
 	}
-	_, err := manager.ddb.PutItem(ctx, input)
+
+	_, err = manager.ddb.TransactWriteItems(ctx, input)
 	if err != nil {
-		var conditionalCheckFailedException *types.ConditionalCheckFailedException
-		if errors.As(err, &conditionalCheckFailedException) {
+		var transactionCanceledException *types.TransactionCanceledException
+		if errors.As(err, &transactionCanceledException) && isConditionalCheckFailed(transactionCanceledException, 0) {
 			return AccountAlreadyExistsError{
 				AccountID:   accountID,
 				AccountType: createAccountInput.AccountType,
@@ -140,28 +270,87 @@ type DeleteAccountInput struct {
 }
 
 func (manager accountManagerImpl) DeleteAccount(ctx context.Context, accountID string, deleteAccountInput DeleteAccountInput) error {
-	item := make(map[string]types.AttributeValue)
-	item[accountIDAttr] = &types.AttributeValueMemberS{Value: accountID}
-	item[accountTypeAttr] = &types.AttributeValueMemberS{Value: deleteAccountInput.AccountType}
+	return retryOnConflict(ctx, transferRetryPolicy, "DeleteAccount", func() error {
+		return manager.deleteAccount(ctx, accountID, deleteAccountInput)
+	})
+}
+
+// deleteAccount makes a single attempt at deleting an account, returning a TransactionConflictError if a concurrent
+// transaction was in progress on the account
+func (manager accountManagerImpl) deleteAccount(ctx context.Context, accountID string, deleteAccountInput DeleteAccountInput) error {
+	key := AccountKey{
+		AccountID:   accountID,
+		AccountType: deleteAccountInput.AccountType,
+	}
+	// Whether holds have expired can't be checked in a condition, so the holds are read first and the delete is
+	// conditioned on them being unchanged
+	account, err := manager.getAccount(ctx, key)
+	if err != nil {
+		var accountDoesNotExistErr AccountDoesNotExistError
+		if errors.As(err, &accountDoesNotExistErr) {
+			// Succeed if the account doesn't exist to simplify error handling and allow for idempotent calls
+			return nil
+		}
+		return err
+	}
+	if err := account.checkDelete(key); err != nil {
+		return err
+	}
 
 	expressionAttributeValues := make(map[string]types.AttributeValue)
 	expressionAttributeValues[":b"] = &types.AttributeValueMemberN{Value: "0"}
+	expressionAttributeValues[":frozen"] = &types.AttributeValueMemberS{Value: string(AccountStatusFrozen)}
+	condition := fmt.Sprintf("attribute_exists(%s) AND %s = :b AND (attribute_not_exists(%s) OR %s <> :frozen)",
+		accountIDAttr, balanceAttr, accountStatusAttr, accountStatusAttr) + holdsVersionCondition(account.holdsVersion, expressionAttributeValues)
+
+	// Frozen accounts are kept until they are unfrozen
+	accountItemTransaction := types.TransactWriteItem{
+		Delete: &types.Delete{
+			Key:                       key.toAccountItem(),
+			TableName:                 aws.String(tableName),
+			ConditionExpression:       aws.String(condition),
+			ExpressionAttributeValues: expressionAttributeValues,
+			// Return the existing item on failure to distinguish a missing account from a non-zero balance
+			ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+		},
+	}
 
-	input := &dynamodb.DeleteItemInput{
-		Key:       item,
-		TableName: aws.String(tableName),
-		// Succeed if the account doesn't exist to simplify error handling and allow for idempotent calls
-		ConditionExpression:       aws.String(fmt.Sprintf("%s = :b OR attribute_not_exists(%s)", balanceAttr, accountIDAttr)),
-		ExpressionAttributeValues: expressionAttributeValues,
+	tx := newTransaction(TransactionTypeDelete, 0, &TransactionParty{
+		AccountID:   accountID,
+		AccountType: deleteAccountInput.AccountType,
+	}, nil)
+
+	input := &dynamodb.TransactWriteItemsInput{
+		TransactItems: append([]types.TransactWriteItem{accountItemTransaction}, tx.toTransactWriteItems()...),
 	}
-	_, err := manager.ddb.DeleteItem(ctx, input)
+
+	_, err = manager.ddb.TransactWriteItems(ctx, input)
 	if err != nil {
-		var conditionalCheckFailedException *types.ConditionalCheckFailedException
-		if errors.As(err, &conditionalCheckFailedException) {
-			return NonZeroBalanceError{
-				AccountID:   accountID,
-				AccountType: deleteAccountInput.AccountType,
+		var transactionCanceledException *types.TransactionCanceledException
+		if errors.As(err, &transactionCanceledException) && isConditionalCheckFailed(transactionCanceledException, 0) {
+			// The account may have been deleted since it was read
+			existing := transactionCanceledException.CancellationReasons[0].Item
+			if len(existing) == 0 {
+				return nil
+			}
+			if status := accountStatusFromItem(existing); status == AccountStatusFrozen {
+				return AccountNotActiveError{
+					AccountID:   accountID,
+					AccountType: deleteAccountInput.AccountType,
+					Status:      status,
+				}
+			}
+			if balance, err := numberFromItem(existing, balanceAttr); err != nil || balance != 0 {
+				return NonZeroBalanceError{
+					AccountID:   accountID,
+					AccountType: deleteAccountInput.AccountType,
+				}
 			}
+			// Only the holds changed since they were read, so the delete is retried against the new holds
+			return TransactionConflictError{Src: key, Delete: true, Err: err}
+		}
+		if errors.As(err, &transactionCanceledException) && isTransactionConflict(transactionCanceledException) {
+			return TransactionConflictError{Src: key, Delete: true, Err: err}
 		}
 		return err
 	}
@@ -169,75 +358,420 @@ func (manager accountManagerImpl) DeleteAccount(ctx context.Context, accountID s
 	return nil
 }
 
+// checkDelete returns an error unless the account may be deleted. Frozen accounts are kept until they are unfrozen,
+// and an account may only be deleted once its balance is zero and it has no holds outstanding.
+func (account account) checkDelete(key AccountKey) error {
+	if account.status == AccountStatusFrozen {
+		return AccountNotActiveError{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
+			Status:      account.status,
+		}
+	}
+	if account.balance != 0 {
+		return NonZeroBalanceError{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
+		}
+	}
+	if account.held() > 0 {
+		return HoldsOutstandingError{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
+		}
+	}
+	return nil
+}
+
 type TransferInput struct {
 	SrcAccountType  string `json:"srcAccountType" validate:"required"`
 	DestAccountID   string `json:"destAccountID" validate:"required"`
 	DestAccountType string `json:"destAccountType" validate:"required"`
 	// Use pointer for Amount to ensure that it's explicitly defined
 	Amount *int `json:"amount" validate:"gt=0"`
+	// Currency of Amount, which is checked against the source account if defined
+	Currency string `json:"currency,omitempty" validate:"omitempty,currency"`
+	// Amount to credit the destination account with in its own currency, for a transfer between accounts of different
+	// currencies which is not converted at a quoted rate
+	DestAmount *int `json:"destAmount,omitempty" validate:"omitempty,gt=0"`
+	// ID of a quote to convert between the currencies of the accounts at, which is used up by the transfer
+	QuoteID string `json:"quoteID,omitempty" validate:"omitempty,max=64,excluded_with=DestAmount"`
+	// Retrying a transfer with the same IdempotencyKey returns the original result rather than transferring again
+	IdempotencyKey string `json:"idempotencyKey,omitempty" validate:"max=255"`
+
+	// Occurrence of a schedule which the transfer makes, whose execution is recorded with the transfer
+	occurrence *Schedule
 }
 
-func (manager accountManagerImpl) Transfer(ctx context.Context, srcAccountID string, transferInput TransferInput) error {
-	exprAttrValues := make(map[string]types.AttributeValue)
-	exprAttrValues[":a"] = &types.AttributeValueMemberN{Value: strconv.Itoa(*transferInput.Amount)}
+type TransferOutput struct {
+	Transaction Transaction `json:"transaction"`
+}
 
-	srcKey := make(map[string]types.AttributeValue)
-	srcKey[accountIDAttr] = &types.AttributeValueMemberS{Value: srcAccountID}
-	srcKey[accountTypeAttr] = &types.AttributeValueMemberS{Value: transferInput.SrcAccountType}
-	srcItemTransaction := types.TransactWriteItem{
-		Update: &types.Update{
-			Key:                       srcKey,
-			TableName:                 aws.String(tableName),
-			UpdateExpression:          aws.String(fmt.Sprintf("SET %s = %s - :a", balanceAttr, balanceAttr)),
-			ConditionExpression:       aws.String(fmt.Sprintf("attribute_exists(%s) and (%s >= :a)", accountIDAttr, balanceAttr)),
-			ExpressionAttributeValues: exprAttrValues,
-		},
+func (manager accountManagerImpl) Transfer(ctx context.Context, srcAccountID string, transferInput TransferInput) (TransferOutput, error) {
+	var output TransferOutput
+	err := retryOnConflict(ctx, transferRetryPolicy, "Transfer", func() error {
+		var err error
+		output, err = manager.transfer(ctx, srcAccountID, transferInput)
+		return err
+	})
+	return output, err
+}
+
+// transfer makes a single attempt at a transfer, returning a TransactionConflictError if it raced with another
+// transaction
+func (manager accountManagerImpl) transfer(ctx context.Context, srcAccountID string, transferInput TransferInput) (TransferOutput, error) {
+	requestHash := transferInput.requestHash()
+	if transferInput.IdempotencyKey != "" {
+		record, found, err := manager.getIdempotencyRecord(ctx, srcAccountID, transferInput.IdempotencyKey)
+		if err != nil {
+			return TransferOutput{}, err
+		}
+		if found {
+			return record.replay(transferInput.IdempotencyKey, requestHash)
+		}
 	}
 
-	destKey := make(map[string]types.AttributeValue)
-	destKey[accountIDAttr] = &types.AttributeValueMemberS{Value: transferInput.DestAccountID}
-	destKey[accountTypeAttr] = &types.AttributeValueMemberS{Value: transferInput.DestAccountType}
-	destItemTransaction := types.TransactWriteItem{
-		Update: &types.Update{
-			Key:                       destKey,
-			TableName:                 aws.String(tableName),
-			UpdateExpression:          aws.String(fmt.Sprintf("SET %s = %s + :a", balanceAttr, balanceAttr)),
-			ConditionExpression:       aws.String(fmt.Sprintf("attribute_exists(%s)", accountIDAttr)),
-			ExpressionAttributeValues: exprAttrValues,
-		},
+	plan, err := manager.planTransfer(ctx, srcAccountID, transferInput)
+	if err != nil {
+		return TransferOutput{}, err
 	}
+	srcKey, destKey, tx, quote := plan.srcKey, plan.destKey, plan.tx, plan.quote
 
+	// The fees are debited from the source with the amount, and credited to the fee income account by the postings of
+	// the transaction recorded in the same call
+	transactItems := []types.TransactWriteItem{
+		balanceUpdate(srcKey, plan.src, tx.Src.Balance),
+		balanceUpdate(destKey, plan.dest, tx.Dest.Balance),
+	}
+	if transferInput.IdempotencyKey != "" {
+		transactItems = append(transactItems, idempotencyPut(srcAccountID, transferInput.IdempotencyKey, requestHash, tx))
+	}
+	quoteIndex := len(transactItems)
+	if quote != nil {
+		transactItems = append(transactItems, quoteDelete(srcAccountID, *quote, tx.Timestamp))
+	}
+	occurrenceIndex := len(transactItems)
+	if occurrence := transferInput.occurrence; occurrence != nil {
+		transactItems = append(transactItems, executionWrites(*occurrence, occurrence.succeeded(tx))...)
+	}
 	input := &dynamodb.TransactWriteItemsInput{
-		TransactItems: []types.TransactWriteItem{srcItemTransaction, destItemTransaction},
+		TransactItems: append(transactItems, tx.toTransactWriteItems()...),
 	}
 
-	_, err := manager.ddb.TransactWriteItems(ctx, input)
+	_, err = manager.ddb.TransactWriteItems(ctx, input)
 	if err != nil {
 		var transactionCanceledException *types.TransactionCanceledException
 		if errors.As(err, &transactionCanceledException) {
-
 			// Index of cancellation reasons is dependent on the ordering of TransactWriteItem above
-			conditionalCheckFailedException := &types.ConditionalCheckFailedException{}
-			if *transactionCanceledException.CancellationReasons[0].Code == conditionalCheckFailedException.ErrorCode() {
-				// TODO: Return a separate error if the source account does not exist
-				return InsufficientFundsError{
-					AccountID:   transferInput.DestAccountID,
-					AccountType: transferInput.DestAccountType,
+			if transferInput.IdempotencyKey != "" && isConditionalCheckFailed(transactionCanceledException, 2) {
+				// A concurrent request claimed the idempotency key first
+				record, found, err := manager.getIdempotencyRecord(ctx, srcAccountID, transferInput.IdempotencyKey)
+				if err != nil {
+					return TransferOutput{}, err
+				}
+				if found {
+					return record.replay(transferInput.IdempotencyKey, requestHash)
 				}
 			}
-
-			if *transactionCanceledException.CancellationReasons[1].Code == conditionalCheckFailedException.ErrorCode() {
-				return AccountDoesNotExistError{
-					AccountID:   transferInput.DestAccountID,
-					AccountType: transferInput.DestAccountType,
+			if quote != nil && isConditionalCheckFailed(transactionCanceledException, quoteIndex) {
+				// A concurrent transfer used the quote first, or it expired since it was read
+				return TransferOutput{}, QuoteNotFoundError{QuoteID: quote.QuoteID}
+			}
+			if transferInput.occurrence != nil && (isConditionalCheckFailed(transactionCanceledException, occurrenceIndex) ||
+				isConditionalCheckFailed(transactionCanceledException, occurrenceIndex+1)) {
+				return TransferOutput{}, errOccurrenceExecuted
+			}
+			if isConditionalCheckFailed(transactionCanceledException, 0) ||
+				isConditionalCheckFailed(transactionCanceledException, 1) ||
+				isTransactionConflict(transactionCanceledException) {
+				return TransferOutput{}, TransactionConflictError{
+					Src:  srcKey,
+					Dest: destKey,
+					Err:  err,
 				}
 			}
 		}
 
+		return TransferOutput{}, err
+	}
+
+	return TransferOutput{
+		Transaction: tx,
+	}, nil
+}
+
+// transferPlan is a transfer which has been checked against its accounts as they were read, and the transaction which
+// would record it
+type transferPlan struct {
+	srcKey, destKey AccountKey
+	src, dest       account
+	quote           *Quote
+	tx              Transaction
+}
+
+// planTransfer reads the accounts of a transfer, checks that it may be made and prices its fees, without writing
+// anything
+func (manager accountManagerImpl) planTransfer(ctx context.Context, srcAccountID string, transferInput TransferInput) (transferPlan, error) {
+	srcKey := AccountKey{
+		AccountID:   srcAccountID,
+		AccountType: transferInput.SrcAccountType,
+	}
+	destKey := AccountKey{
+		AccountID:   transferInput.DestAccountID,
+		AccountType: transferInput.DestAccountType,
+	}
+	amount := *transferInput.Amount
+	// DynamoDB rejects transactions which include multiple operations on the same item
+	if srcKey == destKey {
+		return transferPlan{}, SelfTransferError(srcKey)
+	}
+
+	// The current balances are read up front so that the resulting balances can be recorded on the transaction. The
+	// writes of a transfer are conditioned on these balances being unchanged.
+	src, err := manager.getAccount(ctx, srcKey)
+	if err != nil {
+		var accountDoesNotExistErr AccountDoesNotExistError
+		if errors.As(err, &accountDoesNotExistErr) {
+			return transferPlan{}, SourceAccountDoesNotExistError(accountDoesNotExistErr)
+		}
+		return transferPlan{}, err
+	}
+	if err := src.checkDebit(srcKey); err != nil {
+		return transferPlan{}, err
+	}
+	// Accounts created before the catalog, whose type is not in it, transfer without rules or fees
+	product, found, err := manager.getProduct(ctx, srcKey.AccountType)
+	if err != nil {
+		return transferPlan{}, err
+	}
+	if found {
+		if err := product.checkTransfer(srcAccountID, transferInput); err != nil {
+			return transferPlan{}, err
+		}
+	}
+	if err := src.checkCurrency(srcKey, transferInput.Currency); err != nil {
+		return transferPlan{}, err
+	}
+	if err := src.checkFunds(srcKey, amount); err != nil {
+		return transferPlan{}, err
+	}
+
+	dest, err := manager.getAccount(ctx, destKey)
+	if err != nil {
+		return transferPlan{}, err
+	}
+	if err := dest.checkCredit(destKey); err != nil {
+		return transferPlan{}, err
+	}
+
+	var quote *Quote
+	if transferInput.QuoteID != "" {
+		found, err := manager.getQuote(ctx, srcAccountID, transferInput.QuoteID)
+		if err != nil {
+			return transferPlan{}, err
+		}
+		quote = &found
+	}
+	destAmount, err := transferInput.creditedAmount(src.currency, dest.currency, quote)
+	if err != nil {
+		return transferPlan{}, err
+	}
+
+	converted := src.currency != dest.currency
+	fees := product.Fees.charges(srcAccountID, src.balance, transferInput, converted)
+	// The fees are debited along with the amount, so the funds must cover both
+	if err := src.checkFunds(srcKey, amount+totalFees(fees)); err != nil {
+		return transferPlan{}, err
+	}
+
+	tx := newTransaction(TransactionTypeTransfer, amount, &TransactionParty{
+		AccountID:   srcKey.AccountID,
+		AccountType: srcKey.AccountType,
+		Currency:    src.currency,
+		Balance:     src.balance - amount - totalFees(fees),
+	}, &TransactionParty{
+		AccountID:   destKey.AccountID,
+		AccountType: destKey.AccountType,
+		Currency:    dest.currency,
+		Balance:     dest.balance + destAmount,
+	})
+	if converted {
+		tx.convert(destAmount, quote)
+	}
+	tx.chargeFees(fees)
+
+	return transferPlan{
+		srcKey:  srcKey,
+		destKey: destKey,
+		src:     src,
+		dest:    dest,
+		quote:   quote,
+		tx:      tx,
+	}, nil
+}
+
+// DepositInput credits an account with money from outside the ledger. Deposits are made by operators on behalf of the
+// account's owner, so the account ID is part of the input.
+type DepositInput struct {
+	AccountID   string `json:"accountID" validate:"required"`
+	AccountType string `json:"accountType" validate:"required"`
+	// Use pointer for Amount to ensure that it's explicitly defined
+	Amount *int `json:"amount" validate:"required,gt=0"`
+	// Currency of Amount, which is checked against the account if defined
+	Currency string `json:"currency,omitempty" validate:"omitempty,currency"`
+	// Identifies the movement of money outside the ledger, e.g. the trace ID of a wire or ACH transfer
+	ExternalReference string `json:"externalReference" validate:"required,max=255"`
+}
+
+type DepositOutput struct {
+	Transaction Transaction `json:"transaction"`
+}
+
+func (manager accountManagerImpl) Deposit(ctx context.Context, depositInput DepositInput) (DepositOutput, error) {
+	key := AccountKey{
+		AccountID:   depositInput.AccountID,
+		AccountType: depositInput.AccountType,
+	}
+
+	var output DepositOutput
+	err := retryOnConflict(ctx, transferRetryPolicy, "Deposit", func() error {
+		tx, err := manager.adjustBalance(ctx, key, *depositInput.Amount, depositInput.Currency, TransactionTypeDeposit, depositInput.ExternalReference)
+		output.Transaction = tx
+		return err
+	})
+	return output, err
+}
+
+// WithdrawInput debits an account with money leaving the ledger. Like deposits, withdrawals are made by operators on
+// behalf of the account's owner.
+type WithdrawInput struct {
+	AccountID   string `json:"accountID" validate:"required"`
+	AccountType string `json:"accountType" validate:"required"`
+	// Use pointer for Amount to ensure that it's explicitly defined
+	Amount *int `json:"amount" validate:"required,gt=0"`
+	// Currency of Amount, which is checked against the account if defined
+	Currency string `json:"currency,omitempty" validate:"omitempty,currency"`
+	// Identifies the movement of money outside the ledger, e.g. the trace ID of a wire or ACH transfer
+	ExternalReference string `json:"externalReference" validate:"required,max=255"`
+}
+
+type WithdrawOutput struct {
+	Transaction Transaction `json:"transaction"`
+}
+
+func (manager accountManagerImpl) Withdraw(ctx context.Context, withdrawInput WithdrawInput) (WithdrawOutput, error) {
+	key := AccountKey{
+		AccountID:   withdrawInput.AccountID,
+		AccountType: withdrawInput.AccountType,
+	}
+
+	var output WithdrawOutput
+	err := retryOnConflict(ctx, transferRetryPolicy, "Withdraw", func() error {
+		tx, err := manager.adjustBalance(ctx, key, -*withdrawInput.Amount, withdrawInput.Currency, TransactionTypeWithdrawal, withdrawInput.ExternalReference)
+		output.Transaction = tx
 		return err
+	})
+	return output, err
+}
+
+// adjustBalance makes a single attempt at adding delta to the balance of an account, which is negative for withdrawals.
+// Like transfer, it returns a TransactionConflictError if it raced with another transaction.
+func (manager accountManagerImpl) adjustBalance(ctx context.Context, key AccountKey, delta int, currency string, transactionType TransactionType, externalReference string) (Transaction, error) {
+	account, err := manager.getAccount(ctx, key)
+	if err != nil {
+		return Transaction{}, err
+	}
+	if err := account.checkCurrency(key, currency); err != nil {
+		return Transaction{}, err
+	}
+	balance := account.balance
+	if delta < 0 {
+		if err := account.checkDebit(key); err != nil {
+			return Transaction{}, err
+		}
+		if err := account.checkFunds(key, -delta); err != nil {
+			return Transaction{}, err
+		}
+	} else if err := account.checkCredit(key); err != nil {
+		return Transaction{}, err
 	}
 
-	return nil
+	party := &TransactionParty{
+		AccountID:   key.AccountID,
+		AccountType: key.AccountType,
+		Currency:    account.currency,
+		Balance:     balance + delta,
+	}
+	var tx Transaction
+	var conflictErr TransactionConflictError
+	if delta < 0 {
+		tx = newTransaction(transactionType, -delta, party, nil)
+		conflictErr.Src = key
+	} else {
+		tx = newTransaction(transactionType, delta, nil, party)
+		conflictErr.Dest = key
+	}
+	tx.ExternalReference = externalReference
+
+	input := &dynamodb.TransactWriteItemsInput{
+		TransactItems: append([]types.TransactWriteItem{balanceUpdate(key, account, balance+delta)}, tx.toTransactWriteItems()...),
+	}
+
+	_, err = manager.ddb.TransactWriteItems(ctx, input)
+	if err != nil {
+		var transactionCanceledException *types.TransactionCanceledException
+		if errors.As(err, &transactionCanceledException) &&
+			(isConditionalCheckFailed(transactionCanceledException, 0) || isTransactionConflict(transactionCanceledException)) {
+			conflictErr.Err = err
+			return Transaction{}, conflictErr
+		}
+		return Transaction{}, err
+	}
+
+	return tx, nil
+}
+
+// balanceUpdate sets the balance of an account, conditioned on the balance being unchanged since it was read, on the
+// status of the account allowing the change and, for debits, on the holds being unchanged and the overdraft limit
+// covering the new balance less the amount held
+func balanceUpdate(key AccountKey, account account, newBalance int) types.TransactWriteItem {
+	exprAttrValues := make(map[string]types.AttributeValue)
+	exprAttrValues[":old"] = &types.AttributeValueMemberN{Value: strconv.Itoa(account.balance)}
+	exprAttrValues[":new"] = &types.AttributeValueMemberN{Value: strconv.Itoa(newBalance)}
+	condition := fmt.Sprintf("%s = :old", balanceAttr) + statusCondition(newBalance < account.balance, exprAttrValues)
+	if newBalance < account.balance {
+		condition += holdsVersionCondition(account.holdsVersion, exprAttrValues)
+		condition += overdraftCondition(newBalance-account.held(), exprAttrValues)
+	}
+
+	return types.TransactWriteItem{
+		Update: &types.Update{
+			Key:                       key.toAccountItem(),
+			TableName:                 aws.String(tableName),
+			UpdateExpression:          aws.String(fmt.Sprintf("SET %s = :new", balanceAttr)),
+			ConditionExpression:       aws.String(condition),
+			ExpressionAttributeValues: exprAttrValues,
+		},
+	}
+}
+
+func isConditionalCheckFailed(err *types.TransactionCanceledException, index int) bool {
+	if index >= len(err.CancellationReasons) {
+		return false
+	}
+	code := err.CancellationReasons[index].Code
+	return code != nil && *code == conditionalCheckFailedReason
+}
+
+// isTransactionConflict reports whether the transaction was cancelled because another transaction was in progress on
+// one of its items
+func isTransactionConflict(err *types.TransactionCanceledException) bool {
+	for _, reason := range err.CancellationReasons {
+		if reason.Code != nil && *reason.Code == transactionConflictReason {
+			return true
+		}
+	}
+	return false
 }
 
 type GetBalanceInput struct {
@@ -245,45 +779,126 @@ type GetBalanceInput struct {
 }
 
 type GetBalanceOutput struct {
-	Balance int `json:"balance"`
+	// Ledger balance in minor units of Currency, which is negative while the account is overdrawn
+	Balance  int    `json:"balance"`
+	Currency string `json:"currency"`
+	// How far below zero the balance may be debited
+	OverdraftLimit int `json:"overdraftLimit"`
+	// Amount reserved by unexpired holds, which is not yet debited from Balance
+	Held int `json:"held"`
+	// Amount which may be debited, i.e. Balance plus the remaining overdraft headroom less the amount held
+	Available int `json:"available"`
+	// Interest accrued in whole minor units which has not yet been posted to Balance
+	AccruedInterest int           `json:"accruedInterest,omitempty"`
+	Status          AccountStatus `json:"status"`
 }
 
 func (manager accountManagerImpl) GetBalance(ctx context.Context, accountID string, getBalanceInput GetBalanceInput) (GetBalanceOutput, error) {
-	item := make(map[string]types.AttributeValue)
-	item[accountIDAttr] = &types.AttributeValueMemberS{Value: accountID}
-	item[accountTypeAttr] = &types.AttributeValueMemberS{Value: getBalanceInput.AccountType}
+	account, err := manager.getAccount(ctx, AccountKey{
+		AccountID:   accountID,
+		AccountType: getBalanceInput.AccountType,
+	})
+	if err != nil {
+		return GetBalanceOutput{}, err
+	}
+
+	return account.toGetBalanceOutput(), nil
+}
+
+// account is the state of an account item which transactions depend on
+type account struct {
+	balance        int
+	currency       string
+	overdraftLimit int
+	status         AccountStatus
+	// Holds by ID, including any which have expired but not yet been removed
+	holds        map[string]Hold
+	holdsVersion int
+	// Interest accrued in millionths of a minor unit which has not yet been posted, and the last day it accrued for
+	accruedInterest int
+	accruedThrough  string
+}
+
+func (account account) toGetBalanceOutput() GetBalanceOutput {
+	held := account.held()
+	return GetBalanceOutput{
+		Balance:         account.balance,
+		Currency:        account.currency,
+		OverdraftLimit:  account.overdraftLimit,
+		Held:            held,
+		Available:       account.balance + account.overdraftLimit - held,
+		AccruedInterest: account.accruedMinorUnits(),
+		Status:          account.status,
+	}
+}
 
+// checkCurrency returns a CurrencyMismatchError if a request asserted a currency other than the account's
+func (account account) checkCurrency(key AccountKey, currency string) error {
+	if currency != "" && currency != account.currency {
+		return CurrencyMismatchError{
+			AccountID:       key.AccountID,
+			AccountType:     key.AccountType,
+			AccountCurrency: account.currency,
+			RequestCurrency: currency,
+		}
+	}
+	return nil
+}
+
+func (manager accountManagerImpl) getAccount(ctx context.Context, key AccountKey) (account, error) {
 	input := &dynamodb.GetItemInput{
-		Key:                  item,
-		TableName:            aws.String(tableName),
-		ConsistentRead:       aws.Bool(true),
-		ProjectionExpression: aws.String(balanceAttr),
+		Key:            key.toAccountItem(),
+		TableName:      aws.String(tableName),
+		ConsistentRead: aws.Bool(true),
+		ProjectionExpression: aws.String(fmt.Sprintf("%s,%s,%s,%s,%s,%s,%s,%s", balanceAttr, currencyAttr, overdraftLimitAttr, holdsAttr,
+			holdsVersionAttr, accruedInterestAttr, accruedThroughAttr, accountStatusAttr)),
 	}
 
 	output, err := manager.ddb.GetItem(ctx, input)
 	if err != nil {
-		return GetBalanceOutput{}, err
+		return account{}, err
 	}
 
 	if len(output.Item) == 0 {
-		return GetBalanceOutput{}, AccountDoesNotExistError{
-			AccountID:   accountID,
-			AccountType: getBalanceInput.AccountType,
+		return account{}, AccountDoesNotExistError{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
 		}
 	}
 
-	attrValue, ok := output.Item[balanceAttr].(*types.AttributeValueMemberN)
-	if !ok {
-		return GetBalanceOutput{}, errors.New("balance must be a number")
+	balance, err := numberFromItem(output.Item, balanceAttr)
+	if err != nil {
+		return account{}, err
 	}
-
-	val, err := strconv.Atoi(attrValue.Value)
+	var overdraftLimit int
+	if _, ok := output.Item[overdraftLimitAttr]; ok {
+		overdraftLimit, err = numberFromItem(output.Item, overdraftLimitAttr)
+		if err != nil {
+			return account{}, err
+		}
+	}
+	holds, holdsVersion, err := holdsFromItem(output.Item)
 	if err != nil {
-		return GetBalanceOutput{}, err
+		return account{}, err
+	}
+	var accruedInterest int
+	if _, ok := output.Item[accruedInterestAttr]; ok {
+		accruedInterest, err = numberFromItem(output.Item, accruedInterestAttr)
+		if err != nil {
+			return account{}, err
+		}
 	}
 
-	return GetBalanceOutput{
-		Balance: val,
+	return account{
+		balance: balance,
+		// Accounts created before accounts had currencies hold the default currency
+		currency:        currencyOrDefault(stringFromItem(output.Item, currencyAttr)),
+		overdraftLimit:  overdraftLimit,
+		status:          accountStatusFromItem(output.Item),
+		holds:           holds,
+		holdsVersion:    holdsVersion,
+		accruedInterest: accruedInterest,
+		accruedThrough:  stringFromItem(output.Item, accruedThroughAttr),
 	}, nil
 }
 
@@ -384,3 +999,113 @@ func (manager accountManagerImpl) ListAccountsAdmin(ctx context.Context, listAcc
 		LastEvaluatedKey: lastEvaluatedKey,
 	}, nil
 }
+
+type ListTransactionsInput struct {
+	ExclusiveStartKey *TransactionKey `json:"exclusiveStartKey"`
+	Limit             *int32          `json:"limit"`
+}
+
+type ListTransactionsOutput struct {
+	Transactions     []Transaction  `json:"transactions"`
+	LastEvaluatedKey TransactionKey `json:"lastEvaluatedKey"`
+}
+
+// ListTransactions returns the transactions affecting any of the accounts owned by accountID, most recent first
+func (manager accountManagerImpl) ListTransactions(ctx context.Context, accountID string, listTransactionsInput ListTransactionsInput) (ListTransactionsOutput, error) {
+	exprAttrValues := make(map[string]types.AttributeValue)
+	exprAttrValues[":id"] = &types.AttributeValueMemberS{Value: accountID}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(transactionsTableName),
+		ExpressionAttributeValues: exprAttrValues,
+		KeyConditionExpression:    aws.String(fmt.Sprintf("%s = :id", accountIDAttr)),
+		ScanIndexForward:          aws.Bool(false),
+	}
+
+	if listTransactionsInput.ExclusiveStartKey != nil {
+		input.ExclusiveStartKey = listTransactionsInput.ExclusiveStartKey.toTransactionItem()
+	}
+	if listTransactionsInput.Limit != nil {
+		input.Limit = listTransactionsInput.Limit
+	}
+
+	output, err := manager.ddb.Query(ctx, input)
+	if err != nil {
+		return ListTransactionsOutput{}, err
+	}
+
+	var transactions []Transaction
+	for _, item := range output.Items {
+		transaction, err := NewTransactionFromItem(item)
+		if err != nil {
+			return ListTransactionsOutput{}, err
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	var lastEvaluatedKey TransactionKey
+	if len(output.LastEvaluatedKey) != 0 {
+		lastEvaluatedKey, err = NewTransactionKeyFromItem(output.LastEvaluatedKey)
+		if err != nil {
+			return ListTransactionsOutput{}, err
+		}
+	}
+
+	return ListTransactionsOutput{
+		Transactions:     transactions,
+		LastEvaluatedKey: lastEvaluatedKey,
+	}, nil
+}
+
+// Reconcile derives the balance of an account from the postings of its journal entries, and checks it against the
+// stored balance. A transaction which commits between reading the two may be reported as a mismatch, so a failed
+// reconciliation should be repeated before it is acted on.
+func (manager accountManagerImpl) Reconcile(ctx context.Context, reconcileInput ReconcileInput) (ReconcileOutput, error) {
+	key := AccountKey{
+		AccountID:   reconcileInput.AccountID,
+		AccountType: reconcileInput.AccountType,
+	}
+
+	partition, date, err := reconcilePartition(reconcileInput)
+	if err != nil {
+		return ReconcileOutput{}, err
+	}
+
+	var transactions []Transaction
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(transactionsTableName),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":id": &types.AttributeValueMemberS{Value: partition}},
+		KeyConditionExpression:    aws.String(fmt.Sprintf("%s = :id", accountIDAttr)),
+		ConsistentRead:            aws.Bool(true),
+	}
+	paginator := dynamodb.NewQueryPaginator(manager.ddb, input)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return ReconcileOutput{}, err
+		}
+		for _, item := range output.Items {
+			transaction, err := NewTransactionFromItem(item)
+			if err != nil {
+				return ReconcileOutput{}, err
+			}
+			transactions = append(transactions, transaction)
+		}
+	}
+
+	// System accounts hold every currency, and have no stored balance
+	if key.AccountID == SystemAccountID {
+		output := reconcile(key, currencyOrDefault(reconcileInput.Currency), nil, transactions)
+		output.Date = date
+		return output, nil
+	}
+
+	account, err := manager.getAccount(ctx, key)
+	if err != nil {
+		return ReconcileOutput{}, err
+	}
+	if err := account.checkCurrency(key, reconcileInput.Currency); err != nil {
+		return ReconcileOutput{}, err
+	}
+	return reconcile(key, account.currency, &account.balance, transactions), nil
+}
@@ -0,0 +1,442 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"golang.org/x/exp/slices"
+	"strconv"
+	"strings"
+)
+
+// Attribute of the status of an account item. Accounts created before statuses existed have no status, and are active.
+// STATUS is a reserved word of DynamoDB expressions, so the attribute is named differently.
+const accountStatusAttr = "AccountStatus"
+
+type AccountStatus string
+
+const (
+	AccountStatusActive AccountStatus = "ACTIVE"
+	// Frozen accounts can neither be debited nor credited, e.g. while compliance investigates them
+	AccountStatusFrozen AccountStatus = "FROZEN"
+	// Dormant accounts have not been used for a long time, and can be credited but not debited until reactivated
+	AccountStatusDormant AccountStatus = "DORMANT"
+	// Closed accounts can no longer be used, but are kept along with their transactions
+	AccountStatusClosed AccountStatus = "CLOSED"
+)
+
+// accountStatusTransitions are the statuses that an account may move to from each status. Closing an account is final,
+// although an account of the same type may be created again in its place.
+var accountStatusTransitions = map[AccountStatus][]AccountStatus{
+	AccountStatusActive:  {AccountStatusFrozen, AccountStatusDormant, AccountStatusClosed},
+	AccountStatusFrozen:  {AccountStatusActive, AccountStatusClosed},
+	AccountStatusDormant: {AccountStatusActive, AccountStatusClosed},
+}
+
+// statusesMovingTo returns the statuses that an account may move to status from, including status itself so that
+// setting the current status again succeeds
+func statusesMovingTo(status AccountStatus) []AccountStatus {
+	from := []AccountStatus{status}
+	for _, candidate := range []AccountStatus{AccountStatusActive, AccountStatusFrozen, AccountStatusDormant} {
+		if candidate != status && slices.Contains(accountStatusTransitions[candidate], status) {
+			from = append(from, candidate)
+		}
+	}
+	return from
+}
+
+// AccountNotActiveError is returned when debiting an account which is not active, or crediting an account which is
+// frozen or closed
+type AccountNotActiveError struct {
+	AccountID   string        `json:"accountID"`
+	AccountType string        `json:"accountType"`
+	Status      AccountStatus `json:"status"`
+}
+
+func (err AccountNotActiveError) Error() string {
+	return fmt.Sprintf("The account %s:%s is %s.", err.AccountID, err.AccountType, strings.ToLower(string(err.Status)))
+}
+
+// InvalidStatusTransitionError is returned when setting a status which the current status of an account cannot move to
+type InvalidStatusTransitionError struct {
+	AccountID   string        `json:"accountID"`
+	AccountType string        `json:"accountType"`
+	From        AccountStatus `json:"from"`
+	To          AccountStatus `json:"to"`
+}
+
+func (err InvalidStatusTransitionError) Error() string {
+	return fmt.Sprintf("The account %s:%s cannot move from %s to %s.", err.AccountID, err.AccountType, err.From, err.To)
+}
+
+// checkDebit returns an AccountNotActiveError unless the account may be debited
+func (account account) checkDebit(key AccountKey) error {
+	if account.status != AccountStatusActive {
+		return AccountNotActiveError{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
+			Status:      account.status,
+		}
+	}
+	return nil
+}
+
+// checkCredit returns an AccountNotActiveError unless the account may be credited
+func (account account) checkCredit(key AccountKey) error {
+	if account.status != AccountStatusActive && account.status != AccountStatusDormant {
+		return AccountNotActiveError{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
+			Status:      account.status,
+		}
+	}
+	return nil
+}
+
+// statusCondition returns the condition that the status of an account still allows a debit, or otherwise a credit.
+// Like the balance, the status may have changed since the account was read, so it is checked in the write rather than
+// only up front.
+func statusCondition(debit bool, exprAttrValues map[string]types.AttributeValue) string {
+	exprAttrValues[":activeStatus"] = &types.AttributeValueMemberS{Value: string(AccountStatusActive)}
+	if debit {
+		return fmt.Sprintf(" AND (attribute_not_exists(%s) OR %s = :activeStatus)", accountStatusAttr, accountStatusAttr)
+	}
+	exprAttrValues[":dormantStatus"] = &types.AttributeValueMemberS{Value: string(AccountStatusDormant)}
+	return fmt.Sprintf(" AND (attribute_not_exists(%s) OR %s IN (:activeStatus, :dormantStatus))", accountStatusAttr, accountStatusAttr)
+}
+
+// accountStatusFromItem returns the status of an account item
+func accountStatusFromItem(item map[string]types.AttributeValue) AccountStatus {
+	if status := stringFromItem(item, accountStatusAttr); status != "" {
+		return AccountStatus(status)
+	}
+	return AccountStatusActive
+}
+
+// SetAccountStatusInput moves an account to another status. Statuses are set by operators and admins on behalf of the
+// account's owner, so the account ID is part of the input.
+type SetAccountStatusInput struct {
+	AccountID   string        `json:"accountID" validate:"required"`
+	AccountType string        `json:"accountType" validate:"required"`
+	Status      AccountStatus `json:"status" validate:"required,oneof=ACTIVE FROZEN DORMANT CLOSED"`
+}
+
+// SetAccountStatus moves an account to a status that its current status may move to. An account may only be closed
+// once its balance is zero and it has no holds outstanding.
+func (manager accountManagerImpl) SetAccountStatus(ctx context.Context, setAccountStatusInput SetAccountStatusInput) error {
+	return retryOnConflict(ctx, transferRetryPolicy, "SetAccountStatus", func() error {
+		return manager.setAccountStatus(ctx, setAccountStatusInput)
+	})
+}
+
+// setAccountStatus makes a single attempt at setting the status of an account, returning a TransactionConflictError if
+// another transaction was in progress on the account
+func (manager accountManagerImpl) setAccountStatus(ctx context.Context, setAccountStatusInput SetAccountStatusInput) error {
+	key := AccountKey{
+		AccountID:   setAccountStatusInput.AccountID,
+		AccountType: setAccountStatusInput.AccountType,
+	}
+	status := setAccountStatusInput.Status
+	from := statusesMovingTo(status)
+
+	exprAttrValues := make(map[string]types.AttributeValue)
+	exprAttrValues[":status"] = &types.AttributeValueMemberS{Value: string(status)}
+	var placeholders []string
+	for i, fromStatus := range from {
+		placeholder := fmt.Sprintf(":from%d", i)
+		exprAttrValues[placeholder] = &types.AttributeValueMemberS{Value: string(fromStatus)}
+		placeholders = append(placeholders, placeholder)
+	}
+	fromCondition := fmt.Sprintf("%s IN (%s)", accountStatusAttr, strings.Join(placeholders, ", "))
+	if slices.Contains(from, AccountStatusActive) {
+		fromCondition = fmt.Sprintf("attribute_not_exists(%s) OR %s", accountStatusAttr, fromCondition)
+	}
+	condition := fmt.Sprintf("attribute_exists(%s) AND (%s)", accountIDAttr, fromCondition)
+	if status == AccountStatusClosed {
+		// Whether holds have expired can't be checked in a condition, so the holds are read first and the write is
+		// conditioned on them being unchanged
+		account, err := manager.getAccount(ctx, key)
+		if err != nil {
+			return err
+		}
+		if err := account.checkStatusChange(key, status); err != nil {
+			return err
+		}
+		exprAttrValues[":zero"] = &types.AttributeValueMemberN{Value: "0"}
+		condition += fmt.Sprintf(" AND %s = :zero", balanceAttr) + holdsVersionCondition(account.holdsVersion, exprAttrValues)
+	}
+
+	// A transaction rather than an update, as only transactions return the existing item when their condition fails
+	_, err := manager.ddb.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Update: &types.Update{
+					Key:                       key.toAccountItem(),
+					TableName:                 aws.String(tableName),
+					UpdateExpression:          aws.String(fmt.Sprintf("SET %s = :status", accountStatusAttr)),
+					ConditionExpression:       aws.String(condition),
+					ExpressionAttributeValues: exprAttrValues,
+					// Return the existing item on failure to distinguish a missing account from one in another status
+					ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+				},
+			},
+		},
+	})
+	if err != nil {
+		var transactionCanceledException *types.TransactionCanceledException
+		if errors.As(err, &transactionCanceledException) {
+			if isConditionalCheckFailed(transactionCanceledException, 0) {
+				if statusErr := statusChangeError(key, status, transactionCanceledException.CancellationReasons[0].Item); statusErr != nil {
+					return statusErr
+				}
+				// Only the holds changed since they were read, so the change is retried against the new holds
+				return TransactionConflictError{Src: key, Status: status, Err: err}
+			}
+			if isTransactionConflict(transactionCanceledException) {
+				return TransactionConflictError{Src: key, Status: status, Err: err}
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+// statusChangeError returns the reason that the status of an account item could not be set, or nil if it was only its
+// holds which changed
+func statusChangeError(key AccountKey, status AccountStatus, item map[string]types.AttributeValue) error {
+	if len(item) == 0 {
+		return AccountDoesNotExistError{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
+		}
+	}
+	if current := accountStatusFromItem(item); !slices.Contains(statusesMovingTo(status), current) {
+		return InvalidStatusTransitionError{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
+			From:        current,
+			To:          status,
+		}
+	}
+	if balance, err := numberFromItem(item, balanceAttr); err != nil || balance != 0 {
+		return NonZeroBalanceError{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
+		}
+	}
+	return nil
+}
+
+// checkStatusChange returns an error unless the account may move to status. An account may only be closed once its
+// balance is zero and it has no holds outstanding.
+func (account account) checkStatusChange(key AccountKey, status AccountStatus) error {
+	if !slices.Contains(statusesMovingTo(status), account.status) {
+		return InvalidStatusTransitionError{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
+			From:        account.status,
+			To:          status,
+		}
+	}
+	if status != AccountStatusClosed {
+		return nil
+	}
+	if account.balance != 0 {
+		return NonZeroBalanceError{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
+		}
+	}
+	if account.held() > 0 {
+		return HoldsOutstandingError{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
+		}
+	}
+	return nil
+}
+
+// HoldsOutstandingError is returned when closing or deleting an account which has funds reserved by holds that have
+// not been captured or released
+type HoldsOutstandingError struct {
+	AccountID   string `json:"accountID"`
+	AccountType string `json:"accountType"`
+}
+
+func (err HoldsOutstandingError) Error() string {
+	return fmt.Sprintf("The account %s:%s has holds outstanding.", err.AccountID, err.AccountType)
+}
+
+// CloseAccountInput closes one of the caller's accounts, sweeping its balance to the destination account
+type CloseAccountInput struct {
+	AccountType     string `json:"accountType" validate:"required"`
+	DestAccountID   string `json:"destAccountID" validate:"required"`
+	DestAccountType string `json:"destAccountType" validate:"required"`
+}
+
+type CloseAccountOutput struct {
+	// Records the sweep of the balance and the closure, which is nil if the account did not exist or was already closed
+	Transaction *Transaction `json:"transaction,omitempty"`
+	// Records the posting of the interest the account had accrued, which is swept with the rest of its balance, and is
+	// nil if it had accrued none
+	Interest *Transaction `json:"interest,omitempty"`
+}
+
+// sweep checks that the account may be closed by sweeping its balance to dest, and returns the transaction which
+// records it. The destination is only a party to the transaction if there is a balance to sweep.
+func (account account) sweep(key AccountKey, destKey AccountKey, dest account) (Transaction, error) {
+	// Only an active account may be debited, so a frozen or dormant account is never closed by a sweep, even with a zero
+	// balance. A dormant account is reactivated first, while a frozen one may only be closed by set-account-status once
+	// its balance is zero.
+	if err := account.checkDebit(key); err != nil {
+		return Transaction{}, err
+	}
+	// An overdrawn account is closed once its balance is brought back to zero
+	if account.balance < 0 {
+		return Transaction{}, NonZeroBalanceError{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
+		}
+	}
+	if account.held() > 0 {
+		return Transaction{}, HoldsOutstandingError{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
+		}
+	}
+	if err := dest.checkCredit(destKey); err != nil {
+		return Transaction{}, err
+	}
+	// Sweeps are not converted, so the destination must hold the same currency
+	if account.currency != dest.currency {
+		return Transaction{}, ConversionRequiredError{
+			SrcCurrency:  account.currency,
+			DestCurrency: dest.currency,
+		}
+	}
+
+	src := &TransactionParty{
+		AccountID:   key.AccountID,
+		AccountType: key.AccountType,
+		Currency:    account.currency,
+		Balance:     0,
+	}
+	if account.balance == 0 {
+		return newTransaction(TransactionTypeClose, 0, src, nil), nil
+	}
+	return newTransaction(TransactionTypeClose, account.balance, src, &TransactionParty{
+		AccountID:   destKey.AccountID,
+		AccountType: destKey.AccountType,
+		Currency:    dest.currency,
+		Balance:     dest.balance + account.balance,
+	}), nil
+}
+
+// CloseAccount sweeps the balance of an account to the destination and marks the account closed in one transaction.
+// Like DeleteAccount, closing an account which doesn't exist succeeds, as does closing it again.
+func (manager accountManagerImpl) CloseAccount(ctx context.Context, accountID string, closeAccountInput CloseAccountInput) (CloseAccountOutput, error) {
+	var output CloseAccountOutput
+	err := retryOnConflict(ctx, transferRetryPolicy, "CloseAccount", func() error {
+		var err error
+		output, err = manager.closeAccount(ctx, accountID, closeAccountInput)
+		return err
+	})
+	return output, err
+}
+
+// closeAccount makes a single attempt at closing an account, returning a TransactionConflictError if it raced with
+// another transaction
+func (manager accountManagerImpl) closeAccount(ctx context.Context, accountID string, closeAccountInput CloseAccountInput) (CloseAccountOutput, error) {
+	key := AccountKey{
+		AccountID:   accountID,
+		AccountType: closeAccountInput.AccountType,
+	}
+	destKey := AccountKey{
+		AccountID:   closeAccountInput.DestAccountID,
+		AccountType: closeAccountInput.DestAccountType,
+	}
+
+	account, err := manager.getAccount(ctx, key)
+	if err != nil {
+		var accountDoesNotExistErr AccountDoesNotExistError
+		if errors.As(err, &accountDoesNotExistErr) {
+			// Succeed if the account doesn't exist to simplify error handling and allow for idempotent calls
+			return CloseAccountOutput{}, nil
+		}
+		return CloseAccountOutput{}, err
+	}
+	if account.status == AccountStatusClosed {
+		return CloseAccountOutput{}, nil
+	}
+	// DynamoDB rejects transactions which include multiple operations on the same item
+	if key == destKey {
+		return CloseAccountOutput{}, SelfTransferError(key)
+	}
+	dest, err := manager.getAccount(ctx, destKey)
+	if err != nil {
+		return CloseAccountOutput{}, err
+	}
+	closing, interest := account.postAccruedInterest(key)
+	tx, err := closing.sweep(key, destKey, dest)
+	if err != nil {
+		return CloseAccountOutput{}, err
+	}
+
+	// The accrued interest is posted and swept in the same transaction as the account is closed
+	transactItems := []types.TransactWriteItem{closeUpdate(key, account)}
+	if tx.Dest != nil {
+		transactItems = append(transactItems, balanceUpdate(destKey, dest, tx.Dest.Balance))
+	}
+	if interest != nil {
+		transactItems = append(transactItems, interest.toTransactWriteItems()...)
+	}
+	_, err = manager.ddb.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: append(transactItems, tx.toTransactWriteItems()...),
+	})
+	if err != nil {
+		var transactionCanceledException *types.TransactionCanceledException
+		if errors.As(err, &transactionCanceledException) &&
+			(isConditionalCheckFailed(transactionCanceledException, 0) ||
+				(tx.Dest != nil && isConditionalCheckFailed(transactionCanceledException, 1)) ||
+				isTransactionConflict(transactionCanceledException)) {
+			return CloseAccountOutput{}, TransactionConflictError{
+				Src:  key,
+				Dest: destKey,
+				Err:  err,
+			}
+		}
+		return CloseAccountOutput{}, err
+	}
+
+	return CloseAccountOutput{
+		Transaction: &tx,
+		Interest:    interest,
+	}, nil
+}
+
+// closeUpdate empties and closes an account, including any interest it has accrued, conditioned on its balance and
+// holds being unchanged since they were read and on it still being active
+func closeUpdate(key AccountKey, account account) types.TransactWriteItem {
+	exprAttrValues := make(map[string]types.AttributeValue)
+	exprAttrValues[":old"] = &types.AttributeValueMemberN{Value: strconv.Itoa(account.balance)}
+	exprAttrValues[":zero"] = &types.AttributeValueMemberN{Value: "0"}
+	exprAttrValues[":closed"] = &types.AttributeValueMemberS{Value: string(AccountStatusClosed)}
+	condition := fmt.Sprintf("%s = :old", balanceAttr) +
+		holdsVersionCondition(account.holdsVersion, exprAttrValues) +
+		statusCondition(true, exprAttrValues)
+
+	return types.TransactWriteItem{
+		Update: &types.Update{
+			Key:       key.toAccountItem(),
+			TableName: aws.String(tableName),
+			UpdateExpression: aws.String(fmt.Sprintf("SET %s = :zero, %s = :closed REMOVE %s",
+				balanceAttr, accountStatusAttr, accruedInterestAttr)),
+			ConditionExpression:       aws.String(condition),
+			ExpressionAttributeValues: exprAttrValues,
+		},
+	}
+}
@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestStatusesMovingTo(t *testing.T) {
+	tests := []struct {
+		status   AccountStatus
+		expected []AccountStatus
+	}{
+		{status: AccountStatusActive, expected: []AccountStatus{AccountStatusActive, AccountStatusFrozen, AccountStatusDormant}},
+		{status: AccountStatusFrozen, expected: []AccountStatus{AccountStatusFrozen, AccountStatusActive}},
+		{status: AccountStatusDormant, expected: []AccountStatus{AccountStatusDormant, AccountStatusActive}},
+		{status: AccountStatusClosed, expected: []AccountStatus{AccountStatusClosed, AccountStatusActive, AccountStatusFrozen, AccountStatusDormant}},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.status), func(t *testing.T) {
+			assert.Equal(t, test.expected, statusesMovingTo(test.status))
+		})
+	}
+}
+
+func TestAccount_CheckDebitAndCredit(t *testing.T) {
+	key := AccountKey{AccountID: "123456789", AccountType: "savings"}
+	tests := []struct {
+		status    AccountStatus
+		canDebit  bool
+		canCredit bool
+	}{
+		{status: AccountStatusActive, canDebit: true, canCredit: true},
+		{status: AccountStatusFrozen},
+		{status: AccountStatusDormant, canCredit: true},
+		{status: AccountStatusClosed},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.status), func(t *testing.T) {
+			account := account{status: test.status}
+			notActiveErr := AccountNotActiveError{AccountID: "123456789", AccountType: "savings", Status: test.status}
+			if test.canDebit {
+				assert.NoError(t, account.checkDebit(key))
+			} else {
+				assert.Equal(t, notActiveErr, account.checkDebit(key))
+			}
+			if test.canCredit {
+				assert.NoError(t, account.checkCredit(key))
+			} else {
+				assert.Equal(t, notActiveErr, account.checkCredit(key))
+			}
+		})
+	}
+}
+
+func TestAccount_SweepErrors(t *testing.T) {
+	key := AccountKey{AccountID: "123456789", AccountType: "checking"}
+	destKey := AccountKey{AccountID: "123456789", AccountType: "savings"}
+	dest := account{currency: "USD", status: AccountStatusActive}
+	tests := []struct {
+		name     string
+		account  account
+		dest     account
+		expected error
+	}{
+		{
+			name:     "overdrawn",
+			account:  account{balance: -5, currency: "USD", overdraftLimit: 10, status: AccountStatusActive},
+			dest:     dest,
+			expected: NonZeroBalanceError{AccountID: "123456789", AccountType: "checking"},
+		},
+		{
+			name:     "destination closed",
+			account:  account{balance: 5, currency: "USD", status: AccountStatusActive},
+			dest:     account{currency: "USD", status: AccountStatusClosed},
+			expected: AccountNotActiveError{AccountID: "123456789", AccountType: "savings", Status: AccountStatusClosed},
+		},
+		{
+			name:     "dormant",
+			account:  account{balance: 5, currency: "USD", status: AccountStatusDormant},
+			dest:     dest,
+			expected: AccountNotActiveError{AccountID: "123456789", AccountType: "checking", Status: AccountStatusDormant},
+		},
+		{
+			name:     "currencies differ",
+			account:  account{balance: 5, currency: "USD", status: AccountStatusActive},
+			dest:     account{currency: "EUR", status: AccountStatusActive},
+			expected: ConversionRequiredError{SrcCurrency: "USD", DestCurrency: "EUR"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.account.sweep(key, destKey, test.dest)
+			assert.Equal(t, test.expected, err)
+		})
+	}
+}
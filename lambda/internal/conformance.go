@@ -0,0 +1,2852 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/exp/slices"
+	"math/big"
+	mathrand "math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AccountManagerConformanceSuite verifies that an AccountManager implementation behaves exactly like the DynamoDB
+// implementation, including the error types it returns and its pagination semantics. Run it against an implementation
+// with suite.Run:
+//
+//	suite.Run(t, &internal.AccountManagerConformanceSuite{NewAccountManager: internal.NewInMemoryAccountManager})
+//
+// Each test uses freshly generated account IDs, so the suite can be run against a backend that already holds data.
+type AccountManagerConformanceSuite struct {
+	suite.Suite
+	// NewAccountManager returns an implementation which quotes at the given rates
+	NewAccountManager func(rates RateProvider) AccountManager
+
+	manager AccountManager
+}
+
+// conformanceRates are the exchange rates that the suite quotes at
+var conformanceRates = NewStaticRateProvider(map[string]*big.Rat{
+	"USD/JPY": big.NewRat(14825, 100),
+	"USD/EUR": big.NewRat(9214, 10000),
+})
+
+// conformanceAccountTypes are the products that the suite's accounts are created with, whose terms allow every
+// operation that the tests make. Tests of the terms of products use account types of their own, so that they don't
+// change these products for other tests sharing the backend.
+var conformanceAccountTypes = []string{"savings", "checking", "merchant", "brokerage"}
+
+func (suite *AccountManagerConformanceSuite) SetupTest() {
+	suite.manager = suite.NewAccountManager(conformanceRates)
+	for _, accountType := range conformanceAccountTypes {
+		suite.putProduct(Product{AccountType: accountType, OverdraftAllowance: 100})
+	}
+}
+
+func (suite *AccountManagerConformanceSuite) TestCreateAccount() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+
+	// === When ===
+	err := suite.manager.CreateAccount(ctx, accountID, CreateAccountInput{AccountType: "savings", InitialBalance: aws.Int(5)})
+
+	// === Then ===
+	suite.Require().NoError(err)
+	suite.assertBalance(accountID, "savings", 5)
+	output, err := suite.manager.GetBalance(ctx, accountID, GetBalanceInput{AccountType: "savings"})
+	suite.Require().NoError(err)
+	suite.Equal(DefaultCurrency, output.Currency)
+}
+
+func (suite *AccountManagerConformanceSuite) TestCreateAccount_WithCurrency() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+
+	// === When ===
+	err := suite.manager.CreateAccount(ctx, accountID, CreateAccountInput{AccountType: "savings", InitialBalance: aws.Int(500), Currency: "JPY"})
+
+	// === Then ===
+	suite.Require().NoError(err)
+	output, err := suite.manager.GetBalance(ctx, accountID, GetBalanceInput{AccountType: "savings"})
+	suite.Require().NoError(err)
+	suite.Equal(GetBalanceOutput{Balance: 500, Currency: "JPY", Available: 500, Status: AccountStatusActive}, output)
+}
+
+func (suite *AccountManagerConformanceSuite) TestCreateAccount_ErrorWhenAccountAlreadyExists() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "savings", 5)
+
+	// === When ===
+	err := suite.manager.CreateAccount(ctx, accountID, CreateAccountInput{AccountType: "savings", InitialBalance: aws.Int(10)})
+
+	// === Then ===
+	suite.Equal(AccountAlreadyExistsError{AccountID: accountID, AccountType: "savings"}, err)
+	suite.assertBalance(accountID, "savings", 5)
+}
+
+func (suite *AccountManagerConformanceSuite) TestCreateAccount_ErrorWhenProductNotFound() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	accountType := "unlisted-" + accountID
+
+	// === When ===
+	err := suite.manager.CreateAccount(ctx, accountID, CreateAccountInput{AccountType: accountType, InitialBalance: aws.Int(5)})
+
+	// === Then ===
+	suite.Equal(ProductNotFoundError{AccountType: accountType}, err)
+	_, err = suite.manager.GetBalance(ctx, accountID, GetBalanceInput{AccountType: accountType})
+	suite.ErrorAs(err, &AccountDoesNotExistError{})
+}
+
+func (suite *AccountManagerConformanceSuite) TestCreateAccount_ErrorWhenProductTermsAreNotMet() {
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	accountType := "premium-" + accountID
+	suite.putProduct(Product{AccountType: accountType, MinimumOpeningBalance: 100, OverdraftAllowance: 50})
+	retiredAccountType := "retired-" + accountID
+	suite.putProduct(Product{AccountType: retiredAccountType, Retired: true})
+
+	tests := []struct {
+		name        string
+		input       CreateAccountInput
+		expectedErr error
+	}{
+		{
+			name:        "below the minimum opening balance",
+			input:       CreateAccountInput{AccountType: accountType, InitialBalance: aws.Int(99)},
+			expectedErr: MinimumOpeningBalanceError{AccountType: accountType, MinimumOpeningBalance: 100},
+		},
+		{
+			name:        "overdraft limit above the allowance",
+			input:       CreateAccountInput{AccountType: accountType, InitialBalance: aws.Int(100), OverdraftLimit: 51},
+			expectedErr: OverdraftAllowanceExceededError{AccountType: accountType, OverdraftAllowance: 50},
+		},
+		{
+			name:        "retired product",
+			input:       CreateAccountInput{AccountType: retiredAccountType, InitialBalance: aws.Int(0)},
+			expectedErr: ProductRetiredError{AccountType: retiredAccountType},
+		},
+	}
+
+	for _, test := range tests {
+		suite.Run(test.name, func() {
+			// === When ===
+			err := suite.manager.CreateAccount(ctx, accountID, test.input)
+
+			// === Then ===
+			suite.Equal(test.expectedErr, err)
+		})
+	}
+
+	suite.NoError(suite.manager.CreateAccount(ctx, accountID, CreateAccountInput{
+		AccountType:    accountType,
+		InitialBalance: aws.Int(100),
+		OverdraftLimit: 50,
+	}))
+}
+
+func (suite *AccountManagerConformanceSuite) TestTransfer_ErrorWhenTransferRulesForbid() {
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	otherAccountID := newConformanceAccountID()
+	accountType := "restricted-" + accountID
+	suite.putProduct(Product{AccountType: accountType, TransferRules: TransferRules{
+		MaxAmount:        5,
+		OwnAccountsOnly:  true,
+		DestAccountTypes: []string{"checking"},
+	}})
+	suite.createAccount(accountID, accountType, 10)
+	suite.createAccount(accountID, "checking", 0)
+	suite.createAccount(accountID, "savings", 0)
+	suite.createAccount(otherAccountID, "checking", 0)
+
+	tests := []struct {
+		name           string
+		destAccountID  string
+		destType       string
+		amount         int
+		expectedReason string
+	}{
+		{name: "more than the maximum amount", destAccountID: accountID, destType: "checking", amount: 6, expectedReason: "transfers of more than 5"},
+		{name: "to another owner", destAccountID: otherAccountID, destType: "checking", amount: 5, expectedReason: "transfers to accounts of other owners"},
+		{name: "to another account type", destAccountID: accountID, destType: "savings", amount: 5, expectedReason: "transfers to accounts of type savings"},
+	}
+
+	for _, test := range tests {
+		suite.Run(test.name, func() {
+			// === When ===
+			_, err := suite.manager.Transfer(ctx, accountID, TransferInput{
+				SrcAccountType:  accountType,
+				DestAccountID:   test.destAccountID,
+				DestAccountType: test.destType,
+				Amount:          aws.Int(test.amount),
+			})
+
+			// === Then ===
+			suite.Equal(TransferNotAllowedError{AccountType: accountType, Reason: test.expectedReason}, err)
+			suite.assertBalance(accountID, accountType, 10)
+		})
+	}
+
+	_, err := suite.manager.Transfer(ctx, accountID, TransferInput{
+		SrcAccountType:  accountType,
+		DestAccountID:   accountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(5),
+	})
+	suite.Require().NoError(err)
+	suite.assertBalance(accountID, "checking", 5)
+}
+
+func (suite *AccountManagerConformanceSuite) TestTransfer_ChargesFees() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	otherAccountID := newConformanceAccountID()
+	accountType := "fees-" + accountID
+	suite.putProduct(Product{AccountType: accountType, Fees: FeeRules{
+		CrossOwner:          &Fee{Flat: 5},
+		BelowMinimumBalance: &Fee{Flat: 10},
+		MinimumBalance:      50,
+	}})
+	suite.createAccount(accountID, accountType, 100)
+	suite.createAccount(otherAccountID, "checking", 0)
+
+	// === When ===
+	output, err := suite.manager.Transfer(ctx, accountID, TransferInput{
+		SrcAccountType:  accountType,
+		DestAccountID:   otherAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(60),
+	})
+
+	// === Then ===
+	suite.Require().NoError(err)
+	suite.Equal(60, output.Transaction.Amount)
+	suite.Equal([]FeeCharge{{Type: FeeTypeCrossOwner, Amount: 5}, {Type: FeeTypeBelowMinimumBalance, Amount: 10}}, output.Transaction.Fees)
+	suite.Equal(&TransactionParty{AccountID: accountID, AccountType: accountType, Currency: "USD", Balance: 25}, output.Transaction.Src)
+	suite.Equal(&TransactionParty{AccountID: otherAccountID, AccountType: "checking", Currency: "USD", Balance: 60}, output.Transaction.Dest)
+	suite.Contains(output.Transaction.Postings, Posting{AccountID: SystemAccountID, AccountType: "fee-income", Currency: "USD", Amount: 15})
+	suite.assertBalance(accountID, accountType, 25)
+	suite.assertBalance(otherAccountID, "checking", 60)
+
+	// The fees are recorded in the history of the source account, which reconciles with them
+	transactions, err := suite.manager.ListTransactions(ctx, accountID, ListTransactionsInput{Limit: aws.Int32(1)})
+	suite.Require().NoError(err)
+	suite.Require().Len(transactions.Transactions, 1)
+	suite.Equal(output.Transaction.Fees, transactions.Transactions[0].Fees)
+	reconciled, err := suite.manager.Reconcile(ctx, ReconcileInput{AccountID: accountID, AccountType: accountType})
+	suite.Require().NoError(err)
+	suite.True(reconciled.Reconciled)
+	feeIncome, err := suite.manager.Reconcile(ctx, ReconcileInput{AccountID: SystemAccountID, AccountType: "fee-income", Date: output.Transaction.Timestamp.Format("2006-01-02")})
+	suite.Require().NoError(err)
+	suite.GreaterOrEqual(feeIncome.PostedBalance, 15)
+	suite.True(feeIncome.Reconciled)
+}
+
+func (suite *AccountManagerConformanceSuite) TestTransfer_ErrorWhenFundsDoNotCoverFees() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	otherAccountID := newConformanceAccountID()
+	accountType := "fees-" + accountID
+	suite.putProduct(Product{AccountType: accountType, Fees: FeeRules{CrossOwner: &Fee{Flat: 5}}})
+	suite.createAccount(accountID, accountType, 10)
+	suite.createAccount(otherAccountID, "checking", 0)
+
+	// === When ===
+	_, err := suite.manager.Transfer(ctx, accountID, TransferInput{
+		SrcAccountType:  accountType,
+		DestAccountID:   otherAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(6),
+	})
+
+	// === Then ===
+	suite.Equal(InsufficientFundsError{AccountID: accountID, AccountType: accountType}, err)
+	suite.assertBalance(accountID, accountType, 10)
+	suite.assertBalance(otherAccountID, "checking", 0)
+}
+
+func (suite *AccountManagerConformanceSuite) TestQuoteTransfer() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	otherAccountID := newConformanceAccountID()
+	accountType := "fees-" + accountID
+	suite.putProduct(Product{AccountType: accountType, Fees: FeeRules{
+		CrossOwner: &Fee{Flat: 5},
+		FX:         &Fee{BasisPoints: 100},
+	}})
+	suite.createAccount(accountID, accountType, 1000)
+	suite.createAccountInCurrency(otherAccountID, "checking", 0, "EUR")
+	quote, err := suite.manager.Quote(ctx, accountID, QuoteInput{SrcCurrency: "USD", DestCurrency: "EUR", Amount: aws.Int(500)})
+	suite.Require().NoError(err)
+	transferInput := TransferInput{
+		SrcAccountType:  accountType,
+		DestAccountID:   otherAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(500),
+		QuoteID:         quote.Quote.QuoteID,
+	}
+
+	// === When ===
+	output, err := suite.manager.QuoteTransfer(ctx, accountID, transferInput)
+
+	// === Then ===
+	suite.Require().NoError(err)
+	suite.Equal(QuoteTransferOutput{
+		Amount:       500,
+		Currency:     "USD",
+		DestAmount:   quote.Quote.DestAmount,
+		DestCurrency: "EUR",
+		Fees:         []FeeCharge{{Type: FeeTypeCrossOwner, Amount: 5}, {Type: FeeTypeFX, Amount: 5}},
+		TotalFees:    10,
+		TotalDebit:   510,
+	}, output)
+	suite.assertBalance(accountID, accountType, 1000)
+	suite.assertBalance(otherAccountID, "checking", 0)
+
+	// The quote is still available to the transfer, which charges the quoted fees
+	transfer, err := suite.manager.Transfer(ctx, accountID, transferInput)
+	suite.Require().NoError(err)
+	suite.Equal(output.Fees, transfer.Transaction.Fees)
+	suite.assertBalance(accountID, accountType, 490)
+}
+
+func (suite *AccountManagerConformanceSuite) TestQuoteTransfer_ErrorWhenTransferWouldFail() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "savings", 3)
+	suite.createAccount(accountID, "checking", 0)
+
+	// === When ===
+	_, err := suite.manager.QuoteTransfer(ctx, accountID, TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   accountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(4),
+	})
+
+	// === Then ===
+	suite.Equal(InsufficientFundsError{AccountID: accountID, AccountType: "savings"}, err)
+}
+
+func (suite *AccountManagerConformanceSuite) TestListProducts() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	product := Product{
+		AccountType:           "listed-" + accountID,
+		Description:           "Listed account",
+		MinimumOpeningBalance: 100,
+		OverdraftAllowance:    50,
+		TransferRules:         TransferRules{MaxAmount: 5, OwnAccountsOnly: true, DestAccountTypes: []string{"savings", "checking"}},
+	}
+	suite.putProduct(product)
+	retired := Product{AccountType: "retired-" + accountID, Retired: true}
+	suite.putProduct(retired)
+
+	// === When ===
+	active, err := suite.manager.ListProducts(ctx, ListProductsInput{})
+	suite.Require().NoError(err)
+	all, err := suite.manager.ListProducts(ctx, ListProductsInput{IncludeRetired: true})
+	suite.Require().NoError(err)
+
+	// === Then ===
+	// String sets are read back in order
+	product.TransferRules.DestAccountTypes = []string{"checking", "savings"}
+	suite.Equal([]Product{product}, productsOf(active, accountID))
+	suite.Equal([]Product{product, retired}, productsOf(all, accountID))
+	suite.True(slices.IsSortedFunc(all.Products, func(a, b Product) bool {
+		return a.AccountType < b.AccountType
+	}))
+}
+
+func (suite *AccountManagerConformanceSuite) TestDeleteAccount() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "savings", 0)
+
+	// === When ===
+	err := suite.manager.DeleteAccount(ctx, accountID, DeleteAccountInput{AccountType: "savings"})
+
+	// === Then ===
+	suite.Require().NoError(err)
+	_, err = suite.manager.GetBalance(ctx, accountID, GetBalanceInput{AccountType: "savings"})
+	suite.Equal(AccountDoesNotExistError{AccountID: accountID, AccountType: "savings"}, err)
+}
+
+func (suite *AccountManagerConformanceSuite) TestDeleteAccount_SucceedsWhenAccountDoesNotExist() {
+	// === When ===
+	err := suite.manager.DeleteAccount(context.Background(), newConformanceAccountID(), DeleteAccountInput{AccountType: "savings"})
+
+	// === Then ===
+	suite.NoError(err)
+}
+
+func (suite *AccountManagerConformanceSuite) TestDeleteAccount_ErrorWhenBalanceIsNonZero() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "savings", 5)
+
+	// === When ===
+	err := suite.manager.DeleteAccount(ctx, accountID, DeleteAccountInput{AccountType: "savings"})
+
+	// === Then ===
+	suite.Equal(NonZeroBalanceError{AccountID: accountID, AccountType: "savings"}, err)
+	suite.assertBalance(accountID, "savings", 5)
+}
+
+func (suite *AccountManagerConformanceSuite) TestDeleteAccount_ErrorWhenHoldsAreOutstanding() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 0)
+	suite.createAccount(accountID, "savings", 0)
+	// The hold is covered by the overdraft, so the balance stays at zero
+	suite.Require().NoError(suite.manager.SetOverdraftLimit(ctx, SetOverdraftLimitInput{
+		AccountID:      accountID,
+		AccountType:    "checking",
+		OverdraftLimit: aws.Int(10),
+	}))
+	placed, err := suite.manager.PlaceHold(ctx, PlaceHoldInput{
+		AccountID:         accountID,
+		AccountType:       "checking",
+		DestAccountID:     accountID,
+		DestAccountType:   "savings",
+		Amount:            aws.Int(4),
+		ExternalReference: "auth-0001",
+	})
+	suite.Require().NoError(err)
+
+	// === When ===
+	err = suite.manager.DeleteAccount(ctx, accountID, DeleteAccountInput{AccountType: "checking"})
+
+	// === Then ===
+	suite.Equal(HoldsOutstandingError{AccountID: accountID, AccountType: "checking"}, err)
+	suite.assertBalance(accountID, "checking", 0)
+
+	// The account may be deleted once the hold is released
+	suite.Require().NoError(suite.manager.ReleaseHold(ctx, ReleaseHoldInput{
+		AccountID:   accountID,
+		AccountType: "checking",
+		HoldID:      placed.Hold.HoldID,
+	}))
+	suite.NoError(suite.manager.DeleteAccount(ctx, accountID, DeleteAccountInput{AccountType: "checking"}))
+}
+
+func (suite *AccountManagerConformanceSuite) TestGetBalance_ErrorWhenAccountDoesNotExist() {
+	// === Given ===
+	accountID := newConformanceAccountID()
+
+	// === When ===
+	_, err := suite.manager.GetBalance(context.Background(), accountID, GetBalanceInput{AccountType: "savings"})
+
+	// === Then ===
+	suite.Equal(AccountDoesNotExistError{AccountID: accountID, AccountType: "savings"}, err)
+}
+
+func (suite *AccountManagerConformanceSuite) TestTransfer() {
+	// === Given ===
+	ctx := context.Background()
+	srcAccountID := newConformanceAccountID()
+	destAccountID := newConformanceAccountID()
+	suite.createAccount(srcAccountID, "savings", 10)
+	suite.createAccount(destAccountID, "checking", 1)
+
+	// === When ===
+	output, err := suite.manager.Transfer(ctx, srcAccountID, TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   destAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(4),
+	})
+
+	// === Then ===
+	suite.Require().NoError(err)
+	suite.Equal(TransactionTypeTransfer, output.Transaction.Type)
+	suite.Equal(4, output.Transaction.Amount)
+	suite.Equal(&TransactionParty{AccountID: srcAccountID, AccountType: "savings", Currency: "USD", Balance: 6}, output.Transaction.Src)
+	suite.Equal(&TransactionParty{AccountID: destAccountID, AccountType: "checking", Currency: "USD", Balance: 5}, output.Transaction.Dest)
+	suite.assertBalance(srcAccountID, "savings", 6)
+	suite.assertBalance(destAccountID, "checking", 5)
+}
+
+func (suite *AccountManagerConformanceSuite) TestTransfer_ErrorWhenInsufficientFunds() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "savings", 3)
+	suite.createAccount(accountID, "checking", 0)
+
+	// === When ===
+	_, err := suite.manager.Transfer(ctx, accountID, TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   accountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(4),
+	})
+
+	// === Then ===
+	suite.Equal(InsufficientFundsError{AccountID: accountID, AccountType: "savings"}, err)
+	suite.assertBalance(accountID, "savings", 3)
+	suite.assertBalance(accountID, "checking", 0)
+}
+
+func (suite *AccountManagerConformanceSuite) TestTransfer_ErrorWhenSrcAccountDoesNotExist() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 0)
+
+	// === When ===
+	_, err := suite.manager.Transfer(ctx, accountID, TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   accountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(4),
+	})
+
+	// === Then ===
+	suite.Equal(SourceAccountDoesNotExistError{AccountID: accountID, AccountType: "savings"}, err)
+	suite.assertBalance(accountID, "checking", 0)
+}
+
+func (suite *AccountManagerConformanceSuite) TestTransfer_ErrorWhenSrcAccountIsDestAccount() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "savings", 10)
+
+	// === When ===
+	_, err := suite.manager.Transfer(ctx, accountID, TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   accountID,
+		DestAccountType: "savings",
+		Amount:          aws.Int(4),
+	})
+
+	// === Then ===
+	suite.Equal(SelfTransferError{AccountID: accountID, AccountType: "savings"}, err)
+	suite.assertBalance(accountID, "savings", 10)
+}
+
+func (suite *AccountManagerConformanceSuite) TestTransfer_ErrorWhenDestAccountDoesNotExist() {
+	// === Given ===
+	ctx := context.Background()
+	srcAccountID := newConformanceAccountID()
+	destAccountID := newConformanceAccountID()
+	suite.createAccount(srcAccountID, "savings", 10)
+
+	// === When ===
+	_, err := suite.manager.Transfer(ctx, srcAccountID, TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   destAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(4),
+	})
+
+	// === Then ===
+	suite.Equal(AccountDoesNotExistError{AccountID: destAccountID, AccountType: "checking"}, err)
+	suite.assertBalance(srcAccountID, "savings", 10)
+}
+
+func (suite *AccountManagerConformanceSuite) TestTransfer_ErrorWhenCurrencyDoesNotMatchSrcAccount() {
+	// === Given ===
+	ctx := context.Background()
+	srcAccountID := newConformanceAccountID()
+	destAccountID := newConformanceAccountID()
+	suite.createAccount(srcAccountID, "savings", 10)
+	suite.createAccount(destAccountID, "checking", 0)
+
+	// === When ===
+	_, err := suite.manager.Transfer(ctx, srcAccountID, TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   destAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(4),
+		Currency:        "EUR",
+	})
+
+	// === Then ===
+	suite.Equal(CurrencyMismatchError{
+		AccountID:       srcAccountID,
+		AccountType:     "savings",
+		AccountCurrency: "USD",
+		RequestCurrency: "EUR",
+	}, err)
+	suite.assertBalance(srcAccountID, "savings", 10)
+}
+
+func (suite *AccountManagerConformanceSuite) TestTransfer_ErrorWhenCurrenciesDifferWithoutConversion() {
+	// === Given ===
+	ctx := context.Background()
+	srcAccountID := newConformanceAccountID()
+	destAccountID := newConformanceAccountID()
+	suite.createAccount(srcAccountID, "savings", 10)
+	suite.createAccountInCurrency(destAccountID, "checking", 0, "EUR")
+
+	// === When ===
+	_, err := suite.manager.Transfer(ctx, srcAccountID, TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   destAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(4),
+	})
+
+	// === Then ===
+	suite.Equal(ConversionRequiredError{SrcCurrency: "USD", DestCurrency: "EUR"}, err)
+	suite.assertBalance(srcAccountID, "savings", 10)
+	suite.assertBalance(destAccountID, "checking", 0)
+}
+
+func (suite *AccountManagerConformanceSuite) TestTransfer_ErrorWhenConvertingWithinCurrency() {
+	// === Given ===
+	ctx := context.Background()
+	srcAccountID := newConformanceAccountID()
+	destAccountID := newConformanceAccountID()
+	suite.createAccount(srcAccountID, "savings", 10)
+	suite.createAccount(destAccountID, "checking", 0)
+
+	// === When ===
+	_, err := suite.manager.Transfer(ctx, srcAccountID, TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   destAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(4),
+		DestAmount:      aws.Int(5),
+	})
+
+	// === Then ===
+	suite.Equal(UnexpectedConversionError{Currency: "USD"}, err)
+	suite.assertBalance(srcAccountID, "savings", 10)
+	suite.assertBalance(destAccountID, "checking", 0)
+}
+
+func (suite *AccountManagerConformanceSuite) TestTransfer_Conversion() {
+	// === Given ===
+	ctx := context.Background()
+	srcAccountID := newConformanceAccountID()
+	destAccountID := newConformanceAccountID()
+	suite.createAccount(srcAccountID, "savings", 1000)
+	suite.createAccountInCurrency(destAccountID, "checking", 0, "JPY")
+
+	// === When ===
+	output, err := suite.manager.Transfer(ctx, srcAccountID, TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   destAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(500),
+		Currency:        "USD",
+		DestAmount:      aws.Int(740),
+	})
+
+	// === Then ===
+	suite.Require().NoError(err)
+	suite.Equal("USD", output.Transaction.Currency)
+	suite.Equal(500, output.Transaction.Amount)
+	suite.Equal(740, output.Transaction.DestAmount)
+	suite.Equal(&TransactionParty{AccountID: destAccountID, AccountType: "checking", Currency: "JPY", Balance: 740}, output.Transaction.Dest)
+	suite.Equal([]Posting{
+		{AccountID: srcAccountID, AccountType: "savings", Currency: "USD", Amount: -500},
+		{AccountID: SystemAccountID, AccountType: "fx-position", Currency: "USD", Amount: 500},
+		{AccountID: SystemAccountID, AccountType: "fx-position", Currency: "JPY", Amount: -740},
+		{AccountID: destAccountID, AccountType: "checking", Currency: "JPY", Amount: 740},
+	}, output.Transaction.Postings)
+	suite.assertBalance(srcAccountID, "savings", 500)
+	suite.assertBalance(destAccountID, "checking", 740)
+
+	// Each account reconciles in its own currency
+	dest, err := suite.manager.Reconcile(ctx, ReconcileInput{AccountID: destAccountID, AccountType: "checking"})
+	suite.Require().NoError(err)
+	suite.Equal(ReconcileOutput{Currency: "JPY", Balance: aws.Int(740), PostedBalance: 740, Reconciled: true}, dest)
+	position, err := suite.manager.Reconcile(ctx, ReconcileInput{AccountID: SystemAccountID, AccountType: "fx-position", Currency: "JPY", Date: output.Transaction.Timestamp.Format("2006-01-02")})
+	suite.Require().NoError(err)
+	suite.LessOrEqual(position.PostedBalance, -740)
+	suite.True(position.Reconciled)
+}
+
+func (suite *AccountManagerConformanceSuite) TestTransfer_AtQuotedRate() {
+	// === Given ===
+	ctx := context.Background()
+	srcAccountID := newConformanceAccountID()
+	destAccountID := newConformanceAccountID()
+	suite.createAccount(srcAccountID, "savings", 1000)
+	suite.createAccountInCurrency(destAccountID, "checking", 0, "JPY")
+	quote, err := suite.manager.Quote(ctx, srcAccountID, QuoteInput{SrcCurrency: "USD", DestCurrency: "JPY", Amount: aws.Int(333)})
+	suite.Require().NoError(err)
+
+	// === When ===
+	output, err := suite.manager.Transfer(ctx, srcAccountID, TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   destAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(333),
+		QuoteID:         quote.Quote.QuoteID,
+	})
+
+	// === Then ===
+	// 3.33 USD at 148.25 is 493.6725 JPY
+	suite.Require().NoError(err)
+	suite.Equal(Quote{
+		QuoteID:      quote.Quote.QuoteID,
+		SrcCurrency:  "USD",
+		DestCurrency: "JPY",
+		Amount:       333,
+		DestAmount:   494,
+		Rate:         "148.25",
+		Rounding:     "0.3275",
+		ExpiresAt:    quote.Quote.ExpiresAt,
+	}, quote.Quote)
+	suite.Equal(494, output.Transaction.DestAmount)
+	suite.Equal(&Conversion{QuoteID: quote.Quote.QuoteID, Rate: "148.25", Rounding: "0.3275"}, output.Transaction.Conversion)
+	suite.assertBalance(srcAccountID, "savings", 667)
+	suite.assertBalance(destAccountID, "checking", 494)
+
+	transactions, err := suite.manager.ListTransactions(ctx, destAccountID, ListTransactionsInput{})
+	suite.Require().NoError(err)
+	suite.Equal(output.Transaction.Conversion, transactions.Transactions[0].Conversion)
+}
+
+func (suite *AccountManagerConformanceSuite) TestTransfer_ErrorWhenQuoteIsUsed() {
+	// === Given ===
+	ctx := context.Background()
+	srcAccountID := newConformanceAccountID()
+	destAccountID := newConformanceAccountID()
+	suite.createAccount(srcAccountID, "savings", 1000)
+	suite.createAccountInCurrency(destAccountID, "checking", 0, "EUR")
+	quote, err := suite.manager.Quote(ctx, srcAccountID, QuoteInput{SrcCurrency: "USD", DestCurrency: "EUR", Amount: aws.Int(100)})
+	suite.Require().NoError(err)
+	input := TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   destAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(100),
+		QuoteID:         quote.Quote.QuoteID,
+	}
+	_, err = suite.manager.Transfer(ctx, srcAccountID, input)
+	suite.Require().NoError(err)
+
+	// === When ===
+	_, err = suite.manager.Transfer(ctx, srcAccountID, input)
+
+	// === Then ===
+	suite.Equal(QuoteNotFoundError{QuoteID: quote.Quote.QuoteID}, err)
+	suite.assertBalance(srcAccountID, "savings", 900)
+	suite.assertBalance(destAccountID, "checking", 92)
+}
+
+func (suite *AccountManagerConformanceSuite) TestTransfer_ErrorWhenQuoteDoesNotMatch() {
+	// === Given ===
+	ctx := context.Background()
+	srcAccountID := newConformanceAccountID()
+	destAccountID := newConformanceAccountID()
+	suite.createAccount(srcAccountID, "savings", 1000)
+	suite.createAccountInCurrency(destAccountID, "checking", 0, "EUR")
+	quote, err := suite.manager.Quote(ctx, srcAccountID, QuoteInput{SrcCurrency: "USD", DestCurrency: "JPY", Amount: aws.Int(100)})
+	suite.Require().NoError(err)
+
+	// === When ===
+	_, err = suite.manager.Transfer(ctx, srcAccountID, TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   destAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(100),
+		QuoteID:         quote.Quote.QuoteID,
+	})
+
+	// === Then ===
+	suite.Equal(QuoteMismatchError{QuoteID: quote.Quote.QuoteID}, err)
+	suite.assertBalance(srcAccountID, "savings", 1000)
+}
+
+func (suite *AccountManagerConformanceSuite) TestTransfer_ErrorWhenQuoteBelongsToAnotherAccount() {
+	// === Given ===
+	ctx := context.Background()
+	srcAccountID := newConformanceAccountID()
+	destAccountID := newConformanceAccountID()
+	suite.createAccount(srcAccountID, "savings", 1000)
+	suite.createAccountInCurrency(destAccountID, "checking", 0, "EUR")
+	quote, err := suite.manager.Quote(ctx, destAccountID, QuoteInput{SrcCurrency: "USD", DestCurrency: "EUR", Amount: aws.Int(100)})
+	suite.Require().NoError(err)
+
+	// === When ===
+	_, err = suite.manager.Transfer(ctx, srcAccountID, TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   destAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(100),
+		QuoteID:         quote.Quote.QuoteID,
+	})
+
+	// === Then ===
+	suite.Equal(QuoteNotFoundError{QuoteID: quote.Quote.QuoteID}, err)
+}
+
+func (suite *AccountManagerConformanceSuite) TestQuote_ErrorWhenRateIsNotAvailable() {
+	// === When ===
+	_, err := suite.manager.Quote(context.Background(), newConformanceAccountID(), QuoteInput{SrcCurrency: "GBP", DestCurrency: "CHF", Amount: aws.Int(100)})
+
+	// === Then ===
+	suite.Equal(RateNotAvailableError{From: "GBP", To: "CHF"}, err)
+}
+
+func (suite *AccountManagerConformanceSuite) TestTransfer_Idempotent() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "savings", 10)
+	suite.createAccount(accountID, "checking", 0)
+	input := TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   accountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(4),
+		IdempotencyKey:  "idempotency-key",
+	}
+
+	// === When ===
+	first, err := suite.manager.Transfer(ctx, accountID, input)
+	suite.Require().NoError(err)
+	replay, err := suite.manager.Transfer(ctx, accountID, input)
+	suite.Require().NoError(err)
+
+	// === Then ===
+	suite.Equal(first.Transaction.TransactionID, replay.Transaction.TransactionID)
+	suite.Equal(first.Transaction.Src, replay.Transaction.Src)
+	suite.Equal(first.Transaction.Dest, replay.Transaction.Dest)
+	suite.assertBalance(accountID, "savings", 6)
+	suite.assertBalance(accountID, "checking", 4)
+}
+
+func (suite *AccountManagerConformanceSuite) TestTransfer_ErrorWhenIdempotencyKeyIsReusedWithDifferentPayload() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "savings", 10)
+	suite.createAccount(accountID, "checking", 0)
+	input := TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   accountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(4),
+		IdempotencyKey:  "idempotency-key",
+	}
+	_, err := suite.manager.Transfer(ctx, accountID, input)
+	suite.Require().NoError(err)
+
+	// === When ===
+	input.Amount = aws.Int(5)
+	_, err = suite.manager.Transfer(ctx, accountID, input)
+
+	// === Then ===
+	suite.Equal(IdempotencyKeyConflictError{IdempotencyKey: "idempotency-key"}, err)
+	suite.assertBalance(accountID, "savings", 6)
+}
+
+func (suite *AccountManagerConformanceSuite) TestDeposit() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 1)
+
+	// === When ===
+	output, err := suite.manager.Deposit(ctx, DepositInput{
+		AccountID:         accountID,
+		AccountType:       "checking",
+		Amount:            aws.Int(4),
+		ExternalReference: "wire-0001",
+	})
+
+	// === Then ===
+	suite.Require().NoError(err)
+	suite.Equal(TransactionTypeDeposit, output.Transaction.Type)
+	suite.Equal(4, output.Transaction.Amount)
+	suite.Nil(output.Transaction.Src)
+	suite.Equal(&TransactionParty{AccountID: accountID, AccountType: "checking", Currency: "USD", Balance: 5}, output.Transaction.Dest)
+	suite.Equal("wire-0001", output.Transaction.ExternalReference)
+	suite.assertBalance(accountID, "checking", 5)
+
+	transactions, err := suite.manager.ListTransactions(ctx, accountID, ListTransactionsInput{Limit: aws.Int32(1)})
+	suite.Require().NoError(err)
+	suite.Require().Len(transactions.Transactions, 1)
+	suite.Equal(output.Transaction.TransactionID, transactions.Transactions[0].TransactionID)
+	suite.Equal("wire-0001", transactions.Transactions[0].ExternalReference)
+}
+
+func (suite *AccountManagerConformanceSuite) TestDeposit_ErrorWhenAccountDoesNotExist() {
+	// === Given ===
+	accountID := newConformanceAccountID()
+
+	// === When ===
+	_, err := suite.manager.Deposit(context.Background(), DepositInput{
+		AccountID:         accountID,
+		AccountType:       "checking",
+		Amount:            aws.Int(4),
+		ExternalReference: "wire-0001",
+	})
+
+	// === Then ===
+	suite.Equal(AccountDoesNotExistError{AccountID: accountID, AccountType: "checking"}, err)
+}
+
+func (suite *AccountManagerConformanceSuite) TestDeposit_ErrorWhenCurrencyDoesNotMatchAccount() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccountInCurrency(accountID, "checking", 0, "EUR")
+
+	// === When ===
+	_, err := suite.manager.Deposit(ctx, DepositInput{
+		AccountID:         accountID,
+		AccountType:       "checking",
+		Amount:            aws.Int(5),
+		Currency:          "USD",
+		ExternalReference: "wire-0001",
+	})
+
+	// === Then ===
+	suite.Equal(CurrencyMismatchError{
+		AccountID:       accountID,
+		AccountType:     "checking",
+		AccountCurrency: "EUR",
+		RequestCurrency: "USD",
+	}, err)
+	suite.assertBalance(accountID, "checking", 0)
+}
+
+func (suite *AccountManagerConformanceSuite) TestWithdraw() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 10)
+
+	// === When ===
+	output, err := suite.manager.Withdraw(ctx, WithdrawInput{
+		AccountID:         accountID,
+		AccountType:       "checking",
+		Amount:            aws.Int(4),
+		ExternalReference: "ach-0001",
+	})
+
+	// === Then ===
+	suite.Require().NoError(err)
+	suite.Equal(TransactionTypeWithdrawal, output.Transaction.Type)
+	suite.Equal(4, output.Transaction.Amount)
+	suite.Equal(&TransactionParty{AccountID: accountID, AccountType: "checking", Currency: "USD", Balance: 6}, output.Transaction.Src)
+	suite.Nil(output.Transaction.Dest)
+	suite.Equal("ach-0001", output.Transaction.ExternalReference)
+	suite.assertBalance(accountID, "checking", 6)
+}
+
+func (suite *AccountManagerConformanceSuite) TestWithdraw_ErrorWhenInsufficientFunds() {
+	// === Given ===
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 3)
+
+	// === When ===
+	_, err := suite.manager.Withdraw(context.Background(), WithdrawInput{
+		AccountID:         accountID,
+		AccountType:       "checking",
+		Amount:            aws.Int(4),
+		ExternalReference: "ach-0001",
+	})
+
+	// === Then ===
+	suite.Equal(InsufficientFundsError{AccountID: accountID, AccountType: "checking"}, err)
+	suite.assertBalance(accountID, "checking", 3)
+}
+
+func (suite *AccountManagerConformanceSuite) TestTransfer_WithinOverdraftLimit() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.Require().NoError(suite.manager.CreateAccount(ctx, accountID, CreateAccountInput{
+		AccountType:    "checking",
+		InitialBalance: aws.Int(3),
+		OverdraftLimit: 5,
+	}))
+	suite.createAccount(accountID, "savings", 0)
+
+	// === When ===
+	output, err := suite.manager.Transfer(ctx, accountID, TransferInput{
+		SrcAccountType:  "checking",
+		DestAccountID:   accountID,
+		DestAccountType: "savings",
+		Amount:          aws.Int(8),
+	})
+
+	// === Then ===
+	suite.Require().NoError(err)
+	suite.Equal(-5, output.Transaction.Src.Balance)
+	balance, err := suite.manager.GetBalance(ctx, accountID, GetBalanceInput{AccountType: "checking"})
+	suite.Require().NoError(err)
+	suite.Equal(GetBalanceOutput{Balance: -5, Currency: "USD", OverdraftLimit: 5, Available: 0, Status: AccountStatusActive}, balance)
+
+	position, err := suite.manager.Reconcile(ctx, ReconcileInput{AccountID: accountID, AccountType: "checking"})
+	suite.Require().NoError(err)
+	suite.True(position.Reconciled)
+}
+
+func (suite *AccountManagerConformanceSuite) TestTransfer_ErrorWhenOverdraftLimitIsExceeded() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.Require().NoError(suite.manager.CreateAccount(ctx, accountID, CreateAccountInput{
+		AccountType:    "checking",
+		InitialBalance: aws.Int(3),
+		OverdraftLimit: 5,
+	}))
+	suite.createAccount(accountID, "savings", 0)
+
+	// === When ===
+	_, err := suite.manager.Transfer(ctx, accountID, TransferInput{
+		SrcAccountType:  "checking",
+		DestAccountID:   accountID,
+		DestAccountType: "savings",
+		Amount:          aws.Int(9),
+	})
+
+	// === Then ===
+	suite.Equal(InsufficientFundsError{AccountID: accountID, AccountType: "checking"}, err)
+	suite.assertBalance(accountID, "checking", 3)
+}
+
+func (suite *AccountManagerConformanceSuite) TestSetOverdraftLimit() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 3)
+
+	// === When ===
+	err := suite.manager.SetOverdraftLimit(ctx, SetOverdraftLimitInput{
+		AccountID:      accountID,
+		AccountType:    "checking",
+		OverdraftLimit: aws.Int(10),
+	})
+
+	// === Then ===
+	suite.Require().NoError(err)
+	balance, err := suite.manager.GetBalance(ctx, accountID, GetBalanceInput{AccountType: "checking"})
+	suite.Require().NoError(err)
+	suite.Equal(GetBalanceOutput{Balance: 3, Currency: "USD", OverdraftLimit: 10, Available: 13, Status: AccountStatusActive}, balance)
+
+	output, err := suite.manager.Withdraw(ctx, WithdrawInput{
+		AccountID:         accountID,
+		AccountType:       "checking",
+		Amount:            aws.Int(13),
+		ExternalReference: "ach-0001",
+	})
+	suite.Require().NoError(err)
+	suite.Equal(-10, output.Transaction.Src.Balance)
+}
+
+func (suite *AccountManagerConformanceSuite) TestSetOverdraftLimit_ErrorWhenAboveAllowance() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	accountType := "limited-" + accountID
+	suite.putProduct(Product{AccountType: accountType, OverdraftAllowance: 20})
+	suite.createAccount(accountID, accountType, 0)
+
+	// === When ===
+	err := suite.manager.SetOverdraftLimit(ctx, SetOverdraftLimitInput{
+		AccountID:      accountID,
+		AccountType:    accountType,
+		OverdraftLimit: aws.Int(21),
+	})
+
+	// === Then ===
+	suite.Equal(OverdraftAllowanceExceededError{AccountType: accountType, OverdraftAllowance: 20}, err)
+	balance, err := suite.manager.GetBalance(ctx, accountID, GetBalanceInput{AccountType: accountType})
+	suite.Require().NoError(err)
+	suite.Zero(balance.OverdraftLimit)
+}
+
+func (suite *AccountManagerConformanceSuite) TestSetOverdraftLimit_LoweringPreventsFurtherDebits() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.Require().NoError(suite.manager.CreateAccount(ctx, accountID, CreateAccountInput{
+		AccountType:    "checking",
+		InitialBalance: aws.Int(0),
+		OverdraftLimit: 10,
+	}))
+	_, err := suite.manager.Withdraw(ctx, WithdrawInput{AccountID: accountID, AccountType: "checking", Amount: aws.Int(6), ExternalReference: "ach-0001"})
+	suite.Require().NoError(err)
+
+	// === When ===
+	err = suite.manager.SetOverdraftLimit(ctx, SetOverdraftLimitInput{
+		AccountID:      accountID,
+		AccountType:    "checking",
+		OverdraftLimit: aws.Int(4),
+	})
+
+	// === Then ===
+	suite.Require().NoError(err)
+	_, err = suite.manager.Withdraw(ctx, WithdrawInput{AccountID: accountID, AccountType: "checking", Amount: aws.Int(1), ExternalReference: "ach-0002"})
+	suite.Equal(InsufficientFundsError{AccountID: accountID, AccountType: "checking"}, err)
+
+	// Credits are still accepted while the account is overdrawn beyond its limit
+	_, err = suite.manager.Deposit(ctx, DepositInput{AccountID: accountID, AccountType: "checking", Amount: aws.Int(1), ExternalReference: "wire-0001"})
+	suite.Require().NoError(err)
+	suite.assertBalance(accountID, "checking", -5)
+}
+
+func (suite *AccountManagerConformanceSuite) TestSetOverdraftLimit_ErrorWhenAccountDoesNotExist() {
+	// === Given ===
+	accountID := newConformanceAccountID()
+
+	// === When ===
+	err := suite.manager.SetOverdraftLimit(context.Background(), SetOverdraftLimitInput{
+		AccountID:      accountID,
+		AccountType:    "checking",
+		OverdraftLimit: aws.Int(10),
+	})
+
+	// === Then ===
+	suite.Equal(AccountDoesNotExistError{AccountID: accountID, AccountType: "checking"}, err)
+}
+
+func (suite *AccountManagerConformanceSuite) TestSetAccountStatus_FrozenAccountIsNeitherDebitedNorCredited() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	otherAccountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 10)
+	suite.createAccount(otherAccountID, "checking", 10)
+
+	// === When ===
+	err := suite.manager.SetAccountStatus(ctx, SetAccountStatusInput{
+		AccountID:   accountID,
+		AccountType: "checking",
+		Status:      AccountStatusFrozen,
+	})
+
+	// === Then ===
+	suite.Require().NoError(err)
+	frozenErr := AccountNotActiveError{AccountID: accountID, AccountType: "checking", Status: AccountStatusFrozen}
+	_, err = suite.manager.Transfer(ctx, accountID, TransferInput{
+		SrcAccountType:  "checking",
+		DestAccountID:   otherAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(1),
+	})
+	suite.Equal(frozenErr, err)
+	_, err = suite.manager.Transfer(ctx, otherAccountID, TransferInput{
+		SrcAccountType:  "checking",
+		DestAccountID:   accountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(1),
+	})
+	suite.Equal(frozenErr, err)
+	_, err = suite.manager.Deposit(ctx, DepositInput{
+		AccountID:         accountID,
+		AccountType:       "checking",
+		Amount:            aws.Int(1),
+		ExternalReference: "wire-0001",
+	})
+	suite.Equal(frozenErr, err)
+	err = suite.manager.DeleteAccount(ctx, accountID, DeleteAccountInput{AccountType: "checking"})
+	suite.Equal(frozenErr, err)
+
+	balance, err := suite.manager.GetBalance(ctx, accountID, GetBalanceInput{AccountType: "checking"})
+	suite.Require().NoError(err)
+	suite.Equal(AccountStatusFrozen, balance.Status)
+	suite.Equal(10, balance.Balance)
+	suite.assertBalance(otherAccountID, "checking", 10)
+}
+
+func (suite *AccountManagerConformanceSuite) TestSetAccountStatus_DormantAccountIsOnlyCredited() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 10)
+
+	// === When ===
+	err := suite.manager.SetAccountStatus(ctx, SetAccountStatusInput{
+		AccountID:   accountID,
+		AccountType: "checking",
+		Status:      AccountStatusDormant,
+	})
+
+	// === Then ===
+	suite.Require().NoError(err)
+	_, err = suite.manager.Deposit(ctx, DepositInput{
+		AccountID:         accountID,
+		AccountType:       "checking",
+		Amount:            aws.Int(5),
+		ExternalReference: "wire-0001",
+	})
+	suite.NoError(err)
+	_, err = suite.manager.Withdraw(ctx, WithdrawInput{
+		AccountID:         accountID,
+		AccountType:       "checking",
+		Amount:            aws.Int(1),
+		ExternalReference: "ach-0001",
+	})
+	suite.Equal(AccountNotActiveError{AccountID: accountID, AccountType: "checking", Status: AccountStatusDormant}, err)
+	suite.assertBalance(accountID, "checking", 15)
+}
+
+func (suite *AccountManagerConformanceSuite) TestSetAccountStatus_Reactivates() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 10)
+	suite.Require().NoError(suite.manager.SetAccountStatus(ctx, SetAccountStatusInput{
+		AccountID:   accountID,
+		AccountType: "checking",
+		Status:      AccountStatusFrozen,
+	}))
+
+	// === When ===
+	err := suite.manager.SetAccountStatus(ctx, SetAccountStatusInput{
+		AccountID:   accountID,
+		AccountType: "checking",
+		Status:      AccountStatusActive,
+	})
+
+	// === Then ===
+	suite.Require().NoError(err)
+	_, err = suite.manager.Withdraw(ctx, WithdrawInput{
+		AccountID:         accountID,
+		AccountType:       "checking",
+		Amount:            aws.Int(4),
+		ExternalReference: "ach-0001",
+	})
+	suite.NoError(err)
+	suite.assertBalance(accountID, "checking", 6)
+}
+
+func (suite *AccountManagerConformanceSuite) TestSetAccountStatus_ErrorWhenTransitionIsInvalid() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 0)
+	suite.Require().NoError(suite.manager.SetAccountStatus(ctx, SetAccountStatusInput{
+		AccountID:   accountID,
+		AccountType: "checking",
+		Status:      AccountStatusFrozen,
+	}))
+
+	// === When ===
+	err := suite.manager.SetAccountStatus(ctx, SetAccountStatusInput{
+		AccountID:   accountID,
+		AccountType: "checking",
+		Status:      AccountStatusDormant,
+	})
+
+	// === Then ===
+	suite.Equal(InvalidStatusTransitionError{
+		AccountID:   accountID,
+		AccountType: "checking",
+		From:        AccountStatusFrozen,
+		To:          AccountStatusDormant,
+	}, err)
+}
+
+func (suite *AccountManagerConformanceSuite) TestSetAccountStatus_ErrorWhenClosingWithNonZeroBalance() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 5)
+
+	// === When ===
+	err := suite.manager.SetAccountStatus(ctx, SetAccountStatusInput{
+		AccountID:   accountID,
+		AccountType: "checking",
+		Status:      AccountStatusClosed,
+	})
+
+	// === Then ===
+	suite.Equal(NonZeroBalanceError{AccountID: accountID, AccountType: "checking"}, err)
+	balance, err := suite.manager.GetBalance(ctx, accountID, GetBalanceInput{AccountType: "checking"})
+	suite.Require().NoError(err)
+	suite.Equal(AccountStatusActive, balance.Status)
+}
+
+func (suite *AccountManagerConformanceSuite) TestSetAccountStatus_ErrorWhenClosingWithHoldsOutstanding() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 0)
+	suite.createAccount(accountID, "savings", 0)
+	// The hold is covered by the overdraft, so the balance stays at zero
+	suite.Require().NoError(suite.manager.SetOverdraftLimit(ctx, SetOverdraftLimitInput{
+		AccountID:      accountID,
+		AccountType:    "checking",
+		OverdraftLimit: aws.Int(10),
+	}))
+	placed, err := suite.manager.PlaceHold(ctx, PlaceHoldInput{
+		AccountID:         accountID,
+		AccountType:       "checking",
+		DestAccountID:     accountID,
+		DestAccountType:   "savings",
+		Amount:            aws.Int(4),
+		ExternalReference: "auth-0001",
+	})
+	suite.Require().NoError(err)
+	input := SetAccountStatusInput{
+		AccountID:   accountID,
+		AccountType: "checking",
+		Status:      AccountStatusClosed,
+	}
+
+	// === When ===
+	err = suite.manager.SetAccountStatus(ctx, input)
+
+	// === Then ===
+	suite.Equal(HoldsOutstandingError{AccountID: accountID, AccountType: "checking"}, err)
+	balance, err := suite.manager.GetBalance(ctx, accountID, GetBalanceInput{AccountType: "checking"})
+	suite.Require().NoError(err)
+	suite.Equal(AccountStatusActive, balance.Status)
+
+	// The account may be closed once the hold is released
+	suite.Require().NoError(suite.manager.ReleaseHold(ctx, ReleaseHoldInput{
+		AccountID:   accountID,
+		AccountType: "checking",
+		HoldID:      placed.Hold.HoldID,
+	}))
+	suite.NoError(suite.manager.SetAccountStatus(ctx, input))
+}
+
+func (suite *AccountManagerConformanceSuite) TestSetAccountStatus_ClosingIsFinal() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 0)
+
+	// === When ===
+	err := suite.manager.SetAccountStatus(ctx, SetAccountStatusInput{
+		AccountID:   accountID,
+		AccountType: "checking",
+		Status:      AccountStatusClosed,
+	})
+
+	// === Then ===
+	suite.Require().NoError(err)
+	err = suite.manager.SetAccountStatus(ctx, SetAccountStatusInput{
+		AccountID:   accountID,
+		AccountType: "checking",
+		Status:      AccountStatusActive,
+	})
+	suite.Equal(InvalidStatusTransitionError{
+		AccountID:   accountID,
+		AccountType: "checking",
+		From:        AccountStatusClosed,
+		To:          AccountStatusActive,
+	}, err)
+	_, err = suite.manager.Deposit(ctx, DepositInput{
+		AccountID:         accountID,
+		AccountType:       "checking",
+		Amount:            aws.Int(1),
+		ExternalReference: "wire-0001",
+	})
+	suite.Equal(AccountNotActiveError{AccountID: accountID, AccountType: "checking", Status: AccountStatusClosed}, err)
+
+	// An account of the same type may be opened in its place
+	suite.createAccount(accountID, "checking", 3)
+	balance, err := suite.manager.GetBalance(ctx, accountID, GetBalanceInput{AccountType: "checking"})
+	suite.Require().NoError(err)
+	suite.Equal(GetBalanceOutput{Balance: 3, Currency: "USD", Available: 3, Status: AccountStatusActive}, balance)
+}
+
+func (suite *AccountManagerConformanceSuite) TestSetAccountStatus_ErrorWhenAccountDoesNotExist() {
+	// === Given ===
+	accountID := newConformanceAccountID()
+
+	// === When ===
+	err := suite.manager.SetAccountStatus(context.Background(), SetAccountStatusInput{
+		AccountID:   accountID,
+		AccountType: "checking",
+		Status:      AccountStatusFrozen,
+	})
+
+	// === Then ===
+	suite.Equal(AccountDoesNotExistError{AccountID: accountID, AccountType: "checking"}, err)
+}
+
+func (suite *AccountManagerConformanceSuite) TestCloseAccount_SweepsBalance() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	destAccountID := newConformanceAccountID()
+	suite.createAccount(accountID, "savings", 10)
+	suite.createAccount(destAccountID, "checking", 1)
+
+	// === When ===
+	output, err := suite.manager.CloseAccount(ctx, accountID, CloseAccountInput{
+		AccountType:     "savings",
+		DestAccountID:   destAccountID,
+		DestAccountType: "checking",
+	})
+
+	// === Then ===
+	suite.Require().NoError(err)
+	suite.Require().NotNil(output.Transaction)
+	suite.Equal(TransactionTypeClose, output.Transaction.Type)
+	suite.Equal(10, output.Transaction.Amount)
+	suite.Equal(&TransactionParty{AccountID: accountID, AccountType: "savings", Currency: "USD", Balance: 0}, output.Transaction.Src)
+	suite.Equal(&TransactionParty{AccountID: destAccountID, AccountType: "checking", Currency: "USD", Balance: 11}, output.Transaction.Dest)
+	balance, err := suite.manager.GetBalance(ctx, accountID, GetBalanceInput{AccountType: "savings"})
+	suite.Require().NoError(err)
+	suite.Equal(GetBalanceOutput{Balance: 0, Currency: "USD", Available: 0, Status: AccountStatusClosed}, balance)
+	suite.assertBalance(destAccountID, "checking", 11)
+
+	reconciled, err := suite.manager.Reconcile(ctx, ReconcileInput{AccountID: destAccountID, AccountType: "checking"})
+	suite.Require().NoError(err)
+	suite.True(reconciled.Reconciled)
+}
+
+func (suite *AccountManagerConformanceSuite) TestCloseAccount_PostsAccruedInterest() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	destAccountID := newConformanceAccountID()
+	accountType := "savings-" + accountID
+	suite.putProduct(Product{
+		AccountType: accountType,
+		Interest:    &InterestTerms{APY: "0.0425", DayCount: DayCountActual365, Compounding: CompoundingMonthly},
+	})
+	suite.createAccount(accountID, accountType, 100000)
+	suite.createAccount(destAccountID, "checking", 1)
+	_, err := suite.manager.AccrueProductInterest(ctx, AccrueProductInterestInput{
+		AccountType: accountType,
+		Date:        "2022-09-29",
+	})
+	suite.Require().NoError(err)
+
+	// === When ===
+	output, err := suite.manager.CloseAccount(ctx, accountID, CloseAccountInput{
+		AccountType:     accountType,
+		DestAccountID:   destAccountID,
+		DestAccountType: "checking",
+	})
+
+	// === Then ===
+	suite.Require().NoError(err)
+	suite.Require().NotNil(output.Interest)
+	suite.Equal(TransactionTypeInterest, output.Interest.Type)
+	suite.Equal(11, output.Interest.Amount)
+	suite.Equal(&TransactionParty{AccountID: accountID, AccountType: accountType, Currency: "USD", Balance: 100011}, output.Interest.Dest)
+	suite.Require().NotNil(output.Transaction)
+	suite.Equal(100011, output.Transaction.Amount)
+	suite.assertBalance(destAccountID, "checking", 100012)
+	balance, err := suite.manager.GetBalance(ctx, accountID, GetBalanceInput{AccountType: accountType})
+	suite.Require().NoError(err)
+	suite.Equal(GetBalanceOutput{Balance: 0, Currency: "USD", Available: 0, Status: AccountStatusClosed}, balance)
+
+	reconciled, err := suite.manager.Reconcile(ctx, ReconcileInput{AccountID: accountID, AccountType: accountType})
+	suite.Require().NoError(err)
+	suite.True(reconciled.Reconciled)
+}
+
+func (suite *AccountManagerConformanceSuite) TestCloseAccount_ErrorWhenAccountIsDormant() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 10)
+	suite.createAccount(accountID, "savings", 0)
+	suite.Require().NoError(suite.manager.SetAccountStatus(ctx, SetAccountStatusInput{
+		AccountID:   accountID,
+		AccountType: "checking",
+		Status:      AccountStatusDormant,
+	}))
+
+	// === When ===
+	_, err := suite.manager.CloseAccount(ctx, accountID, CloseAccountInput{
+		AccountType:     "checking",
+		DestAccountID:   accountID,
+		DestAccountType: "savings",
+	})
+
+	// === Then ===
+	suite.Equal(AccountNotActiveError{AccountID: accountID, AccountType: "checking", Status: AccountStatusDormant}, err)
+	suite.assertBalance(accountID, "checking", 10)
+	suite.assertBalance(accountID, "savings", 0)
+}
+
+func (suite *AccountManagerConformanceSuite) TestCloseAccount_Idempotent() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	destAccountID := newConformanceAccountID()
+	suite.createAccount(accountID, "savings", 0)
+	suite.createAccount(destAccountID, "checking", 1)
+	input := CloseAccountInput{
+		AccountType:     "savings",
+		DestAccountID:   destAccountID,
+		DestAccountType: "checking",
+	}
+	first, err := suite.manager.CloseAccount(ctx, accountID, input)
+	suite.Require().NoError(err)
+
+	// === When ===
+	second, err := suite.manager.CloseAccount(ctx, accountID, input)
+
+	// === Then ===
+	suite.Require().NoError(err)
+	suite.Require().NotNil(first.Transaction)
+	suite.Equal(0, first.Transaction.Amount)
+	suite.Nil(first.Transaction.Dest)
+	suite.Nil(second.Transaction)
+	suite.assertBalance(destAccountID, "checking", 1)
+}
+
+func (suite *AccountManagerConformanceSuite) TestCloseAccount_SucceedsWhenAccountDoesNotExist() {
+	// === Given ===
+	destAccountID := newConformanceAccountID()
+	suite.createAccount(destAccountID, "checking", 1)
+
+	// === When ===
+	output, err := suite.manager.CloseAccount(context.Background(), newConformanceAccountID(), CloseAccountInput{
+		AccountType:     "savings",
+		DestAccountID:   destAccountID,
+		DestAccountType: "checking",
+	})
+
+	// === Then ===
+	suite.NoError(err)
+	suite.Nil(output.Transaction)
+}
+
+func (suite *AccountManagerConformanceSuite) TestCloseAccount_ErrorWhenDestAccountDoesNotExist() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	destAccountID := newConformanceAccountID()
+	suite.createAccount(accountID, "savings", 10)
+
+	// === When ===
+	_, err := suite.manager.CloseAccount(ctx, accountID, CloseAccountInput{
+		AccountType:     "savings",
+		DestAccountID:   destAccountID,
+		DestAccountType: "checking",
+	})
+
+	// === Then ===
+	suite.Equal(AccountDoesNotExistError{AccountID: destAccountID, AccountType: "checking"}, err)
+	balance, err := suite.manager.GetBalance(ctx, accountID, GetBalanceInput{AccountType: "savings"})
+	suite.Require().NoError(err)
+	suite.Equal(AccountStatusActive, balance.Status)
+	suite.Equal(10, balance.Balance)
+}
+
+func (suite *AccountManagerConformanceSuite) TestCloseAccount_ErrorWhenHoldsAreOutstanding() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 10)
+	suite.createAccount(accountID, "savings", 0)
+	_, err := suite.manager.PlaceHold(ctx, PlaceHoldInput{
+		AccountID:         accountID,
+		AccountType:       "checking",
+		DestAccountID:     accountID,
+		DestAccountType:   "savings",
+		Amount:            aws.Int(4),
+		ExternalReference: "auth-0001",
+	})
+	suite.Require().NoError(err)
+
+	// === When ===
+	_, err = suite.manager.CloseAccount(ctx, accountID, CloseAccountInput{
+		AccountType:     "checking",
+		DestAccountID:   accountID,
+		DestAccountType: "savings",
+	})
+
+	// === Then ===
+	suite.Equal(HoldsOutstandingError{AccountID: accountID, AccountType: "checking"}, err)
+	suite.assertBalance(accountID, "checking", 10)
+	suite.assertBalance(accountID, "savings", 0)
+}
+
+func (suite *AccountManagerConformanceSuite) TestCloseAccount_ErrorWhenAccountIsFrozen() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 10)
+	suite.createAccount(accountID, "savings", 0)
+	suite.Require().NoError(suite.manager.SetAccountStatus(ctx, SetAccountStatusInput{
+		AccountID:   accountID,
+		AccountType: "checking",
+		Status:      AccountStatusFrozen,
+	}))
+
+	// === When ===
+	_, err := suite.manager.CloseAccount(ctx, accountID, CloseAccountInput{
+		AccountType:     "checking",
+		DestAccountID:   accountID,
+		DestAccountType: "savings",
+	})
+
+	// === Then ===
+	suite.Equal(AccountNotActiveError{AccountID: accountID, AccountType: "checking", Status: AccountStatusFrozen}, err)
+	suite.assertBalance(accountID, "checking", 10)
+	suite.assertBalance(accountID, "savings", 0)
+}
+
+func (suite *AccountManagerConformanceSuite) TestPlaceHold() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 10)
+	suite.createAccount(accountID, "savings", 0)
+
+	// === When ===
+	output, err := suite.manager.PlaceHold(ctx, PlaceHoldInput{
+		AccountID:         accountID,
+		AccountType:       "checking",
+		DestAccountID:     accountID,
+		DestAccountType:   "savings",
+		Amount:            aws.Int(4),
+		ExternalReference: "auth-0001",
+	})
+
+	// === Then ===
+	suite.Require().NoError(err)
+	suite.NotEmpty(output.Hold.HoldID)
+	suite.Equal(4, output.Hold.Amount)
+	balance, err := suite.manager.GetBalance(ctx, accountID, GetBalanceInput{AccountType: "checking"})
+	suite.Require().NoError(err)
+	suite.Equal(GetBalanceOutput{Balance: 10, Currency: "USD", Held: 4, Available: 6, Status: AccountStatusActive}, balance)
+
+	// Held funds cannot be debited by anything but a capture
+	_, err = suite.manager.Transfer(ctx, accountID, TransferInput{
+		SrcAccountType:  "checking",
+		DestAccountID:   accountID,
+		DestAccountType: "savings",
+		Amount:          aws.Int(7),
+	})
+	suite.Equal(InsufficientFundsError{AccountID: accountID, AccountType: "checking"}, err)
+	_, err = suite.manager.Withdraw(ctx, WithdrawInput{AccountID: accountID, AccountType: "checking", Amount: aws.Int(6), ExternalReference: "ach-0001"})
+	suite.Require().NoError(err)
+	suite.assertBalance(accountID, "checking", 4)
+}
+
+func (suite *AccountManagerConformanceSuite) TestPlaceHold_ErrorWhenInsufficientFunds() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 10)
+	suite.createAccount(accountID, "savings", 0)
+	input := PlaceHoldInput{
+		AccountID:         accountID,
+		AccountType:       "checking",
+		DestAccountID:     accountID,
+		DestAccountType:   "savings",
+		Amount:            aws.Int(6),
+		ExternalReference: "auth-0001",
+	}
+	_, err := suite.manager.PlaceHold(ctx, input)
+	suite.Require().NoError(err)
+
+	// === When ===
+	_, err = suite.manager.PlaceHold(ctx, input)
+
+	// === Then ===
+	suite.Equal(InsufficientFundsError{AccountID: accountID, AccountType: "checking"}, err)
+}
+
+func (suite *AccountManagerConformanceSuite) TestPlaceHold_ErrorWhenCurrenciesDiffer() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 10)
+	suite.createAccountInCurrency(accountID, "savings", 0, "EUR")
+
+	// === When ===
+	_, err := suite.manager.PlaceHold(ctx, PlaceHoldInput{
+		AccountID:         accountID,
+		AccountType:       "checking",
+		DestAccountID:     accountID,
+		DestAccountType:   "savings",
+		Amount:            aws.Int(4),
+		ExternalReference: "auth-0001",
+	})
+
+	// === Then ===
+	suite.Equal(ConversionRequiredError{SrcCurrency: "USD", DestCurrency: "EUR"}, err)
+}
+
+func (suite *AccountManagerConformanceSuite) TestCaptureHold_Partially() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	destAccountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 10)
+	suite.createAccount(destAccountID, "merchant", 0)
+	placed, err := suite.manager.PlaceHold(ctx, PlaceHoldInput{
+		AccountID:         accountID,
+		AccountType:       "checking",
+		DestAccountID:     destAccountID,
+		DestAccountType:   "merchant",
+		Amount:            aws.Int(6),
+		ExternalReference: "auth-0001",
+	})
+	suite.Require().NoError(err)
+	input := CaptureHoldInput{
+		AccountID:   accountID,
+		AccountType: "checking",
+		HoldID:      placed.Hold.HoldID,
+	}
+
+	// === When ===
+	partialInput := input
+	partialInput.Amount = aws.Int(4)
+	partial, err := suite.manager.CaptureHold(ctx, partialInput)
+	suite.Require().NoError(err)
+	balance, err := suite.manager.GetBalance(ctx, accountID, GetBalanceInput{AccountType: "checking"})
+	suite.Require().NoError(err)
+	rest, err := suite.manager.CaptureHold(ctx, input)
+	suite.Require().NoError(err)
+
+	// === Then ===
+	suite.Equal(TransactionTypeCapture, partial.Transaction.Type)
+	suite.Equal(4, partial.Transaction.Amount)
+	suite.Equal(placed.Hold.HoldID, partial.Transaction.HoldID)
+	suite.Equal("auth-0001", partial.Transaction.ExternalReference)
+	suite.Equal(2, partial.Hold.Amount)
+	suite.Equal(GetBalanceOutput{Balance: 6, Currency: "USD", Held: 2, Available: 4, Status: AccountStatusActive}, balance)
+
+	suite.Equal(2, rest.Transaction.Amount)
+	suite.Equal(0, rest.Hold.Amount)
+	suite.assertBalance(accountID, "checking", 4)
+	suite.assertBalance(destAccountID, "merchant", 6)
+
+	_, err = suite.manager.CaptureHold(ctx, input)
+	suite.Equal(HoldNotFoundError{AccountID: accountID, AccountType: "checking", HoldID: placed.Hold.HoldID}, err)
+
+	for _, key := range []AccountKey{{AccountID: accountID, AccountType: "checking"}, {AccountID: destAccountID, AccountType: "merchant"}} {
+		position, err := suite.manager.Reconcile(ctx, ReconcileInput{AccountID: key.AccountID, AccountType: key.AccountType})
+		suite.Require().NoError(err)
+		suite.True(position.Reconciled)
+	}
+}
+
+func (suite *AccountManagerConformanceSuite) TestCaptureHold_ErrorWhenCaptureExceedsHold() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 10)
+	suite.createAccount(accountID, "savings", 0)
+	placed, err := suite.manager.PlaceHold(ctx, PlaceHoldInput{
+		AccountID:         accountID,
+		AccountType:       "checking",
+		DestAccountID:     accountID,
+		DestAccountType:   "savings",
+		Amount:            aws.Int(6),
+		ExternalReference: "auth-0001",
+	})
+	suite.Require().NoError(err)
+
+	// === When ===
+	_, err = suite.manager.CaptureHold(ctx, CaptureHoldInput{
+		AccountID:   accountID,
+		AccountType: "checking",
+		HoldID:      placed.Hold.HoldID,
+		Amount:      aws.Int(7),
+	})
+
+	// === Then ===
+	suite.Equal(CaptureExceedsHoldError{HoldID: placed.Hold.HoldID, HeldAmount: 6}, err)
+	suite.assertBalance(accountID, "checking", 10)
+}
+
+func (suite *AccountManagerConformanceSuite) TestReleaseHold() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 10)
+	suite.createAccount(accountID, "savings", 0)
+	placed, err := suite.manager.PlaceHold(ctx, PlaceHoldInput{
+		AccountID:         accountID,
+		AccountType:       "checking",
+		DestAccountID:     accountID,
+		DestAccountType:   "savings",
+		Amount:            aws.Int(6),
+		ExternalReference: "auth-0001",
+	})
+	suite.Require().NoError(err)
+	input := ReleaseHoldInput{
+		AccountID:   accountID,
+		AccountType: "checking",
+		HoldID:      placed.Hold.HoldID,
+	}
+
+	// === When ===
+	err = suite.manager.ReleaseHold(ctx, input)
+
+	// === Then ===
+	suite.Require().NoError(err)
+	balance, err := suite.manager.GetBalance(ctx, accountID, GetBalanceInput{AccountType: "checking"})
+	suite.Require().NoError(err)
+	suite.Equal(GetBalanceOutput{Balance: 10, Currency: "USD", Held: 0, Available: 10, Status: AccountStatusActive}, balance)
+	suite.Equal(HoldNotFoundError{AccountID: accountID, AccountType: "checking", HoldID: placed.Hold.HoldID}, suite.manager.ReleaseHold(ctx, input))
+}
+
+func (suite *AccountManagerConformanceSuite) TestHold_Expires() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 10)
+	suite.createAccount(accountID, "savings", 0)
+	placed, err := suite.manager.PlaceHold(ctx, PlaceHoldInput{
+		AccountID:         accountID,
+		AccountType:       "checking",
+		DestAccountID:     accountID,
+		DestAccountType:   "savings",
+		Amount:            aws.Int(6),
+		ExternalReference: "auth-0001",
+		ValiditySeconds:   1,
+	})
+	suite.Require().NoError(err)
+
+	// === When ===
+	time.Sleep(time.Until(placed.Hold.ExpiresAt))
+
+	// === Then ===
+	balance, err := suite.manager.GetBalance(ctx, accountID, GetBalanceInput{AccountType: "checking"})
+	suite.Require().NoError(err)
+	suite.Equal(GetBalanceOutput{Balance: 10, Currency: "USD", Held: 0, Available: 10, Status: AccountStatusActive}, balance)
+	_, err = suite.manager.CaptureHold(ctx, CaptureHoldInput{AccountID: accountID, AccountType: "checking", HoldID: placed.Hold.HoldID})
+	suite.Equal(HoldNotFoundError{AccountID: accountID, AccountType: "checking", HoldID: placed.Hold.HoldID}, err)
+}
+
+func (suite *AccountManagerConformanceSuite) TestCreateSchedule_ErrorWhenSrcAccountIsDestAccount() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 10)
+
+	// === When ===
+	_, err := suite.manager.CreateSchedule(ctx, accountID, CreateScheduleInput{
+		SrcAccountType:  "checking",
+		DestAccountID:   accountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(4),
+	})
+
+	// === Then ===
+	suite.Equal(SelfTransferError{AccountID: accountID, AccountType: "checking"}, err)
+	suite.assertNoSchedules(accountID)
+}
+
+func (suite *AccountManagerConformanceSuite) TestCreateSchedule_ErrorWhenAccountDoesNotExist() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	destAccountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 10)
+
+	// === When ===
+	_, srcErr := suite.manager.CreateSchedule(ctx, accountID, CreateScheduleInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   accountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(4),
+	})
+	_, destErr := suite.manager.CreateSchedule(ctx, accountID, CreateScheduleInput{
+		SrcAccountType:  "checking",
+		DestAccountID:   destAccountID,
+		DestAccountType: "savings",
+		Amount:          aws.Int(4),
+	})
+
+	// === Then ===
+	suite.Equal(SourceAccountDoesNotExistError{AccountID: accountID, AccountType: "savings"}, srcErr)
+	suite.Equal(AccountDoesNotExistError{AccountID: destAccountID, AccountType: "savings"}, destErr)
+	suite.assertNoSchedules(accountID)
+}
+
+func (suite *AccountManagerConformanceSuite) TestCreateSchedule_ErrorWhenCurrenciesDiffer() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccountInCurrency(accountID, "checking", 10, "USD")
+	suite.createAccountInCurrency(accountID, "savings", 0, "EUR")
+
+	// === When ===
+	_, requestErr := suite.manager.CreateSchedule(ctx, accountID, CreateScheduleInput{
+		SrcAccountType:  "checking",
+		DestAccountID:   accountID,
+		DestAccountType: "savings",
+		Amount:          aws.Int(4),
+		Currency:        "EUR",
+	})
+	_, accountsErr := suite.manager.CreateSchedule(ctx, accountID, CreateScheduleInput{
+		SrcAccountType:  "checking",
+		DestAccountID:   accountID,
+		DestAccountType: "savings",
+		Amount:          aws.Int(4),
+	})
+
+	// === Then ===
+	suite.Equal(CurrencyMismatchError{AccountID: accountID, AccountType: "checking", AccountCurrency: "USD", RequestCurrency: "EUR"}, requestErr)
+	suite.Equal(ConversionRequiredError{SrcCurrency: "USD", DestCurrency: "EUR"}, accountsErr)
+	suite.assertNoSchedules(accountID)
+}
+
+func (suite *AccountManagerConformanceSuite) TestRunSchedules_OneOffTransfer() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 10)
+	suite.createAccount(accountID, "savings", 0)
+	created, err := suite.manager.CreateSchedule(ctx, accountID, CreateScheduleInput{
+		SrcAccountType:  "checking",
+		DestAccountID:   accountID,
+		DestAccountType: "savings",
+		Amount:          aws.Int(4),
+		StartAt:         aws.Time(time.Now().Add(-time.Minute)),
+	})
+	suite.Require().NoError(err)
+	suite.Equal(DefaultCurrency, created.Schedule.Currency)
+
+	// === When ===
+	output, err := suite.manager.RunSchedules(ctx, RunSchedulesInput{})
+
+	// === Then ===
+	suite.Require().NoError(err)
+	executions := executionsOf(output, created.Schedule.ScheduleID)
+	suite.Require().Len(executions, 1)
+	suite.Equal(ExecutionStatusSucceeded, executions[0].Status)
+	suite.NotEmpty(executions[0].TransactionID)
+	suite.assertBalance(accountID, "checking", 6)
+	suite.assertBalance(accountID, "savings", 4)
+
+	schedule := suite.schedule(accountID, created.Schedule.ScheduleID)
+	suite.Equal(ScheduleStatusCompleted, schedule.Status)
+	suite.Equal(1, schedule.Occurrences)
+	suite.Nil(schedule.NextRunAt)
+
+	listed, err := suite.manager.ListScheduleExecutions(ctx, accountID, ListScheduleExecutionsInput{ScheduleID: created.Schedule.ScheduleID})
+	suite.Require().NoError(err)
+	suite.Equal(executions, listed.Executions)
+
+	rerun, err := suite.manager.RunSchedules(ctx, RunSchedulesInput{})
+	suite.Require().NoError(err)
+	suite.Empty(executionsOf(rerun, created.Schedule.ScheduleID))
+	suite.assertBalance(accountID, "checking", 6)
+}
+
+func (suite *AccountManagerConformanceSuite) TestRunSchedules_RecurringTransferCatchesUpOnePerRun() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 10)
+	suite.createAccount(accountID, "savings", 0)
+	startAt := time.Now().UTC().Truncate(time.Second).AddDate(0, 0, -2)
+	created, err := suite.manager.CreateSchedule(ctx, accountID, CreateScheduleInput{
+		SrcAccountType:  "checking",
+		DestAccountID:   accountID,
+		DestAccountType: "savings",
+		Amount:          aws.Int(1),
+		StartAt:         &startAt,
+		Recurrence:      "FREQ=DAILY",
+	})
+	suite.Require().NoError(err)
+	defer func() {
+		suite.NoError(suite.manager.CancelSchedule(ctx, accountID, CancelScheduleInput{ScheduleID: created.Schedule.ScheduleID}))
+	}()
+
+	// === When ===
+	for i := 0; i < 2; i++ {
+		output, err := suite.manager.RunSchedules(ctx, RunSchedulesInput{})
+		suite.Require().NoError(err)
+		suite.Len(executionsOf(output, created.Schedule.ScheduleID), 1)
+	}
+
+	// === Then ===
+	suite.assertBalance(accountID, "checking", 8)
+	suite.assertBalance(accountID, "savings", 2)
+	schedule := suite.schedule(accountID, created.Schedule.ScheduleID)
+	suite.Equal(ScheduleStatusActive, schedule.Status)
+	suite.Equal(2, schedule.Occurrences)
+	suite.Require().NotNil(schedule.NextRunAt)
+	suite.Equal(startAt.AddDate(0, 0, 2), *schedule.NextRunAt)
+
+	listed, err := suite.manager.ListScheduleExecutions(ctx, accountID, ListScheduleExecutionsInput{
+		ScheduleID: created.Schedule.ScheduleID,
+		Limit:      aws.Int32(1),
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(listed.Executions, 1)
+	suite.Equal(1, listed.Executions[0].Occurrence)
+	suite.Equal(startAt.AddDate(0, 0, 1), listed.Executions[0].ScheduledAt)
+	suite.Require().NotNil(listed.LastEvaluatedOccurrence)
+
+	next, err := suite.manager.ListScheduleExecutions(ctx, accountID, ListScheduleExecutionsInput{
+		ScheduleID:               created.Schedule.ScheduleID,
+		ExclusiveStartOccurrence: listed.LastEvaluatedOccurrence,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(next.Executions, 1)
+	suite.Equal(0, next.Executions[0].Occurrence)
+	suite.Nil(next.LastEvaluatedOccurrence)
+}
+
+func (suite *AccountManagerConformanceSuite) TestRunSchedules_OccurrenceTransferredOnce() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 10)
+	suite.createAccount(accountID, "savings", 0)
+	created, err := suite.manager.CreateSchedule(ctx, accountID, CreateScheduleInput{
+		SrcAccountType:  "checking",
+		DestAccountID:   accountID,
+		DestAccountType: "savings",
+		Amount:          aws.Int(4),
+		StartAt:         aws.Time(time.Now().Add(-time.Minute)),
+	})
+	suite.Require().NoError(err)
+	output, err := suite.manager.RunSchedules(ctx, RunSchedulesInput{})
+	suite.Require().NoError(err)
+	suite.Require().Len(executionsOf(output, created.Schedule.ScheduleID), 1)
+
+	// === When ===
+	// A run which read the schedule before its occurrence was executed, however long ago
+	_, err = executeSchedule(ctx, suite.manager, created.Schedule)
+
+	// === Then ===
+	suite.ErrorIs(err, errOccurrenceExecuted)
+	suite.assertBalance(accountID, "checking", 6)
+	suite.assertBalance(accountID, "savings", 4)
+	listed, err := suite.manager.ListScheduleExecutions(ctx, accountID, ListScheduleExecutionsInput{ScheduleID: created.Schedule.ScheduleID})
+	suite.Require().NoError(err)
+	suite.Len(listed.Executions, 1)
+}
+
+func (suite *AccountManagerConformanceSuite) TestRunSchedules_OccurrenceNotTransferredOnceCancelled() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 10)
+	suite.createAccount(accountID, "savings", 0)
+	created, err := suite.manager.CreateSchedule(ctx, accountID, CreateScheduleInput{
+		SrcAccountType:  "checking",
+		DestAccountID:   accountID,
+		DestAccountType: "savings",
+		Amount:          aws.Int(4),
+		StartAt:         aws.Time(time.Now().Add(-time.Minute)),
+	})
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.manager.CancelSchedule(ctx, accountID, CancelScheduleInput{ScheduleID: created.Schedule.ScheduleID}))
+
+	// === When ===
+	// A run which read the schedule before it was cancelled
+	_, err = executeSchedule(ctx, suite.manager, created.Schedule)
+
+	// === Then ===
+	suite.ErrorIs(err, errOccurrenceExecuted)
+	suite.assertBalance(accountID, "checking", 10)
+	suite.Equal(ScheduleStatusCancelled, suite.schedule(accountID, created.Schedule.ScheduleID).Status)
+}
+
+func (suite *AccountManagerConformanceSuite) TestRunSchedules_RecordsRejectedTransfer() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 1)
+	suite.createAccount(accountID, "savings", 0)
+	created, err := suite.manager.CreateSchedule(ctx, accountID, CreateScheduleInput{
+		SrcAccountType:  "checking",
+		DestAccountID:   accountID,
+		DestAccountType: "savings",
+		Amount:          aws.Int(4),
+	})
+	suite.Require().NoError(err)
+
+	// === When ===
+	output, err := suite.manager.RunSchedules(ctx, RunSchedulesInput{})
+
+	// === Then ===
+	suite.Require().NoError(err)
+	executions := executionsOf(output, created.Schedule.ScheduleID)
+	suite.Require().Len(executions, 1)
+	suite.Equal(ExecutionStatusFailed, executions[0].Status)
+	suite.Equal(InsufficientFundsError{AccountID: accountID, AccountType: "checking"}.Error(), executions[0].Error)
+	suite.Empty(executions[0].TransactionID)
+	suite.assertBalance(accountID, "checking", 1)
+	suite.Equal(ScheduleStatusCompleted, suite.schedule(accountID, created.Schedule.ScheduleID).Status)
+}
+
+func (suite *AccountManagerConformanceSuite) TestRunSchedules_RecordsTransferForbiddenByProduct() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	accountType := "restricted-" + accountID
+	suite.putProduct(Product{AccountType: accountType, TransferRules: TransferRules{MaxAmount: 5}})
+	suite.createAccount(accountID, accountType, 10)
+	suite.createAccount(accountID, "savings", 0)
+	created, err := suite.manager.CreateSchedule(ctx, accountID, CreateScheduleInput{
+		SrcAccountType:  accountType,
+		DestAccountID:   accountID,
+		DestAccountType: "savings",
+		Amount:          aws.Int(6),
+		Recurrence:      "FREQ=DAILY;COUNT=2",
+		StartAt:         aws.Time(time.Now().Add(-time.Minute)),
+	})
+	suite.Require().NoError(err)
+
+	// === When ===
+	output, err := suite.manager.RunSchedules(ctx, RunSchedulesInput{})
+
+	// === Then ===
+	// The rejected occurrence is recorded and skipped, rather than retried by every run
+	suite.Require().NoError(err)
+	executions := executionsOf(output, created.Schedule.ScheduleID)
+	suite.Require().Len(executions, 1)
+	suite.Equal(ExecutionStatusFailed, executions[0].Status)
+	suite.Equal(TransferNotAllowedError{AccountType: accountType, Reason: "transfers of more than 5"}.Error(), executions[0].Error)
+	suite.assertBalance(accountID, accountType, 10)
+	schedule := suite.schedule(accountID, created.Schedule.ScheduleID)
+	suite.Equal(ScheduleStatusActive, schedule.Status)
+	suite.Equal(1, schedule.Occurrences)
+}
+
+func (suite *AccountManagerConformanceSuite) TestCancelSchedule() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	otherAccountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 10)
+	suite.createAccount(accountID, "savings", 0)
+	created, err := suite.manager.CreateSchedule(ctx, accountID, CreateScheduleInput{
+		SrcAccountType:  "checking",
+		DestAccountID:   accountID,
+		DestAccountType: "savings",
+		Amount:          aws.Int(4),
+		Recurrence:      "FREQ=MONTHLY;BYMONTHDAY=-1",
+	})
+	suite.Require().NoError(err)
+	input := CancelScheduleInput{ScheduleID: created.Schedule.ScheduleID}
+
+	// === When ===
+	err = suite.manager.CancelSchedule(ctx, accountID, input)
+
+	// === Then ===
+	suite.Require().NoError(err)
+	schedule := suite.schedule(accountID, created.Schedule.ScheduleID)
+	suite.Equal(ScheduleStatusCancelled, schedule.Status)
+	suite.Nil(schedule.NextRunAt)
+
+	output, err := suite.manager.RunSchedules(ctx, RunSchedulesInput{})
+	suite.Require().NoError(err)
+	suite.Empty(executionsOf(output, created.Schedule.ScheduleID))
+	suite.assertBalance(accountID, "checking", 10)
+
+	suite.NoError(suite.manager.CancelSchedule(ctx, accountID, input))
+	suite.Equal(ScheduleNotFoundError{ScheduleID: created.Schedule.ScheduleID}, suite.manager.CancelSchedule(ctx, otherAccountID, input))
+	_, err = suite.manager.ListScheduleExecutions(ctx, otherAccountID, ListScheduleExecutionsInput{ScheduleID: created.Schedule.ScheduleID})
+	suite.Equal(ScheduleNotFoundError{ScheduleID: created.Schedule.ScheduleID}, err)
+}
+
+func (suite *AccountManagerConformanceSuite) TestRunSchedules_NotDueBeforeStart() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 10)
+	suite.createAccount(accountID, "savings", 0)
+	created, err := suite.manager.CreateSchedule(ctx, accountID, CreateScheduleInput{
+		SrcAccountType:  "checking",
+		DestAccountID:   accountID,
+		DestAccountType: "savings",
+		Amount:          aws.Int(4),
+		StartAt:         aws.Time(time.Now().Add(time.Hour)),
+	})
+	suite.Require().NoError(err)
+
+	// === When ===
+	output, err := suite.manager.RunSchedules(ctx, RunSchedulesInput{})
+
+	// === Then ===
+	suite.Require().NoError(err)
+	suite.Empty(executionsOf(output, created.Schedule.ScheduleID))
+	suite.assertBalance(accountID, "checking", 10)
+	suite.NoError(suite.manager.CancelSchedule(ctx, accountID, CancelScheduleInput{ScheduleID: created.Schedule.ScheduleID}))
+}
+
+func (suite *AccountManagerConformanceSuite) TestListSchedules_Pagination() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 0)
+	suite.createAccount(accountID, "savings", 0)
+	var expected []string
+	for i := 0; i < 3; i++ {
+		created, err := suite.manager.CreateSchedule(ctx, accountID, CreateScheduleInput{
+			SrcAccountType:  "checking",
+			DestAccountID:   accountID,
+			DestAccountType: "savings",
+			Amount:          aws.Int(1),
+			StartAt:         aws.Time(time.Now().Add(time.Hour)),
+		})
+		suite.Require().NoError(err)
+		expected = append(expected, created.Schedule.ScheduleID)
+		defer func() {
+			suite.NoError(suite.manager.CancelSchedule(ctx, accountID, CancelScheduleInput{ScheduleID: created.Schedule.ScheduleID}))
+		}()
+	}
+	sort.Strings(expected)
+
+	// === When ===
+	first, err := suite.manager.ListSchedules(ctx, accountID, ListSchedulesInput{Limit: aws.Int32(2)})
+	suite.Require().NoError(err)
+	second, err := suite.manager.ListSchedules(ctx, accountID, ListSchedulesInput{ExclusiveStartKey: &first.LastEvaluatedKey})
+	suite.Require().NoError(err)
+
+	// === Then ===
+	var listed []string
+	for _, schedule := range append(first.Schedules, second.Schedules...) {
+		listed = append(listed, schedule.ScheduleID)
+	}
+	suite.Equal(expected, listed)
+	suite.Equal(ScheduleKey{}, second.LastEvaluatedKey)
+}
+
+func (suite *AccountManagerConformanceSuite) TestAccrueProductInterest() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	// Every test accrues a product of its own, so that accruals of other tests' accounts don't interfere
+	accountType := "savings-" + accountID
+	suite.putProduct(Product{
+		AccountType: accountType,
+		Interest:    &InterestTerms{APY: "0.0425", DayCount: DayCountActual365, Compounding: CompoundingMonthly},
+	})
+	suite.createAccount(accountID, accountType, 100000)
+	input := AccrueProductInterestInput{
+		AccountType: accountType,
+		Date:        "2022-09-29",
+	}
+
+	// === When ===
+	first, err := suite.manager.AccrueProductInterest(ctx, input)
+	suite.Require().NoError(err)
+	again, err := suite.manager.AccrueProductInterest(ctx, input)
+	suite.Require().NoError(err)
+
+	// === Then ===
+	suite.Equal(ProductAccrual{AccountType: accountType, Accrued: 1}, first)
+	suite.Equal(ProductAccrual{AccountType: accountType}, again)
+	output, err := suite.manager.GetBalance(ctx, accountID, GetBalanceInput{AccountType: accountType})
+	suite.Require().NoError(err)
+	suite.Equal(100000, output.Balance)
+	suite.Equal(11, output.AccruedInterest)
+}
+
+func (suite *AccountManagerConformanceSuite) TestAccrueProductInterest_NothingWithoutInterestTerms() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	accountType := "savings-" + accountID
+	suite.putProduct(Product{AccountType: accountType})
+	suite.createAccount(accountID, accountType, 100000)
+
+	// === When ===
+	accrual, err := suite.manager.AccrueProductInterest(ctx, AccrueProductInterestInput{
+		AccountType: accountType,
+		Date:        "2022-09-29",
+	})
+
+	// === Then ===
+	suite.Require().NoError(err)
+	suite.Equal(ProductAccrual{AccountType: accountType}, accrual)
+	output, err := suite.manager.GetBalance(ctx, accountID, GetBalanceInput{AccountType: accountType})
+	suite.Require().NoError(err)
+	suite.Zero(output.AccruedInterest)
+}
+
+func (suite *AccountManagerConformanceSuite) TestAccrueProductInterest_ErrorWhenProductDoesNotExist() {
+	// === Given ===
+	accountType := "savings-" + newConformanceAccountID()
+
+	// === When ===
+	_, err := suite.manager.AccrueProductInterest(context.Background(), AccrueProductInterestInput{
+		AccountType: accountType,
+		Date:        "2022-09-29",
+	})
+
+	// === Then ===
+	suite.Equal(ProductNotFoundError{AccountType: accountType}, err)
+}
+
+func (suite *AccountManagerConformanceSuite) TestAccrueProductInterest_PostsAtEndOfPeriod() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	accountType := "savings-" + accountID
+	suite.putProduct(Product{
+		AccountType: accountType,
+		Interest:    &InterestTerms{APY: "0.0425", DayCount: DayCountActual365, Compounding: CompoundingMonthly},
+	})
+	suite.createAccount(accountID, accountType, 100000)
+	input := AccrueProductInterestInput{
+		AccountType: accountType,
+		Date:        "2022-09-29",
+	}
+	_, err := suite.manager.AccrueProductInterest(ctx, input)
+	suite.Require().NoError(err)
+	input.Date = "2022-09-30"
+
+	// === When ===
+	accrual, err := suite.manager.AccrueProductInterest(ctx, input)
+
+	// === Then ===
+	suite.Require().NoError(err)
+	suite.Equal(ProductAccrual{AccountType: accountType, Accrued: 1, Posted: 1}, accrual)
+	output, err := suite.manager.GetBalance(ctx, accountID, GetBalanceInput{AccountType: accountType})
+	suite.Require().NoError(err)
+	suite.Equal(100022, output.Balance)
+	suite.Zero(output.AccruedInterest)
+
+	transactions, err := suite.manager.ListTransactions(ctx, accountID, ListTransactionsInput{})
+	suite.Require().NoError(err)
+	suite.Require().Len(transactions.Transactions, 2)
+	interest := transactions.Transactions[0]
+	suite.Equal(TransactionTypeInterest, interest.Type)
+	suite.Equal(22, interest.Amount)
+	suite.Equal(100022, interest.Dest.Balance)
+
+	reconciled, err := suite.manager.Reconcile(ctx, ReconcileInput{AccountID: accountID, AccountType: accountType})
+	suite.Require().NoError(err)
+	suite.True(reconciled.Reconciled)
+}
+
+func (suite *AccountManagerConformanceSuite) TestAccrueProductInterest_NotBeforeLastAccrual() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	accountType := "savings-" + accountID
+	suite.putProduct(Product{
+		AccountType: accountType,
+		Interest:    &InterestTerms{APY: "0.0425", DayCount: DayCountActual365, Compounding: CompoundingMonthly},
+	})
+	suite.createAccount(accountID, accountType, 100000)
+	input := AccrueProductInterestInput{
+		AccountType: accountType,
+		Date:        "2022-09-29",
+	}
+	_, err := suite.manager.AccrueProductInterest(ctx, input)
+	suite.Require().NoError(err)
+	input.Date = "2022-09-28"
+
+	// === When ===
+	accrual, err := suite.manager.AccrueProductInterest(ctx, input)
+
+	// === Then ===
+	suite.Require().NoError(err)
+	suite.Equal(ProductAccrual{AccountType: accountType}, accrual)
+}
+
+func (suite *AccountManagerConformanceSuite) TestAccrueProductInterest_CatchesUpMissedDays() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	otherAccountID := newConformanceAccountID()
+	accountType := "savings-" + accountID
+	suite.putProduct(Product{
+		AccountType: accountType,
+		Interest:    &InterestTerms{APY: "0.0425", DayCount: DayCountActual365, Compounding: CompoundingMonthly},
+	})
+	suite.createAccount(accountID, accountType, 100000)
+	suite.createAccount(otherAccountID, accountType, 100000)
+	input := AccrueProductInterestInput{
+		AccountType: accountType,
+		Date:        "2022-09-28",
+	}
+	_, err := suite.manager.AccrueProductInterest(ctx, input)
+	suite.Require().NoError(err)
+
+	// === When ===
+	// The runs for the 29th and 30th are missed, and the next run is on the 1st
+	input.Date = "2022-10-01"
+	accrual, err := suite.manager.AccrueProductInterest(ctx, input)
+
+	// === Then ===
+	suite.Require().NoError(err)
+	suite.Equal(ProductAccrual{AccountType: accountType, Accrued: 2, Posted: 2}, accrual)
+	// The interest of September, including the missed days, is posted at the end of the month
+	for _, id := range []string{accountID, otherAccountID} {
+		output, err := suite.manager.GetBalance(ctx, id, GetBalanceInput{AccountType: accountType})
+		suite.Require().NoError(err)
+		suite.Equal(100034, output.Balance)
+		suite.Equal(11, output.AccruedInterest)
+	}
+}
+
+func (suite *AccountManagerConformanceSuite) TestAccrueProductInterest_NotOnFrozenAccounts() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	accountType := "savings-" + accountID
+	suite.putProduct(Product{
+		AccountType: accountType,
+		Interest:    &InterestTerms{APY: "0.0425", DayCount: DayCountActual365, Compounding: CompoundingMonthly},
+	})
+	suite.createAccount(accountID, accountType, 100000)
+	input := AccrueProductInterestInput{
+		AccountType: accountType,
+		Date:        "2022-09-28",
+	}
+	_, err := suite.manager.AccrueProductInterest(ctx, input)
+	suite.Require().NoError(err)
+	statusInput := SetAccountStatusInput{AccountID: accountID, AccountType: accountType, Status: AccountStatusFrozen}
+	suite.Require().NoError(suite.manager.SetAccountStatus(ctx, statusInput))
+
+	// === When ===
+	input.Date = "2022-09-29"
+	frozen, err := suite.manager.AccrueProductInterest(ctx, input)
+	suite.Require().NoError(err)
+	statusInput.Status = AccountStatusActive
+	suite.Require().NoError(suite.manager.SetAccountStatus(ctx, statusInput))
+	input.Date = "2022-09-30"
+	unfrozen, err := suite.manager.AccrueProductInterest(ctx, input)
+	suite.Require().NoError(err)
+
+	// === Then ===
+	suite.Equal(ProductAccrual{AccountType: accountType}, frozen)
+	suite.Equal(ProductAccrual{AccountType: accountType, Accrued: 1, Posted: 1}, unfrozen)
+	// The day the account was frozen is not caught up once it is unfrozen, so it accrues two days of September
+	output, err := suite.manager.GetBalance(ctx, accountID, GetBalanceInput{AccountType: accountType})
+	suite.Require().NoError(err)
+	suite.Equal(100022, output.Balance)
+}
+
+func (suite *AccountManagerConformanceSuite) TestReconcile() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	otherAccountID := newConformanceAccountID()
+	suite.createAccount(accountID, "savings", 10)
+	suite.createAccount(accountID, "checking", 0)
+	suite.createAccount(otherAccountID, "checking", 0)
+	_, err := suite.manager.Transfer(ctx, accountID, TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   accountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(4),
+	})
+	suite.Require().NoError(err)
+	_, err = suite.manager.Transfer(ctx, accountID, TransferInput{
+		SrcAccountType:  "checking",
+		DestAccountID:   otherAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(1),
+	})
+	suite.Require().NoError(err)
+	_, err = suite.manager.Deposit(ctx, DepositInput{AccountID: accountID, AccountType: "savings", Amount: aws.Int(3), ExternalReference: "wire-0001"})
+	suite.Require().NoError(err)
+	_, err = suite.manager.Withdraw(ctx, WithdrawInput{AccountID: accountID, AccountType: "savings", Amount: aws.Int(2), ExternalReference: "ach-0001"})
+	suite.Require().NoError(err)
+
+	// === When ===
+	savings, err := suite.manager.Reconcile(ctx, ReconcileInput{AccountID: accountID, AccountType: "savings"})
+	suite.Require().NoError(err)
+	checking, err := suite.manager.Reconcile(ctx, ReconcileInput{AccountID: accountID, AccountType: "checking"})
+	suite.Require().NoError(err)
+	other, err := suite.manager.Reconcile(ctx, ReconcileInput{AccountID: otherAccountID, AccountType: "checking"})
+	suite.Require().NoError(err)
+
+	// === Then ===
+	suite.Equal(ReconcileOutput{Currency: "USD", Balance: aws.Int(7), PostedBalance: 7, Reconciled: true}, savings)
+	suite.Equal(ReconcileOutput{Currency: "USD", Balance: aws.Int(3), PostedBalance: 3, Reconciled: true}, checking)
+	suite.Equal(ReconcileOutput{Currency: "USD", Balance: aws.Int(1), PostedBalance: 1, Reconciled: true}, other)
+}
+
+func (suite *AccountManagerConformanceSuite) TestReconcile_ErrorWhenAccountDoesNotExist() {
+	// === Given ===
+	accountID := newConformanceAccountID()
+
+	// === When ===
+	_, err := suite.manager.Reconcile(context.Background(), ReconcileInput{AccountID: accountID, AccountType: "savings"})
+
+	// === Then ===
+	suite.Equal(AccountDoesNotExistError{AccountID: accountID, AccountType: "savings"}, err)
+}
+
+func (suite *AccountManagerConformanceSuite) TestTransactions_PostToSystemAccounts() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "savings", 10)
+
+	// === When ===
+	output, err := suite.manager.Deposit(ctx, DepositInput{AccountID: accountID, AccountType: "savings", Amount: aws.Int(3), ExternalReference: "wire-0001"})
+
+	// === Then ===
+	suite.Require().NoError(err)
+	suite.Equal([]Posting{
+		{AccountID: SystemAccountID, AccountType: "deposits", Currency: "USD", Amount: -3},
+		{AccountID: accountID, AccountType: "savings", Currency: "USD", Amount: 3},
+	}, output.Transaction.Postings)
+
+	// The system account is reconciled from its postings on the day of the deposit, which include those of other tests
+	date := output.Transaction.Timestamp.Format("2006-01-02")
+	deposits, err := suite.manager.Reconcile(ctx, ReconcileInput{AccountID: SystemAccountID, AccountType: "deposits", Date: date})
+	suite.Require().NoError(err)
+	suite.Equal(date, deposits.Date)
+	suite.Nil(deposits.Balance)
+	suite.LessOrEqual(deposits.PostedBalance, -3)
+	suite.True(deposits.Reconciled)
+}
+
+func (suite *AccountManagerConformanceSuite) TestListAccounts_Pagination() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 0)
+	suite.createAccount(accountID, "savings", 0)
+	suite.createAccount(accountID, "brokerage", 0)
+	suite.createAccount(newConformanceAccountID(), "savings", 0)
+
+	// === When ===
+	firstPage, err := suite.manager.ListAccounts(ctx, accountID, ListAccountsInput{Limit: aws.Int32(2)})
+	suite.Require().NoError(err)
+	secondPage, err := suite.manager.ListAccounts(ctx, accountID, ListAccountsInput{
+		ExclusiveStartKey: &firstPage.LastEvaluatedKey,
+		Limit:             aws.Int32(2),
+	})
+	suite.Require().NoError(err)
+
+	// === Then ===
+	// Accounts are returned in order of account type
+	suite.Equal([]AccountKey{
+		{AccountID: accountID, AccountType: "brokerage"},
+		{AccountID: accountID, AccountType: "checking"},
+	}, firstPage.Accounts)
+	suite.Equal(AccountKey{AccountID: accountID, AccountType: "checking"}, firstPage.LastEvaluatedKey)
+	suite.Equal([]AccountKey{
+		{AccountID: accountID, AccountType: "savings"},
+	}, secondPage.Accounts)
+	suite.Equal(AccountKey{}, secondPage.LastEvaluatedKey)
+}
+
+func (suite *AccountManagerConformanceSuite) TestListAccounts_WithoutLimit() {
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 0)
+	suite.createAccount(accountID, "savings", 0)
+
+	// === When ===
+	output, err := suite.manager.ListAccounts(ctx, accountID, ListAccountsInput{})
+
+	// === Then ===
+	suite.Require().NoError(err)
+	suite.Equal([]AccountKey{
+		{AccountID: accountID, AccountType: "checking"},
+		{AccountID: accountID, AccountType: "savings"},
+	}, output.Accounts)
+	suite.Equal(AccountKey{}, output.LastEvaluatedKey)
+}
+
+func (suite *AccountManagerConformanceSuite) TestListAccountsAdmin_Pagination() {
+	// === Given ===
+	ctx := context.Background()
+	expected := []AccountKey{
+		{AccountID: newConformanceAccountID(), AccountType: "savings"},
+		{AccountID: newConformanceAccountID(), AccountType: "checking"},
+		{AccountID: newConformanceAccountID(), AccountType: "savings"},
+	}
+	for _, key := range expected {
+		suite.createAccount(key.AccountID, key.AccountType, 0)
+	}
+
+	// === When ===
+	var accounts []AccountKey
+	input := ListAccountsInput{Limit: aws.Int32(1)}
+	for {
+		output, err := suite.manager.ListAccountsAdmin(ctx, input)
+		suite.Require().NoError(err)
+		suite.Require().LessOrEqual(len(output.Accounts), 1)
+		accounts = append(accounts, output.Accounts...)
+		if output.LastEvaluatedKey == (AccountKey{}) {
+			break
+		}
+		input.ExclusiveStartKey = &output.LastEvaluatedKey
+	}
+
+	// === Then ===
+	// Other accounts may exist in the backend, and the scan order is unspecified
+	for _, key := range expected {
+		suite.Contains(accounts, key)
+	}
+}
+
+func (suite *AccountManagerConformanceSuite) TestListTransactions() {
+	// === Given ===
+	ctx := context.Background()
+	srcAccountID := newConformanceAccountID()
+	destAccountID := newConformanceAccountID()
+	suite.createAccount(srcAccountID, "savings", 10)
+	suite.createAccount(destAccountID, "checking", 0)
+	transfer, err := suite.manager.Transfer(ctx, srcAccountID, TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   destAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(10),
+	})
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.manager.DeleteAccount(ctx, srcAccountID, DeleteAccountInput{AccountType: "savings"}))
+
+	// === When ===
+	firstPage, err := suite.manager.ListTransactions(ctx, srcAccountID, ListTransactionsInput{Limit: aws.Int32(2)})
+	suite.Require().NoError(err)
+	secondPage, err := suite.manager.ListTransactions(ctx, srcAccountID, ListTransactionsInput{
+		ExclusiveStartKey: &firstPage.LastEvaluatedKey,
+		Limit:             aws.Int32(2),
+	})
+	suite.Require().NoError(err)
+	destTransactions, err := suite.manager.ListTransactions(ctx, destAccountID, ListTransactionsInput{})
+	suite.Require().NoError(err)
+
+	// === Then ===
+	// Transactions are returned most recent first
+	suite.Require().Len(firstPage.Transactions, 2)
+	suite.Equal(TransactionTypeDelete, firstPage.Transactions[0].Type)
+	suite.Equal(&TransactionParty{AccountID: srcAccountID, AccountType: "savings"}, firstPage.Transactions[0].Src)
+	suite.Equal(transfer.Transaction.TransactionID, firstPage.Transactions[1].TransactionID)
+	suite.Equal(TransactionKey{AccountID: srcAccountID, TransactionID: transfer.Transaction.TransactionID}, firstPage.LastEvaluatedKey)
+
+	suite.Require().Len(secondPage.Transactions, 1)
+	suite.Equal(TransactionTypeCreate, secondPage.Transactions[0].Type)
+	suite.Equal(10, secondPage.Transactions[0].Amount)
+	suite.Equal(&TransactionParty{AccountID: srcAccountID, AccountType: "savings", Currency: "USD", Balance: 10}, secondPage.Transactions[0].Dest)
+	suite.Equal(TransactionKey{}, secondPage.LastEvaluatedKey)
+
+	// The transfer is also recorded for the destination account's owner
+	suite.Require().Len(destTransactions.Transactions, 2)
+	suite.Equal(transfer.Transaction.TransactionID, destTransactions.Transactions[0].TransactionID)
+	suite.Equal(&TransactionParty{AccountID: destAccountID, AccountType: "checking", Currency: "USD", Balance: 10}, destTransactions.Transactions[0].Dest)
+}
+
+// TestConcurrentTransfers_ConserveMoney moves money between a set of accounts from many goroutines at once. Individual
+// transfers may fail, but money must never be created or destroyed and no balance may go negative.
+func (suite *AccountManagerConformanceSuite) TestConcurrentTransfers_ConserveMoney() {
+	const (
+		accountCount   = 4
+		initialBalance = 100
+		workers        = 8
+		transfersEach  = 25
+	)
+
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	var accountTypes []string
+	for i := 0; i < accountCount; i++ {
+		accountType := fmt.Sprintf("account-%d", i)
+		accountTypes = append(accountTypes, accountType)
+		suite.putProduct(Product{AccountType: accountType})
+		suite.createAccount(accountID, accountType, initialBalance)
+	}
+
+	// === When ===
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := mathrand.New(mathrand.NewSource(seed))
+			for i := 0; i < transfersEach; i++ {
+				src := rng.Intn(accountCount)
+				dest := (src + 1 + rng.Intn(accountCount-1)) % accountCount
+				_, _ = suite.manager.Transfer(ctx, accountID, TransferInput{
+					SrcAccountType:  accountTypes[src],
+					DestAccountID:   accountID,
+					DestAccountType: accountTypes[dest],
+					Amount:          aws.Int(1 + rng.Intn(30)),
+				})
+			}
+		}(int64(w))
+	}
+	wg.Wait()
+
+	// === Then ===
+	total := 0
+	for _, accountType := range accountTypes {
+		output, err := suite.manager.GetBalance(ctx, accountID, GetBalanceInput{AccountType: accountType})
+		suite.Require().NoError(err)
+		suite.GreaterOrEqual(output.Balance, 0)
+		total += output.Balance
+	}
+	suite.Equal(accountCount*initialBalance, total)
+}
+
+// TestConcurrentDeletes_DoNotFail deletes an account from many goroutines while others deposit to it. Each deletion
+// must either succeed or be refused for the balance, rather than fail because of the concurrent deposits.
+func (suite *AccountManagerConformanceSuite) TestConcurrentDeletes_DoNotFail() {
+	const workers = 4
+
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "checking", 0)
+
+	// === When ===
+	var wg sync.WaitGroup
+	deleteErrs := make(chan error, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			deleteErrs <- suite.manager.DeleteAccount(ctx, accountID, DeleteAccountInput{AccountType: "checking"})
+		}()
+		go func(w int) {
+			defer wg.Done()
+			_, _ = suite.manager.Deposit(ctx, DepositInput{
+				AccountID:         accountID,
+				AccountType:       "checking",
+				Amount:            aws.Int(1),
+				ExternalReference: fmt.Sprintf("wire-%04d", w),
+			})
+		}(w)
+	}
+	wg.Wait()
+	close(deleteErrs)
+
+	// === Then ===
+	for err := range deleteErrs {
+		if err != nil {
+			suite.Equal(NonZeroBalanceError{AccountID: accountID, AccountType: "checking"}, err)
+		}
+	}
+}
+
+// TestConcurrentTransfers_IdempotencyKey replays the same transfer from many goroutines at once. At most one of them
+// may move money, and every successful call must report the same transaction.
+func (suite *AccountManagerConformanceSuite) TestConcurrentTransfers_IdempotencyKey() {
+	const workers = 8
+
+	// === Given ===
+	ctx := context.Background()
+	accountID := newConformanceAccountID()
+	suite.createAccount(accountID, "savings", 10)
+	suite.createAccount(accountID, "checking", 0)
+	input := TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   accountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(4),
+		IdempotencyKey:  "idempotency-key",
+	}
+
+	// === When ===
+	var wg sync.WaitGroup
+	transactionIDs := make(chan string, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			output, err := suite.manager.Transfer(ctx, accountID, input)
+			if err == nil {
+				transactionIDs <- output.Transaction.TransactionID
+			}
+		}()
+	}
+	wg.Wait()
+	close(transactionIDs)
+
+	// === Then ===
+	distinct := make(map[string]bool)
+	for transactionID := range transactionIDs {
+		distinct[transactionID] = true
+	}
+	suite.LessOrEqual(len(distinct), 1)
+	suite.assertBalance(accountID, "savings", 10-4*len(distinct))
+	suite.assertBalance(accountID, "checking", 4*len(distinct))
+}
+
+func (suite *AccountManagerConformanceSuite) putProduct(product Product) {
+	require.NoError(suite.T(), suite.manager.PutProduct(context.Background(), product))
+}
+
+func (suite *AccountManagerConformanceSuite) createAccount(accountID, accountType string, initialBalance int) {
+	suite.createAccountInCurrency(accountID, accountType, initialBalance, "")
+}
+
+func (suite *AccountManagerConformanceSuite) createAccountInCurrency(accountID, accountType string, initialBalance int, currency string) {
+	err := suite.manager.CreateAccount(context.Background(), accountID, CreateAccountInput{
+		AccountType:    accountType,
+		InitialBalance: aws.Int(initialBalance),
+		Currency:       currency,
+	})
+	require.NoError(suite.T(), err)
+}
+
+// schedule returns the schedule of an account with the given ID
+func (suite *AccountManagerConformanceSuite) schedule(accountID, scheduleID string) Schedule {
+	output, err := suite.manager.ListSchedules(context.Background(), accountID, ListSchedulesInput{})
+	require.NoError(suite.T(), err)
+	for _, schedule := range output.Schedules {
+		if schedule.ScheduleID == scheduleID {
+			return schedule
+		}
+	}
+	require.FailNow(suite.T(), "schedule not found", scheduleID)
+	return Schedule{}
+}
+
+// assertNoSchedules asserts that an account ID has no schedules
+func (suite *AccountManagerConformanceSuite) assertNoSchedules(accountID string) {
+	output, err := suite.manager.ListSchedules(context.Background(), accountID, ListSchedulesInput{})
+	require.NoError(suite.T(), err)
+	suite.Empty(output.Schedules)
+}
+
+// productsOf returns the products of the test of an account ID in a listing, which may include other tests' products
+func productsOf(output ListProductsOutput, accountID string) []Product {
+	var products []Product
+	for _, product := range output.Products {
+		if strings.HasSuffix(product.AccountType, "-"+accountID) {
+			products = append(products, product)
+		}
+	}
+	return products
+}
+
+// executionsOf returns the executions of one schedule in the output of a run, which may have run other schedules too
+func executionsOf(output RunSchedulesOutput, scheduleID string) []ScheduleExecution {
+	var executions []ScheduleExecution
+	for _, execution := range output.Executions {
+		if execution.ScheduleID == scheduleID {
+			executions = append(executions, execution)
+		}
+	}
+	return executions
+}
+
+func (suite *AccountManagerConformanceSuite) assertBalance(accountID, accountType string, expected int) {
+	output, err := suite.manager.GetBalance(context.Background(), accountID, GetBalanceInput{AccountType: accountType})
+	if assert.NoError(suite.T(), err) {
+		assert.Equal(suite.T(), expected, output.Balance, "balance of %s:%s", accountID, accountType)
+	}
+}
+
+// newConformanceAccountID returns a random 12 digit account ID, in the same format as an AWS account ID
+func newConformanceAccountID() string {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000_000_000))
+	if err != nil {
+		panic(err)
+	}
+	return fmt.Sprintf("%012d", n)
+}
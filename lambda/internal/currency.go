@@ -0,0 +1,137 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	currencyAttr = "Currency"
+
+	// Currency of accounts created before accounts had currencies, and of accounts created without one
+	DefaultCurrency = "USD"
+)
+
+// currencyExponents is the number of digits after the decimal separator of each active ISO 4217 currency, so that an
+// amount of 1234 minor units is 12.34 USD but 1234 JPY. Funds and precious metals, which have no minor unit, are not
+// supported.
+var currencyExponents = map[string]int{}
+
+func init() {
+	exponents := map[int]string{
+		0: "BIF CLP DJF GNF ISK JPY KMF KRW PYG RWF UGX UYI VND VUV XAF XOF XPF",
+		2: "AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BMD BND BOB BOV BRL BSD BTN BWP BYN BZD CAD CDF " +
+			"CHE CHF CHW CNY COP COU CRC CUC CUP CVE CZK DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL GHS GIP GMD GTQ " +
+			"GYD HKD HNL HTG HUF IDR ILS INR IRR JMD KES KGS KHR KPW KYD KZT LAK LBP LKR LRD LSL MAD MDL MGA MKD MMK " +
+			"MNT MOP MRU MUR MVR MWK MXN MXV MYR MZN NAD NGN NIO NOK NPR NZD PAB PEN PGK PHP PKR PLN QAR RON RSD RUB " +
+			"SAR SBD SCR SDG SEK SGD SHP SLE SLL SOS SRD SSP STN SVC SYP SZL THB TJS TMT TOP TRY TTD TWD TZS UAH USD " +
+			"USN UYU UZS VED VES WST XCD YER ZAR ZMW ZWL",
+		3: "BHD IQD JOD KWD LYD OMR TND",
+		4: "CLF UYW",
+	}
+	for exponent, codes := range exponents {
+		for _, code := range strings.Fields(codes) {
+			currencyExponents[code] = exponent
+		}
+	}
+}
+
+// IsSupportedCurrency returns whether code is an ISO 4217 currency that accounts may hold
+func IsSupportedCurrency(code string) bool {
+	_, ok := currencyExponents[code]
+	return ok
+}
+
+// CurrencyExponent returns the number of digits after the decimal separator of a supported currency
+func CurrencyExponent(code string) int {
+	return currencyExponents[code]
+}
+
+// FormatAmount formats an amount of minor units in the major units of its currency, e.g. 1234 USD as "12.34 USD"
+func FormatAmount(amount int, currency string) string {
+	exponent := CurrencyExponent(currency)
+	if exponent == 0 {
+		return fmt.Sprintf("%d %s", amount, currency)
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	digits := strconv.Itoa(amount)
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+	split := len(digits) - exponent
+	return fmt.Sprintf("%s%s.%s %s", sign, digits[:split], digits[split:], currency)
+}
+
+// currencyOrDefault returns the currency of an item, which is absent for items written before accounts had currencies
+func currencyOrDefault(currency string) string {
+	if currency == "" {
+		return DefaultCurrency
+	}
+	return currency
+}
+
+// CurrencyMismatchError is returned when a request asserts a currency other than the one an account holds
+type CurrencyMismatchError struct {
+	AccountID       string `json:"accountID"`
+	AccountType     string `json:"accountType"`
+	AccountCurrency string `json:"accountCurrency"`
+	RequestCurrency string `json:"requestCurrency"`
+}
+
+func (err CurrencyMismatchError) Error() string {
+	return fmt.Sprintf("The account %s:%s holds %s, not %s.", err.AccountID, err.AccountType, err.AccountCurrency, err.RequestCurrency)
+}
+
+// ConversionRequiredError is returned for a transfer between accounts of different currencies which does not request a
+// conversion
+type ConversionRequiredError struct {
+	SrcCurrency  string `json:"srcCurrency"`
+	DestCurrency string `json:"destCurrency"`
+}
+
+func (err ConversionRequiredError) Error() string {
+	return fmt.Sprintf("The transfer from %s to %s requires a currency conversion.", err.SrcCurrency, err.DestCurrency)
+}
+
+// UnexpectedConversionError is returned for a transfer between accounts of the same currency which requests a
+// conversion to a different amount
+type UnexpectedConversionError struct {
+	Currency string `json:"currency"`
+}
+
+func (err UnexpectedConversionError) Error() string {
+	return fmt.Sprintf("The transfer cannot convert between accounts which both hold %s.", err.Currency)
+}
+
+// creditedAmount returns the amount to credit the destination of a transfer with, which only differs from the amount
+// debited from the source when converting between currencies, either at the rate of quote or to DestAmount
+func (transferInput TransferInput) creditedAmount(srcCurrency, destCurrency string, quote *Quote) (int, error) {
+	if quote != nil {
+		err := quote.check(*transferInput.Amount, srcCurrency, destCurrency)
+		if err != nil {
+			return 0, err
+		}
+		return quote.DestAmount, nil
+	}
+
+	if srcCurrency == destCurrency {
+		if transferInput.DestAmount != nil && *transferInput.DestAmount != *transferInput.Amount {
+			return 0, UnexpectedConversionError{Currency: srcCurrency}
+		}
+		return *transferInput.Amount, nil
+	}
+
+	if transferInput.DestAmount == nil {
+		return 0, ConversionRequiredError{
+			SrcCurrency:  srcCurrency,
+			DestCurrency: destCurrency,
+		}
+	}
+	return *transferInput.DestAmount, nil
+}
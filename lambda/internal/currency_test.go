@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFormatAmount(t *testing.T) {
+	assert.Equal(t, "12.34 USD", FormatAmount(1234, "USD"))
+	assert.Equal(t, "0.05 USD", FormatAmount(5, "USD"))
+	assert.Equal(t, "-0.05 EUR", FormatAmount(-5, "EUR"))
+	assert.Equal(t, "1234 JPY", FormatAmount(1234, "JPY"))
+	assert.Equal(t, "1.234 KWD", FormatAmount(1234, "KWD"))
+	assert.Equal(t, "0.0001 CLF", FormatAmount(1, "CLF"))
+}
+
+func TestIsSupportedCurrency(t *testing.T) {
+	assert.True(t, IsSupportedCurrency("USD"))
+	assert.True(t, IsSupportedCurrency("JPY"))
+	assert.False(t, IsSupportedCurrency("usd"))
+	assert.False(t, IsSupportedCurrency("XAU"))
+	assert.Equal(t, 0, CurrencyExponent("JPY"))
+	assert.Equal(t, 3, CurrencyExponent("BHD"))
+}
+
+func TestTransferInput_CreditedAmount(t *testing.T) {
+	tests := []struct {
+		name         string
+		destAmount   *int
+		destCurrency string
+		expected     int
+		expectedErr  error
+	}{
+		{
+			name:         "same currency",
+			destCurrency: "USD",
+			expected:     500,
+		},
+		{
+			name:         "same currency with matching dest amount",
+			destAmount:   aws.Int(500),
+			destCurrency: "USD",
+			expected:     500,
+		},
+		{
+			name:         "same currency with different dest amount",
+			destAmount:   aws.Int(400),
+			destCurrency: "USD",
+			expectedErr:  UnexpectedConversionError{Currency: "USD"},
+		},
+		{
+			name:         "conversion",
+			destAmount:   aws.Int(740),
+			destCurrency: "JPY",
+			expected:     740,
+		},
+		{
+			name:         "conversion without dest amount",
+			destCurrency: "JPY",
+			expectedErr:  ConversionRequiredError{SrcCurrency: "USD", DestCurrency: "JPY"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// === Given ===
+			input := TransferInput{Amount: aws.Int(500), DestAmount: test.destAmount}
+
+			// === When ===
+			amount, err := input.creditedAmount("USD", test.destCurrency, nil)
+
+			// === Then ===
+			assert.Equal(t, test.expectedErr, err)
+			assert.Equal(t, test.expected, amount)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// NewLocalDynamoDBClient returns a client for a DynamoDB Local instance listening on endpoint, e.g.
+// http://localhost:8000
+func NewLocalDynamoDBClient(ctx context.Context, endpoint string) (*dynamodb.Client, error) {
+	resolver := aws.EndpointResolverWithOptionsFunc(func(_, _ string, _ ...interface{}) (aws.Endpoint, error) {
+		return aws.Endpoint{
+			URL:           endpoint,
+			SigningRegion: "localhost",
+		}, nil
+	})
+	cfg, err := config.LoadDefaultConfig(ctx,
+		config.WithEndpointResolverWithOptions(resolver),
+		config.WithRegion("localhost"),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider("fakekey", "fakesecretkey", "")))
+	if err != nil {
+		return nil, err
+	}
+
+	return dynamodb.NewFromConfig(cfg), nil
+}
+
+// CreateTables creates the tables used by the AccountManager, including those of schedules and the product catalog,
+// the roles table and the rates table if they do not already exist. In AWS the tables are managed by the CDK stack, so this is only intended for DynamoDB Local.
+func CreateTables(ctx context.Context, ddb *dynamodb.Client) error {
+	tables := []struct {
+		name string
+		// Empty for tables partitioned by AccountId
+		partitionKey string
+		// Empty for tables keyed by their partition key alone
+		sortKey string
+		// Global secondary index keyed by indexPartitionKey and indexSortKey, if indexName is defined
+		indexName, indexPartitionKey, indexSortKey string
+	}{
+		{name: tableName, sortKey: accountTypeAttr,
+			indexName: accountsByTypeIndexName, indexPartitionKey: accountTypeAttr, indexSortKey: accountIDAttr},
+		{name: transactionsTableName, sortKey: transactionIDAttr},
+		{name: idempotencyTableName, sortKey: idempotencyKeyAttr},
+		{name: quotesTableName, sortKey: quoteIDAttr},
+		{name: rolesTableName},
+		{name: ratesTableName, partitionKey: pairAttr},
+		{name: schedulesTableName, sortKey: scheduleIDAttr,
+			indexName: dueSchedulesIndexName, indexPartitionKey: scheduleStatusAttr, indexSortKey: nextRunAtAttr},
+		{name: scheduleExecutionsTableName, partitionKey: scheduleIDAttr, sortKey: occurrenceAttr},
+		{name: productsTableName, partitionKey: accountTypeAttr},
+	}
+
+	for _, table := range tables {
+		partitionKey := table.partitionKey
+		if partitionKey == "" {
+			partitionKey = accountIDAttr
+		}
+		input := &dynamodb.CreateTableInput{
+			AttributeDefinitions: []types.AttributeDefinition{
+				{
+					AttributeName: aws.String(partitionKey),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String(partitionKey),
+					KeyType:       types.KeyTypeHash,
+				},
+			},
+			TableName:   aws.String(table.name),
+			BillingMode: types.BillingModePayPerRequest,
+		}
+		if table.sortKey != "" {
+			input.AttributeDefinitions = append(input.AttributeDefinitions, types.AttributeDefinition{
+				AttributeName: aws.String(table.sortKey),
+				AttributeType: types.ScalarAttributeTypeS,
+			})
+			input.KeySchema = append(input.KeySchema, types.KeySchemaElement{
+				AttributeName: aws.String(table.sortKey),
+				KeyType:       types.KeyTypeRange,
+			})
+		}
+		if table.indexName != "" {
+			// Attributes which already key the table are only defined once
+			for _, indexKey := range []string{table.indexPartitionKey, table.indexSortKey} {
+				if indexKey == partitionKey || indexKey == table.sortKey {
+					continue
+				}
+				input.AttributeDefinitions = append(input.AttributeDefinitions, types.AttributeDefinition{
+					AttributeName: aws.String(indexKey),
+					AttributeType: types.ScalarAttributeTypeS,
+				})
+			}
+			input.GlobalSecondaryIndexes = []types.GlobalSecondaryIndex{
+				{
+					IndexName: aws.String(table.indexName),
+					KeySchema: []types.KeySchemaElement{
+						{
+							AttributeName: aws.String(table.indexPartitionKey),
+							KeyType:       types.KeyTypeHash,
+						},
+						{
+							AttributeName: aws.String(table.indexSortKey),
+							KeyType:       types.KeyTypeRange,
+						},
+					},
+					Projection: &types.Projection{
+						ProjectionType: types.ProjectionTypeAll,
+					},
+				},
+			}
+		}
+
+		_, err := ddb.CreateTable(ctx, input)
+		if err != nil {
+			var resourceInUseException *types.ResourceInUseException
+			if errors.As(err, &resourceInUseException) {
+				continue
+			}
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package internal
+
+// Stable codes which identify the errors of the account manager in error responses
+const (
+	CodeAccountAlreadyExists       = "ACCOUNT_ALREADY_EXISTS"
+	CodeNonZeroBalance             = "NON_ZERO_BALANCE"
+	CodeInsufficientFunds          = "INSUFFICIENT_FUNDS"
+	CodeAccountNotFound            = "ACCOUNT_NOT_FOUND"
+	CodeSourceAccountNotFound      = "SOURCE_ACCOUNT_NOT_FOUND"
+	CodeSelfTransfer               = "SELF_TRANSFER"
+	CodeTransactionConflict        = "TRANSACTION_CONFLICT"
+	CodeIdempotencyKeyConflict     = "IDEMPOTENCY_KEY_CONFLICT"
+	CodeCurrencyMismatch           = "CURRENCY_MISMATCH"
+	CodeConversionRequired         = "CONVERSION_REQUIRED"
+	CodeUnexpectedConversion       = "UNEXPECTED_CONVERSION"
+	CodeRateNotAvailable           = "RATE_NOT_AVAILABLE"
+	CodeConversionTooSmall         = "CONVERSION_TOO_SMALL"
+	CodeQuoteNotFound              = "QUOTE_NOT_FOUND"
+	CodeQuoteMismatch              = "QUOTE_MISMATCH"
+	CodeHoldNotFound               = "HOLD_NOT_FOUND"
+	CodeCaptureExceedsHold         = "CAPTURE_EXCEEDS_HOLD"
+	CodeScheduleNotFound           = "SCHEDULE_NOT_FOUND"
+	CodeProductNotFound            = "PRODUCT_NOT_FOUND"
+	CodeProductRetired             = "PRODUCT_RETIRED"
+	CodeMinimumOpeningBalance      = "BELOW_MINIMUM_OPENING_BALANCE"
+	CodeOverdraftAllowanceExceeded = "OVERDRAFT_ALLOWANCE_EXCEEDED"
+	CodeTransferNotAllowed         = "TRANSFER_NOT_ALLOWED"
+	CodeAccountNotActive           = "ACCOUNT_NOT_ACTIVE"
+	CodeInvalidStatusTransition    = "INVALID_STATUS_TRANSITION"
+	CodeHoldsOutstanding           = "HOLDS_OUTSTANDING"
+)
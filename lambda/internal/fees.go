@@ -0,0 +1,249 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"math/big"
+	"strconv"
+)
+
+const (
+	feesAttr           = "Fees"
+	feeTypeAttr        = "FeeType"
+	flatAttr           = "Flat"
+	basisPointsAttr    = "BasisPoints"
+	minimumBalanceAttr = "MinimumBalance"
+)
+
+type FeeType string
+
+const (
+	// Charged on transfers to accounts of other owners
+	FeeTypeCrossOwner FeeType = "CROSS_OWNER"
+	// Charged on transfers which leave the source account below the minimum balance of its product
+	FeeTypeBelowMinimumBalance FeeType = "BELOW_MINIMUM_BALANCE"
+	// Charged on transfers which convert between currencies
+	FeeTypeFX FeeType = "FX"
+)
+
+// feeTypes are the types of fee in the order they are charged
+var feeTypes = []FeeType{FeeTypeCrossOwner, FeeTypeBelowMinimumBalance, FeeTypeFX}
+
+// Fee is the price of a kind of transfer, as a flat amount plus a share of the amount transferred, both in minor units
+// of the source account's currency
+type Fee struct {
+	Flat int `json:"flat,omitempty" validate:"gte=0"`
+	// Share of the amount transferred in hundredths of a percent, which is rounded half to even to a minor unit
+	BasisPoints int `json:"basisPoints,omitempty" validate:"gte=0,lte=10000"`
+}
+
+// FeeRules set the fees charged on transfers out of accounts of a product. Each fee which applies to a transfer is
+// charged, and the zero value charges nothing.
+type FeeRules struct {
+	CrossOwner *Fee `json:"crossOwner,omitempty"`
+	// Charged when the amount transferred takes the balance below MinimumBalance
+	BelowMinimumBalance *Fee `json:"belowMinimumBalance,omitempty"`
+	MinimumBalance      int  `json:"minimumBalance,omitempty"`
+	FX                  *Fee `json:"fx,omitempty"`
+}
+
+// FeeCharge is a fee charged on a transaction, in minor units of the source account's currency
+type FeeCharge struct {
+	Type   FeeType `json:"type"`
+	Amount int     `json:"amount"`
+}
+
+// amount returns the fee on a transfer of amount
+func (fee Fee) amount(amount int) int {
+	share := new(big.Rat).SetFrac64(int64(amount)*int64(fee.BasisPoints), 10000)
+	return fee.Flat + int(roundHalfEven(share).Int64())
+}
+
+// fee returns the fee of feeType, which is nil if the product doesn't charge it
+func (rules FeeRules) fee(feeType FeeType) *Fee {
+	switch feeType {
+	case FeeTypeCrossOwner:
+		return rules.CrossOwner
+	case FeeTypeBelowMinimumBalance:
+		return rules.BelowMinimumBalance
+	case FeeTypeFX:
+		return rules.FX
+	}
+	return nil
+}
+
+// charges returns the fees charged on a transfer out of an account of srcAccountID with srcBalance, which converts
+// between currencies if converted
+func (rules FeeRules) charges(srcAccountID string, srcBalance int, transferInput TransferInput, converted bool) []FeeCharge {
+	amount := *transferInput.Amount
+	applies := map[FeeType]bool{
+		FeeTypeCrossOwner:          transferInput.DestAccountID != srcAccountID,
+		FeeTypeBelowMinimumBalance: srcBalance-amount < rules.MinimumBalance,
+		FeeTypeFX:                  converted,
+	}
+
+	var charges []FeeCharge
+	for _, feeType := range feeTypes {
+		fee := rules.fee(feeType)
+		if fee == nil || !applies[feeType] {
+			continue
+		}
+		if feeAmount := fee.amount(amount); feeAmount > 0 {
+			charges = append(charges, FeeCharge{Type: feeType, Amount: feeAmount})
+		}
+	}
+	return charges
+}
+
+// totalFees returns the sum of fees
+func totalFees(fees []FeeCharge) int {
+	total := 0
+	for _, fee := range fees {
+		total += fee.Amount
+	}
+	return total
+}
+
+// chargeFees records that the source of a transfer was also debited with fees, which are posted to the fee income
+// account
+func (tx *Transaction) chargeFees(fees []FeeCharge) {
+	tx.Fees = fees
+	tx.Postings = journalEntry(tx)
+}
+
+// QuoteTransferOutput is what a transfer would move and charge if it were made now
+type QuoteTransferOutput struct {
+	// Amount debited from the source account before fees, in minor units of Currency
+	Amount   int    `json:"amount"`
+	Currency string `json:"currency"`
+	// Amount credited to the destination account, in minor units of DestCurrency
+	DestAmount   int         `json:"destAmount"`
+	DestCurrency string      `json:"destCurrency"`
+	Fees         []FeeCharge `json:"fees"`
+	TotalFees    int         `json:"totalFees"`
+	// Amount debited from the source account including fees
+	TotalDebit int `json:"totalDebit"`
+}
+
+func newQuoteTransferOutput(tx Transaction) QuoteTransferOutput {
+	output := QuoteTransferOutput{
+		Amount:       tx.Amount,
+		Currency:     tx.Currency,
+		DestAmount:   tx.Amount,
+		DestCurrency: tx.Dest.Currency,
+		Fees:         tx.Fees,
+		TotalFees:    totalFees(tx.Fees),
+	}
+	if tx.DestAmount != 0 {
+		output.DestAmount = tx.DestAmount
+	}
+	if output.Fees == nil {
+		output.Fees = []FeeCharge{}
+	}
+	output.TotalDebit = output.Amount + output.TotalFees
+	return output
+}
+
+// QuoteTransfer makes the same checks as a transfer and returns the amounts it would move and the fees it would
+// charge, without transferring. A quote given by ID is checked but not used up.
+func (manager accountManagerImpl) QuoteTransfer(ctx context.Context, srcAccountID string, transferInput TransferInput) (QuoteTransferOutput, error) {
+	plan, err := manager.planTransfer(ctx, srcAccountID, transferInput)
+	if err != nil {
+		return QuoteTransferOutput{}, err
+	}
+	return newQuoteTransferOutput(plan.tx), nil
+}
+
+func (rules FeeRules) toAttributeValue() types.AttributeValue {
+	fees := make(map[string]types.AttributeValue)
+	for _, feeType := range feeTypes {
+		if fee := rules.fee(feeType); fee != nil {
+			fees[string(feeType)] = &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+				flatAttr:        &types.AttributeValueMemberN{Value: strconv.Itoa(fee.Flat)},
+				basisPointsAttr: &types.AttributeValueMemberN{Value: strconv.Itoa(fee.BasisPoints)},
+			}}
+		}
+	}
+	if rules.MinimumBalance != 0 {
+		fees[minimumBalanceAttr] = &types.AttributeValueMemberN{Value: strconv.Itoa(rules.MinimumBalance)}
+	}
+	return &types.AttributeValueMemberM{Value: fees}
+}
+
+func feeRulesFromAttributeValue(attrValue types.AttributeValue) (FeeRules, error) {
+	fees, ok := attrValue.(*types.AttributeValueMemberM)
+	if !ok {
+		return FeeRules{}, errors.New("fees must be a map")
+	}
+
+	var rules FeeRules
+	if _, ok := fees.Value[minimumBalanceAttr]; ok {
+		minimumBalance, err := numberFromItem(fees.Value, minimumBalanceAttr)
+		if err != nil {
+			return FeeRules{}, err
+		}
+		rules.MinimumBalance = minimumBalance
+	}
+	for _, feeType := range feeTypes {
+		item, ok := fees.Value[string(feeType)].(*types.AttributeValueMemberM)
+		if !ok {
+			continue
+		}
+		flat, err := numberFromItem(item.Value, flatAttr)
+		if err != nil {
+			return FeeRules{}, err
+		}
+		basisPoints, err := numberFromItem(item.Value, basisPointsAttr)
+		if err != nil {
+			return FeeRules{}, err
+		}
+		fee := &Fee{Flat: flat, BasisPoints: basisPoints}
+		switch feeType {
+		case FeeTypeCrossOwner:
+			rules.CrossOwner = fee
+		case FeeTypeBelowMinimumBalance:
+			rules.BelowMinimumBalance = fee
+		case FeeTypeFX:
+			rules.FX = fee
+		}
+	}
+	return rules, nil
+}
+
+func feesToAttributeValue(fees []FeeCharge) types.AttributeValue {
+	list := make([]types.AttributeValue, len(fees))
+	for i, fee := range fees {
+		list[i] = &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			feeTypeAttr: &types.AttributeValueMemberS{Value: string(fee.Type)},
+			amountAttr:  &types.AttributeValueMemberN{Value: strconv.Itoa(fee.Amount)},
+		}}
+	}
+	return &types.AttributeValueMemberL{Value: list}
+}
+
+func feesFromAttributeValue(attrValue types.AttributeValue) ([]FeeCharge, error) {
+	list, ok := attrValue.(*types.AttributeValueMemberL)
+	if !ok {
+		return nil, errors.New("fees must be a list")
+	}
+
+	var fees []FeeCharge
+	for _, element := range list.Value {
+		item, ok := element.(*types.AttributeValueMemberM)
+		if !ok {
+			return nil, errors.New("fee must be a map")
+		}
+		feeType, ok := item.Value[feeTypeAttr].(*types.AttributeValueMemberS)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string", feeTypeAttr)
+		}
+		amount, err := numberFromItem(item.Value, amountAttr)
+		if err != nil {
+			return nil, err
+		}
+		fees = append(fees, FeeCharge{Type: FeeType(feeType.Value), Amount: amount})
+	}
+	return fees, nil
+}
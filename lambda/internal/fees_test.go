@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFee_Amount(t *testing.T) {
+	tests := []struct {
+		name     string
+		fee      Fee
+		amount   int
+		expected int
+	}{
+		{name: "flat", fee: Fee{Flat: 25}, amount: 1000, expected: 25},
+		{name: "basis points", fee: Fee{BasisPoints: 150}, amount: 1000, expected: 15},
+		{name: "flat and basis points", fee: Fee{Flat: 25, BasisPoints: 150}, amount: 1000, expected: 40},
+		{name: "rounds half down to even", fee: Fee{BasisPoints: 50}, amount: 100, expected: 0},
+		{name: "rounds half up to even", fee: Fee{BasisPoints: 150}, amount: 100, expected: 2},
+		{name: "whole amount", fee: Fee{BasisPoints: 10000}, amount: 7, expected: 7},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.fee.amount(test.amount))
+		})
+	}
+}
+
+func TestFeeRules_Charges(t *testing.T) {
+	rules := FeeRules{
+		CrossOwner:          &Fee{Flat: 25},
+		BelowMinimumBalance: &Fee{Flat: 100},
+		MinimumBalance:      500,
+		FX:                  &Fee{BasisPoints: 100},
+	}
+	tests := []struct {
+		name          string
+		rules         FeeRules
+		destAccountID string
+		srcBalance    int
+		converted     bool
+		expected      []FeeCharge
+	}{
+		{
+			name:          "no fees apply",
+			rules:         rules,
+			destAccountID: "123456789",
+			srcBalance:    1500,
+		},
+		{
+			name:          "cross owner",
+			rules:         rules,
+			destAccountID: "987654321",
+			srcBalance:    1500,
+			expected:      []FeeCharge{{Type: FeeTypeCrossOwner, Amount: 25}},
+		},
+		{
+			name:          "leaves exactly the minimum balance",
+			rules:         rules,
+			destAccountID: "123456789",
+			srcBalance:    1500,
+		},
+		{
+			name:          "below minimum balance",
+			rules:         rules,
+			destAccountID: "123456789",
+			srcBalance:    1499,
+			expected:      []FeeCharge{{Type: FeeTypeBelowMinimumBalance, Amount: 100}},
+		},
+		{
+			name:          "every fee",
+			rules:         rules,
+			destAccountID: "987654321",
+			srcBalance:    1000,
+			converted:     true,
+			expected: []FeeCharge{
+				{Type: FeeTypeCrossOwner, Amount: 25},
+				{Type: FeeTypeBelowMinimumBalance, Amount: 100},
+				{Type: FeeTypeFX, Amount: 10},
+			},
+		},
+		{
+			name:          "no rules",
+			destAccountID: "987654321",
+			srcBalance:    0,
+			converted:     true,
+		},
+		{
+			name:          "zero fee is not charged",
+			rules:         FeeRules{CrossOwner: &Fee{BasisPoints: 1}},
+			destAccountID: "987654321",
+			srcBalance:    1500,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// === Given ===
+			amount := 1000
+			transferInput := TransferInput{
+				SrcAccountType:  "savings",
+				DestAccountID:   test.destAccountID,
+				DestAccountType: "checking",
+				Amount:          &amount,
+			}
+
+			// === When ===
+			charges := test.rules.charges("123456789", test.srcBalance, transferInput, test.converted)
+
+			// === Then ===
+			assert.Equal(t, test.expected, charges)
+		})
+	}
+}
@@ -0,0 +1,402 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ratesTableName  = "fx-rates-table"
+	quotesTableName = "fx-quotes-table"
+
+	pairAttr     = "Pair"
+	rateAttr     = "Rate"
+	quoteIDAttr  = "QuoteId"
+	roundingAttr = "Rounding"
+
+	// How long the rate of a quote is locked for
+	quoteValidity = 30 * time.Second
+	// Number of decimal places rates are quoted to
+	rateScale = 10
+)
+
+// RateProvider looks up the exchange rate between two currencies, i.e. the units of to that one unit of from buys
+type RateProvider interface {
+	GetRate(ctx context.Context, from, to string) (*big.Rat, error)
+}
+
+// NewStaticRateProvider returns a RateProvider of the given rates, keyed by currency pairs of the form "USD/EUR". The
+// rate of a pair which is missing is the inverse of the reverse pair, if it is present.
+func NewStaticRateProvider(rates map[string]*big.Rat) RateProvider {
+	return staticRateProvider(rates)
+}
+
+// ParseStaticRateProvider parses a RateProvider from JSON of the form
+//
+//	{"USD/EUR": "0.9214", "USD/JPY": "148.25"}
+//
+// Rates are decimal strings, so that they are exact.
+func ParseStaticRateProvider(config []byte) (RateProvider, error) {
+	var decimals map[string]string
+	err := json.Unmarshal(config, &decimals)
+	if err != nil {
+		return nil, fmt.Errorf("parsing rates: %w", err)
+	}
+
+	rates := make(map[string]*big.Rat)
+	for pair, decimal := range decimals {
+		rate, err := parseRate(decimal)
+		if err != nil {
+			return nil, fmt.Errorf("parsing rate of %s: %w", pair, err)
+		}
+		rates[pair] = rate
+	}
+	return NewStaticRateProvider(rates), nil
+}
+
+type staticRateProvider map[string]*big.Rat
+
+func (provider staticRateProvider) GetRate(_ context.Context, from, to string) (*big.Rat, error) {
+	if rate, ok := provider[currencyPair(from, to)]; ok {
+		return rate, nil
+	}
+	if rate, ok := provider[currencyPair(to, from)]; ok {
+		return new(big.Rat).Inv(rate), nil
+	}
+	return nil, RateNotAvailableError{From: from, To: to}
+}
+
+// NewDynamoDBRateProvider returns a RateProvider backed by the rates table, which has an item of the form
+// {Pair: S, Rate: S} for each currency pair, e.g. {Pair: "USD/EUR", Rate: "0.9214"}. Unlike the static provider, the
+// reverse pair is not consulted, so that each direction can be priced independently.
+func NewDynamoDBRateProvider(ddb *dynamodb.Client) RateProvider {
+	return dynamoDBRateProvider{ddb: ddb}
+}
+
+type dynamoDBRateProvider struct {
+	ddb *dynamodb.Client
+}
+
+func (provider dynamoDBRateProvider) GetRate(ctx context.Context, from, to string) (*big.Rat, error) {
+	output, err := provider.ddb.GetItem(ctx, &dynamodb.GetItemInput{
+		Key: map[string]types.AttributeValue{
+			pairAttr: &types.AttributeValueMemberS{Value: currencyPair(from, to)},
+		},
+		TableName:            aws.String(ratesTableName),
+		ProjectionExpression: aws.String(rateAttr),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	attrValue, ok := output.Item[rateAttr].(*types.AttributeValueMemberS)
+	if !ok {
+		return nil, RateNotAvailableError{From: from, To: to}
+	}
+	return parseRate(attrValue.Value)
+}
+
+func currencyPair(from, to string) string {
+	return from + "/" + to
+}
+
+func parseRate(decimal string) (*big.Rat, error) {
+	rate, ok := new(big.Rat).SetString(decimal)
+	if !ok || rate.Sign() <= 0 {
+		return nil, fmt.Errorf("rate must be a positive decimal, got %q", decimal)
+	}
+	return rate, nil
+}
+
+// convert converts an amount in minor units of one currency to minor units of another at rate. The result is rounded
+// half to even, and rounding is the difference between the result and the exact conversion, in minor units of to.
+func convert(amount int, from, to string, rate *big.Rat) (converted int, rounding *big.Rat) {
+	exact := new(big.Rat).Mul(big.NewRat(int64(amount), 1), rate)
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(CurrencyExponent(to)-CurrencyExponent(from)))), nil))
+	if CurrencyExponent(to) >= CurrencyExponent(from) {
+		exact.Mul(exact, scale)
+	} else {
+		exact.Quo(exact, scale)
+	}
+
+	// Amounts and rates are positive
+	quotient := roundHalfEven(exact)
+	rounded := new(big.Rat).SetInt(quotient)
+	return int(quotient.Int64()), rounded.Sub(rounded, exact)
+}
+
+// roundHalfEven rounds a non-negative value to the nearest integer, and ties to the even integer
+func roundHalfEven(value *big.Rat) *big.Int {
+	// The value is non-negative, so the quotient is rounded down
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	switch new(big.Int).Lsh(remainder, 1).Cmp(value.Denom()) {
+	case 1:
+		quotient.Add(quotient, big.NewInt(1))
+	case 0:
+		if quotient.Bit(0) == 1 {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return quotient
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// formatDecimal formats a terminating decimal exactly, without trailing zeros
+func formatDecimal(value *big.Rat) string {
+	formatted := value.FloatString(rateScale * 2)
+	formatted = strings.TrimRight(formatted, "0")
+	return strings.TrimSuffix(formatted, ".")
+}
+
+// Quote locks the rate of a conversion between currencies until it expires. A transfer which refers to the quote by its
+// ID converts at the quoted rate, and uses up the quote.
+type Quote struct {
+	QuoteID      string `json:"quoteID"`
+	SrcCurrency  string `json:"srcCurrency"`
+	DestCurrency string `json:"destCurrency"`
+	// Amount to debit in minor units of SrcCurrency
+	Amount int `json:"amount"`
+	// Amount to credit in minor units of DestCurrency, which is Amount converted at Rate and rounded half to even
+	DestAmount int `json:"destAmount"`
+	// Units of DestCurrency that one unit of SrcCurrency buys, as a decimal
+	Rate string `json:"rate"`
+	// Difference between DestAmount and the exact conversion in minor units of DestCurrency, as a decimal
+	Rounding  string    `json:"rounding"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// newQuote quotes the conversion of amount at rate, which is rounded to rateScale decimal places so that the quoted
+// rate is exactly the one applied
+func newQuote(amount int, srcCurrency, destCurrency string, rate *big.Rat) (Quote, error) {
+	rate, _ = new(big.Rat).SetString(rate.FloatString(rateScale))
+	destAmount, rounding := convert(amount, srcCurrency, destCurrency, rate)
+	if destAmount <= 0 {
+		return Quote{}, ConversionTooSmallError{
+			Amount:       amount,
+			SrcCurrency:  srcCurrency,
+			DestCurrency: destCurrency,
+		}
+	}
+
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return Quote{
+		QuoteID:      hex.EncodeToString(id),
+		SrcCurrency:  srcCurrency,
+		DestCurrency: destCurrency,
+		Amount:       amount,
+		DestAmount:   destAmount,
+		Rate:         formatDecimal(rate),
+		Rounding:     formatDecimal(rounding),
+		// Expiry is stored to the second, like the TTL of the quotes table
+		ExpiresAt: time.Now().UTC().Add(quoteValidity).Truncate(time.Second),
+	}, nil
+}
+
+// check returns a QuoteMismatchError unless the quote is for a transfer of amount between the given currencies
+func (quote Quote) check(amount int, srcCurrency, destCurrency string) error {
+	if quote.Amount != amount || quote.SrcCurrency != srcCurrency || quote.DestCurrency != destCurrency {
+		return QuoteMismatchError{QuoteID: quote.QuoteID}
+	}
+	return nil
+}
+
+// conversion returns the record of the conversion made at the quote's rate
+func (quote Quote) conversion() *Conversion {
+	return &Conversion{
+		QuoteID:  quote.QuoteID,
+		Rate:     quote.Rate,
+		Rounding: quote.Rounding,
+	}
+}
+
+// Conversion records the rate that a transfer between currencies was converted at
+type Conversion struct {
+	QuoteID  string `json:"quoteID"`
+	Rate     string `json:"rate"`
+	Rounding string `json:"rounding"`
+}
+
+type QuoteInput struct {
+	SrcCurrency  string `json:"srcCurrency" validate:"required,currency"`
+	DestCurrency string `json:"destCurrency" validate:"required,currency"`
+	// Use pointer for Amount to ensure that it's explicitly defined
+	Amount *int `json:"amount" validate:"required,gt=0"`
+}
+
+type QuoteOutput struct {
+	Quote Quote `json:"quote"`
+}
+
+type RateNotAvailableError struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func (err RateNotAvailableError) Error() string {
+	return fmt.Sprintf("No exchange rate is available from %s to %s.", err.From, err.To)
+}
+
+// ConversionTooSmallError is returned for a quote of an amount which converts to less than one minor unit
+type ConversionTooSmallError struct {
+	Amount       int    `json:"amount"`
+	SrcCurrency  string `json:"srcCurrency"`
+	DestCurrency string `json:"destCurrency"`
+}
+
+func (err ConversionTooSmallError) Error() string {
+	return fmt.Sprintf("%s converts to less than the smallest unit of %s.", FormatAmount(err.Amount, err.SrcCurrency), err.DestCurrency)
+}
+
+// QuoteNotFoundError is returned for a quote which does not exist, has expired or has already been used
+type QuoteNotFoundError struct {
+	QuoteID string `json:"quoteID"`
+}
+
+func (err QuoteNotFoundError) Error() string {
+	return fmt.Sprintf("The quote %s does not exist, has expired or has already been used.", err.QuoteID)
+}
+
+// QuoteMismatchError is returned for a transfer whose amount or currencies differ from those of its quote
+type QuoteMismatchError struct {
+	QuoteID string `json:"quoteID"`
+}
+
+func (err QuoteMismatchError) Error() string {
+	return fmt.Sprintf("The transfer does not match the amount and currencies of the quote %s.", err.QuoteID)
+}
+
+func (manager accountManagerImpl) Quote(ctx context.Context, accountID string, quoteInput QuoteInput) (QuoteOutput, error) {
+	if quoteInput.SrcCurrency == quoteInput.DestCurrency {
+		return QuoteOutput{}, UnexpectedConversionError{Currency: quoteInput.SrcCurrency}
+	}
+
+	rate, err := manager.rates.GetRate(ctx, quoteInput.SrcCurrency, quoteInput.DestCurrency)
+	if err != nil {
+		return QuoteOutput{}, err
+	}
+	quote, err := newQuote(*quoteInput.Amount, quoteInput.SrcCurrency, quoteInput.DestCurrency, rate)
+	if err != nil {
+		return QuoteOutput{}, err
+	}
+
+	_, err = manager.ddb.PutItem(ctx, &dynamodb.PutItemInput{
+		Item:      quote.toItem(accountID),
+		TableName: aws.String(quotesTableName),
+	})
+	if err != nil {
+		return QuoteOutput{}, err
+	}
+
+	return QuoteOutput{
+		Quote: quote,
+	}, nil
+}
+
+func quoteKeyItem(accountID, quoteID string) map[string]types.AttributeValue {
+	item := make(map[string]types.AttributeValue)
+	item[accountIDAttr] = &types.AttributeValueMemberS{Value: accountID}
+	item[quoteIDAttr] = &types.AttributeValueMemberS{Value: quoteID}
+	return item
+}
+
+// toItem returns the item of a quote, which only the account it was quoted for can use
+func (quote Quote) toItem(accountID string) map[string]types.AttributeValue {
+	item := quoteKeyItem(accountID, quote.QuoteID)
+	item[srcCurrencyAttr] = &types.AttributeValueMemberS{Value: quote.SrcCurrency}
+	item[destCurrencyAttr] = &types.AttributeValueMemberS{Value: quote.DestCurrency}
+	item[amountAttr] = &types.AttributeValueMemberN{Value: strconv.Itoa(quote.Amount)}
+	item[destAmountAttr] = &types.AttributeValueMemberN{Value: strconv.Itoa(quote.DestAmount)}
+	item[rateAttr] = &types.AttributeValueMemberS{Value: quote.Rate}
+	item[roundingAttr] = &types.AttributeValueMemberS{Value: quote.Rounding}
+	item[expiresAtAttr] = &types.AttributeValueMemberN{Value: strconv.FormatInt(quote.ExpiresAt.Unix(), 10)}
+	return item
+}
+
+func quoteFromItem(item map[string]types.AttributeValue) (Quote, error) {
+	quoteID, ok := item[quoteIDAttr].(*types.AttributeValueMemberS)
+	if !ok {
+		return Quote{}, errors.New("quoteID must be a string")
+	}
+	amount, err := numberFromItem(item, amountAttr)
+	if err != nil {
+		return Quote{}, err
+	}
+	destAmount, err := numberFromItem(item, destAmountAttr)
+	if err != nil {
+		return Quote{}, err
+	}
+	expiresAt, err := numberFromItem(item, expiresAtAttr)
+	if err != nil {
+		return Quote{}, err
+	}
+
+	return Quote{
+		QuoteID:      quoteID.Value,
+		SrcCurrency:  stringFromItem(item, srcCurrencyAttr),
+		DestCurrency: stringFromItem(item, destCurrencyAttr),
+		Amount:       amount,
+		DestAmount:   destAmount,
+		Rate:         stringFromItem(item, rateAttr),
+		Rounding:     stringFromItem(item, roundingAttr),
+		ExpiresAt:    time.Unix(int64(expiresAt), 0).UTC(),
+	}, nil
+}
+
+// getQuote returns an unexpired quote of an account, or a QuoteNotFoundError
+func (manager accountManagerImpl) getQuote(ctx context.Context, accountID, quoteID string) (Quote, error) {
+	output, err := manager.ddb.GetItem(ctx, &dynamodb.GetItemInput{
+		Key:            quoteKeyItem(accountID, quoteID),
+		TableName:      aws.String(quotesTableName),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return Quote{}, err
+	}
+
+	// DynamoDB deletes expired items lazily, so an expired quote may still be present
+	if len(output.Item) == 0 {
+		return Quote{}, QuoteNotFoundError{QuoteID: quoteID}
+	}
+	quote, err := quoteFromItem(output.Item)
+	if err != nil {
+		return Quote{}, err
+	}
+	if !time.Now().Before(quote.ExpiresAt) {
+		return Quote{}, QuoteNotFoundError{QuoteID: quoteID}
+	}
+	return quote, nil
+}
+
+// quoteDelete uses up a quote. It is intended to be included in the same TransactWriteItems call that applies the
+// transfer, and fails its condition if the quote has already been used or has expired.
+func quoteDelete(accountID string, quote Quote, now time.Time) types.TransactWriteItem {
+	exprAttrValues := make(map[string]types.AttributeValue)
+	exprAttrValues[":now"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)}
+
+	return types.TransactWriteItem{
+		Delete: &types.Delete{
+			Key:                       quoteKeyItem(accountID, quote.QuoteID),
+			TableName:                 aws.String(quotesTableName),
+			ConditionExpression:       aws.String(fmt.Sprintf("attribute_exists(%s) AND %s > :now", quoteIDAttr, expiresAtAttr)),
+			ExpressionAttributeValues: exprAttrValues,
+		},
+	}
+}
@@ -0,0 +1,100 @@
+package internal
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name             string
+		amount           int
+		from, to         string
+		rate             string
+		expected         int
+		expectedRounding string
+	}{
+		{name: "rounds down", amount: 100, from: "USD", to: "EUR", rate: "0.92141", expected: 92, expectedRounding: "-0.141"},
+		{name: "rounds up", amount: 100, from: "USD", to: "EUR", rate: "0.9261", expected: 93, expectedRounding: "0.39"},
+		{name: "rounds half to even down", amount: 1, from: "USD", to: "EUR", rate: "2.5", expected: 2, expectedRounding: "-0.5"},
+		{name: "rounds half to even up", amount: 1, from: "USD", to: "EUR", rate: "3.5", expected: 4, expectedRounding: "0.5"},
+		{name: "to fewer decimal places", amount: 333, from: "USD", to: "JPY", rate: "148.25", expected: 494, expectedRounding: "0.3275"},
+		{name: "to more decimal places", amount: 100, from: "JPY", to: "KWD", rate: "0.00206", expected: 206, expectedRounding: "0"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// === Given ===
+			rate, err := parseRate(test.rate)
+			require.NoError(t, err)
+
+			// === When ===
+			converted, rounding := convert(test.amount, test.from, test.to, rate)
+
+			// === Then ===
+			assert.Equal(t, test.expected, converted)
+			assert.Equal(t, test.expectedRounding, formatDecimal(rounding))
+		})
+	}
+}
+
+func TestNewQuote_RoundsRateToScale(t *testing.T) {
+	// === When ===
+	quote, err := newQuote(300, "EUR", "USD", big.NewRat(1, 3))
+
+	// === Then ===
+	assert.NoError(t, err)
+	assert.Equal(t, "0.3333333333", quote.Rate)
+	assert.Equal(t, 100, quote.DestAmount)
+	assert.Equal(t, "0.00000001", quote.Rounding)
+}
+
+func TestNewQuote_ErrorWhenConversionIsTooSmall(t *testing.T) {
+	// === When ===
+	_, err := newQuote(1, "JPY", "USD", big.NewRat(1, 300))
+
+	// === Then ===
+	assert.Equal(t, ConversionTooSmallError{Amount: 1, SrcCurrency: "JPY", DestCurrency: "USD"}, err)
+}
+
+func TestParseStaticRateProvider(t *testing.T) {
+	// === Given ===
+	provider, err := ParseStaticRateProvider([]byte(`{"USD/EUR": "0.8"}`))
+	require.NoError(t, err)
+
+	// === When ===
+	direct, directErr := provider.GetRate(context.Background(), "USD", "EUR")
+	inverse, inverseErr := provider.GetRate(context.Background(), "EUR", "USD")
+	_, missingErr := provider.GetRate(context.Background(), "USD", "JPY")
+
+	// === Then ===
+	assert.NoError(t, directErr)
+	assert.Equal(t, big.NewRat(4, 5), direct)
+	assert.NoError(t, inverseErr)
+	assert.Equal(t, big.NewRat(5, 4), inverse)
+	assert.Equal(t, RateNotAvailableError{From: "USD", To: "JPY"}, missingErr)
+}
+
+func TestParseStaticRateProvider_ErrorWhenRateIsInvalid(t *testing.T) {
+	_, err := ParseStaticRateProvider([]byte(`{"USD/EUR": "-1"}`))
+	assert.Error(t, err)
+
+	_, err = ParseStaticRateProvider([]byte(`{"USD/EUR": "abc"}`))
+	assert.Error(t, err)
+}
+
+func TestQuote_ItemRoundTrip(t *testing.T) {
+	// === Given ===
+	quote, err := newQuote(333, "USD", "JPY", big.NewRat(14825, 100))
+	require.NoError(t, err)
+
+	// === When ===
+	parsed, err := quoteFromItem(quote.toItem("123456789"))
+
+	// === Then ===
+	assert.NoError(t, err)
+	assert.Equal(t, quote, parsed)
+}
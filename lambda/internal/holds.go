@@ -0,0 +1,479 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"strconv"
+	"time"
+)
+
+const (
+	// Attributes of an account item holding its holds by ID, and a counter of changes to them which writes that depend
+	// on the holds are conditioned on. Accounts which have never had a hold have neither.
+	holdsAttr        = "Holds"
+	holdsVersionAttr = "HoldsVersion"
+	holdIDAttr       = "HoldId"
+
+	// How long a hold lasts when it is placed without a validity
+	defaultHoldValidity = 7 * 24 * time.Hour
+)
+
+// Hold reserves funds of an account for a payment to another account, e.g. a card authorization, which is settled
+// later by capturing it. A hold reduces the available balance of the account but not its ledger balance, until it is
+// captured, released or expires.
+type Hold struct {
+	HoldID string `json:"holdID"`
+	// Amount still held in minor units of Currency, which partial captures reduce
+	Amount int `json:"amount"`
+	// Currency of the account, and of its destination
+	Currency        string `json:"currency"`
+	DestAccountID   string `json:"destAccountID"`
+	DestAccountType string `json:"destAccountType"`
+	// Identifies the payment outside the ledger that the hold is for, and is recorded on its captures
+	ExternalReference string    `json:"externalReference"`
+	ExpiresAt         time.Time `json:"expiresAt"`
+}
+
+func newHold(placeHoldInput PlaceHoldInput, currency string) Hold {
+	validity := defaultHoldValidity
+	if placeHoldInput.ValiditySeconds != 0 {
+		validity = time.Duration(placeHoldInput.ValiditySeconds) * time.Second
+	}
+
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return Hold{
+		HoldID:            hex.EncodeToString(id),
+		Amount:            *placeHoldInput.Amount,
+		Currency:          currency,
+		DestAccountID:     placeHoldInput.DestAccountID,
+		DestAccountType:   placeHoldInput.DestAccountType,
+		ExternalReference: placeHoldInput.ExternalReference,
+		// Expiry is stored to the second, like that of quotes
+		ExpiresAt: time.Now().UTC().Add(validity).Truncate(time.Second),
+	}
+}
+
+func (hold Hold) destKey() AccountKey {
+	return AccountKey{
+		AccountID:   hold.DestAccountID,
+		AccountType: hold.DestAccountType,
+	}
+}
+
+func (hold Hold) expired(now time.Time) bool {
+	return !now.Before(hold.ExpiresAt)
+}
+
+// PlaceHoldInput reserves funds of an account for a later capture. Holds are placed by operators on behalf of the
+// account's owner, so the account ID is part of the input.
+type PlaceHoldInput struct {
+	AccountID       string `json:"accountID" validate:"required"`
+	AccountType     string `json:"accountType" validate:"required"`
+	DestAccountID   string `json:"destAccountID" validate:"required"`
+	DestAccountType string `json:"destAccountType" validate:"required"`
+	// Use pointer for Amount to ensure that it's explicitly defined
+	Amount *int `json:"amount" validate:"required,gt=0"`
+	// Currency of Amount, which is checked against the account if defined
+	Currency string `json:"currency,omitempty" validate:"omitempty,currency"`
+	// Identifies the payment outside the ledger that the hold is for, e.g. the ID of a card authorization
+	ExternalReference string `json:"externalReference" validate:"required,max=255"`
+	// How long the hold lasts unless it is captured or released, which defaults to 7 days and may be at most 30
+	ValiditySeconds int `json:"validitySeconds,omitempty" validate:"gte=0,lte=2592000"`
+}
+
+type PlaceHoldOutput struct {
+	Hold Hold `json:"hold"`
+}
+
+// CaptureHoldInput transfers funds reserved by a hold to its destination account
+type CaptureHoldInput struct {
+	AccountID   string `json:"accountID" validate:"required"`
+	AccountType string `json:"accountType" validate:"required"`
+	HoldID      string `json:"holdID" validate:"required,max=64"`
+	// Amount to capture, which defaults to the whole amount still held. The rest of a partial capture remains held.
+	Amount *int `json:"amount,omitempty" validate:"omitempty,gt=0"`
+}
+
+type CaptureHoldOutput struct {
+	Transaction Transaction `json:"transaction"`
+	// The hold after the capture, whose Amount is zero once it has been captured in full
+	Hold Hold `json:"hold"`
+}
+
+// ReleaseHoldInput releases the funds reserved by a hold without capturing them
+type ReleaseHoldInput struct {
+	AccountID   string `json:"accountID" validate:"required"`
+	AccountType string `json:"accountType" validate:"required"`
+	HoldID      string `json:"holdID" validate:"required,max=64"`
+}
+
+// HoldNotFoundError is returned for a hold which does not exist, has expired or has already been captured in full or
+// released
+type HoldNotFoundError struct {
+	AccountID   string `json:"accountID"`
+	AccountType string `json:"accountType"`
+	HoldID      string `json:"holdID"`
+}
+
+func (err HoldNotFoundError) Error() string {
+	return fmt.Sprintf("The hold %s on %s:%s does not exist, has expired or has already been captured or released.",
+		err.HoldID, err.AccountID, err.AccountType)
+}
+
+// CaptureExceedsHoldError is returned for a capture of more than the amount still held
+type CaptureExceedsHoldError struct {
+	HoldID string `json:"holdID"`
+	// Amount still held, which is the most that may be captured
+	HeldAmount int `json:"heldAmount"`
+}
+
+func (err CaptureExceedsHoldError) Error() string {
+	return fmt.Sprintf("The capture exceeds the %d still held by the hold %s.", err.HeldAmount, err.HoldID)
+}
+
+// held returns the total amount of the unexpired holds of the account
+func (account account) held() int {
+	now := time.Now()
+	held := 0
+	for _, hold := range account.holds {
+		if !hold.expired(now) {
+			held += hold.Amount
+		}
+	}
+	return held
+}
+
+// hold returns an unexpired hold of the account, or a HoldNotFoundError
+func (account account) hold(key AccountKey, holdID string) (Hold, error) {
+	hold, ok := account.holds[holdID]
+	if !ok || hold.expired(time.Now()) {
+		return Hold{}, HoldNotFoundError{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
+			HoldID:      holdID,
+		}
+	}
+	return hold, nil
+}
+
+// activeHolds returns a copy of the unexpired holds of the account, which is how expired holds are removed when the
+// holds are next written
+func (account account) activeHolds() map[string]Hold {
+	now := time.Now()
+	holds := make(map[string]Hold)
+	for id, hold := range account.holds {
+		if !hold.expired(now) {
+			holds[id] = hold
+		}
+	}
+	return holds
+}
+
+// placeHold checks that a hold may be placed on the account for a payment to dest, and returns it
+func (account account) placeHold(key AccountKey, dest account, placeHoldInput PlaceHoldInput) (Hold, error) {
+	if err := account.checkCurrency(key, placeHoldInput.Currency); err != nil {
+		return Hold{}, err
+	}
+	if err := account.checkFunds(key, *placeHoldInput.Amount); err != nil {
+		return Hold{}, err
+	}
+	// Captures are not converted, so the destination must hold the same currency
+	if account.currency != dest.currency {
+		return Hold{}, ConversionRequiredError{
+			SrcCurrency:  account.currency,
+			DestCurrency: dest.currency,
+		}
+	}
+	return newHold(placeHoldInput, account.currency), nil
+}
+
+// checkHoldStatuses checks that the statuses of the account and dest allow a hold between them to be placed or
+// captured. Either may have changed status since the hold was placed, so captures are checked again.
+func checkHoldStatuses(key AccountKey, account account, destKey AccountKey, dest account) error {
+	if err := account.checkDebit(key); err != nil {
+		return err
+	}
+	return dest.checkCredit(destKey)
+}
+
+// capture returns the amount of a hold to capture and the hold after capturing it, or a CaptureExceedsHoldError
+func (hold Hold) capture(amount *int) (int, Hold, error) {
+	if amount == nil {
+		amount = &hold.Amount
+	}
+	if *amount > hold.Amount {
+		return 0, Hold{}, CaptureExceedsHoldError{
+			HoldID:     hold.HoldID,
+			HeldAmount: hold.Amount,
+		}
+	}
+	captured := *amount
+	hold.Amount -= captured
+	return captured, hold, nil
+}
+
+// withHold returns holds with hold added or replaced, or removed once nothing remains held
+func withHold(holds map[string]Hold, hold Hold) map[string]Hold {
+	if hold.Amount == 0 {
+		delete(holds, hold.HoldID)
+	} else {
+		holds[hold.HoldID] = hold
+	}
+	return holds
+}
+
+func (manager accountManagerImpl) PlaceHold(ctx context.Context, placeHoldInput PlaceHoldInput) (PlaceHoldOutput, error) {
+	key := AccountKey{
+		AccountID:   placeHoldInput.AccountID,
+		AccountType: placeHoldInput.AccountType,
+	}
+	destKey := AccountKey{
+		AccountID:   placeHoldInput.DestAccountID,
+		AccountType: placeHoldInput.DestAccountType,
+	}
+	if key == destKey {
+		return PlaceHoldOutput{}, fmt.Errorf("cannot place a hold on %s:%s for itself", key.AccountID, key.AccountType)
+	}
+
+	var output PlaceHoldOutput
+	err := retryOnConflict(ctx, transferRetryPolicy, "PlaceHold", func() error {
+		account, err := manager.getAccount(ctx, key)
+		if err != nil {
+			return err
+		}
+		dest, err := manager.getAccount(ctx, destKey)
+		if err != nil {
+			return err
+		}
+		if err := checkHoldStatuses(key, account, destKey, dest); err != nil {
+			return err
+		}
+		hold, err := account.placeHold(key, dest, placeHoldInput)
+		if err != nil {
+			return err
+		}
+
+		holds := withHold(account.activeHolds(), hold)
+		err = manager.writeHolds(ctx, []types.TransactWriteItem{holdsUpdate(key, account, account.balance, holds)}, key, AccountKey{}, hold.HoldID)
+		output.Hold = hold
+		return err
+	})
+	return output, err
+}
+
+func (manager accountManagerImpl) CaptureHold(ctx context.Context, captureHoldInput CaptureHoldInput) (CaptureHoldOutput, error) {
+	key := AccountKey{
+		AccountID:   captureHoldInput.AccountID,
+		AccountType: captureHoldInput.AccountType,
+	}
+
+	var output CaptureHoldOutput
+	err := retryOnConflict(ctx, transferRetryPolicy, "CaptureHold", func() error {
+		account, err := manager.getAccount(ctx, key)
+		if err != nil {
+			return err
+		}
+		hold, err := account.hold(key, captureHoldInput.HoldID)
+		if err != nil {
+			return err
+		}
+		amount, remaining, err := hold.capture(captureHoldInput.Amount)
+		if err != nil {
+			return err
+		}
+		dest, err := manager.getAccount(ctx, hold.destKey())
+		if err != nil {
+			return err
+		}
+		if err := checkHoldStatuses(key, account, hold.destKey(), dest); err != nil {
+			return err
+		}
+
+		// The captured funds were already reserved, so the capture is not checked against the available balance
+		tx := newCaptureTransaction(key, account, hold, dest, amount)
+		transactItems := []types.TransactWriteItem{
+			holdsUpdate(key, account, account.balance-amount, withHold(account.activeHolds(), remaining)),
+			balanceUpdate(hold.destKey(), dest, dest.balance+amount),
+		}
+		err = manager.writeHolds(ctx, append(transactItems, tx.toTransactWriteItems()...), key, hold.destKey(), hold.HoldID)
+		output = CaptureHoldOutput{
+			Transaction: tx,
+			Hold:        remaining,
+		}
+		return err
+	})
+	return output, err
+}
+
+func (manager accountManagerImpl) ReleaseHold(ctx context.Context, releaseHoldInput ReleaseHoldInput) error {
+	key := AccountKey{
+		AccountID:   releaseHoldInput.AccountID,
+		AccountType: releaseHoldInput.AccountType,
+	}
+
+	return retryOnConflict(ctx, transferRetryPolicy, "ReleaseHold", func() error {
+		account, err := manager.getAccount(ctx, key)
+		if err != nil {
+			return err
+		}
+		if _, err := account.hold(key, releaseHoldInput.HoldID); err != nil {
+			return err
+		}
+
+		holds := account.activeHolds()
+		delete(holds, releaseHoldInput.HoldID)
+		return manager.writeHolds(ctx, []types.TransactWriteItem{holdsUpdate(key, account, account.balance, holds)}, key, AccountKey{}, releaseHoldInput.HoldID)
+	})
+}
+
+// newCaptureTransaction returns the transaction which moves amount of a hold from the account to its destination
+func newCaptureTransaction(key AccountKey, account account, hold Hold, dest account, amount int) Transaction {
+	tx := newTransaction(TransactionTypeCapture, amount, &TransactionParty{
+		AccountID:   key.AccountID,
+		AccountType: key.AccountType,
+		Currency:    account.currency,
+		Balance:     account.balance - amount,
+	}, &TransactionParty{
+		AccountID:   hold.DestAccountID,
+		AccountType: hold.DestAccountType,
+		Currency:    dest.currency,
+		Balance:     dest.balance + amount,
+	})
+	tx.HoldID = hold.HoldID
+	tx.ExternalReference = hold.ExternalReference
+	return tx
+}
+
+// writeHolds makes a single attempt at writing transactItems, whose first items are the updates of the account and, for
+// captures, the destination. Like transfer, it returns a TransactionConflictError if it raced with another transaction.
+func (manager accountManagerImpl) writeHolds(ctx context.Context, transactItems []types.TransactWriteItem, key, destKey AccountKey, holdID string) error {
+	_, err := manager.ddb.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: transactItems,
+	})
+	if err != nil {
+		var transactionCanceledException *types.TransactionCanceledException
+		if errors.As(err, &transactionCanceledException) &&
+			(isConditionalCheckFailed(transactionCanceledException, 0) ||
+				(destKey != AccountKey{} && isConditionalCheckFailed(transactionCanceledException, 1)) ||
+				isTransactionConflict(transactionCanceledException)) {
+			return TransactionConflictError{
+				Src:    key,
+				Dest:   destKey,
+				HoldID: holdID,
+				Err:    err,
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// holdsUpdate sets the balance and holds of an account, conditioned on neither having changed since they were read
+// and, when more is held than before, on the overdraft limit covering the funds which are no longer available. Placing
+// and capturing holds is also conditioned on the account still being active, while releasing them is always allowed.
+func holdsUpdate(key AccountKey, account account, newBalance int, holds map[string]Hold) types.TransactWriteItem {
+	exprAttrValues := make(map[string]types.AttributeValue)
+	exprAttrValues[":old"] = &types.AttributeValueMemberN{Value: strconv.Itoa(account.balance)}
+	exprAttrValues[":new"] = &types.AttributeValueMemberN{Value: strconv.Itoa(newBalance)}
+	exprAttrValues[":holds"] = holdsToAttributeValue(holds)
+	exprAttrValues[":version"] = &types.AttributeValueMemberN{Value: strconv.Itoa(account.holdsVersion + 1)}
+	condition := fmt.Sprintf("%s = :old", balanceAttr) + holdsVersionCondition(account.holdsVersion, exprAttrValues)
+	held := totalHeld(holds)
+	if held > account.held() || newBalance < account.balance {
+		condition += statusCondition(true, exprAttrValues)
+	}
+	if held > account.held() {
+		condition += overdraftCondition(newBalance-held, exprAttrValues)
+	}
+
+	return types.TransactWriteItem{
+		Update: &types.Update{
+			Key:                       key.toAccountItem(),
+			TableName:                 aws.String(tableName),
+			UpdateExpression:          aws.String(fmt.Sprintf("SET %s = :new, %s = :holds, %s = :version", balanceAttr, holdsAttr, holdsVersionAttr)),
+			ConditionExpression:       aws.String(condition),
+			ExpressionAttributeValues: exprAttrValues,
+		},
+	}
+}
+
+// holdsVersionCondition returns the condition that the holds of an account are unchanged since they were read at
+// version, which is zero for accounts which have never had a hold
+func holdsVersionCondition(version int, exprAttrValues map[string]types.AttributeValue) string {
+	if version == 0 {
+		return fmt.Sprintf(" AND attribute_not_exists(%s)", holdsVersionAttr)
+	}
+	exprAttrValues[":holdsVersion"] = &types.AttributeValueMemberN{Value: strconv.Itoa(version)}
+	return fmt.Sprintf(" AND %s = :holdsVersion", holdsVersionAttr)
+}
+
+func totalHeld(holds map[string]Hold) int {
+	held := 0
+	for _, hold := range holds {
+		held += hold.Amount
+	}
+	return held
+}
+
+func holdsToAttributeValue(holds map[string]Hold) types.AttributeValue {
+	value := make(map[string]types.AttributeValue)
+	for id, hold := range holds {
+		value[id] = &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			amountAttr:          &types.AttributeValueMemberN{Value: strconv.Itoa(hold.Amount)},
+			currencyAttr:        &types.AttributeValueMemberS{Value: hold.Currency},
+			destAccountIDAttr:   &types.AttributeValueMemberS{Value: hold.DestAccountID},
+			destAccountTypeAttr: &types.AttributeValueMemberS{Value: hold.DestAccountType},
+			externalRefAttr:     &types.AttributeValueMemberS{Value: hold.ExternalReference},
+			expiresAtAttr:       &types.AttributeValueMemberN{Value: strconv.FormatInt(hold.ExpiresAt.Unix(), 10)},
+		}}
+	}
+	return &types.AttributeValueMemberM{Value: value}
+}
+
+// holdsFromItem returns the holds of an account item, including any which have expired
+func holdsFromItem(item map[string]types.AttributeValue) (map[string]Hold, int, error) {
+	if _, ok := item[holdsAttr]; !ok {
+		return nil, 0, nil
+	}
+	value, ok := item[holdsAttr].(*types.AttributeValueMemberM)
+	if !ok {
+		return nil, 0, errors.New("holds must be a map")
+	}
+	version, err := numberFromItem(item, holdsVersionAttr)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	holds := make(map[string]Hold)
+	for id, element := range value.Value {
+		holdItem, ok := element.(*types.AttributeValueMemberM)
+		if !ok {
+			return nil, 0, errors.New("hold must be a map")
+		}
+		amount, err := numberFromItem(holdItem.Value, amountAttr)
+		if err != nil {
+			return nil, 0, err
+		}
+		expiresAt, err := numberFromItem(holdItem.Value, expiresAtAttr)
+		if err != nil {
+			return nil, 0, err
+		}
+		holds[id] = Hold{
+			HoldID:            id,
+			Amount:            amount,
+			Currency:          stringFromItem(holdItem.Value, currencyAttr),
+			DestAccountID:     stringFromItem(holdItem.Value, destAccountIDAttr),
+			DestAccountType:   stringFromItem(holdItem.Value, destAccountTypeAttr),
+			ExternalReference: stringFromItem(holdItem.Value, externalRefAttr),
+			ExpiresAt:         time.Unix(int64(expiresAt), 0).UTC(),
+		}
+	}
+	return holds, version, nil
+}
@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestAccount_ExpiredHoldsAreNotHeld(t *testing.T) {
+	// === Given ===
+	now := time.Now().UTC().Truncate(time.Second)
+	key := AccountKey{AccountID: "123456789", AccountType: "savings"}
+	account := account{
+		balance:        10,
+		currency:       "USD",
+		overdraftLimit: 5,
+		holds: map[string]Hold{
+			"active":  {HoldID: "active", Amount: 3, ExpiresAt: now.Add(time.Hour)},
+			"expired": {HoldID: "expired", Amount: 4, ExpiresAt: now.Add(-time.Second)},
+		},
+		holdsVersion: 2,
+	}
+
+	// === When ===
+	output := account.toGetBalanceOutput()
+	_, activeErr := account.hold(key, "active")
+	_, expiredErr := account.hold(key, "expired")
+
+	// === Then ===
+	assert.Equal(t, GetBalanceOutput{Balance: 10, Currency: "USD", OverdraftLimit: 5, Held: 3, Available: 12}, output)
+	assert.NoError(t, activeErr)
+	assert.ErrorIs(t, expiredErr, HoldNotFoundError{AccountID: "123456789", AccountType: "savings", HoldID: "expired"})
+	assert.Equal(t, []string{"active"}, holdIDs(account.activeHolds()))
+}
+
+func TestHold_Capture(t *testing.T) {
+	hold := Hold{HoldID: "0123456789abcdef", Amount: 10}
+
+	tests := []struct {
+		name              string
+		amount            *int
+		expectedCaptured  int
+		expectedRemaining int
+		expectedErr       error
+	}{
+		{name: "in full by default", expectedCaptured: 10, expectedRemaining: 0},
+		{name: "in full", amount: aws.Int(10), expectedCaptured: 10, expectedRemaining: 0},
+		{name: "partially", amount: aws.Int(4), expectedCaptured: 4, expectedRemaining: 6},
+		{name: "more than held", amount: aws.Int(11), expectedErr: CaptureExceedsHoldError{HoldID: "0123456789abcdef", HeldAmount: 10}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// === When ===
+			captured, remaining, err := hold.capture(test.amount)
+
+			// === Then ===
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedCaptured, captured)
+			assert.Equal(t, test.expectedRemaining, remaining.Amount)
+		})
+	}
+}
+
+func TestHolds_ItemRoundTrip(t *testing.T) {
+	// === Given ===
+	holds := map[string]Hold{
+		"0123456789abcdef": {
+			HoldID:            "0123456789abcdef",
+			Amount:            5,
+			Currency:          "USD",
+			DestAccountID:     "987654321",
+			DestAccountType:   "checking",
+			ExternalReference: "auth-0001",
+			ExpiresAt:         time.Now().UTC().Add(time.Hour).Truncate(time.Second),
+		},
+	}
+	item := map[string]types.AttributeValue{
+		holdsAttr:        holdsToAttributeValue(holds),
+		holdsVersionAttr: &types.AttributeValueMemberN{Value: "3"},
+	}
+
+	// === When ===
+	parsed, version, err := holdsFromItem(item)
+
+	// === Then ===
+	require.NoError(t, err)
+	assert.Equal(t, holds, parsed)
+	assert.Equal(t, 3, version)
+}
+
+func TestHolds_ItemWithoutHolds(t *testing.T) {
+	// === When ===
+	parsed, version, err := holdsFromItem(map[string]types.AttributeValue{})
+
+	// === Then ===
+	require.NoError(t, err)
+	assert.Empty(t, parsed)
+	assert.Equal(t, 0, version)
+}
+
+func holdIDs(holds map[string]Hold) []string {
+	var ids []string
+	for id := range holds {
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -0,0 +1,129 @@
+package internal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"strconv"
+	"time"
+)
+
+const (
+	idempotencyTableName = "idempotency-table"
+
+	idempotencyKeyAttr = "IdempotencyKey"
+	requestHashAttr    = "RequestHash"
+	expiresAtAttr      = "ExpiresAt"
+
+	// How long an idempotency key is remembered before it may be reused
+	idempotencyKeyTTL = 24 * time.Hour
+)
+
+type IdempotencyKeyConflictError struct {
+	IdempotencyKey string `json:"idempotencyKey"`
+}
+
+func (err IdempotencyKeyConflictError) Error() string {
+	return fmt.Sprintf("The idempotency key %s was already used for a different request.", err.IdempotencyKey)
+}
+
+// idempotencyRecord is the stored outcome of a request made with an idempotency key
+type idempotencyRecord struct {
+	requestHash string
+	transaction Transaction
+}
+
+// replay returns the original result of the request, provided the replayed request is identical to the original
+func (record idempotencyRecord) replay(idempotencyKey, requestHash string) (TransferOutput, error) {
+	if record.requestHash != requestHash {
+		return TransferOutput{}, IdempotencyKeyConflictError{
+			IdempotencyKey: idempotencyKey,
+		}
+	}
+	return TransferOutput{
+		Transaction: record.transaction,
+	}, nil
+}
+
+// requestHash identifies the payload of a transfer, excluding the idempotency key itself
+func (transferInput TransferInput) requestHash() string {
+	transferInput.IdempotencyKey = ""
+	payload, _ := json.Marshal(transferInput)
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+func idempotencyKeyItem(accountID, idempotencyKey string) map[string]types.AttributeValue {
+	item := make(map[string]types.AttributeValue)
+	item[accountIDAttr] = &types.AttributeValueMemberS{Value: accountID}
+	item[idempotencyKeyAttr] = &types.AttributeValueMemberS{Value: idempotencyKey}
+	return item
+}
+
+// idempotencyPut claims an idempotency key for a transaction. It is intended to be included in the same
+// TransactWriteItems call that applies the transaction, and fails its condition if the key has already been claimed.
+func idempotencyPut(accountID, idempotencyKey, requestHash string, tx Transaction) types.TransactWriteItem {
+	item := tx.toItem(accountID)
+	item[idempotencyKeyAttr] = &types.AttributeValueMemberS{Value: idempotencyKey}
+	item[requestHashAttr] = &types.AttributeValueMemberS{Value: requestHash}
+	item[expiresAtAttr] = &types.AttributeValueMemberN{Value: strconv.FormatInt(tx.Timestamp.Add(idempotencyKeyTTL).Unix(), 10)}
+
+	exprAttrValues := make(map[string]types.AttributeValue)
+	exprAttrValues[":now"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(tx.Timestamp.Unix(), 10)}
+
+	return types.TransactWriteItem{
+		Put: &types.Put{
+			Item:      item,
+			TableName: aws.String(idempotencyTableName),
+			// DynamoDB deletes expired items lazily, so an expired key may still be present
+			ConditionExpression:       aws.String(fmt.Sprintf("attribute_not_exists(%s) OR %s < :now", idempotencyKeyAttr, expiresAtAttr)),
+			ExpressionAttributeValues: exprAttrValues,
+		},
+	}
+}
+
+func (manager accountManagerImpl) getIdempotencyRecord(ctx context.Context, accountID, idempotencyKey string) (idempotencyRecord, bool, error) {
+	input := &dynamodb.GetItemInput{
+		Key:            idempotencyKeyItem(accountID, idempotencyKey),
+		TableName:      aws.String(idempotencyTableName),
+		ConsistentRead: aws.Bool(true),
+	}
+
+	output, err := manager.ddb.GetItem(ctx, input)
+	if err != nil {
+		return idempotencyRecord{}, false, err
+	}
+
+	if len(output.Item) == 0 {
+		return idempotencyRecord{}, false, nil
+	}
+
+	expiresAt, err := numberFromItem(output.Item, expiresAtAttr)
+	if err != nil {
+		return idempotencyRecord{}, false, err
+	}
+	if int64(expiresAt) < time.Now().Unix() {
+		return idempotencyRecord{}, false, nil
+	}
+
+	requestHash, ok := output.Item[requestHashAttr].(*types.AttributeValueMemberS)
+	if !ok {
+		return idempotencyRecord{}, false, errors.New("requestHash must be a string")
+	}
+
+	transaction, err := NewTransactionFromItem(output.Item)
+	if err != nil {
+		return idempotencyRecord{}, false, err
+	}
+
+	return idempotencyRecord{
+		requestHash: requestHash.Value,
+		transaction: transaction,
+	}, true, nil
+}
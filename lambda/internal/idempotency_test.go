@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRequestHash_IgnoresIdempotencyKey(t *testing.T) {
+	// === Given ===
+	input := TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   "123456789",
+		DestAccountType: "checking",
+		Amount:          aws.Int(5),
+	}
+	withKey := input
+	withKey.IdempotencyKey = "key"
+	otherAmount := withKey
+	otherAmount.Amount = aws.Int(6)
+
+	// === Then ===
+	assert.Equal(t, input.requestHash(), withKey.requestHash())
+	assert.NotEqual(t, withKey.requestHash(), otherAmount.requestHash())
+}
+
+func TestIdempotencyRecord_Replay(t *testing.T) {
+	// === Given ===
+	tx := newTransaction(TransactionTypeTransfer, 5, nil, nil)
+	record := idempotencyRecord{
+		requestHash: "hash",
+		transaction: tx,
+	}
+
+	// === When ===
+	output, err := record.replay("key", "hash")
+	_, conflictErr := record.replay("key", "other-hash")
+
+	// === Then ===
+	assert.NoError(t, err)
+	assert.Equal(t, tx, output.Transaction)
+	assert.ErrorIs(t, conflictErr, IdempotencyKeyConflictError{IdempotencyKey: "key"})
+}
@@ -0,0 +1,482 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+)
+
+const (
+	accruedInterestAttr = "AccruedInterest"
+	accruedThroughAttr  = "AccruedThrough"
+
+	// Layout of the dates that interest is accrued for
+	interestDateLayout = "2006-01-02"
+
+	// Interest accrues in millionths of a minor unit, so that small balances still accrue and rounding each day's
+	// interest is negligible
+	accrualScale = 1_000_000
+	// Decimal places that the nominal rate derived from an APY is rounded to, so that accruals don't depend on the
+	// precision of floating point
+	nominalRateScale = 12
+)
+
+// DayCount is the convention by which a day is counted as a fraction of a year
+type DayCount string
+
+const (
+	// Every day is 1/365 of a year, including in leap years
+	DayCountActual365 DayCount = "ACT/365"
+	// Every day is 1/360 of a year
+	DayCountActual360 DayCount = "ACT/360"
+	// Every day is a fraction of its own year, i.e. 1/366 in leap years
+	DayCountActualActual DayCount = "ACT/ACT"
+	// Every month has 30 days of a 360 day year, so the 30th of a 31 day month accrues nothing and the last day of
+	// February accrues the rest of the month
+	DayCount30360 DayCount = "30/360"
+)
+
+// Compounding is how often accrued interest is added to the balance that interest accrues on
+type Compounding string
+
+const (
+	// Interest accrues on the interest accrued so far each day, and is posted monthly
+	CompoundingDaily     Compounding = "DAILY"
+	CompoundingMonthly   Compounding = "MONTHLY"
+	CompoundingQuarterly Compounding = "QUARTERLY"
+	CompoundingAnnually  Compounding = "ANNUALLY"
+)
+
+// InterestTerms are the interest paid on every account of a product, i.e. an account type
+type InterestTerms struct {
+	// Annual percentage yield as a decimal fraction, e.g. "0.0425" for 4.25%
+	APY         string      `json:"apy" validate:"apy"`
+	DayCount    DayCount    `json:"dayCount" validate:"oneof=ACT/365 ACT/360 ACT/ACT 30/360"`
+	Compounding Compounding `json:"compounding" validate:"oneof=DAILY MONTHLY QUARTERLY ANNUALLY"`
+}
+
+// IsValidAPY reports whether apy is a non-negative decimal fraction that interest terms may yield
+func IsValidAPY(apy string) bool {
+	rate, ok := new(big.Rat).SetString(apy)
+	return ok && rate.Sign() >= 0
+}
+
+// Validate returns an error unless the terms have a valid APY, and a day count and compounding which are supported
+func (terms InterestTerms) Validate() error {
+	_, err := terms.nominalRate()
+	return err
+}
+
+// periodsPerYear returns the number of compounding periods in a year
+func (terms InterestTerms) periodsPerYear() int {
+	switch terms.Compounding {
+	case CompoundingDaily:
+		if terms.DayCount == DayCountActual360 || terms.DayCount == DayCount30360 {
+			return 360
+		}
+		return 365
+	case CompoundingMonthly:
+		return 12
+	case CompoundingQuarterly:
+		return 4
+	default:
+		return 1
+	}
+}
+
+// nominalRate returns the annual rate which, compounded at the frequency of the terms, yields their APY
+func (terms InterestTerms) nominalRate() (*big.Rat, error) {
+	if !IsValidAPY(terms.APY) {
+		return nil, fmt.Errorf("apy must be a non-negative decimal, got %q", terms.APY)
+	}
+	apy, _ := new(big.Rat).SetString(terms.APY)
+	switch terms.DayCount {
+	case DayCountActual365, DayCountActual360, DayCountActualActual, DayCount30360:
+	default:
+		return nil, fmt.Errorf("unsupported day count %q", terms.DayCount)
+	}
+	switch terms.Compounding {
+	case CompoundingDaily, CompoundingMonthly, CompoundingQuarterly, CompoundingAnnually:
+	default:
+		return nil, fmt.Errorf("unsupported compounding %q", terms.Compounding)
+	}
+
+	// n * ((1 + APY)^(1/n) - 1), which has no exact form, is rounded to a fixed number of decimal places
+	apyFloat, _ := apy.Float64()
+	n := float64(terms.periodsPerYear())
+	rate, _ := new(big.Rat).SetString(strconv.FormatFloat(n*(math.Pow(1+apyFloat, 1/n)-1), 'f', nominalRateScale, 64))
+	return rate, nil
+}
+
+// yearFraction returns the fraction of a year that a day counts as
+func (terms InterestTerms) yearFraction(day time.Time) *big.Rat {
+	switch terms.DayCount {
+	case DayCountActual360:
+		return big.NewRat(1, 360)
+	case DayCountActualActual:
+		year := day.Year()
+		return big.NewRat(1, int64(time.Date(year+1, 1, 1, 0, 0, 0, 0, time.UTC).Sub(time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)).Hours()/24))
+	case DayCount30360:
+		return big.NewRat(int64(days30360(day, day.AddDate(0, 0, 1))), 360)
+	default:
+		return big.NewRat(1, 365)
+	}
+}
+
+// days30360 returns the number of days between two dates by the 30/360 bond basis
+func days30360(start, end time.Time) int {
+	d1, d2 := start.Day(), end.Day()
+	if d1 == 31 {
+		d1 = 30
+	}
+	if d2 == 31 && d1 == 30 {
+		d2 = 30
+	}
+	return 360*(end.Year()-start.Year()) + 30*(int(end.Month())-int(start.Month())) + d2 - d1
+}
+
+// postsOn reports whether interest accrued through day is posted, i.e. whether day is the last of a compounding period.
+// Daily compounding posts monthly.
+func (terms InterestTerms) postsOn(day time.Time) bool {
+	if day.AddDate(0, 0, 1).Day() != 1 {
+		return false
+	}
+	switch terms.Compounding {
+	case CompoundingQuarterly:
+		return day.Month()%3 == 0
+	case CompoundingAnnually:
+		return day.Month() == time.December
+	default:
+		return true
+	}
+}
+
+// accrueInterest returns the interest accrued by an account after a day at the given nominal rate, in millionths of a
+// minor unit, and the whole minor units of it which are posted at the end of the day. Interest only accrues on a
+// positive balance, and the daily interest is rounded half to even. Posting rounds down, so the fraction of a minor
+// unit left accrued is carried into the next period.
+func (account account) accrueInterest(terms InterestTerms, rate *big.Rat, day time.Time) (accrued, posted int) {
+	accrued = account.accruedInterest
+	base := account.balance * accrualScale
+	if terms.Compounding == CompoundingDaily {
+		base += accrued
+	}
+	if base > 0 {
+		interest := new(big.Rat).Mul(big.NewRat(int64(base), 1), rate)
+		interest.Mul(interest, terms.yearFraction(day))
+		accrued += int(roundHalfEven(interest).Int64())
+	}
+
+	if terms.postsOn(day) {
+		posted = accrued / accrualScale
+		accrued -= posted * accrualScale
+	}
+	return accrued, posted
+}
+
+// interestDays returns the days through day that the account has yet to accrue interest for. These start from the day
+// after it last accrued, so that days missed by earlier runs are caught up, or from day itself if it has never accrued.
+// The caught up days all accrue on the current balance, see accrueAccountInterest.
+func (account account) interestDays(day time.Time) []time.Time {
+	first := day
+	if account.accruedThrough != "" {
+		accruedThrough, err := time.Parse(interestDateLayout, account.accruedThrough)
+		if err == nil {
+			first = accruedThrough.AddDate(0, 0, 1)
+		}
+	}
+
+	var days []time.Time
+	for next := first; !next.After(day); next = next.AddDate(0, 0, 1) {
+		days = append(days, next)
+	}
+	return days
+}
+
+// accruedMinorUnits returns the interest accrued by an account which has not yet been posted, rounded down to whole
+// minor units
+func (account account) accruedMinorUnits() int {
+	return account.accruedInterest / accrualScale
+}
+
+// postAccruedInterest returns the account after posting the interest it has accrued, e.g. before it is closed, and the
+// transaction which records the posting, which is nil if there is nothing to post. Only whole minor units are posted,
+// and any fraction of one is forfeited.
+func (account account) postAccruedInterest(key AccountKey) (account, *Transaction) {
+	posted := account.accruedMinorUnits()
+	account.accruedInterest = 0
+	if posted <= 0 {
+		return account, nil
+	}
+
+	account.balance += posted
+	tx := newTransaction(TransactionTypeInterest, posted, nil, &TransactionParty{
+		AccountID:   key.AccountID,
+		AccountType: key.AccountType,
+		Currency:    account.currency,
+		Balance:     account.balance,
+	})
+	return account, &tx
+}
+
+// AccrueInterestInput accrues interest through a day on every account of every product in the catalog which pays
+// interest
+type AccrueInterestInput struct {
+	// Day to accrue interest for, which defaults to yesterday in UTC
+	Date string `json:"date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type AccrueInterestOutput struct {
+	Date     string           `json:"date"`
+	Products []ProductAccrual `json:"products"`
+}
+
+// AccrueProductInterestInput accrues interest through a day on every account of one product, at the interest terms of
+// the product in the catalog
+type AccrueProductInterestInput struct {
+	AccountType string `json:"accountType"`
+	Date        string `json:"date"`
+}
+
+// ProductAccrual counts the accounts of a product whose interest was accrued or posted
+type ProductAccrual struct {
+	AccountType string `json:"accountType"`
+	// Number of accounts which accrued interest, which excludes those already accrued through the day and frozen accounts
+	Accrued int `json:"accrued"`
+	// Number of accounts whose accrued interest was posted as an INTEREST transaction
+	Posted int `json:"posted"`
+}
+
+// AccrueInterest accrues interest through a day through manager on the accounts of each product in the catalog with
+// interest terms. Retired products are included, as their accounts still earn interest. Accruing a day again has no
+// effect, so a failed run can be retried.
+func AccrueInterest(ctx context.Context, manager AccountManager, accrueInterestInput AccrueInterestInput) (AccrueInterestOutput, error) {
+	date := accrueInterestInput.Date
+	if date == "" {
+		date = time.Now().UTC().AddDate(0, 0, -1).Format(interestDateLayout)
+	}
+
+	// Products are listed in order of account type, so that runs are reproducible
+	products, err := manager.ListProducts(ctx, ListProductsInput{IncludeRetired: true})
+	if err != nil {
+		return AccrueInterestOutput{}, err
+	}
+
+	output := AccrueInterestOutput{Date: date}
+	for _, product := range products.Products {
+		if product.Interest == nil {
+			continue
+		}
+		accrual, err := manager.AccrueProductInterest(ctx, AccrueProductInterestInput{
+			AccountType: product.AccountType,
+			Date:        date,
+		})
+		if err != nil {
+			return output, err
+		}
+		output.Products = append(output.Products, accrual)
+	}
+	return output, nil
+}
+
+// parseAccrual returns the nominal rate of the terms that an accrual is at and the day it is for
+func (input AccrueProductInterestInput) parseAccrual(terms InterestTerms) (*big.Rat, time.Time, error) {
+	rate, err := terms.nominalRate()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	day, err := time.Parse(interestDateLayout, input.Date)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return rate, day, nil
+}
+
+func (manager accountManagerImpl) AccrueProductInterest(ctx context.Context, accrueProductInterestInput AccrueProductInterestInput) (ProductAccrual, error) {
+	product, found, err := manager.getProduct(ctx, accrueProductInterestInput.AccountType)
+	if err != nil {
+		return ProductAccrual{}, err
+	}
+	if !found {
+		return ProductAccrual{}, ProductNotFoundError{AccountType: accrueProductInterestInput.AccountType}
+	}
+	accrual := ProductAccrual{AccountType: accrueProductInterestInput.AccountType}
+	if product.Interest == nil {
+		// The product pays no interest
+		return accrual, nil
+	}
+	terms := *product.Interest
+	rate, day, err := accrueProductInterestInput.parseAccrual(terms)
+	if err != nil {
+		return ProductAccrual{}, err
+	}
+
+	// The index is eventually consistent, so an account created just before the accrual may be left to the next one.
+	// Each account is read consistently before it accrues.
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(tableName),
+		IndexName:                 aws.String(accountsByTypeIndexName),
+		ProjectionExpression:      aws.String(fmt.Sprintf("%s,%s", accountIDAttr, accountTypeAttr)),
+		KeyConditionExpression:    aws.String(fmt.Sprintf("%s = :type", accountTypeAttr)),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":type": &types.AttributeValueMemberS{Value: accrueProductInterestInput.AccountType}},
+	}
+	for {
+		output, err := manager.ddb.Query(ctx, input)
+		if err != nil {
+			return accrual, err
+		}
+
+		for _, item := range output.Items {
+			key, err := NewAccountKeyFromItem(item)
+			if err != nil {
+				return accrual, err
+			}
+
+			// Days accrued before a conflict are kept, so a retry only accrues the rest
+			var accrued, posted bool
+			err = retryOnConflict(ctx, transferRetryPolicy, "AccrueInterest", func() error {
+				attemptAccrued, attemptPosted, err := manager.accrueAccountInterest(ctx, key, terms, rate, day)
+				accrued = accrued || attemptAccrued
+				posted = posted || attemptPosted
+				return err
+			})
+			if err != nil {
+				return accrual, err
+			}
+			if accrued {
+				accrual.Accrued++
+			}
+			if posted {
+				accrual.Posted++
+			}
+		}
+
+		if len(output.LastEvaluatedKey) == 0 {
+			return accrual, nil
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
+// accrueAccountInterest accrues interest on an account for each day through day that it has not yet accrued, posting it
+// at the end of each compounding period. Frozen accounts earn no interest, so the days they are frozen are passed over
+// rather than caught up once they are unfrozen.
+//
+// Days missed by earlier runs accrue on the balance when they are caught up, not on the balance at the end of each of
+// them, as the account doesn't keep its past balances. Deposits, withdrawals and transfers made since the last accrual
+// therefore change the interest of the missed days as though they had been made before them.
+func (manager accountManagerImpl) accrueAccountInterest(ctx context.Context, key AccountKey, terms InterestTerms, rate *big.Rat, day time.Time) (accrued, posted bool, err error) {
+	account, err := manager.getAccount(ctx, key)
+	if err != nil {
+		var accountDoesNotExistError AccountDoesNotExistError
+		if errors.As(err, &accountDoesNotExistError) {
+			// The account was deleted since it was listed
+			return false, false, nil
+		}
+		return false, false, err
+	}
+	date := day.Format(interestDateLayout)
+	if account.accruedThrough >= date || account.status == AccountStatusClosed {
+		return false, false, nil
+	}
+	if account.status == AccountStatusFrozen {
+		return false, false, manager.passOverInterest(ctx, key, date)
+	}
+
+	for _, accrualDay := range account.interestDays(day) {
+		dayPosted, err := manager.accrueInterestDay(ctx, key, &account, terms, rate, accrualDay)
+		if err != nil {
+			return accrued, posted, err
+		}
+		accrued = true
+		posted = posted || dayPosted
+	}
+	return accrued, posted, nil
+}
+
+// accrueInterestDay accrues a day of interest on an account as it was read, and posts it if the day ends a compounding
+// period. The account is updated to match once the interest is written.
+func (manager accountManagerImpl) accrueInterestDay(ctx context.Context, key AccountKey, account *account, terms InterestTerms, rate *big.Rat, day time.Time) (bool, error) {
+	date := day.Format(interestDateLayout)
+	accruedInterest, postedInterest := account.accrueInterest(terms, rate, day)
+
+	exprAttrValues := make(map[string]types.AttributeValue)
+	exprAttrValues[":old"] = &types.AttributeValueMemberN{Value: strconv.Itoa(account.balance)}
+	exprAttrValues[":new"] = &types.AttributeValueMemberN{Value: strconv.Itoa(account.balance + postedInterest)}
+	exprAttrValues[":accrued"] = &types.AttributeValueMemberN{Value: strconv.Itoa(accruedInterest)}
+	exprAttrValues[":date"] = &types.AttributeValueMemberS{Value: date}
+	// Interest accrues on the balance it was computed from, only once for each day and only on accounts which may be
+	// credited
+	condition := fmt.Sprintf("%s = :old AND (attribute_not_exists(%s) OR %s < :date)", balanceAttr, accruedThroughAttr, accruedThroughAttr) +
+		statusCondition(false, exprAttrValues)
+	update := types.TransactWriteItem{
+		Update: &types.Update{
+			Key:       key.toAccountItem(),
+			TableName: aws.String(tableName),
+			UpdateExpression: aws.String(fmt.Sprintf("SET %s = :new, %s = :accrued, %s = :date",
+				balanceAttr, accruedInterestAttr, accruedThroughAttr)),
+			ConditionExpression:       aws.String(condition),
+			ExpressionAttributeValues: exprAttrValues,
+		},
+	}
+
+	transactItems := []types.TransactWriteItem{update}
+	if postedInterest > 0 {
+		tx := newTransaction(TransactionTypeInterest, postedInterest, nil, &TransactionParty{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
+			Currency:    account.currency,
+			Balance:     account.balance + postedInterest,
+		})
+		transactItems = append(transactItems, tx.toTransactWriteItems()...)
+	}
+
+	_, err := manager.ddb.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: transactItems,
+	})
+	if err != nil {
+		var transactionCanceledException *types.TransactionCanceledException
+		if errors.As(err, &transactionCanceledException) &&
+			(isConditionalCheckFailed(transactionCanceledException, 0) || isTransactionConflict(transactionCanceledException)) {
+			return false, TransactionConflictError{Dest: key, Err: err}
+		}
+		return false, err
+	}
+
+	account.balance += postedInterest
+	account.accruedInterest = accruedInterest
+	account.accruedThrough = date
+	return postedInterest > 0, nil
+}
+
+// passOverInterest moves the accrual of a frozen account through date without accruing interest
+func (manager accountManagerImpl) passOverInterest(ctx context.Context, key AccountKey, date string) error {
+	exprAttrValues := make(map[string]types.AttributeValue)
+	exprAttrValues[":date"] = &types.AttributeValueMemberS{Value: date}
+	exprAttrValues[":frozen"] = &types.AttributeValueMemberS{Value: string(AccountStatusFrozen)}
+
+	_, err := manager.ddb.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		Key:              key.toAccountItem(),
+		TableName:        aws.String(tableName),
+		UpdateExpression: aws.String(fmt.Sprintf("SET %s = :date", accruedThroughAttr)),
+		ConditionExpression: aws.String(fmt.Sprintf("%s = :frozen AND (attribute_not_exists(%s) OR %s < :date)",
+			accountStatusAttr, accruedThroughAttr, accruedThroughAttr)),
+		ExpressionAttributeValues: exprAttrValues,
+	})
+	if err != nil {
+		var conditionalCheckFailedException *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionalCheckFailedException) {
+			// The account was unfrozen or accrued meanwhile, so it is read again
+			return TransactionConflictError{Dest: key, Err: err}
+		}
+		return err
+	}
+	return nil
+}
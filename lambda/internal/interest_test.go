@@ -0,0 +1,215 @@
+package internal
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestInterestTerms_NominalRate(t *testing.T) {
+	tests := []struct {
+		compounding Compounding
+		expected    string
+	}{
+		{compounding: CompoundingDaily, expected: "0.041624047882"},
+		{compounding: CompoundingMonthly, expected: "0.041693940042"},
+		{compounding: CompoundingQuarterly, expected: "0.041838973206"},
+		{compounding: CompoundingAnnually, expected: "0.042500000000"},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.compounding), func(t *testing.T) {
+			// === Given ===
+			terms := InterestTerms{APY: "0.0425", DayCount: DayCountActual365, Compounding: test.compounding}
+
+			// === When ===
+			rate, err := terms.nominalRate()
+
+			// === Then ===
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, rate.FloatString(nominalRateScale))
+		})
+	}
+}
+
+func TestInterestTerms_YearFraction(t *testing.T) {
+	tests := []struct {
+		name     string
+		dayCount DayCount
+		date     string
+		expected *big.Rat
+	}{
+		{name: "ACT/365 in a leap year", dayCount: DayCountActual365, date: "2024-02-29", expected: big.NewRat(1, 365)},
+		{name: "ACT/360", dayCount: DayCountActual360, date: "2022-09-30", expected: big.NewRat(1, 360)},
+		{name: "ACT/ACT", dayCount: DayCountActualActual, date: "2022-09-30", expected: big.NewRat(1, 365)},
+		{name: "ACT/ACT in a leap year", dayCount: DayCountActualActual, date: "2024-09-30", expected: big.NewRat(1, 366)},
+		{name: "30/360", dayCount: DayCount30360, date: "2022-01-15", expected: big.NewRat(1, 360)},
+		{name: "30/360 on the 30th of a 31 day month", dayCount: DayCount30360, date: "2022-01-30", expected: big.NewRat(0, 1)},
+		{name: "30/360 on the 31st", dayCount: DayCount30360, date: "2022-01-31", expected: big.NewRat(1, 360)},
+		{name: "30/360 at the end of February", dayCount: DayCount30360, date: "2022-02-28", expected: big.NewRat(3, 360)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// === Given ===
+			day, err := time.Parse(interestDateLayout, test.date)
+			require.NoError(t, err)
+
+			// === When ===
+			fraction := InterestTerms{DayCount: test.dayCount}.yearFraction(day)
+
+			// === Then ===
+			assert.Zero(t, test.expected.Cmp(fraction), "expected %s, got %s", test.expected, fraction)
+		})
+	}
+}
+
+func TestInterestTerms_PostsOn(t *testing.T) {
+	tests := []struct {
+		compounding Compounding
+		date        string
+		expected    bool
+	}{
+		{compounding: CompoundingDaily, date: "2022-09-29", expected: false},
+		{compounding: CompoundingDaily, date: "2022-09-30", expected: true},
+		{compounding: CompoundingMonthly, date: "2024-02-28", expected: false},
+		{compounding: CompoundingMonthly, date: "2024-02-29", expected: true},
+		{compounding: CompoundingQuarterly, date: "2022-08-31", expected: false},
+		{compounding: CompoundingQuarterly, date: "2022-09-30", expected: true},
+		{compounding: CompoundingAnnually, date: "2022-11-30", expected: false},
+		{compounding: CompoundingAnnually, date: "2022-12-31", expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.compounding)+" "+test.date, func(t *testing.T) {
+			// === Given ===
+			day, err := time.Parse(interestDateLayout, test.date)
+			require.NoError(t, err)
+
+			// === When ===
+			posts := InterestTerms{Compounding: test.compounding}.postsOn(day)
+
+			// === Then ===
+			assert.Equal(t, test.expected, posts)
+		})
+	}
+}
+
+func TestAccount_AccrueInterest(t *testing.T) {
+	// === Given ===
+	terms := InterestTerms{APY: "0.0425", DayCount: DayCountActual365, Compounding: CompoundingMonthly}
+	rate, err := terms.nominalRate()
+	require.NoError(t, err)
+	account := account{balance: 100000}
+
+	// === When ===
+	firstAccrued, firstPosted := account.accrueInterest(terms, rate, time.Date(2022, 9, 29, 0, 0, 0, 0, time.UTC))
+	account.accruedInterest = firstAccrued
+	secondAccrued, secondPosted := account.accrueInterest(terms, rate, time.Date(2022, 9, 30, 0, 0, 0, 0, time.UTC))
+
+	// === Then ===
+	// 100000 * 0.041693940042 / 365 = 11.422997...
+	assert.Equal(t, 11422997, firstAccrued)
+	assert.Zero(t, firstPosted)
+	// The fraction of a minor unit left at the end of the month is carried into the next
+	assert.Equal(t, 22, secondPosted)
+	assert.Equal(t, 845994, secondAccrued)
+}
+
+func TestAccount_AccrueInterestCompoundsDaily(t *testing.T) {
+	// === Given ===
+	terms := InterestTerms{APY: "0.0425", DayCount: DayCountActual365, Compounding: CompoundingDaily}
+	rate, err := terms.nominalRate()
+	require.NoError(t, err)
+	day := time.Date(2022, 9, 29, 0, 0, 0, 0, time.UTC)
+	account := account{balance: 100000}
+
+	// === When ===
+	withoutAccrued, _ := account.accrueInterest(terms, rate, day)
+	account.accruedInterest = 100 * accrualScale
+	withAccrued, _ := account.accrueInterest(terms, rate, day)
+
+	// === Then ===
+	assert.Greater(t, withAccrued-account.accruedInterest, withoutAccrued)
+}
+
+func TestAccount_AccrueInterestOnlyOnPositiveBalance(t *testing.T) {
+	// === Given ===
+	terms := InterestTerms{APY: "0.0425", DayCount: DayCountActual365, Compounding: CompoundingMonthly}
+	rate, err := terms.nominalRate()
+	require.NoError(t, err)
+	account := account{balance: -100000, overdraftLimit: 200000, accruedInterest: 5}
+
+	// === When ===
+	accrued, posted := account.accrueInterest(terms, rate, time.Date(2022, 9, 29, 0, 0, 0, 0, time.UTC))
+
+	// === Then ===
+	assert.Equal(t, 5, accrued)
+	assert.Zero(t, posted)
+}
+
+func TestInterestTerms_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		terms     InterestTerms
+		expectErr bool
+	}{
+		{name: "valid", terms: InterestTerms{APY: "0.0425", DayCount: DayCountActual365, Compounding: CompoundingMonthly}},
+		{name: "negative APY", terms: InterestTerms{APY: "-0.01", DayCount: DayCountActual365, Compounding: CompoundingMonthly}, expectErr: true},
+		{name: "APY which is not a decimal", terms: InterestTerms{APY: "4.25%", DayCount: DayCountActual365, Compounding: CompoundingMonthly}, expectErr: true},
+		{name: "unsupported day count", terms: InterestTerms{APY: "0.0425", DayCount: "ACT/364", Compounding: CompoundingMonthly}, expectErr: true},
+		{name: "unsupported compounding", terms: InterestTerms{APY: "0.0425", DayCount: DayCountActual365, Compounding: "WEEKLY"}, expectErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// === When ===
+			err := test.terms.Validate()
+
+			// === Then ===
+			if test.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAccount_InterestDays(t *testing.T) {
+	day := time.Date(2022, 10, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		accruedThrough string
+		expected       []time.Time
+	}{
+		{name: "never accrued", accruedThrough: "", expected: []time.Time{day}},
+		{name: "accrued the day before", accruedThrough: "2022-10-01", expected: []time.Time{day}},
+		{
+			name:           "missed days are caught up",
+			accruedThrough: "2022-09-29",
+			expected: []time.Time{
+				time.Date(2022, 9, 30, 0, 0, 0, 0, time.UTC),
+				time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC),
+				day,
+			},
+		},
+		{name: "already accrued", accruedThrough: "2022-10-02", expected: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// === Given ===
+			account := account{balance: 100000, accruedThrough: test.accruedThrough}
+
+			// === When ===
+			days := account.interestDays(day)
+
+			// === Then ===
+			assert.Equal(t, test.expected, days)
+		})
+	}
+}
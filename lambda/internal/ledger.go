@@ -0,0 +1,241 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"strconv"
+	"time"
+)
+
+const (
+	postingsAttr = "Postings"
+
+	// Account ID of the system accounts, which can never be an AWS account ID
+	SystemAccountID = "system"
+
+	// Layout of the days that the postings to system accounts are recorded by
+	postingDateLayout = "2006-01-02"
+)
+
+// System accounts are the other side of every movement of money into or out of the ledger. They have no account item,
+// and their balances, which are never positive for the accounts money enters through, are derived from their postings.
+var (
+	SystemAccountOpeningBalances = AccountKey{AccountID: SystemAccountID, AccountType: "opening-balances"}
+	SystemAccountDeposits        = AccountKey{AccountID: SystemAccountID, AccountType: "deposits"}
+	SystemAccountWithdrawals     = AccountKey{AccountID: SystemAccountID, AccountType: "withdrawals"}
+	// Position of the ledger in each currency, which balances the postings of transfers converting between currencies
+	SystemAccountFXPosition = AccountKey{AccountID: SystemAccountID, AccountType: "fx-position"}
+	// Interest paid to accounts, which the bank bears as an expense
+	SystemAccountInterestExpense = AccountKey{AccountID: SystemAccountID, AccountType: "interest-expense"}
+	// Fees charged on transfers, which the bank earns as income
+	SystemAccountFeeIncome = AccountKey{AccountID: SystemAccountID, AccountType: "fee-income"}
+)
+
+// Posting is a change to the balance of a single account. The postings of a transaction form a double-entry journal
+// entry, and always sum to zero in each currency.
+type Posting struct {
+	AccountID   string `json:"accountID"`
+	AccountType string `json:"accountType"`
+	Currency    string `json:"currency"`
+	Amount      int    `json:"amount"`
+}
+
+func (posting Posting) accountKey() AccountKey {
+	return AccountKey{
+		AccountID:   posting.AccountID,
+		AccountType: posting.AccountType,
+	}
+}
+
+// journalEntry returns the postings of a transaction, which credit and debit the parties to it and the system account
+// on the other side of any money entering or leaving the ledger. A transfer between currencies is posted as a sale of
+// the source currency and a purchase of the destination currency by the FX position, and the fees of a transfer are
+// posted from the source account to the fee income account.
+func journalEntry(tx *Transaction) []Posting {
+	if tx.Amount == 0 {
+		return nil
+	}
+
+	postings := principalPostings(tx)
+	if fees := totalFees(tx.Fees); fees > 0 && tx.Src != nil {
+		postings = append(postings, transfer(tx.Src.accountKey(), SystemAccountFeeIncome, currencyOrDefault(tx.Src.Currency), fees)...)
+	}
+	return postings
+}
+
+// principalPostings returns the postings which move the amount of a transaction
+func principalPostings(tx *Transaction) []Posting {
+	src, dest := tx.Src, tx.Dest
+	switch {
+	case tx.Type == TransactionTypeCreate && dest != nil:
+		return transfer(SystemAccountOpeningBalances, dest.accountKey(), currencyOrDefault(dest.Currency), tx.Amount)
+	case tx.Type == TransactionTypeTransfer && src != nil && dest != nil && tx.DestAmount != 0:
+		return append(
+			transfer(src.accountKey(), SystemAccountFXPosition, currencyOrDefault(src.Currency), tx.Amount),
+			transfer(SystemAccountFXPosition, dest.accountKey(), currencyOrDefault(dest.Currency), tx.DestAmount)...)
+	case tx.Type == TransactionTypeTransfer && src != nil && dest != nil:
+		return transfer(src.accountKey(), dest.accountKey(), currencyOrDefault(src.Currency), tx.Amount)
+	case tx.Type == TransactionTypeCapture && src != nil && dest != nil:
+		return transfer(src.accountKey(), dest.accountKey(), currencyOrDefault(src.Currency), tx.Amount)
+	case tx.Type == TransactionTypeClose && src != nil && dest != nil:
+		return transfer(src.accountKey(), dest.accountKey(), currencyOrDefault(src.Currency), tx.Amount)
+	case tx.Type == TransactionTypeDeposit && dest != nil:
+		return transfer(SystemAccountDeposits, dest.accountKey(), currencyOrDefault(dest.Currency), tx.Amount)
+	case tx.Type == TransactionTypeInterest && dest != nil:
+		return transfer(SystemAccountInterestExpense, dest.accountKey(), currencyOrDefault(dest.Currency), tx.Amount)
+	case tx.Type == TransactionTypeWithdrawal && src != nil:
+		return transfer(src.accountKey(), SystemAccountWithdrawals, currencyOrDefault(src.Currency), tx.Amount)
+	}
+	return nil
+}
+
+// transfer returns the postings which debit one account and credit another
+func transfer(debit, credit AccountKey, currency string, amount int) []Posting {
+	return []Posting{
+		{AccountID: debit.AccountID, AccountType: debit.AccountType, Currency: currency, Amount: -amount},
+		{AccountID: credit.AccountID, AccountType: credit.AccountType, Currency: currency, Amount: amount},
+	}
+}
+
+func (party *TransactionParty) accountKey() AccountKey {
+	return AccountKey{
+		AccountID:   party.AccountID,
+		AccountType: party.AccountType,
+	}
+}
+
+// balanced returns whether the postings of the transaction sum to zero in each currency
+func (tx *Transaction) balanced() bool {
+	sums := make(map[string]int)
+	for _, posting := range tx.Postings {
+		sums[posting.Currency] += posting.Amount
+	}
+	for _, sum := range sums {
+		if sum != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// postedAmount returns the sum of the postings of the transaction to an account in a currency
+func (tx *Transaction) postedAmount(key AccountKey, currency string) int {
+	sum := 0
+	for _, posting := range tx.Postings {
+		if posting.accountKey() == key && posting.Currency == currency {
+			sum += posting.Amount
+		}
+	}
+	return sum
+}
+
+func postingsToAttributeValue(postings []Posting) types.AttributeValue {
+	list := make([]types.AttributeValue, len(postings))
+	for i, posting := range postings {
+		list[i] = &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			accountIDAttr:   &types.AttributeValueMemberS{Value: posting.AccountID},
+			accountTypeAttr: &types.AttributeValueMemberS{Value: posting.AccountType},
+			currencyAttr:    &types.AttributeValueMemberS{Value: posting.Currency},
+			amountAttr:      &types.AttributeValueMemberN{Value: strconv.Itoa(posting.Amount)},
+		}}
+	}
+	return &types.AttributeValueMemberL{Value: list}
+}
+
+func postingsFromAttributeValue(attrValue types.AttributeValue) ([]Posting, error) {
+	list, ok := attrValue.(*types.AttributeValueMemberL)
+	if !ok {
+		return nil, errors.New("postings must be a list")
+	}
+
+	var postings []Posting
+	for _, element := range list.Value {
+		item, ok := element.(*types.AttributeValueMemberM)
+		if !ok {
+			return nil, errors.New("posting must be a map")
+		}
+		key, err := NewAccountKeyFromItem(item.Value)
+		if err != nil {
+			return nil, err
+		}
+		amount, err := numberFromItem(item.Value, amountAttr)
+		if err != nil {
+			return nil, err
+		}
+		postings = append(postings, Posting{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
+			// Postings recorded before accounts had currencies are in the default currency
+			Currency: currencyOrDefault(stringFromItem(item.Value, currencyAttr)),
+			Amount:   amount,
+		})
+	}
+	return postings, nil
+}
+
+// systemPartition returns the key that the transactions posting to the system account key on day are recorded under.
+// Every account posts to the system accounts, so rather than recording them all under SystemAccountID, their
+// transactions are sharded by system account and by day.
+func systemPartition(key AccountKey, day time.Time) string {
+	return fmt.Sprintf("%s:%s:%s", SystemAccountID, key.AccountType, day.UTC().Format(postingDateLayout))
+}
+
+// reconcilePartition returns the key of the transactions to reconcile the account of reconcileInput from, along with
+// the day reconciled for system accounts
+func reconcilePartition(reconcileInput ReconcileInput) (string, string, error) {
+	if reconcileInput.AccountID != SystemAccountID {
+		return reconcileInput.AccountID, "", nil
+	}
+	day := time.Now().UTC()
+	if reconcileInput.Date != "" {
+		var err error
+		if day, err = time.Parse(postingDateLayout, reconcileInput.Date); err != nil {
+			return "", "", err
+		}
+	}
+	key := AccountKey{AccountID: reconcileInput.AccountID, AccountType: reconcileInput.AccountType}
+	return systemPartition(key, day), day.Format(postingDateLayout), nil
+}
+
+type ReconcileInput struct {
+	AccountID   string `json:"accountID" validate:"required"`
+	AccountType string `json:"accountType" validate:"required"`
+	// Currency to reconcile system accounts in, which defaults to the default currency. Other accounts are reconciled in
+	// their own currency.
+	Currency string `json:"currency,omitempty" validate:"omitempty,currency"`
+	// Day to reconcile the postings of system accounts on, which defaults to today in UTC. Other accounts are reconciled
+	// from all of their postings.
+	Date string `json:"date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type ReconcileOutput struct {
+	Currency string `json:"currency"`
+	// Day that the postings of a system account were reconciled on, which is absent for other accounts
+	Date string `json:"date,omitempty"`
+	// Stored balance of the account, which is absent for system accounts
+	Balance *int `json:"balance,omitempty"`
+	// Sum of every posting to the account, or of the postings on Date for system accounts
+	PostedBalance int `json:"postedBalance"`
+	// IDs of the journal entries recorded under the account's owner whose postings do not sum to zero
+	UnbalancedTransactionIDs []string `json:"unbalancedTransactionIDs,omitempty"`
+	// Whether the stored balance matches the postings, and every journal entry sums to zero
+	Reconciled bool `json:"reconciled"`
+}
+
+// reconcile checks the journal entries recorded under the owner of key against the stored balance of the account in
+// currency, which is nil for system accounts
+func reconcile(key AccountKey, currency string, balance *int, transactions []Transaction) ReconcileOutput {
+	output := ReconcileOutput{
+		Currency: currency,
+		Balance:  balance,
+	}
+	for _, tx := range transactions {
+		output.PostedBalance += tx.postedAmount(key, currency)
+		if !tx.balanced() {
+			output.UnbalancedTransactionIDs = append(output.UnbalancedTransactionIDs, tx.TransactionID)
+		}
+	}
+	output.Reconciled = len(output.UnbalancedTransactionIDs) == 0 && (balance == nil || *balance == output.PostedBalance)
+	return output
+}
@@ -0,0 +1,189 @@
+package internal
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestJournalEntry(t *testing.T) {
+	src := &TransactionParty{AccountID: "123456789", AccountType: "savings"}
+	dest := &TransactionParty{AccountID: "987654321", AccountType: "checking"}
+
+	tests := []struct {
+		name            string
+		transactionType TransactionType
+		src, dest       *TransactionParty
+		expected        []Posting
+	}{
+		{
+			name:            "create",
+			transactionType: TransactionTypeCreate,
+			dest:            dest,
+			expected: []Posting{
+				{AccountID: SystemAccountID, AccountType: "opening-balances", Currency: "USD", Amount: -5},
+				{AccountID: "987654321", AccountType: "checking", Currency: "USD", Amount: 5},
+			},
+		},
+		{
+			name:            "transfer",
+			transactionType: TransactionTypeTransfer,
+			src:             src,
+			dest:            dest,
+			expected: []Posting{
+				{AccountID: "123456789", AccountType: "savings", Currency: "USD", Amount: -5},
+				{AccountID: "987654321", AccountType: "checking", Currency: "USD", Amount: 5},
+			},
+		},
+		{
+			name:            "deposit",
+			transactionType: TransactionTypeDeposit,
+			dest:            dest,
+			expected: []Posting{
+				{AccountID: SystemAccountID, AccountType: "deposits", Currency: "USD", Amount: -5},
+				{AccountID: "987654321", AccountType: "checking", Currency: "USD", Amount: 5},
+			},
+		},
+		{
+			name:            "withdrawal",
+			transactionType: TransactionTypeWithdrawal,
+			src:             src,
+			expected: []Posting{
+				{AccountID: "123456789", AccountType: "savings", Currency: "USD", Amount: -5},
+				{AccountID: SystemAccountID, AccountType: "withdrawals", Currency: "USD", Amount: 5},
+			},
+		},
+		{
+			name:            "interest",
+			transactionType: TransactionTypeInterest,
+			dest:            dest,
+			expected: []Posting{
+				{AccountID: SystemAccountID, AccountType: "interest-expense", Currency: "USD", Amount: -5},
+				{AccountID: "987654321", AccountType: "checking", Currency: "USD", Amount: 5},
+			},
+		},
+		{
+			name:            "capture",
+			transactionType: TransactionTypeCapture,
+			src:             src,
+			dest:            dest,
+			expected: []Posting{
+				{AccountID: "123456789", AccountType: "savings", Currency: "USD", Amount: -5},
+				{AccountID: "987654321", AccountType: "checking", Currency: "USD", Amount: 5},
+			},
+		},
+		{
+			name:            "delete",
+			transactionType: TransactionTypeDelete,
+			src:             src,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// === When ===
+			tx := newTransaction(test.transactionType, 5, test.src, test.dest)
+
+			// === Then ===
+			assert.Equal(t, test.expected, tx.Postings)
+			assert.True(t, tx.balanced())
+		})
+	}
+}
+
+func TestJournalEntry_Conversion(t *testing.T) {
+	// === Given ===
+	tx := newTransaction(TransactionTypeTransfer, 500,
+		&TransactionParty{AccountID: "123456789", AccountType: "savings", Currency: "USD"},
+		&TransactionParty{AccountID: "987654321", AccountType: "checking", Currency: "JPY"})
+
+	// === When ===
+	tx.convert(740, nil)
+
+	// === Then ===
+	assert.Equal(t, []Posting{
+		{AccountID: "123456789", AccountType: "savings", Currency: "USD", Amount: -500},
+		{AccountID: SystemAccountID, AccountType: "fx-position", Currency: "USD", Amount: 500},
+		{AccountID: SystemAccountID, AccountType: "fx-position", Currency: "JPY", Amount: -740},
+		{AccountID: "987654321", AccountType: "checking", Currency: "JPY", Amount: 740},
+	}, tx.Postings)
+	assert.True(t, tx.balanced())
+	assert.Equal(t, 0, tx.postedAmount(SystemAccountFXPosition, "EUR"))
+	assert.Equal(t, 740, tx.postedAmount(AccountKey{AccountID: "987654321", AccountType: "checking"}, "JPY"))
+}
+
+func TestJournalEntry_Fees(t *testing.T) {
+	// === Given ===
+	tx := newTransaction(TransactionTypeTransfer, 500,
+		&TransactionParty{AccountID: "123456789", AccountType: "savings", Currency: "USD"},
+		&TransactionParty{AccountID: "987654321", AccountType: "checking", Currency: "USD"})
+
+	// === When ===
+	tx.chargeFees([]FeeCharge{{Type: FeeTypeCrossOwner, Amount: 25}, {Type: FeeTypeBelowMinimumBalance, Amount: 10}})
+
+	// === Then ===
+	assert.Equal(t, []Posting{
+		{AccountID: "123456789", AccountType: "savings", Currency: "USD", Amount: -500},
+		{AccountID: "987654321", AccountType: "checking", Currency: "USD", Amount: 500},
+		{AccountID: "123456789", AccountType: "savings", Currency: "USD", Amount: -35},
+		{AccountID: SystemAccountID, AccountType: "fee-income", Currency: "USD", Amount: 35},
+	}, tx.Postings)
+	assert.True(t, tx.balanced())
+	assert.Equal(t, -535, tx.postedAmount(AccountKey{AccountID: "123456789", AccountType: "savings"}, "USD"))
+}
+
+func TestTransaction_LegacyItemWithoutPostings(t *testing.T) {
+	// === Given ===
+	tx := newTransaction(TransactionTypeTransfer, 5,
+		&TransactionParty{AccountID: "123456789", AccountType: "savings"},
+		&TransactionParty{AccountID: "987654321", AccountType: "checking"})
+	item := tx.toItem("123456789")
+	delete(item, postingsAttr)
+
+	// === When ===
+	parsed, err := NewTransactionFromItem(item)
+
+	// === Then ===
+	assert.NoError(t, err)
+	assert.Equal(t, tx.Postings, parsed.Postings)
+}
+
+func TestReconcile(t *testing.T) {
+	// === Given ===
+	key := AccountKey{AccountID: "123456789", AccountType: "savings"}
+	create := newTransaction(TransactionTypeCreate, 10, nil, &TransactionParty{AccountID: "123456789", AccountType: "savings"})
+	transfer := newTransaction(TransactionTypeTransfer, 4,
+		&TransactionParty{AccountID: "123456789", AccountType: "savings"},
+		&TransactionParty{AccountID: "123456789", AccountType: "checking"})
+	unbalanced := newTransaction(TransactionTypeDeposit, 1, nil, &TransactionParty{AccountID: "123456789", AccountType: "savings"})
+	unbalanced.Postings = unbalanced.Postings[1:]
+
+	// === Then ===
+	assert.Equal(t, ReconcileOutput{
+		Currency:      "USD",
+		Balance:       aws.Int(6),
+		PostedBalance: 6,
+		Reconciled:    true,
+	}, reconcile(key, "USD", aws.Int(6), []Transaction{create, transfer}))
+
+	assert.Equal(t, ReconcileOutput{
+		Currency:      "USD",
+		Balance:       aws.Int(7),
+		PostedBalance: 6,
+		Reconciled:    false,
+	}, reconcile(key, "USD", aws.Int(7), []Transaction{create, transfer}))
+
+	assert.Equal(t, ReconcileOutput{
+		Currency:                 "USD",
+		Balance:                  aws.Int(7),
+		PostedBalance:            7,
+		UnbalancedTransactionIDs: []string{unbalanced.TransactionID},
+		Reconciled:               false,
+	}, reconcile(key, "USD", aws.Int(7), []Transaction{create, transfer, unbalanced}))
+
+	assert.Equal(t, ReconcileOutput{
+		Currency:      "USD",
+		PostedBalance: -10,
+		Reconciled:    true,
+	}, reconcile(SystemAccountOpeningBalances, "USD", nil, []Transaction{create}))
+}
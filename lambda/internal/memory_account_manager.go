@@ -0,0 +1,1011 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang.org/x/exp/slices"
+	"log"
+	"sync"
+	"time"
+)
+
+// NewInMemoryAccountManager returns an AccountManager which keeps all state in memory. It mirrors the semantics of the
+// DynamoDB implementation and is intended for local development and tests.
+func NewInMemoryAccountManager(rates RateProvider) AccountManager {
+	return &inMemoryAccountManager{
+		rates:              rates,
+		accounts:           make(map[AccountKey]account),
+		transactions:       make(map[string][]Transaction),
+		idempotencyRecords: make(map[inMemoryIdempotencyKey]inMemoryIdempotencyRecord),
+		quotes:             make(map[inMemoryQuoteKey]Quote),
+		schedules:          make(map[string]Schedule),
+		executions:         make(map[string][]ScheduleExecution),
+		products:           make(map[string]Product),
+	}
+}
+
+type inMemoryAccountManager struct {
+	rates RateProvider
+
+	mu       sync.Mutex
+	accounts map[AccountKey]account
+	// Transactions by owner account ID, in the order they were recorded
+	transactions       map[string][]Transaction
+	idempotencyRecords map[inMemoryIdempotencyKey]inMemoryIdempotencyRecord
+	quotes             map[inMemoryQuoteKey]Quote
+	// Schedules by ID, and the executions of each schedule in the order they were recorded
+	schedules  map[string]Schedule
+	executions map[string][]ScheduleExecution
+	// Products of the catalog by account type
+	products map[string]Product
+}
+
+type inMemoryQuoteKey struct {
+	accountID string
+	quoteID   string
+}
+
+type inMemoryIdempotencyKey struct {
+	accountID      string
+	idempotencyKey string
+}
+
+type inMemoryIdempotencyRecord struct {
+	idempotencyRecord
+	expiresAt time.Time
+}
+
+func (manager *inMemoryAccountManager) CreateAccount(_ context.Context, accountID string, createAccountInput CreateAccountInput) error {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	product, ok := manager.products[createAccountInput.AccountType]
+	if !ok {
+		return ProductNotFoundError{AccountType: createAccountInput.AccountType}
+	}
+	if err := product.checkAccount(createAccountInput); err != nil {
+		return err
+	}
+
+	key := AccountKey{
+		AccountID:   accountID,
+		AccountType: createAccountInput.AccountType,
+	}
+	// A closed account is replaced by the new account, while its transactions are kept
+	if existing, ok := manager.accounts[key]; ok && existing.status != AccountStatusClosed {
+		return AccountAlreadyExistsError{
+			AccountID:   accountID,
+			AccountType: createAccountInput.AccountType,
+		}
+	}
+
+	manager.accounts[key] = account{
+		balance:        *createAccountInput.InitialBalance,
+		currency:       currencyOrDefault(createAccountInput.Currency),
+		overdraftLimit: createAccountInput.OverdraftLimit,
+		status:         AccountStatusActive,
+	}
+	manager.recordTransaction(newTransaction(TransactionTypeCreate, *createAccountInput.InitialBalance, nil, &TransactionParty{
+		AccountID:   accountID,
+		AccountType: createAccountInput.AccountType,
+		Currency:    currencyOrDefault(createAccountInput.Currency),
+		Balance:     *createAccountInput.InitialBalance,
+	}))
+	return nil
+}
+
+func (manager *inMemoryAccountManager) DeleteAccount(_ context.Context, accountID string, deleteAccountInput DeleteAccountInput) error {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	key := AccountKey{
+		AccountID:   accountID,
+		AccountType: deleteAccountInput.AccountType,
+	}
+	existing, ok := manager.accounts[key]
+	if !ok {
+		// Succeed if the account doesn't exist to simplify error handling and allow for idempotent calls
+		return nil
+	}
+	if err := existing.checkDelete(key); err != nil {
+		return err
+	}
+
+	delete(manager.accounts, key)
+	manager.recordTransaction(newTransaction(TransactionTypeDelete, 0, &TransactionParty{
+		AccountID:   accountID,
+		AccountType: deleteAccountInput.AccountType,
+	}, nil))
+	return nil
+}
+
+func (manager *inMemoryAccountManager) CloseAccount(_ context.Context, accountID string, closeAccountInput CloseAccountInput) (CloseAccountOutput, error) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	key := AccountKey{
+		AccountID:   accountID,
+		AccountType: closeAccountInput.AccountType,
+	}
+	destKey := AccountKey{
+		AccountID:   closeAccountInput.DestAccountID,
+		AccountType: closeAccountInput.DestAccountType,
+	}
+	account, ok := manager.accounts[key]
+	if !ok || account.status == AccountStatusClosed {
+		// Succeed if the account doesn't exist or is already closed to allow for idempotent calls
+		return CloseAccountOutput{}, nil
+	}
+	if key == destKey {
+		return CloseAccountOutput{}, SelfTransferError(key)
+	}
+	dest, ok := manager.accounts[destKey]
+	if !ok {
+		return CloseAccountOutput{}, AccountDoesNotExistError{
+			AccountID:   destKey.AccountID,
+			AccountType: destKey.AccountType,
+		}
+	}
+	account, interest := account.postAccruedInterest(key)
+	tx, err := account.sweep(key, destKey, dest)
+	if err != nil {
+		return CloseAccountOutput{}, err
+	}
+
+	if tx.Dest != nil {
+		dest.balance = tx.Dest.Balance
+		manager.accounts[destKey] = dest
+	}
+	account.balance = 0
+	account.status = AccountStatusClosed
+	manager.accounts[key] = account
+	if interest != nil {
+		manager.recordTransaction(*interest)
+	}
+	manager.recordTransaction(tx)
+	return CloseAccountOutput{
+		Transaction: &tx,
+		Interest:    interest,
+	}, nil
+}
+
+func (manager *inMemoryAccountManager) Transfer(_ context.Context, srcAccountID string, transferInput TransferInput) (TransferOutput, error) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	requestHash := transferInput.requestHash()
+	idempotencyKey := inMemoryIdempotencyKey{
+		accountID:      srcAccountID,
+		idempotencyKey: transferInput.IdempotencyKey,
+	}
+	if transferInput.IdempotencyKey != "" {
+		record, ok := manager.idempotencyRecords[idempotencyKey]
+		if ok && time.Now().Before(record.expiresAt) {
+			return record.replay(transferInput.IdempotencyKey, requestHash)
+		}
+	}
+
+	occurrence := transferInput.occurrence
+	if occurrence != nil {
+		existing := manager.schedules[occurrence.ScheduleID]
+		if existing.Occurrences != occurrence.Occurrences || existing.Status != occurrence.Status {
+			return TransferOutput{}, errOccurrenceExecuted
+		}
+	}
+
+	plan, err := manager.planTransfer(srcAccountID, transferInput)
+	if err != nil {
+		return TransferOutput{}, err
+	}
+	tx := plan.tx
+	delete(manager.quotes, inMemoryQuoteKey{accountID: srcAccountID, quoteID: transferInput.QuoteID})
+
+	src, dest := plan.src, plan.dest
+	src.balance = tx.Src.Balance
+	dest.balance = tx.Dest.Balance
+	manager.accounts[plan.srcKey] = src
+	manager.accounts[plan.destKey] = dest
+	manager.recordTransaction(tx)
+	if transferInput.IdempotencyKey != "" {
+		manager.idempotencyRecords[idempotencyKey] = inMemoryIdempotencyRecord{
+			idempotencyRecord: idempotencyRecord{
+				requestHash: requestHash,
+				transaction: tx,
+			},
+			expiresAt: tx.Timestamp.Add(idempotencyKeyTTL),
+		}
+	}
+	if occurrence != nil {
+		manager.schedules[occurrence.ScheduleID] = occurrence.advance()
+		manager.executions[occurrence.ScheduleID] = append(manager.executions[occurrence.ScheduleID], occurrence.succeeded(tx))
+	}
+
+	return TransferOutput{
+		Transaction: tx,
+	}, nil
+}
+
+// planTransfer checks that a transfer may be made and prices its fees, without changing anything. The caller must hold
+// the lock.
+func (manager *inMemoryAccountManager) planTransfer(srcAccountID string, transferInput TransferInput) (transferPlan, error) {
+	srcKey := AccountKey{
+		AccountID:   srcAccountID,
+		AccountType: transferInput.SrcAccountType,
+	}
+	destKey := AccountKey{
+		AccountID:   transferInput.DestAccountID,
+		AccountType: transferInput.DestAccountType,
+	}
+	amount := *transferInput.Amount
+	if srcKey == destKey {
+		return transferPlan{}, SelfTransferError(srcKey)
+	}
+
+	src, ok := manager.accounts[srcKey]
+	if !ok {
+		return transferPlan{}, SourceAccountDoesNotExistError{
+			AccountID:   srcKey.AccountID,
+			AccountType: srcKey.AccountType,
+		}
+	}
+	if err := src.checkDebit(srcKey); err != nil {
+		return transferPlan{}, err
+	}
+	// Accounts created before the catalog, whose type is not in it, transfer without rules or fees
+	product, ok := manager.products[srcKey.AccountType]
+	if ok {
+		if err := product.checkTransfer(srcAccountID, transferInput); err != nil {
+			return transferPlan{}, err
+		}
+	}
+	if err := src.checkCurrency(srcKey, transferInput.Currency); err != nil {
+		return transferPlan{}, err
+	}
+	if err := src.checkFunds(srcKey, amount); err != nil {
+		return transferPlan{}, err
+	}
+
+	dest, ok := manager.accounts[destKey]
+	if !ok {
+		return transferPlan{}, AccountDoesNotExistError{
+			AccountID:   destKey.AccountID,
+			AccountType: destKey.AccountType,
+		}
+	}
+	if err := dest.checkCredit(destKey); err != nil {
+		return transferPlan{}, err
+	}
+
+	var quote *Quote
+	if transferInput.QuoteID != "" {
+		found, ok := manager.quotes[inMemoryQuoteKey{accountID: srcAccountID, quoteID: transferInput.QuoteID}]
+		if !ok || !time.Now().Before(found.ExpiresAt) {
+			return transferPlan{}, QuoteNotFoundError{QuoteID: transferInput.QuoteID}
+		}
+		quote = &found
+	}
+	destAmount, err := transferInput.creditedAmount(src.currency, dest.currency, quote)
+	if err != nil {
+		return transferPlan{}, err
+	}
+
+	converted := src.currency != dest.currency
+	fees := product.Fees.charges(srcAccountID, src.balance, transferInput, converted)
+	if err := src.checkFunds(srcKey, amount+totalFees(fees)); err != nil {
+		return transferPlan{}, err
+	}
+
+	tx := newTransaction(TransactionTypeTransfer, amount, &TransactionParty{
+		AccountID:   srcKey.AccountID,
+		AccountType: srcKey.AccountType,
+		Currency:    src.currency,
+		Balance:     src.balance - amount - totalFees(fees),
+	}, &TransactionParty{
+		AccountID:   destKey.AccountID,
+		AccountType: destKey.AccountType,
+		Currency:    dest.currency,
+		Balance:     dest.balance + destAmount,
+	})
+	if converted {
+		tx.convert(destAmount, quote)
+	}
+	tx.chargeFees(fees)
+
+	return transferPlan{
+		srcKey:  srcKey,
+		destKey: destKey,
+		src:     src,
+		dest:    dest,
+		quote:   quote,
+		tx:      tx,
+	}, nil
+}
+
+func (manager *inMemoryAccountManager) QuoteTransfer(_ context.Context, srcAccountID string, transferInput TransferInput) (QuoteTransferOutput, error) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	plan, err := manager.planTransfer(srcAccountID, transferInput)
+	if err != nil {
+		return QuoteTransferOutput{}, err
+	}
+	return newQuoteTransferOutput(plan.tx), nil
+}
+
+func (manager *inMemoryAccountManager) Deposit(_ context.Context, depositInput DepositInput) (DepositOutput, error) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	key := AccountKey{
+		AccountID:   depositInput.AccountID,
+		AccountType: depositInput.AccountType,
+	}
+	tx, err := manager.adjustBalance(key, *depositInput.Amount, depositInput.Currency, TransactionTypeDeposit, depositInput.ExternalReference)
+	return DepositOutput{
+		Transaction: tx,
+	}, err
+}
+
+func (manager *inMemoryAccountManager) Withdraw(_ context.Context, withdrawInput WithdrawInput) (WithdrawOutput, error) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	key := AccountKey{
+		AccountID:   withdrawInput.AccountID,
+		AccountType: withdrawInput.AccountType,
+	}
+	tx, err := manager.adjustBalance(key, -*withdrawInput.Amount, withdrawInput.Currency, TransactionTypeWithdrawal, withdrawInput.ExternalReference)
+	return WithdrawOutput{
+		Transaction: tx,
+	}, err
+}
+
+func (manager *inMemoryAccountManager) adjustBalance(key AccountKey, delta int, currency string, transactionType TransactionType, externalReference string) (Transaction, error) {
+	account, ok := manager.accounts[key]
+	if !ok {
+		return Transaction{}, AccountDoesNotExistError{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
+		}
+	}
+	if err := account.checkCurrency(key, currency); err != nil {
+		return Transaction{}, err
+	}
+	if delta < 0 {
+		if err := account.checkDebit(key); err != nil {
+			return Transaction{}, err
+		}
+		if err := account.checkFunds(key, -delta); err != nil {
+			return Transaction{}, err
+		}
+	} else if err := account.checkCredit(key); err != nil {
+		return Transaction{}, err
+	}
+
+	party := &TransactionParty{
+		AccountID:   key.AccountID,
+		AccountType: key.AccountType,
+		Currency:    account.currency,
+		Balance:     account.balance + delta,
+	}
+	var tx Transaction
+	if delta < 0 {
+		tx = newTransaction(transactionType, -delta, party, nil)
+	} else {
+		tx = newTransaction(transactionType, delta, nil, party)
+	}
+	tx.ExternalReference = externalReference
+
+	account.balance += delta
+	manager.accounts[key] = account
+	manager.recordTransaction(tx)
+	return tx, nil
+}
+
+func (manager *inMemoryAccountManager) GetBalance(_ context.Context, accountID string, getBalanceInput GetBalanceInput) (GetBalanceOutput, error) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	account, ok := manager.accounts[AccountKey{
+		AccountID:   accountID,
+		AccountType: getBalanceInput.AccountType,
+	}]
+	if !ok {
+		return GetBalanceOutput{}, AccountDoesNotExistError{
+			AccountID:   accountID,
+			AccountType: getBalanceInput.AccountType,
+		}
+	}
+
+	return account.toGetBalanceOutput(), nil
+}
+
+func (manager *inMemoryAccountManager) ListAccounts(_ context.Context, accountID string, listAccountsInput ListAccountsInput) (ListAccountsOutput, error) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	// Like a DynamoDB query, accounts are returned in order of their sort key
+	return manager.listAccounts(listAccountsInput, func(key AccountKey) bool {
+		return key.AccountID == accountID
+	})
+}
+
+func (manager *inMemoryAccountManager) ListAccountsAdmin(_ context.Context, listAccountsInput ListAccountsInput) (ListAccountsOutput, error) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	return manager.listAccounts(listAccountsInput, func(AccountKey) bool {
+		return true
+	})
+}
+
+func (manager *inMemoryAccountManager) listAccounts(listAccountsInput ListAccountsInput, include func(AccountKey) bool) (ListAccountsOutput, error) {
+	if listAccountsInput.Limit != nil && *listAccountsInput.Limit < 1 {
+		return ListAccountsOutput{}, errors.New("limit must be greater than or equal to 1")
+	}
+
+	var keys []AccountKey
+	for key := range manager.accounts {
+		if include(key) {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortFunc(keys, func(a, b AccountKey) bool {
+		return a.AccountID < b.AccountID || (a.AccountID == b.AccountID && a.AccountType < b.AccountType)
+	})
+
+	if startKey := listAccountsInput.ExclusiveStartKey; startKey != nil {
+		start := 0
+		for start < len(keys) && !(keys[start].AccountID > startKey.AccountID ||
+			(keys[start].AccountID == startKey.AccountID && keys[start].AccountType > startKey.AccountType)) {
+			start++
+		}
+		keys = keys[start:]
+	}
+
+	var lastEvaluatedKey AccountKey
+	if limit := listAccountsInput.Limit; limit != nil && len(keys) >= int(*limit) {
+		// DynamoDB returns a LastEvaluatedKey whenever the limit is reached, even if no items remain
+		keys = keys[:*limit]
+		lastEvaluatedKey = keys[len(keys)-1]
+	}
+
+	return ListAccountsOutput{
+		Accounts:         keys,
+		LastEvaluatedKey: lastEvaluatedKey,
+	}, nil
+}
+
+func (manager *inMemoryAccountManager) ListTransactions(_ context.Context, accountID string, listTransactionsInput ListTransactionsInput) (ListTransactionsOutput, error) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	if listTransactionsInput.Limit != nil && *listTransactionsInput.Limit < 1 {
+		return ListTransactionsOutput{}, errors.New("limit must be greater than or equal to 1")
+	}
+
+	// Most recent first
+	var transactions []Transaction
+	recorded := manager.transactions[accountID]
+	for i := len(recorded) - 1; i >= 0; i-- {
+		startKey := listTransactionsInput.ExclusiveStartKey
+		if startKey != nil && recorded[i].TransactionID >= startKey.TransactionID {
+			continue
+		}
+		transactions = append(transactions, recorded[i])
+	}
+
+	var lastEvaluatedKey TransactionKey
+	if limit := listTransactionsInput.Limit; limit != nil && len(transactions) >= int(*limit) {
+		transactions = transactions[:*limit]
+		lastEvaluatedKey = TransactionKey{
+			AccountID:     accountID,
+			TransactionID: transactions[len(transactions)-1].TransactionID,
+		}
+	}
+
+	return ListTransactionsOutput{
+		Transactions:     transactions,
+		LastEvaluatedKey: lastEvaluatedKey,
+	}, nil
+}
+
+func (manager *inMemoryAccountManager) Reconcile(_ context.Context, reconcileInput ReconcileInput) (ReconcileOutput, error) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	key := AccountKey{
+		AccountID:   reconcileInput.AccountID,
+		AccountType: reconcileInput.AccountType,
+	}
+
+	partition, date, err := reconcilePartition(reconcileInput)
+	if err != nil {
+		return ReconcileOutput{}, err
+	}
+	transactions := manager.transactions[partition]
+	if key.AccountID == SystemAccountID {
+		output := reconcile(key, currencyOrDefault(reconcileInput.Currency), nil, transactions)
+		output.Date = date
+		return output, nil
+	}
+
+	account, ok := manager.accounts[key]
+	if !ok {
+		return ReconcileOutput{}, AccountDoesNotExistError{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
+		}
+	}
+	if err := account.checkCurrency(key, reconcileInput.Currency); err != nil {
+		return ReconcileOutput{}, err
+	}
+	return reconcile(key, account.currency, &account.balance, transactions), nil
+}
+
+func (manager *inMemoryAccountManager) Quote(ctx context.Context, accountID string, quoteInput QuoteInput) (QuoteOutput, error) {
+	if quoteInput.SrcCurrency == quoteInput.DestCurrency {
+		return QuoteOutput{}, UnexpectedConversionError{Currency: quoteInput.SrcCurrency}
+	}
+
+	// The rate is looked up without holding the lock, as the provider may be remote
+	rate, err := manager.rates.GetRate(ctx, quoteInput.SrcCurrency, quoteInput.DestCurrency)
+	if err != nil {
+		return QuoteOutput{}, err
+	}
+	quote, err := newQuote(*quoteInput.Amount, quoteInput.SrcCurrency, quoteInput.DestCurrency, rate)
+	if err != nil {
+		return QuoteOutput{}, err
+	}
+
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	manager.quotes[inMemoryQuoteKey{accountID: accountID, quoteID: quote.QuoteID}] = quote
+	return QuoteOutput{
+		Quote: quote,
+	}, nil
+}
+
+func (manager *inMemoryAccountManager) SetOverdraftLimit(_ context.Context, setOverdraftLimitInput SetOverdraftLimitInput) error {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	key := AccountKey{
+		AccountID:   setOverdraftLimitInput.AccountID,
+		AccountType: setOverdraftLimitInput.AccountType,
+	}
+	if product, ok := manager.products[key.AccountType]; ok {
+		if err := product.checkOverdraftLimit(*setOverdraftLimitInput.OverdraftLimit); err != nil {
+			return err
+		}
+	}
+	account, ok := manager.accounts[key]
+	if !ok {
+		return AccountDoesNotExistError{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
+		}
+	}
+
+	account.overdraftLimit = *setOverdraftLimitInput.OverdraftLimit
+	manager.accounts[key] = account
+	return nil
+}
+
+func (manager *inMemoryAccountManager) SetAccountStatus(_ context.Context, setAccountStatusInput SetAccountStatusInput) error {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	key := AccountKey{
+		AccountID:   setAccountStatusInput.AccountID,
+		AccountType: setAccountStatusInput.AccountType,
+	}
+	account, ok := manager.accounts[key]
+	if !ok {
+		return AccountDoesNotExistError{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
+		}
+	}
+	status := setAccountStatusInput.Status
+	if err := account.checkStatusChange(key, status); err != nil {
+		return err
+	}
+
+	account.status = status
+	manager.accounts[key] = account
+	return nil
+}
+
+func (manager *inMemoryAccountManager) recordTransaction(tx Transaction) {
+	for _, accountID := range tx.partitions() {
+		manager.transactions[accountID] = append(manager.transactions[accountID], tx)
+	}
+}
+
+func (manager *inMemoryAccountManager) PlaceHold(_ context.Context, placeHoldInput PlaceHoldInput) (PlaceHoldOutput, error) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	key := AccountKey{
+		AccountID:   placeHoldInput.AccountID,
+		AccountType: placeHoldInput.AccountType,
+	}
+	destKey := AccountKey{
+		AccountID:   placeHoldInput.DestAccountID,
+		AccountType: placeHoldInput.DestAccountType,
+	}
+	if key == destKey {
+		return PlaceHoldOutput{}, fmt.Errorf("cannot place a hold on %s:%s for itself", key.AccountID, key.AccountType)
+	}
+
+	account, ok := manager.accounts[key]
+	if !ok {
+		return PlaceHoldOutput{}, AccountDoesNotExistError{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
+		}
+	}
+	dest, ok := manager.accounts[destKey]
+	if !ok {
+		return PlaceHoldOutput{}, AccountDoesNotExistError{
+			AccountID:   destKey.AccountID,
+			AccountType: destKey.AccountType,
+		}
+	}
+	if err := checkHoldStatuses(key, account, destKey, dest); err != nil {
+		return PlaceHoldOutput{}, err
+	}
+	hold, err := account.placeHold(key, dest, placeHoldInput)
+	if err != nil {
+		return PlaceHoldOutput{}, err
+	}
+
+	account.holds = withHold(account.activeHolds(), hold)
+	manager.accounts[key] = account
+	return PlaceHoldOutput{
+		Hold: hold,
+	}, nil
+}
+
+func (manager *inMemoryAccountManager) CaptureHold(_ context.Context, captureHoldInput CaptureHoldInput) (CaptureHoldOutput, error) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	key := AccountKey{
+		AccountID:   captureHoldInput.AccountID,
+		AccountType: captureHoldInput.AccountType,
+	}
+	account, ok := manager.accounts[key]
+	if !ok {
+		return CaptureHoldOutput{}, AccountDoesNotExistError{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
+		}
+	}
+	hold, err := account.hold(key, captureHoldInput.HoldID)
+	if err != nil {
+		return CaptureHoldOutput{}, err
+	}
+	amount, remaining, err := hold.capture(captureHoldInput.Amount)
+	if err != nil {
+		return CaptureHoldOutput{}, err
+	}
+	dest, ok := manager.accounts[hold.destKey()]
+	if !ok {
+		return CaptureHoldOutput{}, AccountDoesNotExistError{
+			AccountID:   hold.DestAccountID,
+			AccountType: hold.DestAccountType,
+		}
+	}
+	if err := checkHoldStatuses(key, account, hold.destKey(), dest); err != nil {
+		return CaptureHoldOutput{}, err
+	}
+
+	tx := newCaptureTransaction(key, account, hold, dest, amount)
+	account.holds = withHold(account.activeHolds(), remaining)
+	account.balance -= amount
+	dest.balance += amount
+	manager.accounts[key] = account
+	manager.accounts[hold.destKey()] = dest
+	manager.recordTransaction(tx)
+	return CaptureHoldOutput{
+		Transaction: tx,
+		Hold:        remaining,
+	}, nil
+}
+
+func (manager *inMemoryAccountManager) ReleaseHold(_ context.Context, releaseHoldInput ReleaseHoldInput) error {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	key := AccountKey{
+		AccountID:   releaseHoldInput.AccountID,
+		AccountType: releaseHoldInput.AccountType,
+	}
+	account, ok := manager.accounts[key]
+	if !ok {
+		return AccountDoesNotExistError{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
+		}
+	}
+	if _, err := account.hold(key, releaseHoldInput.HoldID); err != nil {
+		return err
+	}
+
+	account.holds = account.activeHolds()
+	delete(account.holds, releaseHoldInput.HoldID)
+	manager.accounts[key] = account
+	return nil
+}
+
+func (manager *inMemoryAccountManager) CreateSchedule(_ context.Context, accountID string, createScheduleInput CreateScheduleInput) (CreateScheduleOutput, error) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	srcKey, destKey := createScheduleInput.accountKeys(accountID)
+	if srcKey == destKey {
+		return CreateScheduleOutput{}, SelfTransferError(srcKey)
+	}
+	src, ok := manager.accounts[srcKey]
+	if !ok {
+		return CreateScheduleOutput{}, SourceAccountDoesNotExistError(srcKey)
+	}
+	dest, ok := manager.accounts[destKey]
+	if !ok {
+		return CreateScheduleOutput{}, AccountDoesNotExistError(destKey)
+	}
+	if err := createScheduleInput.checkAccounts(srcKey, src, dest); err != nil {
+		return CreateScheduleOutput{}, err
+	}
+	if createScheduleInput.Currency == "" {
+		createScheduleInput.Currency = src.currency
+	}
+
+	schedule := newSchedule(accountID, createScheduleInput)
+	manager.schedules[schedule.ScheduleID] = schedule
+	return CreateScheduleOutput{
+		Schedule: schedule,
+	}, nil
+}
+
+func (manager *inMemoryAccountManager) ListSchedules(_ context.Context, accountID string, listSchedulesInput ListSchedulesInput) (ListSchedulesOutput, error) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	if listSchedulesInput.Limit != nil && *listSchedulesInput.Limit < 1 {
+		return ListSchedulesOutput{}, errors.New("limit must be greater than or equal to 1")
+	}
+
+	// Like a DynamoDB query, schedules are returned in order of their sort key
+	var schedules []Schedule
+	for _, schedule := range manager.schedules {
+		startKey := listSchedulesInput.ExclusiveStartKey
+		if schedule.AccountID != accountID || (startKey != nil && schedule.ScheduleID <= startKey.ScheduleID) {
+			continue
+		}
+		schedules = append(schedules, schedule)
+	}
+	slices.SortFunc(schedules, func(a, b Schedule) bool {
+		return a.ScheduleID < b.ScheduleID
+	})
+
+	var lastEvaluatedKey ScheduleKey
+	if limit := listSchedulesInput.Limit; limit != nil && len(schedules) >= int(*limit) {
+		schedules = schedules[:*limit]
+		lastEvaluatedKey = ScheduleKey{
+			AccountID:  accountID,
+			ScheduleID: schedules[len(schedules)-1].ScheduleID,
+		}
+	}
+
+	return ListSchedulesOutput{
+		Schedules:        schedules,
+		LastEvaluatedKey: lastEvaluatedKey,
+	}, nil
+}
+
+func (manager *inMemoryAccountManager) CancelSchedule(_ context.Context, accountID string, cancelScheduleInput CancelScheduleInput) error {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	schedule, ok := manager.schedules[cancelScheduleInput.ScheduleID]
+	if !ok || schedule.AccountID != accountID {
+		return ScheduleNotFoundError{ScheduleID: cancelScheduleInput.ScheduleID}
+	}
+	if schedule.Status != ScheduleStatusActive {
+		return nil
+	}
+
+	schedule.Status = ScheduleStatusCancelled
+	schedule.NextRunAt = nil
+	manager.schedules[schedule.ScheduleID] = schedule
+	return nil
+}
+
+func (manager *inMemoryAccountManager) ListScheduleExecutions(_ context.Context, accountID string, listScheduleExecutionsInput ListScheduleExecutionsInput) (ListScheduleExecutionsOutput, error) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	if listScheduleExecutionsInput.Limit != nil && *listScheduleExecutionsInput.Limit < 1 {
+		return ListScheduleExecutionsOutput{}, errors.New("limit must be greater than or equal to 1")
+	}
+
+	scheduleID := listScheduleExecutionsInput.ScheduleID
+	if schedule, ok := manager.schedules[scheduleID]; !ok || schedule.AccountID != accountID {
+		return ListScheduleExecutionsOutput{}, ScheduleNotFoundError{ScheduleID: scheduleID}
+	}
+
+	// Most recent first
+	var executions []ScheduleExecution
+	recorded := manager.executions[scheduleID]
+	for i := len(recorded) - 1; i >= 0; i-- {
+		startOccurrence := listScheduleExecutionsInput.ExclusiveStartOccurrence
+		if startOccurrence != nil && recorded[i].Occurrence >= *startOccurrence {
+			continue
+		}
+		executions = append(executions, recorded[i])
+	}
+
+	var lastEvaluatedOccurrence *int
+	if limit := listScheduleExecutionsInput.Limit; limit != nil && len(executions) >= int(*limit) {
+		executions = executions[:*limit]
+		lastEvaluatedOccurrence = &executions[len(executions)-1].Occurrence
+	}
+
+	return ListScheduleExecutionsOutput{
+		Executions:              executions,
+		LastEvaluatedOccurrence: lastEvaluatedOccurrence,
+	}, nil
+}
+
+func (manager *inMemoryAccountManager) RunSchedules(ctx context.Context, runSchedulesInput RunSchedulesInput) (RunSchedulesOutput, error) {
+	limit := defaultScheduleRunLimit
+	if runSchedulesInput.Limit != nil {
+		limit = int(*runSchedulesInput.Limit)
+	}
+
+	// Like the index of due schedules, those due soonest are run first
+	now := time.Now()
+	var due []Schedule
+	manager.mu.Lock()
+	for _, schedule := range manager.schedules {
+		if schedule.Status == ScheduleStatusActive && !schedule.NextRunAt.After(now) {
+			due = append(due, schedule)
+		}
+	}
+	manager.mu.Unlock()
+	slices.SortFunc(due, func(a, b Schedule) bool {
+		return a.NextRunAt.Before(*b.NextRunAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	var runOutput RunSchedulesOutput
+	for _, schedule := range due {
+		// The lock is not held while transferring, as Transfer takes it too
+		execution, err := executeSchedule(ctx, manager, schedule)
+		if errors.Is(err, errOccurrenceExecuted) {
+			continue
+		}
+		if err != nil {
+			log.Printf("Failed to execute occurrence %d of schedule %s, which will be retried: %v", schedule.Occurrences, schedule.ScheduleID, err)
+			continue
+		}
+		if execution.Status == ExecutionStatusSucceeded {
+			// The transfer recorded its execution
+			runOutput.Executions = append(runOutput.Executions, execution)
+			continue
+		}
+
+		manager.mu.Lock()
+		if existing := manager.schedules[schedule.ScheduleID]; existing.Occurrences == schedule.Occurrences {
+			// The schedule may have been cancelled meanwhile, in which case it stays cancelled
+			schedule.Status = existing.Status
+			manager.schedules[schedule.ScheduleID] = schedule.advance()
+			manager.executions[schedule.ScheduleID] = append(manager.executions[schedule.ScheduleID], execution)
+			runOutput.Executions = append(runOutput.Executions, execution)
+		}
+		manager.mu.Unlock()
+	}
+
+	return runOutput, nil
+}
+
+func (manager *inMemoryAccountManager) AccrueProductInterest(_ context.Context, accrueProductInterestInput AccrueProductInterestInput) (ProductAccrual, error) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	product, ok := manager.products[accrueProductInterestInput.AccountType]
+	if !ok {
+		return ProductAccrual{}, ProductNotFoundError{AccountType: accrueProductInterestInput.AccountType}
+	}
+	accrual := ProductAccrual{AccountType: accrueProductInterestInput.AccountType}
+	if product.Interest == nil {
+		// The product pays no interest
+		return accrual, nil
+	}
+	terms := *product.Interest
+	rate, day, err := accrueProductInterestInput.parseAccrual(terms)
+	if err != nil {
+		return ProductAccrual{}, err
+	}
+
+	date := day.Format(interestDateLayout)
+	for key, account := range manager.accounts {
+		if key.AccountType != accrueProductInterestInput.AccountType || account.accruedThrough >= date ||
+			account.status == AccountStatusClosed {
+			continue
+		}
+		// Frozen accounts earn no interest, and the days they are frozen are not caught up once they are unfrozen
+		if account.status == AccountStatusFrozen {
+			account.accruedThrough = date
+			manager.accounts[key] = account
+			continue
+		}
+
+		posted := false
+		for _, accrualDay := range account.interestDays(day) {
+			accruedInterest, postedInterest := account.accrueInterest(terms, rate, accrualDay)
+			account.accruedInterest = accruedInterest
+			account.accruedThrough = accrualDay.Format(interestDateLayout)
+			account.balance += postedInterest
+
+			if postedInterest > 0 {
+				manager.recordTransaction(newTransaction(TransactionTypeInterest, postedInterest, nil, &TransactionParty{
+					AccountID:   key.AccountID,
+					AccountType: key.AccountType,
+					Currency:    account.currency,
+					Balance:     account.balance,
+				}))
+				posted = true
+			}
+		}
+		manager.accounts[key] = account
+		accrual.Accrued++
+		if posted {
+			accrual.Posted++
+		}
+	}
+	return accrual, nil
+}
+
+func (manager *inMemoryAccountManager) PutProduct(_ context.Context, product Product) error {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	// Copy the rules so that later changes by the caller don't change the catalog, ordered and without an empty list
+	// as DynamoDB reads back a string set
+	destAccountTypes := product.TransferRules.DestAccountTypes
+	product.TransferRules.DestAccountTypes = nil
+	if len(destAccountTypes) > 0 {
+		product.TransferRules.DestAccountTypes = slices.Clone(destAccountTypes)
+		slices.Sort(product.TransferRules.DestAccountTypes)
+	}
+	if product.Interest != nil {
+		interest := *product.Interest
+		product.Interest = &interest
+	}
+	manager.products[product.AccountType] = product
+	return nil
+}
+
+func (manager *inMemoryAccountManager) ListProducts(_ context.Context, listProductsInput ListProductsInput) (ListProductsOutput, error) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	output := ListProductsOutput{Products: []Product{}}
+	for _, product := range manager.products {
+		if !product.Retired || listProductsInput.IncludeRetired {
+			product.TransferRules.DestAccountTypes = slices.Clone(product.TransferRules.DestAccountTypes)
+			output.Products = append(output.Products, product)
+		}
+	}
+	slices.SortFunc(output.Products, func(a, b Product) bool {
+		return a.AccountType < b.AccountType
+	})
+	return output, nil
+}
@@ -0,0 +1,132 @@
+package internal
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+const (
+	testAccountID      = "123456789"
+	testOtherAccountID = "987654321"
+)
+
+func TestInMemoryAccountManagerConformance(t *testing.T) {
+	suite.Run(t, &AccountManagerConformanceSuite{NewAccountManager: NewInMemoryAccountManager})
+}
+
+func TestInMemoryAccountManager_Transfer(t *testing.T) {
+	// === Given ===
+	ctx := context.Background()
+	manager := newTestInMemoryAccountManager(t)
+	assert.NoError(t, manager.CreateAccount(ctx, testAccountID, CreateAccountInput{AccountType: "savings", InitialBalance: aws.Int(10)}))
+	assert.NoError(t, manager.CreateAccount(ctx, testOtherAccountID, CreateAccountInput{AccountType: "checking", InitialBalance: aws.Int(0)}))
+
+	// === When ===
+	output, err := manager.Transfer(ctx, testAccountID, TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   testOtherAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(4),
+	})
+
+	// === Then ===
+	assert.NoError(t, err)
+	assert.Equal(t, 6, output.Transaction.Src.Balance)
+	assert.Equal(t, 4, output.Transaction.Dest.Balance)
+
+	srcBalance, err := manager.GetBalance(ctx, testAccountID, GetBalanceInput{AccountType: "savings"})
+	assert.NoError(t, err)
+	assert.Equal(t, 6, srcBalance.Balance)
+
+	destTransactions, err := manager.ListTransactions(ctx, testOtherAccountID, ListTransactionsInput{})
+	assert.NoError(t, err)
+	assert.Len(t, destTransactions.Transactions, 2)
+	assert.Equal(t, output.Transaction, destTransactions.Transactions[0])
+}
+
+func TestInMemoryAccountManager_TransferIsIdempotent(t *testing.T) {
+	// === Given ===
+	ctx := context.Background()
+	manager := newTestInMemoryAccountManager(t)
+	assert.NoError(t, manager.CreateAccount(ctx, testAccountID, CreateAccountInput{AccountType: "savings", InitialBalance: aws.Int(10)}))
+	assert.NoError(t, manager.CreateAccount(ctx, testAccountID, CreateAccountInput{AccountType: "checking", InitialBalance: aws.Int(0)}))
+	input := TransferInput{
+		SrcAccountType:  "savings",
+		DestAccountID:   testAccountID,
+		DestAccountType: "checking",
+		Amount:          aws.Int(4),
+		IdempotencyKey:  "key",
+	}
+
+	// === When ===
+	first, err := manager.Transfer(ctx, testAccountID, input)
+	assert.NoError(t, err)
+	replay, err := manager.Transfer(ctx, testAccountID, input)
+	assert.NoError(t, err)
+	input.Amount = aws.Int(5)
+	_, conflictErr := manager.Transfer(ctx, testAccountID, input)
+
+	// === Then ===
+	assert.Equal(t, first, replay)
+	assert.ErrorAs(t, conflictErr, &IdempotencyKeyConflictError{})
+	balance, err := manager.GetBalance(ctx, testAccountID, GetBalanceInput{AccountType: "savings"})
+	assert.NoError(t, err)
+	assert.Equal(t, 6, balance.Balance)
+}
+
+func TestInMemoryAccountManager_DeleteAccount(t *testing.T) {
+	// === Given ===
+	ctx := context.Background()
+	manager := newTestInMemoryAccountManager(t)
+	assert.NoError(t, manager.CreateAccount(ctx, testAccountID, CreateAccountInput{AccountType: "savings", InitialBalance: aws.Int(10)}))
+	assert.NoError(t, manager.CreateAccount(ctx, testAccountID, CreateAccountInput{AccountType: "checking", InitialBalance: aws.Int(0)}))
+
+	// === Then ===
+	assert.ErrorAs(t, manager.DeleteAccount(ctx, testAccountID, DeleteAccountInput{AccountType: "savings"}), &NonZeroBalanceError{})
+	assert.NoError(t, manager.DeleteAccount(ctx, testAccountID, DeleteAccountInput{AccountType: "checking"}))
+	assert.NoError(t, manager.DeleteAccount(ctx, testAccountID, DeleteAccountInput{AccountType: "checking"}))
+	_, err := manager.GetBalance(ctx, testAccountID, GetBalanceInput{AccountType: "checking"})
+	assert.ErrorAs(t, err, &AccountDoesNotExistError{})
+}
+
+func TestInMemoryAccountManager_ListAccountsPagination(t *testing.T) {
+	// === Given ===
+	ctx := context.Background()
+	manager := newTestInMemoryAccountManager(t)
+	for _, accountType := range []string{"checking", "savings", "brokerage"} {
+		assert.NoError(t, manager.CreateAccount(ctx, testAccountID, CreateAccountInput{AccountType: accountType, InitialBalance: aws.Int(0)}))
+	}
+	assert.NoError(t, manager.CreateAccount(ctx, testOtherAccountID, CreateAccountInput{AccountType: "savings", InitialBalance: aws.Int(0)}))
+
+	// === When ===
+	firstPage, err := manager.ListAccounts(ctx, testAccountID, ListAccountsInput{Limit: aws.Int32(2)})
+	assert.NoError(t, err)
+	secondPage, err := manager.ListAccounts(ctx, testAccountID, ListAccountsInput{
+		ExclusiveStartKey: &firstPage.LastEvaluatedKey,
+		Limit:             aws.Int32(2),
+	})
+	assert.NoError(t, err)
+
+	// === Then ===
+	assert.Equal(t, []AccountKey{
+		{AccountID: testAccountID, AccountType: "brokerage"},
+		{AccountID: testAccountID, AccountType: "checking"},
+	}, firstPage.Accounts)
+	assert.Equal(t, []AccountKey{
+		{AccountID: testAccountID, AccountType: "savings"},
+	}, secondPage.Accounts)
+	assert.Equal(t, AccountKey{}, secondPage.LastEvaluatedKey)
+}
+
+// newTestInMemoryAccountManager returns an in-memory AccountManager whose catalog has the products of the tests'
+// accounts
+func newTestInMemoryAccountManager(t *testing.T) AccountManager {
+	manager := NewInMemoryAccountManager(NewStaticRateProvider(nil))
+	for _, accountType := range []string{"savings", "checking", "brokerage"} {
+		assert.NoError(t, manager.PutProduct(context.Background(), Product{AccountType: accountType}))
+	}
+	return manager
+}
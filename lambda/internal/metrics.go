@@ -0,0 +1,52 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const metricsNamespace = "BankingService"
+
+// metricsOutput receives metrics in the CloudWatch embedded metric format. Lambda forwards stdout to CloudWatch Logs,
+// which extracts the metrics from each line.
+var metricsOutput io.Writer = os.Stdout
+
+type metric struct {
+	name  string
+	unit  string
+	value float64
+}
+
+// emitMetrics writes metrics as a single embedded metric format log line, with the given dimensions
+func emitMetrics(dimensions map[string]string, metrics ...metric) {
+	dimensionNames := make([]string, 0, len(dimensions))
+	line := make(map[string]interface{})
+	for name, value := range dimensions {
+		dimensionNames = append(dimensionNames, name)
+		line[name] = value
+	}
+
+	definitions := make([]map[string]string, 0, len(metrics))
+	for _, m := range metrics {
+		definitions = append(definitions, map[string]string{"Name": m.name, "Unit": m.unit})
+		line[m.name] = m.value
+	}
+
+	line["_aws"] = map[string]interface{}{
+		"Timestamp": time.Now().UnixMilli(),
+		"CloudWatchMetrics": []map[string]interface{}{{
+			"Namespace":  metricsNamespace,
+			"Dimensions": [][]string{dimensionNames},
+			"Metrics":    definitions,
+		}},
+	}
+
+	encoded, err := json.Marshal(line)
+	if err != nil {
+		return
+	}
+	_, _ = fmt.Fprintln(metricsOutput, string(encoded))
+}
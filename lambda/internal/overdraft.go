@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"strconv"
+)
+
+// Attribute of the overdraft limit of an account item, which accounts without an overdraft do not have
+const overdraftLimitAttr = "OverdraftLimit"
+
+// SetOverdraftLimitInput sets how far below zero the balance of an account may be debited. Overdraft limits are set by
+// admins on behalf of the account's owner, so the account ID is part of the input.
+type SetOverdraftLimitInput struct {
+	AccountID   string `json:"accountID" validate:"required"`
+	AccountType string `json:"accountType" validate:"required"`
+	// Use pointer for OverdraftLimit to ensure that it's explicitly defined
+	OverdraftLimit *int `json:"overdraftLimit" validate:"required,gte=0"`
+}
+
+// availableBalance returns the amount which may be debited from the account, including its overdraft headroom and
+// excluding the funds reserved by holds
+func (account account) availableBalance() int {
+	return account.balance + account.overdraftLimit - account.held()
+}
+
+// checkFunds returns an InsufficientFundsError unless amount may be debited from the account
+func (account account) checkFunds(key AccountKey, amount int) error {
+	if account.availableBalance() < amount {
+		return InsufficientFundsError{
+			AccountID:   key.AccountID,
+			AccountType: key.AccountType,
+		}
+	}
+	return nil
+}
+
+// overdraftCondition returns the condition which a debit leaving newBalance must also meet, i.e. that the overdraft
+// limit stored on the account covers a negative balance. Like the balance, the limit may have changed since the account
+// was read, so it is checked in the write rather than only up front.
+func overdraftCondition(newBalance int, exprAttrValues map[string]types.AttributeValue) string {
+	if newBalance >= 0 {
+		return ""
+	}
+	exprAttrValues[":overdrawn"] = &types.AttributeValueMemberN{Value: strconv.Itoa(-newBalance)}
+	// Accounts without an overdraft limit fail the comparison
+	return fmt.Sprintf(" AND %s >= :overdrawn", overdraftLimitAttr)
+}
+
+// SetOverdraftLimit changes the overdraft limit of an existing account, up to the overdraft allowance of its product.
+// Lowering the limit below the amount an account is already overdrawn by is allowed, and only prevents further debits.
+func (manager accountManagerImpl) SetOverdraftLimit(ctx context.Context, setOverdraftLimitInput SetOverdraftLimitInput) error {
+	key := AccountKey{
+		AccountID:   setOverdraftLimitInput.AccountID,
+		AccountType: setOverdraftLimitInput.AccountType,
+	}
+
+	// Accounts created before the catalog, whose type is not in it, have no allowance to stay within
+	product, found, err := manager.getProduct(ctx, key.AccountType)
+	if err != nil {
+		return err
+	}
+	if found {
+		if err := product.checkOverdraftLimit(*setOverdraftLimitInput.OverdraftLimit); err != nil {
+			return err
+		}
+	}
+
+	exprAttrValues := make(map[string]types.AttributeValue)
+	exprAttrValues[":limit"] = &types.AttributeValueMemberN{Value: strconv.Itoa(*setOverdraftLimitInput.OverdraftLimit)}
+
+	_, err = manager.ddb.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		Key:                       key.toAccountItem(),
+		TableName:                 aws.String(tableName),
+		UpdateExpression:          aws.String(fmt.Sprintf("SET %s = :limit", overdraftLimitAttr)),
+		ConditionExpression:       aws.String(fmt.Sprintf("attribute_exists(%s)", accountIDAttr)),
+		ExpressionAttributeValues: exprAttrValues,
+	})
+	if err != nil {
+		var conditionalCheckFailedException *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionalCheckFailedException) {
+			return AccountDoesNotExistError{
+				AccountID:   key.AccountID,
+				AccountType: key.AccountType,
+			}
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,331 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"golang.org/x/exp/slices"
+	"regexp"
+	"strconv"
+)
+
+// Table of the product catalog, which has an item for each account type keyed by AccountType
+const productsTableName = "products-table"
+
+const (
+	descriptionAttr           = "Description"
+	minimumOpeningBalanceAttr = "MinimumOpeningBalance"
+	overdraftAllowanceAttr    = "OverdraftAllowance"
+	maxTransferAmountAttr     = "MaxTransferAmount"
+	ownAccountsOnlyAttr       = "OwnAccountsOnly"
+	destAccountTypesAttr      = "DestAccountTypes"
+	retiredAttr               = "Retired"
+	interestAttr              = "Interest"
+	apyAttr                   = "APY"
+	dayCountAttr              = "DayCount"
+	compoundingAttr           = "Compounding"
+)
+
+// Account types are lowercase words separated by hyphens, so that "savings", "Savings" and "savings " can't be
+// different products. The length is well within the limit of a DynamoDB sort key.
+var accountTypePattern = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+
+const maxAccountTypeLength = 64
+
+// IsValidAccountType reports whether accountType may name a product
+func IsValidAccountType(accountType string) bool {
+	return len(accountType) <= maxAccountTypeLength && accountTypePattern.MatchString(accountType)
+}
+
+// Product defines what an account type means. Accounts may only be created with the account type of a product in the
+// catalog, and are governed by its terms for as long as they exist.
+type Product struct {
+	AccountType string `json:"accountType" validate:"required,accounttype"`
+	Description string `json:"description,omitempty" validate:"max=256"`
+	// Smallest initial balance that an account may be created with
+	MinimumOpeningBalance int `json:"minimumOpeningBalance" validate:"gte=0"`
+	// Largest overdraft limit that an account may be given, on creation or later
+	OverdraftAllowance int           `json:"overdraftAllowance" validate:"gte=0"`
+	TransferRules      TransferRules `json:"transferRules"`
+	Fees               FeeRules      `json:"fees"`
+	// Interest paid on accounts of the product, which pay no interest if nil
+	Interest *InterestTerms `json:"interest,omitempty"`
+	// Retired products are kept for the accounts which already have them, but no more accounts may be created
+	Retired bool `json:"retired,omitempty"`
+}
+
+// TransferRules restrict the transfers out of accounts of a product. The zero value allows any transfer.
+type TransferRules struct {
+	// Largest amount of a single transfer, in minor units of the source account's currency, or zero for no limit
+	MaxAmount int `json:"maxAmount,omitempty" validate:"gte=0"`
+	// Whether transfers may only be made to other accounts of the same owner
+	OwnAccountsOnly bool `json:"ownAccountsOnly,omitempty"`
+	// Account types that transfers may be made to, or any account type if empty
+	DestAccountTypes []string `json:"destAccountTypes,omitempty" validate:"unique,dive,accounttype"`
+}
+
+// ProductNotFoundError is returned when creating an account of, or accruing interest on, a type which is not in the
+// catalog
+type ProductNotFoundError struct {
+	AccountType string `json:"accountType"`
+}
+
+func (err ProductNotFoundError) Error() string {
+	return fmt.Sprintf("The account type %s is not a product in the catalog.", err.AccountType)
+}
+
+// ProductRetiredError is returned when creating an account of a product which has been retired
+type ProductRetiredError struct {
+	AccountType string `json:"accountType"`
+}
+
+func (err ProductRetiredError) Error() string {
+	return fmt.Sprintf("The product %s has been retired and no longer opens new accounts.", err.AccountType)
+}
+
+// MinimumOpeningBalanceError is returned when creating an account with less than its product's minimum opening balance
+type MinimumOpeningBalanceError struct {
+	AccountType           string `json:"accountType"`
+	MinimumOpeningBalance int    `json:"minimumOpeningBalance"`
+}
+
+func (err MinimumOpeningBalanceError) Error() string {
+	return fmt.Sprintf("Accounts of type %s must be opened with a balance of at least %d.", err.AccountType, err.MinimumOpeningBalance)
+}
+
+// OverdraftAllowanceExceededError is returned for an overdraft limit greater than the product of the account allows
+type OverdraftAllowanceExceededError struct {
+	AccountType        string `json:"accountType"`
+	OverdraftAllowance int    `json:"overdraftAllowance"`
+}
+
+func (err OverdraftAllowanceExceededError) Error() string {
+	return fmt.Sprintf("Accounts of type %s may have an overdraft limit of at most %d.", err.AccountType, err.OverdraftAllowance)
+}
+
+// TransferNotAllowedError is returned for a transfer which the transfer rules of the source account's product forbid
+type TransferNotAllowedError struct {
+	AccountType string `json:"accountType"`
+	// The kind of transfer which is forbidden, e.g. "transfers to accounts of other owners"
+	Reason string `json:"reason"`
+}
+
+func (err TransferNotAllowedError) Error() string {
+	return fmt.Sprintf("Accounts of type %s do not allow %s.", err.AccountType, err.Reason)
+}
+
+// checkAccount returns an error unless the product allows an account to be created with createAccountInput
+func (product Product) checkAccount(createAccountInput CreateAccountInput) error {
+	if product.Retired {
+		return ProductRetiredError{AccountType: product.AccountType}
+	}
+	if *createAccountInput.InitialBalance < product.MinimumOpeningBalance {
+		return MinimumOpeningBalanceError{
+			AccountType:           product.AccountType,
+			MinimumOpeningBalance: product.MinimumOpeningBalance,
+		}
+	}
+	return product.checkOverdraftLimit(createAccountInput.OverdraftLimit)
+}
+
+// checkOverdraftLimit returns an OverdraftAllowanceExceededError unless the product allows an overdraft limit
+func (product Product) checkOverdraftLimit(overdraftLimit int) error {
+	if overdraftLimit > product.OverdraftAllowance {
+		return OverdraftAllowanceExceededError{
+			AccountType:        product.AccountType,
+			OverdraftAllowance: product.OverdraftAllowance,
+		}
+	}
+	return nil
+}
+
+// checkTransfer returns a TransferNotAllowedError unless the transfer rules of the product allow a transfer out of an
+// account of srcAccountID
+func (product Product) checkTransfer(srcAccountID string, transferInput TransferInput) error {
+	rules := product.TransferRules
+	var reason string
+	switch {
+	case rules.MaxAmount != 0 && *transferInput.Amount > rules.MaxAmount:
+		reason = fmt.Sprintf("transfers of more than %d", rules.MaxAmount)
+	case rules.OwnAccountsOnly && transferInput.DestAccountID != srcAccountID:
+		reason = "transfers to accounts of other owners"
+	case len(rules.DestAccountTypes) > 0 && !slices.Contains(rules.DestAccountTypes, transferInput.DestAccountType):
+		reason = fmt.Sprintf("transfers to accounts of type %s", transferInput.DestAccountType)
+	default:
+		return nil
+	}
+	return TransferNotAllowedError{
+		AccountType: product.AccountType,
+		Reason:      reason,
+	}
+}
+
+// ListProductsInput lists the products in the catalog, ordered by account type
+type ListProductsInput struct {
+	IncludeRetired bool `json:"includeRetired,omitempty"`
+}
+
+type ListProductsOutput struct {
+	Products []Product `json:"products"`
+}
+
+// PutProduct adds a product to the catalog, or replaces the product of the same account type. Changes to the opening
+// balance and overdraft allowance only apply to accounts created or limits set afterwards, while transfer rules and
+// interest terms apply to every account of the product from then on.
+func (manager accountManagerImpl) PutProduct(ctx context.Context, product Product) error {
+	_, err := manager.ddb.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(productsTableName),
+		Item:      product.toItem(),
+	})
+	return err
+}
+
+func (manager accountManagerImpl) ListProducts(ctx context.Context, listProductsInput ListProductsInput) (ListProductsOutput, error) {
+	input := &dynamodb.ScanInput{
+		TableName:      aws.String(productsTableName),
+		ConsistentRead: aws.Bool(true),
+	}
+
+	// The catalog is small, so every page is read and the products are ordered here
+	output := ListProductsOutput{Products: []Product{}}
+	for {
+		page, err := manager.ddb.Scan(ctx, input)
+		if err != nil {
+			return ListProductsOutput{}, err
+		}
+		for _, item := range page.Items {
+			product, err := productFromItem(item)
+			if err != nil {
+				return ListProductsOutput{}, err
+			}
+			if !product.Retired || listProductsInput.IncludeRetired {
+				output.Products = append(output.Products, product)
+			}
+		}
+
+		if len(page.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = page.LastEvaluatedKey
+	}
+
+	slices.SortFunc(output.Products, func(a, b Product) bool {
+		return a.AccountType < b.AccountType
+	})
+	return output, nil
+}
+
+// getProduct returns the product of an account type, and false if it is not in the catalog
+func (manager accountManagerImpl) getProduct(ctx context.Context, accountType string) (Product, bool, error) {
+	output, err := manager.ddb.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(productsTableName),
+		Key:            map[string]types.AttributeValue{accountTypeAttr: &types.AttributeValueMemberS{Value: accountType}},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return Product{}, false, err
+	}
+	if len(output.Item) == 0 {
+		return Product{}, false, nil
+	}
+
+	product, err := productFromItem(output.Item)
+	if err != nil {
+		return Product{}, false, err
+	}
+	return product, true, nil
+}
+
+func (product Product) toItem() map[string]types.AttributeValue {
+	item := make(map[string]types.AttributeValue)
+	item[accountTypeAttr] = &types.AttributeValueMemberS{Value: product.AccountType}
+	if product.Description != "" {
+		item[descriptionAttr] = &types.AttributeValueMemberS{Value: product.Description}
+	}
+	item[minimumOpeningBalanceAttr] = &types.AttributeValueMemberN{Value: strconv.Itoa(product.MinimumOpeningBalance)}
+	item[overdraftAllowanceAttr] = &types.AttributeValueMemberN{Value: strconv.Itoa(product.OverdraftAllowance)}
+	if product.TransferRules.MaxAmount != 0 {
+		item[maxTransferAmountAttr] = &types.AttributeValueMemberN{Value: strconv.Itoa(product.TransferRules.MaxAmount)}
+	}
+	if product.TransferRules.OwnAccountsOnly {
+		item[ownAccountsOnlyAttr] = &types.AttributeValueMemberBOOL{Value: true}
+	}
+	// String sets may not be empty
+	if len(product.TransferRules.DestAccountTypes) > 0 {
+		item[destAccountTypesAttr] = &types.AttributeValueMemberSS{Value: product.TransferRules.DestAccountTypes}
+	}
+	if product.Fees != (FeeRules{}) {
+		item[feesAttr] = product.Fees.toAttributeValue()
+	}
+	if product.Interest != nil {
+		item[interestAttr] = &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			apyAttr:         &types.AttributeValueMemberS{Value: product.Interest.APY},
+			dayCountAttr:    &types.AttributeValueMemberS{Value: string(product.Interest.DayCount)},
+			compoundingAttr: &types.AttributeValueMemberS{Value: string(product.Interest.Compounding)},
+		}}
+	}
+	if product.Retired {
+		item[retiredAttr] = &types.AttributeValueMemberBOOL{Value: true}
+	}
+	return item
+}
+
+func productFromItem(item map[string]types.AttributeValue) (Product, error) {
+	accountType, ok := item[accountTypeAttr].(*types.AttributeValueMemberS)
+	if !ok {
+		return Product{}, errors.New("accountType must be a string")
+	}
+	minimumOpeningBalance, err := numberFromItem(item, minimumOpeningBalanceAttr)
+	if err != nil {
+		return Product{}, err
+	}
+	overdraftAllowance, err := numberFromItem(item, overdraftAllowanceAttr)
+	if err != nil {
+		return Product{}, err
+	}
+
+	product := Product{
+		AccountType:           accountType.Value,
+		Description:           stringFromItem(item, descriptionAttr),
+		MinimumOpeningBalance: minimumOpeningBalance,
+		OverdraftAllowance:    overdraftAllowance,
+	}
+	if _, ok := item[maxTransferAmountAttr]; ok {
+		product.TransferRules.MaxAmount, err = numberFromItem(item, maxTransferAmountAttr)
+		if err != nil {
+			return Product{}, err
+		}
+	}
+	if ownAccountsOnly, ok := item[ownAccountsOnlyAttr].(*types.AttributeValueMemberBOOL); ok {
+		product.TransferRules.OwnAccountsOnly = ownAccountsOnly.Value
+	}
+	if destAccountTypes, ok := item[destAccountTypesAttr].(*types.AttributeValueMemberSS); ok {
+		product.TransferRules.DestAccountTypes = destAccountTypes.Value
+		// Sets are unordered, so they are sorted to read back the same every time
+		slices.Sort(product.TransferRules.DestAccountTypes)
+	}
+	if attrValue, ok := item[feesAttr]; ok {
+		product.Fees, err = feeRulesFromAttributeValue(attrValue)
+		if err != nil {
+			return Product{}, err
+		}
+	}
+	if attrValue, ok := item[interestAttr]; ok {
+		interest, ok := attrValue.(*types.AttributeValueMemberM)
+		if !ok {
+			return Product{}, errors.New("interest must be a map")
+		}
+		product.Interest = &InterestTerms{
+			APY:         stringFromItem(interest.Value, apyAttr),
+			DayCount:    DayCount(stringFromItem(interest.Value, dayCountAttr)),
+			Compounding: Compounding(stringFromItem(interest.Value, compoundingAttr)),
+		}
+	}
+	if retired, ok := item[retiredAttr].(*types.AttributeValueMemberBOOL); ok {
+		product.Retired = retired.Value
+	}
+	return product, nil
+}
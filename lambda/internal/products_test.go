@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestIsValidAccountType(t *testing.T) {
+	tests := []struct {
+		accountType string
+		expected    bool
+	}{
+		{accountType: "savings", expected: true},
+		{accountType: "high-yield-savings", expected: true},
+		{accountType: "isa2024", expected: true},
+		{accountType: "Savings", expected: false},
+		{accountType: "savings ", expected: false},
+		{accountType: "-savings", expected: false},
+		{accountType: "savings--plus", expected: false},
+		{accountType: "2024-isa", expected: false},
+		{accountType: "", expected: false},
+		{accountType: strings.Repeat("a", maxAccountTypeLength), expected: true},
+		{accountType: strings.Repeat("a", maxAccountTypeLength+1), expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.accountType, func(t *testing.T) {
+			assert.Equal(t, test.expected, IsValidAccountType(test.accountType))
+		})
+	}
+}
+
+func TestProduct_ItemRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		product Product
+	}{
+		{
+			name: "every term",
+			product: Product{
+				AccountType:           "savings",
+				Description:           "Instant access savings",
+				MinimumOpeningBalance: 100,
+				OverdraftAllowance:    50,
+				TransferRules: TransferRules{
+					MaxAmount:        1000,
+					OwnAccountsOnly:  true,
+					DestAccountTypes: []string{"checking", "savings"},
+				},
+				Fees: FeeRules{
+					CrossOwner:          &Fee{Flat: 25},
+					BelowMinimumBalance: &Fee{Flat: 100, BasisPoints: 50},
+					MinimumBalance:      500,
+					FX:                  &Fee{BasisPoints: 150},
+				},
+				Interest: &InterestTerms{APY: "0.0425", DayCount: DayCountActual365, Compounding: CompoundingMonthly},
+				Retired:  true,
+			},
+		},
+		{
+			name:    "no terms",
+			product: Product{AccountType: "checking"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// === When ===
+			parsed, err := productFromItem(test.product.toItem())
+
+			// === Then ===
+			require.NoError(t, err)
+			assert.Equal(t, test.product, parsed)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequencies that a recurrence may repeat at
+const (
+	frequencyDaily   = "DAILY"
+	frequencyWeekly  = "WEEKLY"
+	frequencyMonthly = "MONTHLY"
+)
+
+// Layout of UNTIL in a recurrence, which is always in UTC as in RFC 5545
+const untilLayout = "20060102T150405Z"
+
+// recurrence is a subset of the RRULE of RFC 5545, e.g. "FREQ=MONTHLY;BYMONTHDAY=1" for the 1st of every month. It
+// supports FREQ of DAILY, WEEKLY or MONTHLY, INTERVAL, COUNT, UNTIL and, for monthly recurrences, a single BYMONTHDAY.
+type recurrence struct {
+	frequency string
+	// Number of periods of frequency between occurrences
+	interval int
+	// Day of the month of monthly occurrences, which is that of the first occurrence if zero. Months too short for it
+	// recur on their last day, and -1 is always the last day.
+	monthDay int
+	// Number of occurrences, or zero for no limit
+	count int
+	// Time after which there are no more occurrences, or zero for no limit
+	until time.Time
+}
+
+// IsValidRecurrence reports whether rule is a recurrence that schedules support
+func IsValidRecurrence(rule string) bool {
+	_, err := parseRecurrence(rule)
+	return err == nil
+}
+
+func parseRecurrence(rule string) (recurrence, error) {
+	r := recurrence{interval: 1}
+	for _, part := range strings.Split(strings.TrimPrefix(rule, "RRULE:"), ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return recurrence{}, fmt.Errorf("recurrence part %q is not of the form NAME=VALUE", part)
+		}
+
+		var err error
+		switch name {
+		case "FREQ":
+			if value != frequencyDaily && value != frequencyWeekly && value != frequencyMonthly {
+				return recurrence{}, fmt.Errorf("unsupported frequency %q", value)
+			}
+			r.frequency = value
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(value)
+			if err == nil && r.interval < 1 {
+				err = fmt.Errorf("interval must be at least 1")
+			}
+		case "COUNT":
+			r.count, err = strconv.Atoi(value)
+			if err == nil && r.count < 1 {
+				err = fmt.Errorf("count must be at least 1")
+			}
+		case "UNTIL":
+			r.until, err = time.Parse(untilLayout, value)
+		case "BYMONTHDAY":
+			r.monthDay, err = strconv.Atoi(value)
+			if err == nil && (r.monthDay == 0 || r.monthDay < -1 || r.monthDay > 31) {
+				err = fmt.Errorf("month day must be from 1 to 31, or -1 for the last day")
+			}
+		default:
+			return recurrence{}, fmt.Errorf("unsupported recurrence part %q", name)
+		}
+		if err != nil {
+			return recurrence{}, fmt.Errorf("invalid %s: %w", name, err)
+		}
+	}
+
+	if r.frequency == "" {
+		return recurrence{}, fmt.Errorf("recurrence must have a FREQ")
+	}
+	if r.monthDay != 0 && r.frequency != frequencyMonthly {
+		return recurrence{}, fmt.Errorf("BYMONTHDAY is only supported for MONTHLY recurrences")
+	}
+	return r, nil
+}
+
+// occurrence returns the time of the nth occurrence, counting from zero, of a recurrence starting at start. Like an
+// RRULE, the first occurrence is the first time matching the recurrence from start onwards, and later occurrences are
+// counted in intervals from it. It returns false if the recurrence ends before the nth occurrence.
+func (r recurrence) occurrence(start time.Time, n int) (time.Time, bool) {
+	if r.count != 0 && n >= r.count {
+		return time.Time{}, false
+	}
+
+	var t time.Time
+	switch r.frequency {
+	case frequencyDaily:
+		t = start.AddDate(0, 0, n*r.interval)
+	case frequencyWeekly:
+		t = start.AddDate(0, 0, 7*n*r.interval)
+	case frequencyMonthly:
+		// The first occurrence is in the month after start if the day has already passed in start's month
+		months := n * r.interval
+		if r.monthlyOccurrence(start, 0).Before(start) {
+			months++
+		}
+		t = r.monthlyOccurrence(start, months)
+	}
+
+	if !r.until.IsZero() && t.After(r.until) {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// monthlyOccurrence returns the occurrence of a monthly recurrence in the month the given number of months after that of
+// start
+func (r recurrence) monthlyOccurrence(start time.Time, months int) time.Time {
+	// AddDate normalizes overflowing days into the next month, so the day is clamped to the month instead
+	firstOfMonth := time.Date(start.Year(), start.Month(), 1, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+	month := firstOfMonth.AddDate(0, months, 0)
+	day := r.monthDay
+	if day == 0 {
+		day = start.Day()
+	}
+	lastDay := month.AddDate(0, 1, -1).Day()
+	if day == -1 || day > lastDay {
+		day = lastDay
+	}
+	return month.AddDate(0, 0, day-1)
+}
@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestIsValidRecurrence(t *testing.T) {
+	tests := []struct {
+		rule     string
+		expected bool
+	}{
+		{rule: "FREQ=DAILY", expected: true},
+		{rule: "RRULE:FREQ=WEEKLY;INTERVAL=2", expected: true},
+		{rule: "FREQ=MONTHLY;BYMONTHDAY=1;COUNT=12", expected: true},
+		{rule: "FREQ=MONTHLY;BYMONTHDAY=-1;UNTIL=20231231T000000Z", expected: true},
+		{rule: "", expected: false},
+		{rule: "FREQ=HOURLY", expected: false},
+		{rule: "INTERVAL=2", expected: false},
+		{rule: "FREQ=DAILY;INTERVAL=0", expected: false},
+		{rule: "FREQ=DAILY;COUNT=0", expected: false},
+		{rule: "FREQ=DAILY;UNTIL=2023-12-31", expected: false},
+		{rule: "FREQ=WEEKLY;BYMONTHDAY=1", expected: false},
+		{rule: "FREQ=MONTHLY;BYMONTHDAY=32", expected: false},
+		{rule: "FREQ=MONTHLY;BYDAY=MO", expected: false},
+		{rule: "FREQ=DAILY;", expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.rule, func(t *testing.T) {
+			assert.Equal(t, test.expected, IsValidRecurrence(test.rule))
+		})
+	}
+}
+
+func TestRecurrence_Occurrence(t *testing.T) {
+	start := time.Date(2023, 1, 31, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		rule     string
+		n        int
+		expected time.Time
+		ok       bool
+	}{
+		{name: "first", rule: "FREQ=DAILY", n: 0, expected: start, ok: true},
+		{name: "daily", rule: "FREQ=DAILY", n: 3, expected: time.Date(2023, 2, 3, 9, 0, 0, 0, time.UTC), ok: true},
+		{name: "weekly with interval", rule: "FREQ=WEEKLY;INTERVAL=2", n: 1, expected: time.Date(2023, 2, 14, 9, 0, 0, 0, time.UTC), ok: true},
+		{name: "monthly clamped to short month", rule: "FREQ=MONTHLY", n: 1, expected: time.Date(2023, 2, 28, 9, 0, 0, 0, time.UTC), ok: true},
+		{name: "monthly after short month", rule: "FREQ=MONTHLY", n: 2, expected: time.Date(2023, 3, 31, 9, 0, 0, 0, time.UTC), ok: true},
+		{name: "monthly on last day", rule: "FREQ=MONTHLY;BYMONTHDAY=-1", n: 3, expected: time.Date(2023, 4, 30, 9, 0, 0, 0, time.UTC), ok: true},
+		{name: "monthly by day before start", rule: "FREQ=MONTHLY;BYMONTHDAY=1", n: 0, expected: time.Date(2023, 2, 1, 9, 0, 0, 0, time.UTC), ok: true},
+		{name: "monthly by day before start with interval", rule: "FREQ=MONTHLY;BYMONTHDAY=1;INTERVAL=6", n: 1, expected: time.Date(2023, 8, 1, 9, 0, 0, 0, time.UTC), ok: true},
+		{name: "monthly across year", rule: "FREQ=MONTHLY;BYMONTHDAY=1;INTERVAL=6", n: 2, expected: time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC), ok: true},
+		{name: "within count", rule: "FREQ=DAILY;COUNT=3", n: 2, expected: time.Date(2023, 2, 2, 9, 0, 0, 0, time.UTC), ok: true},
+		{name: "beyond count", rule: "FREQ=DAILY;COUNT=3", n: 3, ok: false},
+		{name: "until inclusive", rule: "FREQ=DAILY;UNTIL=20230202T090000Z", n: 2, expected: time.Date(2023, 2, 2, 9, 0, 0, 0, time.UTC), ok: true},
+		{name: "beyond until", rule: "FREQ=DAILY;UNTIL=20230202T090000Z", n: 3, ok: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// === Given ===
+			r, err := parseRecurrence(test.rule)
+			require.NoError(t, err)
+
+			// === When ===
+			occurrence, ok := r.occurrence(start, test.n)
+
+			// === Then ===
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.expected, occurrence)
+		})
+	}
+}
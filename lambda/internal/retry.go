@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// The global source is deterministic before Go 1.20, which would give every Lambda instance the same jitter
+var (
+	jitterMu sync.Mutex
+	jitter   = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+type retryPolicy struct {
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	// A retry is only attempted while at least this long remains before the deadline of the context, leaving time for
+	// the attempt itself and for the handler to respond
+	deadlineMargin time.Duration
+}
+
+// Transfers to and from hot accounts, such as payroll, regularly conflict with each other
+var transferRetryPolicy = retryPolicy{
+	maxAttempts:    8,
+	baseDelay:      20 * time.Millisecond,
+	maxDelay:       1 * time.Second,
+	deadlineMargin: 250 * time.Millisecond,
+}
+
+// backoff returns the delay before the given retry, using exponential backoff with full jitter so that conflicting
+// requests spread out rather than colliding again
+func (policy retryPolicy) backoff(retry int) time.Duration {
+	ceiling := policy.maxDelay
+	if retry < 32 && policy.baseDelay<<retry < policy.maxDelay {
+		ceiling = policy.baseDelay << retry
+	}
+	jitterMu.Lock()
+	defer jitterMu.Unlock()
+	return time.Duration(jitter.Int63n(int64(ceiling) + 1))
+}
+
+// retryOnConflict calls attempt until it returns something other than a TransactionConflictError, the policy's
+// attempts are exhausted, or retrying would run past the deadline of ctx. The number of retries is emitted as a
+// metric for the operation.
+func retryOnConflict(ctx context.Context, policy retryPolicy, operation string, attempt func() error) error {
+	retries := 0
+	for {
+		err := attempt()
+
+		var conflictErr TransactionConflictError
+		if err == nil || !errors.As(err, &conflictErr) {
+			emitRetryMetrics(operation, retries, false)
+			return err
+		}
+		if retries+1 >= policy.maxAttempts {
+			log.Printf("%s conflicted on all %d attempts: %v", operation, retries+1, err)
+			emitRetryMetrics(operation, retries, true)
+			return err
+		}
+
+		delay := policy.backoff(retries)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay+policy.deadlineMargin {
+			log.Printf("%s conflicted and there is not enough time left to retry: %v", operation, err)
+			emitRetryMetrics(operation, retries, true)
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			emitRetryMetrics(operation, retries, true)
+			return err
+		case <-timer.C:
+		}
+		retries++
+	}
+}
+
+func emitRetryMetrics(operation string, retries int, exhausted bool) {
+	exhaustedCount := 0.0
+	if exhausted {
+		exhaustedCount = 1
+	}
+	emitMetrics(map[string]string{"Operation": operation},
+		metric{name: "ConflictRetries", unit: "Count", value: float64(retries)},
+		metric{name: "ConflictRetriesExhausted", unit: "Count", value: exhaustedCount},
+	)
+}
@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testRetryPolicy = retryPolicy{
+	maxAttempts:    4,
+	baseDelay:      time.Millisecond,
+	maxDelay:       2 * time.Millisecond,
+	deadlineMargin: 10 * time.Millisecond,
+}
+
+// captureMetrics redirects metrics for the duration of the test, returning each emitted line
+func captureMetrics(t *testing.T) func() []map[string]interface{} {
+	var buffer bytes.Buffer
+	previous := metricsOutput
+	metricsOutput = &buffer
+	t.Cleanup(func() {
+		metricsOutput = previous
+	})
+
+	return func() []map[string]interface{} {
+		var lines []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+			var decoded map[string]interface{}
+			assert.NoError(t, json.Unmarshal([]byte(line), &decoded))
+			lines = append(lines, decoded)
+		}
+		return lines
+	}
+}
+
+func TestRetryOnConflict_RetriesUntilSuccess(t *testing.T) {
+	// === Given ===
+	metrics := captureMetrics(t)
+	attempts := 0
+
+	// === When ===
+	err := retryOnConflict(context.Background(), testRetryPolicy, "Transfer", func() error {
+		attempts++
+		if attempts < 3 {
+			return TransactionConflictError{}
+		}
+		return nil
+	})
+
+	// === Then ===
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	lines := metrics()
+	assert.Len(t, lines, 1)
+	assert.Equal(t, "Transfer", lines[0]["Operation"])
+	assert.Equal(t, 2.0, lines[0]["ConflictRetries"])
+	assert.Equal(t, 0.0, lines[0]["ConflictRetriesExhausted"])
+}
+
+func TestRetryOnConflict_StopsAfterMaxAttempts(t *testing.T) {
+	// === Given ===
+	metrics := captureMetrics(t)
+	attempts := 0
+
+	// === When ===
+	err := retryOnConflict(context.Background(), testRetryPolicy, "Transfer", func() error {
+		attempts++
+		return TransactionConflictError{}
+	})
+
+	// === Then ===
+	assert.ErrorAs(t, err, &TransactionConflictError{})
+	assert.Equal(t, testRetryPolicy.maxAttempts, attempts)
+	assert.Equal(t, 1.0, metrics()[0]["ConflictRetriesExhausted"])
+}
+
+func TestRetryOnConflict_DoesNotRetryOtherErrors(t *testing.T) {
+	// === Given ===
+	captureMetrics(t)
+	attempts := 0
+	expectedErr := errors.New("ERROR")
+
+	// === When ===
+	err := retryOnConflict(context.Background(), testRetryPolicy, "Transfer", func() error {
+		attempts++
+		return expectedErr
+	})
+
+	// === Then ===
+	assert.Equal(t, expectedErr, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestRetryOnConflict_StopsBeforeDeadline(t *testing.T) {
+	// === Given ===
+	metrics := captureMetrics(t)
+	ctx, cancel := context.WithTimeout(context.Background(), testRetryPolicy.deadlineMargin/2)
+	defer cancel()
+	attempts := 0
+
+	// === When ===
+	err := retryOnConflict(ctx, testRetryPolicy, "Transfer", func() error {
+		attempts++
+		return TransactionConflictError{}
+	})
+
+	// === Then ===
+	assert.ErrorAs(t, err, &TransactionConflictError{})
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, 1.0, metrics()[0]["ConflictRetriesExhausted"])
+}
+
+func TestRetryPolicy_BackoffIsBounded(t *testing.T) {
+	for retry := 0; retry < 64; retry++ {
+		delay := transferRetryPolicy.backoff(retry)
+		assert.GreaterOrEqual(t, delay, time.Duration(0))
+		assert.LessOrEqual(t, delay, transferRetryPolicy.maxDelay)
+	}
+}
+
+func TestEmitMetrics_EmbeddedMetricFormat(t *testing.T) {
+	// === Given ===
+	metrics := captureMetrics(t)
+
+	// === When ===
+	emitMetrics(map[string]string{"Operation": "Transfer"}, metric{name: "ConflictRetries", unit: "Count", value: 3})
+
+	// === Then ===
+	lines := metrics()
+	assert.Len(t, lines, 1)
+	assert.Equal(t, 3.0, lines[0]["ConflictRetries"])
+	assert.JSONEq(t, `[{
+		"Namespace": "BankingService",
+		"Dimensions": [["Operation"]],
+		"Metrics": [{"Name": "ConflictRetries", "Unit": "Count"}]
+	}]`, mustMarshal(t, lines[0]["_aws"].(map[string]interface{})["CloudWatchMetrics"]))
+}
+
+func mustMarshal(t *testing.T, v interface{}) string {
+	encoded, err := json.Marshal(v)
+	assert.NoError(t, err)
+	return string(encoded)
+}
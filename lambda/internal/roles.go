@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"sync"
+	"time"
+)
+
+const (
+	rolesTableName = "roles-table"
+
+	rolesAttr = "Roles"
+)
+
+type Role string
+
+// Every caller is a customer, and may be granted the other roles in addition
+const (
+	RoleCustomer Role = "customer"
+	RoleOperator Role = "operator"
+	RoleAuditor  Role = "auditor"
+	RoleAdmin    Role = "admin"
+)
+
+// RoleStore looks up the roles granted to a caller by their account ID, in addition to RoleCustomer
+type RoleStore interface {
+	GetRoles(ctx context.Context, accountID string) ([]Role, error)
+}
+
+// NewStaticRoleStore returns a RoleStore of the given roles of each account ID
+func NewStaticRoleStore(roles map[string][]Role) RoleStore {
+	return staticRoleStore(roles)
+}
+
+// ParseStaticRoleStore parses a RoleStore from JSON of the form
+//
+//	{"105343117262": ["admin"], "123456789012": ["auditor", "operator"]}
+func ParseStaticRoleStore(config []byte) (RoleStore, error) {
+	var roles map[string][]Role
+	err := json.Unmarshal(config, &roles)
+	if err != nil {
+		return nil, fmt.Errorf("parsing roles: %w", err)
+	}
+	return NewStaticRoleStore(roles), nil
+}
+
+type staticRoleStore map[string][]Role
+
+func (store staticRoleStore) GetRoles(_ context.Context, accountID string) ([]Role, error) {
+	return store[accountID], nil
+}
+
+// NewDynamoDBRoleStore returns a RoleStore backed by the roles table, which has an item of the form
+// {AccountId: S, Roles: SS} for each caller with roles. Roles are cached for cacheTTL, so that most requests don't pay
+// for a lookup.
+func NewDynamoDBRoleStore(ddb *dynamodb.Client, cacheTTL time.Duration) RoleStore {
+	return &dynamoDBRoleStore{
+		ddb:      ddb,
+		cacheTTL: cacheTTL,
+		cache:    make(map[string]cachedRoles),
+	}
+}
+
+type dynamoDBRoleStore struct {
+	ddb      *dynamodb.Client
+	cacheTTL time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedRoles
+}
+
+type cachedRoles struct {
+	roles     []Role
+	expiresAt time.Time
+}
+
+func (store *dynamoDBRoleStore) GetRoles(ctx context.Context, accountID string) ([]Role, error) {
+	store.mu.Lock()
+	cached, ok := store.cache[accountID]
+	store.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.roles, nil
+	}
+
+	output, err := store.ddb.GetItem(ctx, &dynamodb.GetItemInput{
+		Key: map[string]types.AttributeValue{
+			accountIDAttr: &types.AttributeValueMemberS{Value: accountID},
+		},
+		TableName:            aws.String(rolesTableName),
+		ProjectionExpression: aws.String(rolesAttr),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var roles []Role
+	if attrValue, ok := output.Item[rolesAttr].(*types.AttributeValueMemberSS); ok {
+		for _, role := range attrValue.Value {
+			roles = append(roles, Role(role))
+		}
+	}
+
+	store.mu.Lock()
+	store.cache[accountID] = cachedRoles{
+		roles:     roles,
+		expiresAt: time.Now().Add(store.cacheTTL),
+	}
+	store.mu.Unlock()
+
+	return roles, nil
+}
@@ -0,0 +1,38 @@
+package internal
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseStaticRoleStore(t *testing.T) {
+	// === Given ===
+	config := []byte(`{"105343117262": ["admin"], "210987654321": ["auditor", "operator"]}`)
+
+	// === When ===
+	store, err := ParseStaticRoleStore(config)
+
+	// === Then ===
+	assert.NoError(t, err)
+
+	roles, err := store.GetRoles(context.Background(), "105343117262")
+	assert.NoError(t, err)
+	assert.Equal(t, []Role{RoleAdmin}, roles)
+
+	roles, err = store.GetRoles(context.Background(), "210987654321")
+	assert.NoError(t, err)
+	assert.Equal(t, []Role{RoleAuditor, RoleOperator}, roles)
+
+	roles, err = store.GetRoles(context.Background(), "123456789")
+	assert.NoError(t, err)
+	assert.Empty(t, roles)
+}
+
+func TestParseStaticRoleStore_InvalidJSON(t *testing.T) {
+	// === When ===
+	_, err := ParseStaticRoleStore([]byte(`["admin"]`))
+
+	// === Then ===
+	assert.Error(t, err)
+}
@@ -0,0 +1,739 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"log"
+	"strconv"
+	"time"
+)
+
+const (
+	schedulesTableName          = "schedules-table"
+	scheduleExecutionsTableName = "schedule-executions-table"
+	// Index of active schedules by when they are next due. Only active schedules have a NextRunAt, so the index holds
+	// nothing else.
+	dueSchedulesIndexName = "due-schedules-index"
+
+	scheduleIDAttr      = "ScheduleId"
+	scheduleStatusAttr  = "ScheduleStatus"
+	recurrenceAttr      = "Recurrence"
+	startAtAttr         = "StartAt"
+	nextRunAtAttr       = "NextRunAt"
+	occurrencesAttr     = "Occurrences"
+	occurrenceAttr      = "Occurrence"
+	scheduledAtAttr     = "ScheduledAt"
+	executedAtAttr      = "ExecutedAt"
+	executionStatusAttr = "ExecutionStatus"
+	errorAttr           = "Error"
+
+	// Times of schedules are stored to the second in UTC, in a fixed width layout which sorts chronologically
+	scheduleTimeLayout = "2006-01-02T15:04:05Z"
+	// Occurrences are stored zero padded, so that they sort numerically as strings
+	occurrenceLayout = "%010d"
+
+	// How many due schedules a run executes by default
+	defaultScheduleRunLimit = 100
+)
+
+type ScheduleStatus string
+
+const (
+	ScheduleStatusActive    ScheduleStatus = "ACTIVE"
+	ScheduleStatusCompleted ScheduleStatus = "COMPLETED"
+	ScheduleStatusCancelled ScheduleStatus = "CANCELLED"
+)
+
+type ExecutionStatus string
+
+const (
+	ExecutionStatusSucceeded ExecutionStatus = "SUCCEEDED"
+	// The transfer was rejected, e.g. for insufficient funds, and the occurrence is skipped
+	ExecutionStatusFailed ExecutionStatus = "FAILED"
+)
+
+// Schedule is a transfer from one of its owner's accounts which is made at StartAt, or on each occurrence of Recurrence
+// from StartAt onwards if it has one
+type Schedule struct {
+	ScheduleID      string `json:"scheduleID"`
+	AccountID       string `json:"accountID"`
+	SrcAccountType  string `json:"srcAccountType"`
+	DestAccountID   string `json:"destAccountID"`
+	DestAccountType string `json:"destAccountType"`
+	Amount          int    `json:"amount"`
+	// Currency of Amount, which is the currency of the source account unless the schedule was created with another
+	Currency string `json:"currency,omitempty"`
+	// RRULE of the schedule, e.g. "FREQ=MONTHLY;BYMONTHDAY=1", which is empty for one-off transfers
+	Recurrence string         `json:"recurrence,omitempty"`
+	StartAt    time.Time      `json:"startAt"`
+	Status     ScheduleStatus `json:"status"`
+	// Number of occurrences executed so far, whether or not their transfers succeeded
+	Occurrences int `json:"occurrences"`
+	// When the next occurrence is due, which is absent once the schedule has completed or been cancelled
+	NextRunAt *time.Time `json:"nextRunAt,omitempty"`
+}
+
+type ScheduleKey struct {
+	AccountID  string `json:"accountID"`
+	ScheduleID string `json:"scheduleID"`
+}
+
+// ScheduleExecution records the outcome of one occurrence of a schedule
+type ScheduleExecution struct {
+	ScheduleID  string          `json:"scheduleID"`
+	Occurrence  int             `json:"occurrence"`
+	ScheduledAt time.Time       `json:"scheduledAt"`
+	ExecutedAt  time.Time       `json:"executedAt"`
+	Status      ExecutionStatus `json:"status"`
+	// ID of the transaction of a successful transfer
+	TransactionID string `json:"transactionID,omitempty"`
+	// Why the transfer was rejected
+	Error string `json:"error,omitempty"`
+}
+
+type CreateScheduleInput struct {
+	SrcAccountType  string `json:"srcAccountType" validate:"required"`
+	DestAccountID   string `json:"destAccountID" validate:"required"`
+	DestAccountType string `json:"destAccountType" validate:"required"`
+	// Use pointer for Amount to ensure that it's explicitly defined
+	Amount *int `json:"amount" validate:"required,gt=0"`
+	// Currency of Amount, which is checked against the source account on each transfer if defined
+	Currency string `json:"currency,omitempty" validate:"omitempty,currency"`
+	// When a one-off transfer is made, or the first occurrence of Recurrence is due from, which defaults to now
+	StartAt *time.Time `json:"startAt,omitempty"`
+	// RRULE of the transfers, e.g. "FREQ=MONTHLY;BYMONTHDAY=1". Omitted for a one-off transfer.
+	Recurrence string `json:"recurrence,omitempty" validate:"omitempty,max=255,recurrence"`
+}
+
+type CreateScheduleOutput struct {
+	Schedule Schedule `json:"schedule"`
+}
+
+type ListSchedulesInput struct {
+	ExclusiveStartKey *ScheduleKey `json:"exclusiveStartKey"`
+	Limit             *int32       `json:"limit" validate:"omitempty,gte=1"`
+}
+
+type ListSchedulesOutput struct {
+	Schedules        []Schedule  `json:"schedules"`
+	LastEvaluatedKey ScheduleKey `json:"lastEvaluatedKey"`
+}
+
+type CancelScheduleInput struct {
+	ScheduleID string `json:"scheduleID" validate:"required,max=64"`
+}
+
+type ListScheduleExecutionsInput struct {
+	ScheduleID string `json:"scheduleID" validate:"required,max=64"`
+	// Executions are listed most recent first, from before this occurrence if defined
+	ExclusiveStartOccurrence *int   `json:"exclusiveStartOccurrence,omitempty" validate:"omitempty,gte=0"`
+	Limit                    *int32 `json:"limit" validate:"omitempty,gte=1"`
+}
+
+type ListScheduleExecutionsOutput struct {
+	Executions []ScheduleExecution `json:"executions"`
+	// Occurrence to continue listing from, which is absent once every execution has been listed
+	LastEvaluatedOccurrence *int `json:"lastEvaluatedOccurrence,omitempty"`
+}
+
+type RunSchedulesInput struct {
+	// Maximum number of due schedules to run, which defaults to 100. Any others are left for the next run.
+	Limit *int32 `json:"limit,omitempty" validate:"omitempty,gte=1"`
+}
+
+type RunSchedulesOutput struct {
+	Executions []ScheduleExecution `json:"executions"`
+}
+
+type ScheduleNotFoundError struct {
+	ScheduleID string `json:"scheduleID"`
+}
+
+func (err ScheduleNotFoundError) Error() string {
+	return fmt.Sprintf("The schedule %s does not exist.", err.ScheduleID)
+}
+
+// errOccurrenceExecuted is returned by the transfer of a schedule's occurrence, without transferring, if another run
+// executed the occurrence first or the schedule was cancelled
+var errOccurrenceExecuted = errors.New("the occurrence was already executed or the schedule was cancelled")
+
+// accountKeys returns the keys of the source and destination accounts of a schedule of accountID
+func (createScheduleInput CreateScheduleInput) accountKeys(accountID string) (AccountKey, AccountKey) {
+	srcKey := AccountKey{
+		AccountID:   accountID,
+		AccountType: createScheduleInput.SrcAccountType,
+	}
+	destKey := AccountKey{
+		AccountID:   createScheduleInput.DestAccountID,
+		AccountType: createScheduleInput.DestAccountType,
+	}
+	return srcKey, destKey
+}
+
+// checkAccounts checks that a schedule's accounts can transfer to each other, so that a schedule whose every occurrence
+// would fail is rejected when it is created. The accounts may still change before an occurrence is due, e.g. by being
+// closed, which fails that occurrence.
+func (createScheduleInput CreateScheduleInput) checkAccounts(srcKey AccountKey, src, dest account) error {
+	if err := src.checkCurrency(srcKey, createScheduleInput.Currency); err != nil {
+		return err
+	}
+	// Conversions are priced at the time of a transfer, so a schedule cannot make them
+	if src.currency != dest.currency {
+		return ConversionRequiredError{
+			SrcCurrency:  src.currency,
+			DestCurrency: dest.currency,
+		}
+	}
+	return nil
+}
+
+// newSchedule returns the schedule created by input, whose first occurrence is due at its start, or at the first
+// occurrence of its recurrence from its start onwards
+func newSchedule(accountID string, createScheduleInput CreateScheduleInput) Schedule {
+	startAt := time.Now()
+	if createScheduleInput.StartAt != nil {
+		startAt = *createScheduleInput.StartAt
+	}
+	startAt = startAt.UTC().Truncate(time.Second)
+
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	schedule := Schedule{
+		ScheduleID:      hex.EncodeToString(id),
+		AccountID:       accountID,
+		SrcAccountType:  createScheduleInput.SrcAccountType,
+		DestAccountID:   createScheduleInput.DestAccountID,
+		DestAccountType: createScheduleInput.DestAccountType,
+		Amount:          *createScheduleInput.Amount,
+		Currency:        createScheduleInput.Currency,
+		Recurrence:      createScheduleInput.Recurrence,
+		StartAt:         startAt,
+		Status:          ScheduleStatusActive,
+	}
+	if first, ok := schedule.occurrenceAt(0); ok {
+		schedule.NextRunAt = &first
+	} else {
+		// The recurrence ends before its first occurrence
+		schedule.Status = ScheduleStatusCompleted
+	}
+	return schedule
+}
+
+// occurrenceAt returns when the nth occurrence of the schedule, counting from zero, is due. It returns false if the
+// schedule ends before the nth occurrence.
+func (schedule Schedule) occurrenceAt(n int) (time.Time, bool) {
+	if schedule.Recurrence == "" {
+		return schedule.StartAt, n == 0
+	}
+	// Recurrences are validated when the schedule is created
+	r, err := parseRecurrence(schedule.Recurrence)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return r.occurrence(schedule.StartAt, n)
+}
+
+// advance returns the schedule after its next occurrence has been executed, which is completed if that was the last
+func (schedule Schedule) advance() Schedule {
+	schedule.Occurrences++
+	schedule.NextRunAt = nil
+	if schedule.Status != ScheduleStatusActive {
+		// The schedule was cancelled while its occurrence was being executed
+		return schedule
+	}
+	if next, ok := schedule.occurrenceAt(schedule.Occurrences); ok {
+		schedule.NextRunAt = &next
+	} else {
+		schedule.Status = ScheduleStatusCompleted
+	}
+	return schedule
+}
+
+// transferInput returns the transfer of the schedule's next occurrence. The transfer is only made while the schedule is
+// active and still at that occurrence, and records its execution and advances the schedule in the same transaction, so
+// that the occurrence is transferred at most once however long it takes to execute.
+func (schedule Schedule) transferInput() TransferInput {
+	amount := schedule.Amount
+	return TransferInput{
+		SrcAccountType:  schedule.SrcAccountType,
+		DestAccountID:   schedule.DestAccountID,
+		DestAccountType: schedule.DestAccountType,
+		Amount:          &amount,
+		Currency:        schedule.Currency,
+		occurrence:      &schedule,
+	}
+}
+
+// succeeded returns the execution of the schedule's next occurrence by the transfer of tx
+func (schedule Schedule) succeeded(tx Transaction) ScheduleExecution {
+	return ScheduleExecution{
+		ScheduleID:    schedule.ScheduleID,
+		Occurrence:    schedule.Occurrences,
+		ScheduledAt:   *schedule.NextRunAt,
+		ExecutedAt:    tx.Timestamp.UTC(),
+		Status:        ExecutionStatusSucceeded,
+		TransactionID: tx.TransactionID,
+	}
+}
+
+// executeSchedule makes the transfer of the next occurrence of a schedule through manager, which records a successful
+// execution itself. A transfer which fails with an error that may be transient, or which another run made first, returns
+// the error so that the occurrence is left to the next run, while any other error is returned as a failed execution
+// for the caller to record.
+func executeSchedule(ctx context.Context, manager AccountManager, schedule Schedule) (ScheduleExecution, error) {
+	output, err := manager.Transfer(ctx, schedule.AccountID, schedule.transferInput())
+	switch {
+	case err == nil:
+		return schedule.succeeded(output.Transaction), nil
+	case errors.Is(err, errOccurrenceExecuted) || isTransient(err):
+		return ScheduleExecution{}, err
+	default:
+		return ScheduleExecution{
+			ScheduleID:  schedule.ScheduleID,
+			Occurrence:  schedule.Occurrences,
+			ScheduledAt: *schedule.NextRunAt,
+			ExecutedAt:  time.Now().UTC(),
+			Status:      ExecutionStatusFailed,
+			Error:       err.Error(),
+		}, nil
+	}
+}
+
+// isTransient reports whether err may not recur if the transfer is retried: a conflict with a concurrent transaction,
+// throttling, or the run running out of time. Any other error, such as insufficient funds or an account which was
+// closed, fails the same way again.
+func isTransient(err error) bool {
+	var transactionCanceledException *types.TransactionCanceledException
+	return errors.As(err, new(TransactionConflictError)) ||
+		retry.IsErrorThrottles(retry.DefaultThrottles).IsErrorThrottle(err) == aws.TrueTernary ||
+		errors.As(err, &transactionCanceledException) && isThrottled(transactionCanceledException) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, context.Canceled)
+}
+
+// isThrottled reports whether the transaction was cancelled because one of its items exceeded its provisioned
+// throughput
+func isThrottled(err *types.TransactionCanceledException) bool {
+	for _, reason := range err.CancellationReasons {
+		if reason.Code != nil && *reason.Code == throttlingErrorReason {
+			return true
+		}
+	}
+	return false
+}
+
+func (manager accountManagerImpl) CreateSchedule(ctx context.Context, accountID string, createScheduleInput CreateScheduleInput) (CreateScheduleOutput, error) {
+	srcKey, destKey := createScheduleInput.accountKeys(accountID)
+	if srcKey == destKey {
+		return CreateScheduleOutput{}, SelfTransferError(srcKey)
+	}
+	src, err := manager.getAccount(ctx, srcKey)
+	if err != nil {
+		var accountDoesNotExistErr AccountDoesNotExistError
+		if errors.As(err, &accountDoesNotExistErr) {
+			return CreateScheduleOutput{}, SourceAccountDoesNotExistError(accountDoesNotExistErr)
+		}
+		return CreateScheduleOutput{}, err
+	}
+	dest, err := manager.getAccount(ctx, destKey)
+	if err != nil {
+		return CreateScheduleOutput{}, err
+	}
+	if err := createScheduleInput.checkAccounts(srcKey, src, dest); err != nil {
+		return CreateScheduleOutput{}, err
+	}
+	if createScheduleInput.Currency == "" {
+		createScheduleInput.Currency = src.currency
+	}
+
+	schedule := newSchedule(accountID, createScheduleInput)
+	_, err = manager.ddb.PutItem(ctx, &dynamodb.PutItemInput{
+		Item:                schedule.toItem(),
+		TableName:           aws.String(schedulesTableName),
+		ConditionExpression: aws.String(fmt.Sprintf("attribute_not_exists(%s)", scheduleIDAttr)),
+	})
+	if err != nil {
+		return CreateScheduleOutput{}, err
+	}
+
+	return CreateScheduleOutput{
+		Schedule: schedule,
+	}, nil
+}
+
+func (manager accountManagerImpl) ListSchedules(ctx context.Context, accountID string, listSchedulesInput ListSchedulesInput) (ListSchedulesOutput, error) {
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(schedulesTableName),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":id": &types.AttributeValueMemberS{Value: accountID}},
+		KeyConditionExpression:    aws.String(fmt.Sprintf("%s = :id", accountIDAttr)),
+		Limit:                     listSchedulesInput.Limit,
+	}
+	if startKey := listSchedulesInput.ExclusiveStartKey; startKey != nil {
+		input.ExclusiveStartKey = scheduleKeyItem(startKey.AccountID, startKey.ScheduleID)
+	}
+
+	output, err := manager.ddb.Query(ctx, input)
+	if err != nil {
+		return ListSchedulesOutput{}, err
+	}
+
+	var schedules []Schedule
+	for _, item := range output.Items {
+		schedule, err := scheduleFromItem(item)
+		if err != nil {
+			return ListSchedulesOutput{}, err
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	var lastEvaluatedKey ScheduleKey
+	if len(output.LastEvaluatedKey) != 0 {
+		lastEvaluatedKey = ScheduleKey{
+			AccountID:  stringFromItem(output.LastEvaluatedKey, accountIDAttr),
+			ScheduleID: stringFromItem(output.LastEvaluatedKey, scheduleIDAttr),
+		}
+	}
+
+	return ListSchedulesOutput{
+		Schedules:        schedules,
+		LastEvaluatedKey: lastEvaluatedKey,
+	}, nil
+}
+
+// CancelSchedule stops any further occurrences of a schedule. Cancelling a schedule which has already completed or been
+// cancelled succeeds without changing it.
+func (manager accountManagerImpl) CancelSchedule(ctx context.Context, accountID string, cancelScheduleInput CancelScheduleInput) error {
+	exprAttrValues := make(map[string]types.AttributeValue)
+	exprAttrValues[":active"] = &types.AttributeValueMemberS{Value: string(ScheduleStatusActive)}
+	exprAttrValues[":cancelled"] = &types.AttributeValueMemberS{Value: string(ScheduleStatusCancelled)}
+
+	// A transaction rather than an update, as only transactions return the existing item when their condition fails
+	_, err := manager.ddb.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Update: &types.Update{
+					Key:                       scheduleKeyItem(accountID, cancelScheduleInput.ScheduleID),
+					TableName:                 aws.String(schedulesTableName),
+					UpdateExpression:          aws.String(fmt.Sprintf("SET %s = :cancelled REMOVE %s", scheduleStatusAttr, nextRunAtAttr)),
+					ConditionExpression:       aws.String(fmt.Sprintf("%s = :active", scheduleStatusAttr)),
+					ExpressionAttributeValues: exprAttrValues,
+					// Return the existing item on failure to distinguish a missing schedule from an inactive one
+					ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+				},
+			},
+		},
+	})
+	if err != nil {
+		var transactionCanceledException *types.TransactionCanceledException
+		if errors.As(err, &transactionCanceledException) && isConditionalCheckFailed(transactionCanceledException, 0) {
+			if len(transactionCanceledException.CancellationReasons[0].Item) == 0 {
+				return ScheduleNotFoundError{ScheduleID: cancelScheduleInput.ScheduleID}
+			}
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (manager accountManagerImpl) ListScheduleExecutions(ctx context.Context, accountID string, listScheduleExecutionsInput ListScheduleExecutionsInput) (ListScheduleExecutionsOutput, error) {
+	scheduleID := listScheduleExecutionsInput.ScheduleID
+
+	// Executions are keyed by schedule alone, so the schedule is read first to check that it belongs to the caller
+	schedule, err := manager.ddb.GetItem(ctx, &dynamodb.GetItemInput{
+		Key:                  scheduleKeyItem(accountID, scheduleID),
+		TableName:            aws.String(schedulesTableName),
+		ProjectionExpression: aws.String(scheduleIDAttr),
+	})
+	if err != nil {
+		return ListScheduleExecutionsOutput{}, err
+	}
+	if len(schedule.Item) == 0 {
+		return ListScheduleExecutionsOutput{}, ScheduleNotFoundError{ScheduleID: scheduleID}
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(scheduleExecutionsTableName),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":id": &types.AttributeValueMemberS{Value: scheduleID}},
+		KeyConditionExpression:    aws.String(fmt.Sprintf("%s = :id", scheduleIDAttr)),
+		ScanIndexForward:          aws.Bool(false),
+		Limit:                     listScheduleExecutionsInput.Limit,
+	}
+	if startOccurrence := listScheduleExecutionsInput.ExclusiveStartOccurrence; startOccurrence != nil {
+		input.ExclusiveStartKey = executionKeyItem(scheduleID, *startOccurrence)
+	}
+
+	output, err := manager.ddb.Query(ctx, input)
+	if err != nil {
+		return ListScheduleExecutionsOutput{}, err
+	}
+
+	var executions []ScheduleExecution
+	for _, item := range output.Items {
+		execution, err := executionFromItem(item)
+		if err != nil {
+			return ListScheduleExecutionsOutput{}, err
+		}
+		executions = append(executions, execution)
+	}
+
+	var lastEvaluatedOccurrence *int
+	if len(output.LastEvaluatedKey) != 0 && len(executions) > 0 {
+		lastEvaluatedOccurrence = &executions[len(executions)-1].Occurrence
+	}
+
+	return ListScheduleExecutionsOutput{
+		Executions:              executions,
+		LastEvaluatedOccurrence: lastEvaluatedOccurrence,
+	}, nil
+}
+
+// RunSchedules executes the next occurrence of each schedule which is due. Schedules which fell behind, e.g. while runs
+// were failing, catch up by one occurrence per run. Runs may overlap, as each occurrence is transferred at most once and
+// recorded by whichever run finishes it first.
+func (manager accountManagerImpl) RunSchedules(ctx context.Context, runSchedulesInput RunSchedulesInput) (RunSchedulesOutput, error) {
+	limit := int32(defaultScheduleRunLimit)
+	if runSchedulesInput.Limit != nil {
+		limit = *runSchedulesInput.Limit
+	}
+
+	exprAttrValues := make(map[string]types.AttributeValue)
+	exprAttrValues[":active"] = &types.AttributeValueMemberS{Value: string(ScheduleStatusActive)}
+	exprAttrValues[":now"] = &types.AttributeValueMemberS{Value: time.Now().UTC().Format(scheduleTimeLayout)}
+
+	// The index is eventually consistent, so a schedule which was just advanced or cancelled may still appear due. The
+	// condition on recording its execution catches this.
+	output, err := manager.ddb.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(schedulesTableName),
+		IndexName:                 aws.String(dueSchedulesIndexName),
+		ExpressionAttributeValues: exprAttrValues,
+		KeyConditionExpression:    aws.String(fmt.Sprintf("%s = :active AND %s <= :now", scheduleStatusAttr, nextRunAtAttr)),
+		Limit:                     aws.Int32(limit),
+	})
+	if err != nil {
+		return RunSchedulesOutput{}, err
+	}
+
+	var runOutput RunSchedulesOutput
+	for _, item := range output.Items {
+		schedule, err := scheduleFromItem(item)
+		if err != nil {
+			return runOutput, err
+		}
+
+		execution, err := executeSchedule(ctx, manager, schedule)
+		if errors.Is(err, errOccurrenceExecuted) {
+			continue
+		}
+		if err != nil {
+			log.Printf("Failed to execute occurrence %d of schedule %s, which will be retried: %v", schedule.Occurrences, schedule.ScheduleID, err)
+			continue
+		}
+		if execution.Status == ExecutionStatusFailed {
+			recorded, err := manager.recordExecution(ctx, schedule, execution)
+			if err != nil {
+				return runOutput, err
+			}
+			if !recorded {
+				continue
+			}
+		}
+		runOutput.Executions = append(runOutput.Executions, execution)
+	}
+
+	return runOutput, nil
+}
+
+// recordExecution records the failed execution of a schedule's next occurrence and advances the schedule past it,
+// returning false if another run recorded it first
+func (manager accountManagerImpl) recordExecution(ctx context.Context, schedule Schedule, execution ScheduleExecution) (bool, error) {
+	_, err := manager.ddb.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: executionWrites(schedule, execution),
+	})
+	if err != nil {
+		var transactionCanceledException *types.TransactionCanceledException
+		if !errors.As(err, &transactionCanceledException) {
+			return false, err
+		}
+		if isConditionalCheckFailed(transactionCanceledException, 0) {
+			// The occurrence is still recorded if the schedule was only cancelled meanwhile
+			existing, err := scheduleFromItem(transactionCanceledException.CancellationReasons[0].Item)
+			if err == nil && existing.Occurrences == schedule.Occurrences && existing.Status != schedule.Status {
+				schedule.Status = existing.Status
+				return manager.recordExecution(ctx, schedule, execution)
+			}
+			return false, nil
+		}
+		if isConditionalCheckFailed(transactionCanceledException, 1) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// executionWrites records the execution of a schedule's next occurrence and advances the schedule past it. The writes
+// fail their conditions if the schedule has moved past the occurrence or changed status, or the execution has already
+// been recorded.
+func executionWrites(schedule Schedule, execution ScheduleExecution) []types.TransactWriteItem {
+	advanced := schedule.advance()
+
+	exprAttrValues := make(map[string]types.AttributeValue)
+	exprAttrValues[":old"] = &types.AttributeValueMemberN{Value: strconv.Itoa(schedule.Occurrences)}
+	exprAttrValues[":new"] = &types.AttributeValueMemberN{Value: strconv.Itoa(advanced.Occurrences)}
+	exprAttrValues[":status"] = &types.AttributeValueMemberS{Value: string(schedule.Status)}
+	updateExpression := fmt.Sprintf("SET %s = :new", occurrencesAttr)
+	switch {
+	case advanced.NextRunAt != nil:
+		exprAttrValues[":next"] = &types.AttributeValueMemberS{Value: advanced.NextRunAt.Format(scheduleTimeLayout)}
+		updateExpression += fmt.Sprintf(", %s = :next", nextRunAtAttr)
+	case advanced.Status == ScheduleStatusCompleted:
+		exprAttrValues[":completed"] = &types.AttributeValueMemberS{Value: string(ScheduleStatusCompleted)}
+		updateExpression += fmt.Sprintf(", %s = :completed REMOVE %s", scheduleStatusAttr, nextRunAtAttr)
+	}
+
+	return []types.TransactWriteItem{
+		{
+			Update: &types.Update{
+				Key:                       scheduleKeyItem(schedule.AccountID, schedule.ScheduleID),
+				TableName:                 aws.String(schedulesTableName),
+				UpdateExpression:          aws.String(updateExpression),
+				ConditionExpression:       aws.String(fmt.Sprintf("%s = :old AND %s = :status", occurrencesAttr, scheduleStatusAttr)),
+				ExpressionAttributeValues: exprAttrValues,
+				// Return the existing item on failure to tell whether the schedule was cancelled meanwhile
+				ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+			},
+		},
+		{
+			Put: &types.Put{
+				Item:                execution.toItem(schedule.AccountID),
+				TableName:           aws.String(scheduleExecutionsTableName),
+				ConditionExpression: aws.String(fmt.Sprintf("attribute_not_exists(%s)", scheduleIDAttr)),
+			},
+		},
+	}
+}
+
+func scheduleKeyItem(accountID, scheduleID string) map[string]types.AttributeValue {
+	item := make(map[string]types.AttributeValue)
+	item[accountIDAttr] = &types.AttributeValueMemberS{Value: accountID}
+	item[scheduleIDAttr] = &types.AttributeValueMemberS{Value: scheduleID}
+	return item
+}
+
+func (schedule Schedule) toItem() map[string]types.AttributeValue {
+	item := scheduleKeyItem(schedule.AccountID, schedule.ScheduleID)
+	item[srcAccountTypeAttr] = &types.AttributeValueMemberS{Value: schedule.SrcAccountType}
+	item[destAccountIDAttr] = &types.AttributeValueMemberS{Value: schedule.DestAccountID}
+	item[destAccountTypeAttr] = &types.AttributeValueMemberS{Value: schedule.DestAccountType}
+	item[amountAttr] = &types.AttributeValueMemberN{Value: strconv.Itoa(schedule.Amount)}
+	if schedule.Currency != "" {
+		item[currencyAttr] = &types.AttributeValueMemberS{Value: schedule.Currency}
+	}
+	if schedule.Recurrence != "" {
+		item[recurrenceAttr] = &types.AttributeValueMemberS{Value: schedule.Recurrence}
+	}
+	item[startAtAttr] = &types.AttributeValueMemberS{Value: schedule.StartAt.Format(scheduleTimeLayout)}
+	item[scheduleStatusAttr] = &types.AttributeValueMemberS{Value: string(schedule.Status)}
+	item[occurrencesAttr] = &types.AttributeValueMemberN{Value: strconv.Itoa(schedule.Occurrences)}
+	if schedule.NextRunAt != nil {
+		item[nextRunAtAttr] = &types.AttributeValueMemberS{Value: schedule.NextRunAt.Format(scheduleTimeLayout)}
+	}
+	return item
+}
+
+func scheduleFromItem(item map[string]types.AttributeValue) (Schedule, error) {
+	scheduleID, ok := item[scheduleIDAttr].(*types.AttributeValueMemberS)
+	if !ok {
+		return Schedule{}, errors.New("scheduleID must be a string")
+	}
+	amount, err := numberFromItem(item, amountAttr)
+	if err != nil {
+		return Schedule{}, err
+	}
+	occurrences, err := numberFromItem(item, occurrencesAttr)
+	if err != nil {
+		return Schedule{}, err
+	}
+	startAt, err := time.Parse(scheduleTimeLayout, stringFromItem(item, startAtAttr))
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	schedule := Schedule{
+		ScheduleID:      scheduleID.Value,
+		AccountID:       stringFromItem(item, accountIDAttr),
+		SrcAccountType:  stringFromItem(item, srcAccountTypeAttr),
+		DestAccountID:   stringFromItem(item, destAccountIDAttr),
+		DestAccountType: stringFromItem(item, destAccountTypeAttr),
+		Amount:          amount,
+		Currency:        stringFromItem(item, currencyAttr),
+		Recurrence:      stringFromItem(item, recurrenceAttr),
+		StartAt:         startAt,
+		Status:          ScheduleStatus(stringFromItem(item, scheduleStatusAttr)),
+		Occurrences:     occurrences,
+	}
+	if nextRunAt := stringFromItem(item, nextRunAtAttr); nextRunAt != "" {
+		next, err := time.Parse(scheduleTimeLayout, nextRunAt)
+		if err != nil {
+			return Schedule{}, err
+		}
+		schedule.NextRunAt = &next
+	}
+	return schedule, nil
+}
+
+func executionKeyItem(scheduleID string, occurrence int) map[string]types.AttributeValue {
+	item := make(map[string]types.AttributeValue)
+	item[scheduleIDAttr] = &types.AttributeValueMemberS{Value: scheduleID}
+	item[occurrenceAttr] = &types.AttributeValueMemberS{Value: fmt.Sprintf(occurrenceLayout, occurrence)}
+	return item
+}
+
+func (execution ScheduleExecution) toItem(accountID string) map[string]types.AttributeValue {
+	item := executionKeyItem(execution.ScheduleID, execution.Occurrence)
+	item[accountIDAttr] = &types.AttributeValueMemberS{Value: accountID}
+	item[scheduledAtAttr] = &types.AttributeValueMemberS{Value: execution.ScheduledAt.Format(scheduleTimeLayout)}
+	item[executedAtAttr] = &types.AttributeValueMemberS{Value: execution.ExecutedAt.Format(time.RFC3339Nano)}
+	item[executionStatusAttr] = &types.AttributeValueMemberS{Value: string(execution.Status)}
+	if execution.TransactionID != "" {
+		item[transactionIDAttr] = &types.AttributeValueMemberS{Value: execution.TransactionID}
+	}
+	if execution.Error != "" {
+		item[errorAttr] = &types.AttributeValueMemberS{Value: execution.Error}
+	}
+	return item
+}
+
+func executionFromItem(item map[string]types.AttributeValue) (ScheduleExecution, error) {
+	occurrence, err := strconv.Atoi(stringFromItem(item, occurrenceAttr))
+	if err != nil {
+		return ScheduleExecution{}, fmt.Errorf("occurrence must be a zero padded number: %w", err)
+	}
+	scheduledAt, err := time.Parse(scheduleTimeLayout, stringFromItem(item, scheduledAtAttr))
+	if err != nil {
+		return ScheduleExecution{}, err
+	}
+	executedAt, err := time.Parse(time.RFC3339Nano, stringFromItem(item, executedAtAttr))
+	if err != nil {
+		return ScheduleExecution{}, err
+	}
+
+	return ScheduleExecution{
+		ScheduleID:    stringFromItem(item, scheduleIDAttr),
+		Occurrence:    occurrence,
+		ScheduledAt:   scheduledAt,
+		ExecutedAt:    executedAt,
+		Status:        ExecutionStatus(stringFromItem(item, executionStatusAttr)),
+		TransactionID: stringFromItem(item, transactionIDAttr),
+		Error:         stringFromItem(item, errorAttr),
+	}, nil
+}
@@ -0,0 +1,223 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestSchedule_Advance(t *testing.T) {
+	// === Given ===
+	startAt := time.Date(2023, 1, 31, 9, 0, 0, 0, time.UTC)
+	schedule := newSchedule("123456789", CreateScheduleInput{
+		SrcAccountType:  "checking",
+		DestAccountID:   "987654321",
+		DestAccountType: "savings",
+		Amount:          aws.Int(5),
+		StartAt:         &startAt,
+		Recurrence:      "FREQ=MONTHLY;COUNT=2",
+	})
+
+	// === When ===
+	second := schedule.advance()
+	completed := second.advance()
+
+	// === Then ===
+	assert.Equal(t, startAt, *schedule.NextRunAt)
+	assert.Equal(t, ScheduleStatusActive, second.Status)
+	assert.Equal(t, 1, second.Occurrences)
+	assert.Equal(t, time.Date(2023, 2, 28, 9, 0, 0, 0, time.UTC), *second.NextRunAt)
+	assert.Equal(t, ScheduleStatusCompleted, completed.Status)
+	assert.Equal(t, 2, completed.Occurrences)
+	assert.Nil(t, completed.NextRunAt)
+}
+
+func TestSchedule_StartBetweenOccurrences(t *testing.T) {
+	// === Given ===
+	startAt := time.Date(2023, 1, 15, 9, 0, 0, 0, time.UTC)
+	schedule := newSchedule("123456789", CreateScheduleInput{
+		SrcAccountType:  "checking",
+		DestAccountID:   "987654321",
+		DestAccountType: "savings",
+		Amount:          aws.Int(5),
+		StartAt:         &startAt,
+		Recurrence:      "FREQ=MONTHLY;BYMONTHDAY=1",
+	})
+
+	// === When ===
+	second := schedule.advance()
+	third := second.advance()
+
+	// === Then ===
+	// The first transfer is on the first matching day after the start, rather than at the start itself
+	assert.Equal(t, time.Date(2023, 2, 1, 9, 0, 0, 0, time.UTC), *schedule.NextRunAt)
+	assert.Equal(t, time.Date(2023, 3, 1, 9, 0, 0, 0, time.UTC), *second.NextRunAt)
+	assert.Equal(t, time.Date(2023, 4, 1, 9, 0, 0, 0, time.UTC), *third.NextRunAt)
+}
+
+func TestSchedule_RecurrenceEndingBeforeStart(t *testing.T) {
+	// === Given ===
+	startAt := time.Date(2023, 1, 15, 9, 0, 0, 0, time.UTC)
+
+	// === When ===
+	schedule := newSchedule("123456789", CreateScheduleInput{
+		SrcAccountType:  "checking",
+		DestAccountID:   "987654321",
+		DestAccountType: "savings",
+		Amount:          aws.Int(5),
+		StartAt:         &startAt,
+		Recurrence:      "FREQ=MONTHLY;BYMONTHDAY=1;UNTIL=20230131T000000Z",
+	})
+
+	// === Then ===
+	assert.Equal(t, ScheduleStatusCompleted, schedule.Status)
+	assert.Nil(t, schedule.NextRunAt)
+}
+
+func TestSchedule_AdvanceWhenCancelled(t *testing.T) {
+	// === Given ===
+	schedule := newSchedule("123456789", CreateScheduleInput{
+		SrcAccountType:  "checking",
+		DestAccountID:   "987654321",
+		DestAccountType: "savings",
+		Amount:          aws.Int(5),
+		Recurrence:      "FREQ=DAILY",
+	})
+	schedule.Status = ScheduleStatusCancelled
+
+	// === When ===
+	advanced := schedule.advance()
+
+	// === Then ===
+	assert.Equal(t, ScheduleStatusCancelled, advanced.Status)
+	assert.Equal(t, 1, advanced.Occurrences)
+	assert.Nil(t, advanced.NextRunAt)
+}
+
+func TestSchedule_TransferInputRecordsOccurrence(t *testing.T) {
+	// === Given ===
+	schedule := newSchedule("123456789", CreateScheduleInput{
+		SrcAccountType:  "checking",
+		DestAccountID:   "987654321",
+		DestAccountType: "savings",
+		Amount:          aws.Int(5),
+		Recurrence:      "FREQ=DAILY",
+	})
+
+	// === When ===
+	first := schedule.transferInput()
+	second := schedule.advance().transferInput()
+
+	// === Then ===
+	assert.Equal(t, 5, *first.Amount)
+	require.NotNil(t, first.occurrence)
+	assert.Equal(t, 0, first.occurrence.Occurrences)
+	require.NotNil(t, second.occurrence)
+	assert.Equal(t, 1, second.occurrence.Occurrences)
+}
+
+func TestSchedule_ItemRoundTrip(t *testing.T) {
+	// === Given ===
+	nextRunAt := time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+	schedule := Schedule{
+		ScheduleID:      "0123456789abcdef",
+		AccountID:       "123456789",
+		SrcAccountType:  "checking",
+		DestAccountID:   "987654321",
+		DestAccountType: "savings",
+		Amount:          5,
+		Currency:        "USD",
+		Recurrence:      "FREQ=MONTHLY;BYMONTHDAY=1",
+		StartAt:         time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		Status:          ScheduleStatusActive,
+		Occurrences:     1,
+		NextRunAt:       &nextRunAt,
+	}
+
+	// === When ===
+	parsed, err := scheduleFromItem(schedule.toItem())
+
+	// === Then ===
+	require.NoError(t, err)
+	assert.Equal(t, schedule, parsed)
+}
+
+func TestScheduleExecution_ItemRoundTrip(t *testing.T) {
+	// === Given ===
+	execution := ScheduleExecution{
+		ScheduleID:  "0123456789abcdef",
+		Occurrence:  12,
+		ScheduledAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExecutedAt:  time.Date(2023, 1, 1, 0, 2, 3, 456, time.UTC),
+		Status:      ExecutionStatusFailed,
+		Error:       InsufficientFundsError{AccountID: "123456789", AccountType: "checking"}.Error(),
+	}
+	item := execution.toItem("123456789")
+
+	// === When ===
+	parsed, err := executionFromItem(item)
+
+	// === Then ===
+	require.NoError(t, err)
+	assert.Equal(t, execution, parsed)
+	assert.Equal(t, "0000000012", stringFromItem(item, occurrenceAttr))
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "conflict",
+			err:      TransactionConflictError{},
+			expected: true,
+		},
+		{
+			name:     "throttled",
+			err:      fmt.Errorf("operation error DynamoDB: GetItem: %w", &types.ProvisionedThroughputExceededException{}),
+			expected: true,
+		},
+		{
+			name: "throttled transaction",
+			err: &types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{
+				{Code: aws.String("None")},
+				{Code: aws.String(throttlingErrorReason)},
+			}},
+			expected: true,
+		},
+		{
+			name:     "deadline exceeded",
+			err:      fmt.Errorf("operation error DynamoDB: TransactWriteItems: %w", context.DeadlineExceeded),
+			expected: true,
+		},
+		{
+			name:     "insufficient funds",
+			err:      InsufficientFundsError{AccountID: "123456789", AccountType: "checking"},
+			expected: false,
+		},
+		{
+			name:     "self transfer",
+			err:      SelfTransferError{AccountID: "123456789", AccountType: "checking"},
+			expected: false,
+		},
+		{
+			name:     "unrecognized",
+			err:      errors.New("ERROR"),
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, isTransient(test.err))
+		})
+	}
+}
@@ -0,0 +1,376 @@
+package internal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"golang.org/x/exp/slices"
+	"strconv"
+	"time"
+)
+
+const (
+	transactionsTableName = "transactions-table"
+
+	transactionIDAttr   = "TransactionId"
+	transactionTypeAttr = "TransactionType"
+	timestampAttr       = "Timestamp"
+	amountAttr          = "Amount"
+	srcAccountIDAttr    = "SrcAccountId"
+	srcAccountTypeAttr  = "SrcAccountType"
+	srcBalanceAttr      = "SrcBalance"
+	destAccountIDAttr   = "DestAccountId"
+	destAccountTypeAttr = "DestAccountType"
+	destBalanceAttr     = "DestBalance"
+	destAmountAttr      = "DestAmount"
+	srcCurrencyAttr     = "SrcCurrency"
+	destCurrencyAttr    = "DestCurrency"
+	externalRefAttr     = "ExternalReference"
+)
+
+type TransactionType string
+
+const (
+	TransactionTypeCreate   TransactionType = "CREATE"
+	TransactionTypeTransfer TransactionType = "TRANSFER"
+	TransactionTypeDelete   TransactionType = "DELETE"
+	// Money entering the ledger from outside, e.g. by wire
+	TransactionTypeDeposit TransactionType = "DEPOSIT"
+	// Money leaving the ledger
+	TransactionTypeWithdrawal TransactionType = "WITHDRAWAL"
+	// Funds reserved by a hold moving to the hold's destination
+	TransactionTypeCapture TransactionType = "CAPTURE"
+	// Interest accrued by an account being posted to its balance
+	TransactionTypeInterest TransactionType = "INTEREST"
+	// The balance of an account being swept to another account as it is closed
+	TransactionTypeClose TransactionType = "CLOSE"
+)
+
+// TransactionParty is an account affected by a transaction, along with its balance after the transaction was applied
+type TransactionParty struct {
+	AccountID   string `json:"accountID"`
+	AccountType string `json:"accountType"`
+	// Currency of the account, which is not recorded for deleted accounts or before accounts had currencies
+	Currency string `json:"currency,omitempty"`
+	Balance  int    `json:"balance"`
+}
+
+// Transaction is an immutable record of a change to one or more account balances
+type Transaction struct {
+	TransactionID string          `json:"transactionID"`
+	Type          TransactionType `json:"type"`
+	Timestamp     time.Time       `json:"timestamp"`
+	// Amount in minor units of Currency, which is the currency of the source account, or of the destination account if
+	// there is no source
+	Amount   int    `json:"amount"`
+	Currency string `json:"currency,omitempty"`
+	// Amount credited to the destination account in its own currency, for transfers which convert between currencies
+	DestAmount int `json:"destAmount,omitempty"`
+	// Rate that DestAmount was converted at, for conversions at a quoted rate
+	Conversion *Conversion       `json:"conversion,omitempty"`
+	Src        *TransactionParty `json:"src,omitempty"`
+	Dest       *TransactionParty `json:"dest,omitempty"`
+	// Identifies the movement of money outside the ledger, for deposits and withdrawals
+	ExternalReference string `json:"externalReference,omitempty"`
+	// ID of the hold that a capture settled
+	HoldID string `json:"holdID,omitempty"`
+	// Fees charged to the source account of a transfer on top of Amount, in the same currency
+	Fees []FeeCharge `json:"fees,omitempty"`
+	// Journal entry of the transaction, which sums to zero
+	Postings []Posting `json:"postings,omitempty"`
+}
+
+func newTransaction(transactionType TransactionType, amount int, src, dest *TransactionParty) Transaction {
+	timestamp := time.Now().UTC()
+	tx := Transaction{
+		TransactionID: newTransactionID(timestamp),
+		Type:          transactionType,
+		Timestamp:     timestamp,
+		Amount:        amount,
+		Src:           src,
+		Dest:          dest,
+	}
+	if src != nil {
+		tx.Currency = src.Currency
+	} else if dest != nil {
+		tx.Currency = dest.Currency
+	}
+	tx.Postings = journalEntry(&tx)
+	return tx
+}
+
+// convert records that the destination of a transfer was credited with destAmount in its own currency, at the rate of
+// quote unless the amount was given directly
+func (tx *Transaction) convert(destAmount int, quote *Quote) {
+	tx.DestAmount = destAmount
+	if quote != nil {
+		tx.Conversion = quote.conversion()
+	}
+	tx.Postings = journalEntry(tx)
+}
+
+// Transaction IDs are prefixed with a fixed-width timestamp so that they sort chronologically within a partition
+func newTransactionID(timestamp time.Time) string {
+	suffix := make([]byte, 8)
+	_, _ = rand.Read(suffix)
+	return fmt.Sprintf("%020d-%s", timestamp.UnixNano(), hex.EncodeToString(suffix))
+}
+
+// ownerAccountIDs returns the distinct account IDs of the owners of the accounts that the transaction posts to, so that
+// the balance of any account can be derived from the transactions of its owner. System accounts are not owned.
+func (tx *Transaction) ownerAccountIDs() []string {
+	var accountIDs []string
+	add := func(accountID string) {
+		if accountID != SystemAccountID && !slices.Contains(accountIDs, accountID) {
+			accountIDs = append(accountIDs, accountID)
+		}
+	}
+	if tx.Src != nil {
+		add(tx.Src.AccountID)
+	}
+	if tx.Dest != nil {
+		add(tx.Dest.AccountID)
+	}
+	for _, posting := range tx.Postings {
+		add(posting.AccountID)
+	}
+	return accountIDs
+}
+
+// partitions returns the keys that the transaction is recorded under, which are its owners along with the shard of each
+// system account that it posts to
+func (tx *Transaction) partitions() []string {
+	partitions := tx.ownerAccountIDs()
+	for _, posting := range tx.Postings {
+		if posting.AccountID != SystemAccountID {
+			continue
+		}
+		if partition := systemPartition(posting.accountKey(), tx.Timestamp); !slices.Contains(partitions, partition) {
+			partitions = append(partitions, partition)
+		}
+	}
+	return partitions
+}
+
+func (tx *Transaction) toItem(accountID string) map[string]types.AttributeValue {
+	item := make(map[string]types.AttributeValue)
+	item[accountIDAttr] = &types.AttributeValueMemberS{Value: accountID}
+	item[transactionIDAttr] = &types.AttributeValueMemberS{Value: tx.TransactionID}
+	item[transactionTypeAttr] = &types.AttributeValueMemberS{Value: string(tx.Type)}
+	item[timestampAttr] = &types.AttributeValueMemberS{Value: tx.Timestamp.Format(time.RFC3339Nano)}
+	item[amountAttr] = &types.AttributeValueMemberN{Value: strconv.Itoa(tx.Amount)}
+	if tx.Currency != "" {
+		item[currencyAttr] = &types.AttributeValueMemberS{Value: tx.Currency}
+	}
+	if tx.DestAmount != 0 {
+		item[destAmountAttr] = &types.AttributeValueMemberN{Value: strconv.Itoa(tx.DestAmount)}
+	}
+	if tx.Conversion != nil {
+		item[quoteIDAttr] = &types.AttributeValueMemberS{Value: tx.Conversion.QuoteID}
+		item[rateAttr] = &types.AttributeValueMemberS{Value: tx.Conversion.Rate}
+		item[roundingAttr] = &types.AttributeValueMemberS{Value: tx.Conversion.Rounding}
+	}
+	if tx.Src != nil {
+		item[srcAccountIDAttr] = &types.AttributeValueMemberS{Value: tx.Src.AccountID}
+		item[srcAccountTypeAttr] = &types.AttributeValueMemberS{Value: tx.Src.AccountType}
+		item[srcBalanceAttr] = &types.AttributeValueMemberN{Value: strconv.Itoa(tx.Src.Balance)}
+		if tx.Src.Currency != "" {
+			item[srcCurrencyAttr] = &types.AttributeValueMemberS{Value: tx.Src.Currency}
+		}
+	}
+	if tx.Dest != nil {
+		item[destAccountIDAttr] = &types.AttributeValueMemberS{Value: tx.Dest.AccountID}
+		item[destAccountTypeAttr] = &types.AttributeValueMemberS{Value: tx.Dest.AccountType}
+		item[destBalanceAttr] = &types.AttributeValueMemberN{Value: strconv.Itoa(tx.Dest.Balance)}
+		if tx.Dest.Currency != "" {
+			item[destCurrencyAttr] = &types.AttributeValueMemberS{Value: tx.Dest.Currency}
+		}
+	}
+	if tx.ExternalReference != "" {
+		item[externalRefAttr] = &types.AttributeValueMemberS{Value: tx.ExternalReference}
+	}
+	if tx.HoldID != "" {
+		item[holdIDAttr] = &types.AttributeValueMemberS{Value: tx.HoldID}
+	}
+	if len(tx.Fees) > 0 {
+		item[feesAttr] = feesToAttributeValue(tx.Fees)
+	}
+	if len(tx.Postings) > 0 {
+		item[postingsAttr] = postingsToAttributeValue(tx.Postings)
+	}
+	return item
+}
+
+// toTransactWriteItems returns the writes which record the transaction under each of its partitions. These are intended
+// to be included in the same TransactWriteItems call that applies the balance changes.
+func (tx *Transaction) toTransactWriteItems() []types.TransactWriteItem {
+	var transactItems []types.TransactWriteItem
+	for _, accountID := range tx.partitions() {
+		transactItems = append(transactItems, types.TransactWriteItem{
+			Put: &types.Put{
+				Item:      tx.toItem(accountID),
+				TableName: aws.String(transactionsTableName),
+				// Transactions are immutable once written
+				ConditionExpression: aws.String(fmt.Sprintf("attribute_not_exists(%s)", transactionIDAttr)),
+			},
+		})
+	}
+	return transactItems
+}
+
+func NewTransactionFromItem(item map[string]types.AttributeValue) (Transaction, error) {
+	transactionID, ok := item[transactionIDAttr].(*types.AttributeValueMemberS)
+	if !ok {
+		return Transaction{}, errors.New("transactionID must be a string")
+	}
+
+	transactionType, ok := item[transactionTypeAttr].(*types.AttributeValueMemberS)
+	if !ok {
+		return Transaction{}, errors.New("transactionType must be a string")
+	}
+
+	timestampValue, ok := item[timestampAttr].(*types.AttributeValueMemberS)
+	if !ok {
+		return Transaction{}, errors.New("timestamp must be a string")
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, timestampValue.Value)
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	amount, err := numberFromItem(item, amountAttr)
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	var destAmount int
+	if _, ok := item[destAmountAttr]; ok {
+		destAmount, err = numberFromItem(item, destAmountAttr)
+		if err != nil {
+			return Transaction{}, err
+		}
+	}
+
+	src, err := transactionPartyFromItem(item, srcAccountIDAttr, srcAccountTypeAttr, srcCurrencyAttr, srcBalanceAttr)
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	dest, err := transactionPartyFromItem(item, destAccountIDAttr, destAccountTypeAttr, destCurrencyAttr, destBalanceAttr)
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	tx := Transaction{
+		TransactionID:     transactionID.Value,
+		Type:              TransactionType(transactionType.Value),
+		Timestamp:         timestamp,
+		Amount:            amount,
+		Currency:          stringFromItem(item, currencyAttr),
+		DestAmount:        destAmount,
+		Src:               src,
+		Dest:              dest,
+		ExternalReference: stringFromItem(item, externalRefAttr),
+		HoldID:            stringFromItem(item, holdIDAttr),
+	}
+	if quoteID := stringFromItem(item, quoteIDAttr); quoteID != "" {
+		tx.Conversion = &Conversion{
+			QuoteID:  quoteID,
+			Rate:     stringFromItem(item, rateAttr),
+			Rounding: stringFromItem(item, roundingAttr),
+		}
+	}
+
+	if attrValue, ok := item[feesAttr]; ok {
+		tx.Fees, err = feesFromAttributeValue(attrValue)
+		if err != nil {
+			return Transaction{}, err
+		}
+	}
+
+	// Transactions recorded before the journal have no postings, which are the same as those of a new transaction
+	if attrValue, ok := item[postingsAttr]; ok {
+		tx.Postings, err = postingsFromAttributeValue(attrValue)
+		if err != nil {
+			return Transaction{}, err
+		}
+	} else {
+		tx.Postings = journalEntry(&tx)
+	}
+
+	return tx, nil
+}
+
+func transactionPartyFromItem(item map[string]types.AttributeValue, accountIDKey, accountTypeKey, currencyKey, balanceKey string) (*TransactionParty, error) {
+	if _, ok := item[accountIDKey]; !ok {
+		return nil, nil
+	}
+
+	accountKey, err := NewAccountKeyFromItem(map[string]types.AttributeValue{
+		accountIDAttr:   item[accountIDKey],
+		accountTypeAttr: item[accountTypeKey],
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := numberFromItem(item, balanceKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TransactionParty{
+		AccountID:   accountKey.AccountID,
+		AccountType: accountKey.AccountType,
+		Currency:    stringFromItem(item, currencyKey),
+		Balance:     balance,
+	}, nil
+}
+
+// stringFromItem returns the value of an optional string attribute, which is empty if the attribute is absent
+func stringFromItem(item map[string]types.AttributeValue, key string) string {
+	if attrValue, ok := item[key].(*types.AttributeValueMemberS); ok {
+		return attrValue.Value
+	}
+	return ""
+}
+
+func numberFromItem(item map[string]types.AttributeValue, key string) (int, error) {
+	attrValue, ok := item[key].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, fmt.Errorf("%s must be a number", key)
+	}
+	return strconv.Atoi(attrValue.Value)
+}
+
+func NewTransactionKeyFromItem(item map[string]types.AttributeValue) (TransactionKey, error) {
+	accountID, ok := item[accountIDAttr].(*types.AttributeValueMemberS)
+	if !ok {
+		return TransactionKey{}, errors.New("accountID must be a string")
+	}
+
+	transactionID, ok := item[transactionIDAttr].(*types.AttributeValueMemberS)
+	if !ok {
+		return TransactionKey{}, errors.New("transactionID must be a string")
+	}
+
+	return TransactionKey{
+		AccountID:     accountID.Value,
+		TransactionID: transactionID.Value,
+	}, nil
+}
+
+type TransactionKey struct {
+	AccountID     string `json:"accountID"`
+	TransactionID string `json:"transactionID"`
+}
+
+func (key *TransactionKey) toTransactionItem() map[string]types.AttributeValue {
+	item := make(map[string]types.AttributeValue)
+	item[accountIDAttr] = &types.AttributeValueMemberS{Value: key.AccountID}
+	item[transactionIDAttr] = &types.AttributeValueMemberS{Value: key.TransactionID}
+	return item
+}
@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTransaction_ItemRoundTrip(t *testing.T) {
+	// === Given ===
+	tx := newTransaction(TransactionTypeTransfer, 5, &TransactionParty{
+		AccountID:   "123456789",
+		AccountType: "savings",
+		Currency:    "USD",
+		Balance:     10,
+	}, &TransactionParty{
+		AccountID:   "987654321",
+		AccountType: "checking",
+		Currency:    "EUR",
+		Balance:     4,
+	})
+	tx.convert(4, &Quote{QuoteID: "0123456789abcdef", Rate: "0.92", Rounding: "-0.6"})
+	tx.ExternalReference = "wire-0001"
+	tx.HoldID = "fedcba9876543210"
+	tx.chargeFees([]FeeCharge{{Type: FeeTypeCrossOwner, Amount: 1}, {Type: FeeTypeFX, Amount: 2}})
+
+	// === When ===
+	parsed, err := NewTransactionFromItem(tx.toItem("123456789"))
+
+	// === Then ===
+	assert.NoError(t, err)
+	assert.Equal(t, tx.TransactionID, parsed.TransactionID)
+	assert.True(t, tx.Timestamp.Equal(parsed.Timestamp))
+	assert.Equal(t, tx.Amount, parsed.Amount)
+	assert.Equal(t, tx.Currency, parsed.Currency)
+	assert.Equal(t, tx.DestAmount, parsed.DestAmount)
+	assert.Equal(t, tx.Conversion, parsed.Conversion)
+	assert.Equal(t, tx.Src, parsed.Src)
+	assert.Equal(t, tx.Dest, parsed.Dest)
+	assert.Equal(t, tx.ExternalReference, parsed.ExternalReference)
+	assert.Equal(t, tx.HoldID, parsed.HoldID)
+	assert.Equal(t, tx.Fees, parsed.Fees)
+	assert.Equal(t, tx.Postings, parsed.Postings)
+}
+
+func TestTransaction_RecordedUnderEachOwner(t *testing.T) {
+	// === Given ===
+	crossOwner := newTransaction(TransactionTypeTransfer, 5,
+		&TransactionParty{AccountID: "123456789", AccountType: "savings"},
+		&TransactionParty{AccountID: "987654321", AccountType: "checking"})
+	sameOwner := newTransaction(TransactionTypeTransfer, 5,
+		&TransactionParty{AccountID: "123456789", AccountType: "savings"},
+		&TransactionParty{AccountID: "123456789", AccountType: "checking"})
+	create := newTransaction(TransactionTypeCreate, 5, nil,
+		&TransactionParty{AccountID: "123456789", AccountType: "savings"})
+
+	// === Then ===
+	assert.Equal(t, []string{"123456789", "987654321"}, crossOwner.ownerAccountIDs())
+	assert.Equal(t, []string{"123456789"}, sameOwner.ownerAccountIDs())
+	// The opening balance is posted to a system account, which is recorded under its shard for the day
+	assert.Equal(t, []string{"123456789"}, create.ownerAccountIDs())
+	assert.Equal(t, []string{"123456789", "system:opening-balances:" + create.Timestamp.Format("2006-01-02")}, create.partitions())
+	assert.Len(t, crossOwner.toTransactWriteItems(), 2)
+	assert.Len(t, create.toTransactWriteItems(), 2)
+}
+
+func TestTransaction_IDsSortChronologically(t *testing.T) {
+	// === Given ===
+	first := newTransaction(TransactionTypeCreate, 0, nil, &TransactionParty{})
+	second := newTransaction(TransactionTypeCreate, 0, nil, &TransactionParty{})
+
+	// === Then ===
+	assert.Less(t, first.TransactionID, second.TransactionID)
+}
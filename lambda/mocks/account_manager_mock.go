@@ -35,6 +35,65 @@ func (m *MockAccountManager) EXPECT() *MockAccountManagerMockRecorder {
 	return m.recorder
 }
 
+// AccrueProductInterest mocks base method.
+func (m *MockAccountManager) AccrueProductInterest(ctx context.Context, accrueProductInterestInput internal.AccrueProductInterestInput) (internal.ProductAccrual, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrueProductInterest", ctx, accrueProductInterestInput)
+	ret0, _ := ret[0].(internal.ProductAccrual)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccrueProductInterest indicates an expected call of AccrueProductInterest.
+func (mr *MockAccountManagerMockRecorder) AccrueProductInterest(ctx, accrueProductInterestInput interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrueProductInterest", reflect.TypeOf((*MockAccountManager)(nil).AccrueProductInterest), ctx, accrueProductInterestInput)
+}
+
+// CancelSchedule mocks base method.
+func (m *MockAccountManager) CancelSchedule(ctx context.Context, accountID string, cancelScheduleInput internal.CancelScheduleInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelSchedule", ctx, accountID, cancelScheduleInput)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelSchedule indicates an expected call of CancelSchedule.
+func (mr *MockAccountManagerMockRecorder) CancelSchedule(ctx, accountID, cancelScheduleInput interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSchedule", reflect.TypeOf((*MockAccountManager)(nil).CancelSchedule), ctx, accountID, cancelScheduleInput)
+}
+
+// CaptureHold mocks base method.
+func (m *MockAccountManager) CaptureHold(ctx context.Context, captureHoldInput internal.CaptureHoldInput) (internal.CaptureHoldOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureHold", ctx, captureHoldInput)
+	ret0, _ := ret[0].(internal.CaptureHoldOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CaptureHold indicates an expected call of CaptureHold.
+func (mr *MockAccountManagerMockRecorder) CaptureHold(ctx, captureHoldInput interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHold", reflect.TypeOf((*MockAccountManager)(nil).CaptureHold), ctx, captureHoldInput)
+}
+
+// CloseAccount mocks base method.
+func (m *MockAccountManager) CloseAccount(ctx context.Context, accountID string, closeAccountInput internal.CloseAccountInput) (internal.CloseAccountOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseAccount", ctx, accountID, closeAccountInput)
+	ret0, _ := ret[0].(internal.CloseAccountOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseAccount indicates an expected call of CloseAccount.
+func (mr *MockAccountManagerMockRecorder) CloseAccount(ctx, accountID, closeAccountInput interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAccount", reflect.TypeOf((*MockAccountManager)(nil).CloseAccount), ctx, accountID, closeAccountInput)
+}
+
 // CreateAccount mocks base method.
 func (m *MockAccountManager) CreateAccount(ctx context.Context, accountID string, createAccountInput internal.CreateAccountInput) error {
 	m.ctrl.T.Helper()
@@ -49,6 +108,21 @@ func (mr *MockAccountManagerMockRecorder) CreateAccount(ctx, accountID, createAc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockAccountManager)(nil).CreateAccount), ctx, accountID, createAccountInput)
 }
 
+// CreateSchedule mocks base method.
+func (m *MockAccountManager) CreateSchedule(ctx context.Context, accountID string, createScheduleInput internal.CreateScheduleInput) (internal.CreateScheduleOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSchedule", ctx, accountID, createScheduleInput)
+	ret0, _ := ret[0].(internal.CreateScheduleOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSchedule indicates an expected call of CreateSchedule.
+func (mr *MockAccountManagerMockRecorder) CreateSchedule(ctx, accountID, createScheduleInput interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSchedule", reflect.TypeOf((*MockAccountManager)(nil).CreateSchedule), ctx, accountID, createScheduleInput)
+}
+
 // DeleteAccount mocks base method.
 func (m *MockAccountManager) DeleteAccount(ctx context.Context, accountID string, deleteAccountInput internal.DeleteAccountInput) error {
 	m.ctrl.T.Helper()
@@ -63,6 +137,21 @@ func (mr *MockAccountManagerMockRecorder) DeleteAccount(ctx, accountID, deleteAc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockAccountManager)(nil).DeleteAccount), ctx, accountID, deleteAccountInput)
 }
 
+// Deposit mocks base method.
+func (m *MockAccountManager) Deposit(ctx context.Context, depositInput internal.DepositInput) (internal.DepositOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deposit", ctx, depositInput)
+	ret0, _ := ret[0].(internal.DepositOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Deposit indicates an expected call of Deposit.
+func (mr *MockAccountManagerMockRecorder) Deposit(ctx, depositInput interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deposit", reflect.TypeOf((*MockAccountManager)(nil).Deposit), ctx, depositInput)
+}
+
 // GetBalance mocks base method.
 func (m *MockAccountManager) GetBalance(ctx context.Context, accountID string, getBalanceInput internal.GetBalanceInput) (internal.GetBalanceOutput, error) {
 	m.ctrl.T.Helper()
@@ -108,16 +197,223 @@ func (mr *MockAccountManagerMockRecorder) ListAccountsAdmin(ctx, listAccountsInp
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountsAdmin", reflect.TypeOf((*MockAccountManager)(nil).ListAccountsAdmin), ctx, listAccountsInput)
 }
 
-// Transfer mocks base method.
-func (m *MockAccountManager) Transfer(ctx context.Context, srcAccountID string, transferInput internal.TransferInput) error {
+// ListProducts mocks base method.
+func (m *MockAccountManager) ListProducts(ctx context.Context, listProductsInput internal.ListProductsInput) (internal.ListProductsOutput, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Transfer", ctx, srcAccountID, transferInput)
+	ret := m.ctrl.Call(m, "ListProducts", ctx, listProductsInput)
+	ret0, _ := ret[0].(internal.ListProductsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProducts indicates an expected call of ListProducts.
+func (mr *MockAccountManagerMockRecorder) ListProducts(ctx, listProductsInput interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProducts", reflect.TypeOf((*MockAccountManager)(nil).ListProducts), ctx, listProductsInput)
+}
+
+// ListScheduleExecutions mocks base method.
+func (m *MockAccountManager) ListScheduleExecutions(ctx context.Context, accountID string, listScheduleExecutionsInput internal.ListScheduleExecutionsInput) (internal.ListScheduleExecutionsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduleExecutions", ctx, accountID, listScheduleExecutionsInput)
+	ret0, _ := ret[0].(internal.ListScheduleExecutionsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduleExecutions indicates an expected call of ListScheduleExecutions.
+func (mr *MockAccountManagerMockRecorder) ListScheduleExecutions(ctx, accountID, listScheduleExecutionsInput interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduleExecutions", reflect.TypeOf((*MockAccountManager)(nil).ListScheduleExecutions), ctx, accountID, listScheduleExecutionsInput)
+}
+
+// ListSchedules mocks base method.
+func (m *MockAccountManager) ListSchedules(ctx context.Context, accountID string, listSchedulesInput internal.ListSchedulesInput) (internal.ListSchedulesOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSchedules", ctx, accountID, listSchedulesInput)
+	ret0, _ := ret[0].(internal.ListSchedulesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSchedules indicates an expected call of ListSchedules.
+func (mr *MockAccountManagerMockRecorder) ListSchedules(ctx, accountID, listSchedulesInput interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSchedules", reflect.TypeOf((*MockAccountManager)(nil).ListSchedules), ctx, accountID, listSchedulesInput)
+}
+
+// ListTransactions mocks base method.
+func (m *MockAccountManager) ListTransactions(ctx context.Context, accountID string, listTransactionsInput internal.ListTransactionsInput) (internal.ListTransactionsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactions", ctx, accountID, listTransactionsInput)
+	ret0, _ := ret[0].(internal.ListTransactionsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransactions indicates an expected call of ListTransactions.
+func (mr *MockAccountManagerMockRecorder) ListTransactions(ctx, accountID, listTransactionsInput interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactions", reflect.TypeOf((*MockAccountManager)(nil).ListTransactions), ctx, accountID, listTransactionsInput)
+}
+
+// PlaceHold mocks base method.
+func (m *MockAccountManager) PlaceHold(ctx context.Context, placeHoldInput internal.PlaceHoldInput) (internal.PlaceHoldOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlaceHold", ctx, placeHoldInput)
+	ret0, _ := ret[0].(internal.PlaceHoldOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceHold indicates an expected call of PlaceHold.
+func (mr *MockAccountManagerMockRecorder) PlaceHold(ctx, placeHoldInput interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceHold", reflect.TypeOf((*MockAccountManager)(nil).PlaceHold), ctx, placeHoldInput)
+}
+
+// PutProduct mocks base method.
+func (m *MockAccountManager) PutProduct(ctx context.Context, product internal.Product) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutProduct", ctx, product)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutProduct indicates an expected call of PutProduct.
+func (mr *MockAccountManagerMockRecorder) PutProduct(ctx, product interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutProduct", reflect.TypeOf((*MockAccountManager)(nil).PutProduct), ctx, product)
+}
+
+// Quote mocks base method.
+func (m *MockAccountManager) Quote(ctx context.Context, accountID string, quoteInput internal.QuoteInput) (internal.QuoteOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Quote", ctx, accountID, quoteInput)
+	ret0, _ := ret[0].(internal.QuoteOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Quote indicates an expected call of Quote.
+func (mr *MockAccountManagerMockRecorder) Quote(ctx, accountID, quoteInput interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Quote", reflect.TypeOf((*MockAccountManager)(nil).Quote), ctx, accountID, quoteInput)
+}
+
+// QuoteTransfer mocks base method.
+func (m *MockAccountManager) QuoteTransfer(ctx context.Context, srcAccountID string, transferInput internal.TransferInput) (internal.QuoteTransferOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuoteTransfer", ctx, srcAccountID, transferInput)
+	ret0, _ := ret[0].(internal.QuoteTransferOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QuoteTransfer indicates an expected call of QuoteTransfer.
+func (mr *MockAccountManagerMockRecorder) QuoteTransfer(ctx, srcAccountID, transferInput interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuoteTransfer", reflect.TypeOf((*MockAccountManager)(nil).QuoteTransfer), ctx, srcAccountID, transferInput)
+}
+
+// Reconcile mocks base method.
+func (m *MockAccountManager) Reconcile(ctx context.Context, reconcileInput internal.ReconcileInput) (internal.ReconcileOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reconcile", ctx, reconcileInput)
+	ret0, _ := ret[0].(internal.ReconcileOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reconcile indicates an expected call of Reconcile.
+func (mr *MockAccountManagerMockRecorder) Reconcile(ctx, reconcileInput interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*MockAccountManager)(nil).Reconcile), ctx, reconcileInput)
+}
+
+// ReleaseHold mocks base method.
+func (m *MockAccountManager) ReleaseHold(ctx context.Context, releaseHoldInput internal.ReleaseHoldInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseHold", ctx, releaseHoldInput)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseHold indicates an expected call of ReleaseHold.
+func (mr *MockAccountManagerMockRecorder) ReleaseHold(ctx, releaseHoldInput interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseHold", reflect.TypeOf((*MockAccountManager)(nil).ReleaseHold), ctx, releaseHoldInput)
+}
+
+// RunSchedules mocks base method.
+func (m *MockAccountManager) RunSchedules(ctx context.Context, runSchedulesInput internal.RunSchedulesInput) (internal.RunSchedulesOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunSchedules", ctx, runSchedulesInput)
+	ret0, _ := ret[0].(internal.RunSchedulesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunSchedules indicates an expected call of RunSchedules.
+func (mr *MockAccountManagerMockRecorder) RunSchedules(ctx, runSchedulesInput interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunSchedules", reflect.TypeOf((*MockAccountManager)(nil).RunSchedules), ctx, runSchedulesInput)
+}
+
+// SetAccountStatus mocks base method.
+func (m *MockAccountManager) SetAccountStatus(ctx context.Context, setAccountStatusInput internal.SetAccountStatusInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAccountStatus", ctx, setAccountStatusInput)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// SetAccountStatus indicates an expected call of SetAccountStatus.
+func (mr *MockAccountManagerMockRecorder) SetAccountStatus(ctx, setAccountStatusInput interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccountStatus", reflect.TypeOf((*MockAccountManager)(nil).SetAccountStatus), ctx, setAccountStatusInput)
+}
+
+// SetOverdraftLimit mocks base method.
+func (m *MockAccountManager) SetOverdraftLimit(ctx context.Context, setOverdraftLimitInput internal.SetOverdraftLimitInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOverdraftLimit", ctx, setOverdraftLimitInput)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOverdraftLimit indicates an expected call of SetOverdraftLimit.
+func (mr *MockAccountManagerMockRecorder) SetOverdraftLimit(ctx, setOverdraftLimitInput interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOverdraftLimit", reflect.TypeOf((*MockAccountManager)(nil).SetOverdraftLimit), ctx, setOverdraftLimitInput)
+}
+
+// Transfer mocks base method.
+func (m *MockAccountManager) Transfer(ctx context.Context, srcAccountID string, transferInput internal.TransferInput) (internal.TransferOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transfer", ctx, srcAccountID, transferInput)
+	ret0, _ := ret[0].(internal.TransferOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // Transfer indicates an expected call of Transfer.
 func (mr *MockAccountManagerMockRecorder) Transfer(ctx, srcAccountID, transferInput interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockAccountManager)(nil).Transfer), ctx, srcAccountID, transferInput)
 }
+
+// Withdraw mocks base method.
+func (m *MockAccountManager) Withdraw(ctx context.Context, withdrawInput internal.WithdrawInput) (internal.WithdrawOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Withdraw", ctx, withdrawInput)
+	ret0, _ := ret[0].(internal.WithdrawOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Withdraw indicates an expected call of Withdraw.
+func (mr *MockAccountManagerMockRecorder) Withdraw(ctx, withdrawInput interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockAccountManager)(nil).Withdraw), ctx, withdrawInput)
+}